@@ -17,9 +17,10 @@ import (
 )
 
 var (
-	_ schema.Source      = (*CsvDataSource)(nil)
-	_ schema.Conn        = (*CsvDataSource)(nil)
-	_ schema.ConnScanner = (*CsvDataSource)(nil)
+	_ schema.Source         = (*CsvDataSource)(nil)
+	_ schema.Conn           = (*CsvDataSource)(nil)
+	_ schema.ConnScanner    = (*CsvDataSource)(nil)
+	_ schema.ConnProjection = (*CsvDataSource)(nil)
 )
 
 // Csv DataSource, implements qlbridge schema DataSource, SourceConn, Scanner
@@ -39,6 +40,7 @@ type CsvDataSource struct {
 	rowct    uint64
 	headers  []string
 	colindex map[string]int
+	keep     []bool // columns to read, nil for all of them
 	indexCol int
 	filter   expr.Node
 }
@@ -130,6 +132,17 @@ func (m *CsvDataSource) Open(connInfo string) (schema.Conn, error) {
 	return NewCsvSource(connInfo, 0, f, exit)
 }
 
+// Project part of optional ConnProjection interface, only the values of
+// the given columns are read, the others are nil.
+func (m *CsvDataSource) Project(cols []string) {
+	m.keep = make([]bool, len(m.headers))
+	for _, col := range cols {
+		if i, ok := m.colindex[strings.ToLower(col)]; ok {
+			m.keep[i] = true
+		}
+	}
+}
+
 func (m *CsvDataSource) Close() error {
 	defer func() {
 		if r := recover(); r != nil {
@@ -167,7 +180,9 @@ func (m *CsvDataSource) Next() schema.Message {
 			}
 			vals := make([]driver.Value, len(row))
 			for i, val := range row {
-				vals[i] = val
+				if m.keep == nil || m.keep[i] {
+					vals[i] = val
+				}
 			}
 			//u.Debugf("headers: %#v \n\trows:  %#v", m.headers, row)
 			return NewSqlDriverMessageMap(m.rowct, vals, m.colindex)
@@ -2,6 +2,7 @@ package files
 
 import (
 	"path/filepath"
+	"sync"
 	"time"
 
 	u "github.com/araddon/gou"
@@ -10,15 +11,18 @@ import (
 	"google.golang.org/api/iterator"
 
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
 )
 
 var (
 	// Our file-pager wraps our file-scanners to move onto next file
-	_ FileReaderIterator  = (*FilePager)(nil)
-	_ schema.ConnScanner  = (*FilePager)(nil)
-	_ exec.ExecutorSource = (*FilePager)(nil)
+	_ FileReaderIterator    = (*FilePager)(nil)
+	_ schema.ConnScanner    = (*FilePager)(nil)
+	_ schema.ConnProjection = (*FilePager)(nil)
+	_ schema.ConnLimit      = (*FilePager)(nil)
+	_ exec.ExecutorSource   = (*FilePager)(nil)
 
 	// Default file queue size to buffer by pager
 	FileBufferSize = 5
@@ -32,6 +36,7 @@ type FilePager struct {
 	rowct           int64
 	table           string
 	exit            chan bool
+	exitOnce        sync.Once
 	err             error
 	closed          bool
 	fs              *FileSource
@@ -41,6 +46,9 @@ type FilePager struct {
 	Limit           int
 	tbl             *schema.Table
 	p               *plan.Source
+	cols            []string
+	rowLimit        int // rows of a pushed down LIMIT, 0 for all of them
+	rowsOut         int
 	usePartitioning bool
 
 	schema.ConnScanner
@@ -84,6 +92,23 @@ func (m *FilePager) Columns() []string {
 	return m.tbl.Columns()
 }
 
+// Project part of optional ConnProjection interface, the columns the query
+// reads are passed on to the scanner of each file.
+func (m *FilePager) Project(cols []string) {
+	m.cols = cols
+}
+
+// LimitRows part of optional ConnLimit interface, the pager stops reading
+// and fetching files after n rows.  Files are not sorted, so a limit with
+// an order by is not taken.
+func (m *FilePager) LimitRows(n int, orderBy []expr.Node, desc []bool) bool {
+	if len(orderBy) > 0 {
+		return false
+	}
+	m.rowLimit = n
+	return true
+}
+
 // NextScanner provides the next scanner assuming that each scanner
 // represents different file, and multiple files for single source
 func (m *FilePager) NextScanner() (schema.ConnScanner, error) {
@@ -101,6 +126,9 @@ func (m *FilePager) NextScanner() (schema.ConnScanner, error) {
 		u.Errorf("Could not open file scanner %v err=%v", m.fs.fileType, err)
 		return nil, err
 	}
+	if colScanner, ok := scanner.(schema.ConnProjection); ok && m.cols != nil {
+		colScanner.Project(m.cols)
+	}
 	m.ConnScanner = scanner
 	return scanner, err
 }
@@ -165,7 +193,10 @@ func (m *FilePager) fetcher() {
 		default:
 			o, err := iter.Next()
 			if err == iterator.Done {
-				m.readers <- nil
+				select {
+				case m.readers <- nil:
+				case <-m.exit:
+				}
 				return
 			} else if err == context.Canceled || err == context.DeadlineExceeded {
 				// Return to user
@@ -202,7 +233,7 @@ func (m *FilePager) fetcher() {
 					continue
 				}
 				ctxCancel()
-				close(m.exit)
+				m.closeExit()
 				u.Errorf("could not read %q err=%v", fi.Name, err)
 				return
 			} else {
@@ -228,7 +259,12 @@ func (m *FilePager) fetcher() {
 			}
 
 			// This will back-pressure after we reach our queue size
-			m.readers <- fr
+			select {
+			case m.readers <- fr:
+			case <-m.exit:
+				f.Close()
+				return
+			}
 
 			if m.Limit > 0 && fetchCt >= m.Limit {
 				return
@@ -244,6 +280,11 @@ func (m *FilePager) Next() schema.Message {
 	if m.ConnScanner == nil {
 		m.NextScanner()
 	}
+	if m.rowLimit > 0 && m.rowsOut >= m.rowLimit {
+		// reached the limit, stop fetching files
+		m.Close()
+		return nil
+	}
 	for {
 		if m.closed {
 			return nil
@@ -273,6 +314,7 @@ func (m *FilePager) Next() schema.Message {
 		}
 
 		m.rowct++
+		m.rowsOut++
 		return msg
 	}
 }
@@ -280,6 +322,11 @@ func (m *FilePager) Next() schema.Message {
 // Close this connection/pager
 func (m *FilePager) Close() error {
 	m.closed = true
-	//close(m.exit)
+	m.closeExit()
 	return nil
 }
+
+// closeExit stop the fetcher, once, it also closes it on errors.
+func (m *FilePager) closeExit() {
+	m.exitOnce.Do(func() { close(m.exit) })
+}
@@ -19,9 +19,10 @@ import (
 )
 
 var (
-	_ schema.Source      = (*JsonSource)(nil)
-	_ schema.Conn        = (*JsonSource)(nil)
-	_ schema.ConnScanner = (*JsonSource)(nil)
+	_ schema.Source         = (*JsonSource)(nil)
+	_ schema.Conn           = (*JsonSource)(nil)
+	_ schema.ConnScanner    = (*JsonSource)(nil)
+	_ schema.ConnProjection = (*JsonSource)(nil)
 )
 
 type FileLineHandler func(line []byte) (schema.Message, error)
@@ -46,6 +47,7 @@ type JsonSource struct {
 	lh       FileLineHandler
 	columns  []string
 	colindex map[string]int
+	project  map[string]bool // keys to read, nil for all of them
 	indexCol int
 	filter   expr.Node
 }
@@ -144,6 +146,16 @@ func (m *JsonSource) Open(connInfo string) (schema.Conn, error) {
 	return NewJsonSource(connInfo, f, exit, m.lhSpec, nil)
 }
 
+// Project part of optional ConnProjection interface, the default line
+// handler only reads the keys of the given columns, a custom FileLineHandler
+// reads all of them.
+func (m *JsonSource) Project(cols []string) {
+	m.project = make(map[string]bool, len(cols))
+	for _, col := range cols {
+		m.project[strings.ToLower(col)] = true
+	}
+}
+
 func (m *JsonSource) Close() error {
 	defer func() {
 		if r := recover(); r != nil {
@@ -201,10 +213,13 @@ func (m *JsonSource) jsonDefaultLine(line []byte) (schema.Message, error) {
 	keys := make(map[string]int, len(jm))
 	i := 0
 	for k, val := range jm {
+		if m.project != nil && !m.project[strings.ToLower(k)] {
+			continue
+		}
 		vals[i] = val
 		keys[k] = i
 		i++
 	}
 	u.Debugf("json data: %#v \n%#v", keys, vals)
-	return NewSqlDriverMessageMap(m.rowct, vals, keys), nil
+	return NewSqlDriverMessageMap(m.rowct, vals[:i], keys), nil
 }
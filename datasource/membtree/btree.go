@@ -117,12 +117,26 @@ type StaticDataSource struct {
 	exit     <-chan bool
 	name     string
 	tbl      *schema.Table
-	indexCol int        // Which column position is indexed?  ie primary key
-	cursor   btree.Item // cursor position for paging
+	indexCol int    // Which column position is indexed?  ie primary key
+	cursor   Cursor // cursor position for paging
 	bt       *btree.BTree
-	max      int
 }
 
+// Cursor the position of a scan of the rows of a StaticDataSource, scans
+// each with their own Cursor read every row even if scanning concurrently.
+type Cursor struct {
+	item btree.Item
+}
+
+// staticConn a connection to a StaticDataSource, scanning its rows with
+// its own cursor.
+type staticConn struct {
+	*StaticDataSource
+	cursor Cursor
+}
+
+func (m *staticConn) Next() schema.Message { return m.NextFrom(&m.cursor) }
+
 func NewStaticDataSource(name string, indexedCol int, data [][]driver.Value, cols []string) *StaticDataSource {
 
 	// This source schema is a single table
@@ -155,7 +169,6 @@ func NewStaticData(name string) *StaticDataSource {
 
 func (m *StaticDataSource) Init()                                     {}
 func (m *StaticDataSource) Setup(*schema.Schema) error                { return nil }
-func (m *StaticDataSource) Open(connInfo string) (schema.Conn, error) { return m, nil }
 func (m *StaticDataSource) Table(table string) (*schema.Table, error) { return m.tbl, nil }
 func (m *StaticDataSource) Close() error                              { return nil }
 func (m *StaticDataSource) CreateIterator() schema.Iterator           { return m }
@@ -164,7 +177,16 @@ func (m *StaticDataSource) Columns() []string                         { return m
 func (m *StaticDataSource) Length() int                               { return m.bt.Len() }
 func (m *StaticDataSource) SetColumns(cols []string)                  { m.tbl.SetColumns(cols) }
 
-func (m *StaticDataSource) Next() schema.Message {
+// Open a connection to the source, which scans its rows with its own cursor.
+func (m *StaticDataSource) Open(connInfo string) (schema.Conn, error) {
+	return &staticConn{StaticDataSource: m}, nil
+}
+
+// Next the next row of the scan of the source's own cursor.
+func (m *StaticDataSource) Next() schema.Message { return m.NextFrom(&m.cursor) }
+
+// NextFrom the next row of the scan at cursor, advancing it.
+func (m *StaticDataSource) NextFrom(cursor *Cursor) schema.Message {
 	//u.Infof("Next()")
 	select {
 	case <-m.exit:
@@ -173,39 +195,34 @@ func (m *StaticDataSource) Next() schema.Message {
 		for {
 			var item btree.Item
 
-			if m.cursor == nil {
+			if cursor.item == nil {
 				//u.Infof("create new Ascend len=%d", m.Length())
-				m.max = 0
 				m.bt.Ascend(func(a btree.Item) bool {
 					item = a
-					//u.Debugf("first  item btreeP:%p itemP:%p cursorP:%p  %#v", m, item, m.cursor, item)
+					//u.Debugf("first  item btreeP:%p itemP:%p cursorP:%p  %#v", m, item, cursor.item, item)
 					return false // stop after this
 				})
 			} else {
-				m.bt.AscendGreaterOrEqual(m.cursor, func(a btree.Item) bool {
-					if m.cursor == a {
+				m.bt.AscendGreaterOrEqual(cursor.item, func(a btree.Item) bool {
+					if cursor.item == a {
 						//u.Debugf("equal, return true ie continue")
 						item = nil
 						return true
 					}
 					item = a
-					//u.Debugf("found  item btreeP:%p itemP:%p cursorP:%p  %#v", m, item, m.cursor, item)
+					//u.Debugf("found  item btreeP:%p itemP:%p cursorP:%p  %#v", m, item, cursor.item, item)
 					return false // stop after this
 				})
 			}
-			m.max++
-			// if m.max > 20 {
-			// 	return nil
-			// }
 
 			if item == nil {
 				//u.Debugf("reset cursor to nil  %#v", item)
-				m.cursor = nil
+				cursor.item = nil
 				return nil
 			}
-			m.cursor = item
+			cursor.item = item
 			msg := item.(*DriverItem)
-			//u.Infof("return item btreeP:%p itemP:%p cursorP:%p  %v %v", m, item, m.cursor, msg.Id(), msg.Values())
+			//u.Infof("return item btreeP:%p itemP:%p cursorP:%p  %v %v", m, item, cursor.item, msg.Id(), msg.Values())
 			//u.Debugf("return? %T  %v", item, item.(*DriverItem).SqlDriverMessageMap)
 			return msg.SqlDriverMessageMap.Copy()
 			//return datasource.NewSqlDriverMessageMapVals(uint64(m.cursor-1), m.data[m.cursor-1], m.cols)
@@ -213,7 +230,6 @@ func (m *StaticDataSource) Next() schema.Message {
 	}
 }
 
-// interface for Upsert.Put()
 func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interface{}) (schema.Key, error) {
 
 	//u.Infof("%p Put(),  row:%#v", m, row)
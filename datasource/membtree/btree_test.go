@@ -152,3 +152,27 @@ func TestStaticDataSource(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Equal(t, curSize, delCt, "Should have deleted all records")
 }
+
+func TestStaticScanConns(t *testing.T) {
+
+	static := membtree.NewStaticDataSource("nums", 0, [][]driver.Value{{1}, {2}, {3}}, []string{"n"})
+
+	// each connection scans every row, even with their scans interleaved
+	c1, err := static.Open("nums")
+	assert.Equal(t, nil, err)
+	c2, err := static.Open("nums")
+	assert.Equal(t, nil, err)
+	s1, s2 := c1.(schema.ConnScanner), c2.(schema.ConnScanner)
+	var rows1, rows2 []driver.Value
+	for {
+		m1, m2 := s1.Next(), s2.Next()
+		if m1 == nil || m2 == nil {
+			assert.True(t, m1 == nil && m2 == nil, "both scans end together")
+			break
+		}
+		rows1 = append(rows1, m1.Body().(*datasource.SqlDriverMessageMap).Values()[0])
+		rows2 = append(rows2, m2.Body().(*datasource.SqlDriverMessageMap).Values()[0])
+	}
+	assert.Equal(t, []driver.Value{1, 2, 3}, rows1)
+	assert.Equal(t, []driver.Value{1, 2, 3}, rows2)
+}
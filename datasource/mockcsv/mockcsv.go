@@ -61,11 +61,16 @@ type Source struct {
 	raw           map[string]string
 }
 
-// Table converts the static csv-source into a schema.Conn source
+// Table converts the static csv-source into a schema.Conn source, each
+// with its own cursor so concurrent scans of a table each read every row.
 type Table struct {
 	*membtree.StaticDataSource
+	cursor membtree.Cursor
 }
 
+// Next the next row of the scan of this Table.
+func (m *Table) Next() schema.Message { return m.NextFrom(&m.cursor) }
+
 // New create csv mock source.
 func New() *Source {
 	return &Source{
@@ -239,6 +239,9 @@ func (m *qryconn) WalkSourceSelect(planner plan.Planner, p *plan.Source) (plan.T
 	sqlSelect = p.Stmt.Source
 	u.Infof("original after From(source) rewrite %s", sqlSelect.String())
 	sqlSelect.RewriteAsRawSelect()
+	if p.Needed != nil {
+		projectNeeded(sqlSelect, m.tbl, p.Needed)
+	}
 
 	m.cols = sqlSelect.Columns.UnAliasedFieldNames()
 	m.colidx = sqlSelect.ColIndexes()
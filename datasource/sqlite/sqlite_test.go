@@ -3,6 +3,7 @@ package sqlite_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"os"
 	"sync"
@@ -145,3 +146,18 @@ func TestSuite(t *testing.T) {
 	LoadTestDataOnce(t)
 	testutil.RunSimpleSuite(t)
 }
+
+func TestProjectionPushdown(t *testing.T) {
+	LoadTestDataOnce(t)
+	td.TestContext = planContext
+	defer td.SetContextToMockCsv()
+
+	// the columns of a where sqlite isn't given, and of the order by, are
+	// read from sqlite though they aren't projected
+	testutil.TestSelect(t, `SELECT email FROM users WHERE yy(reg_date) > 10`,
+		[][]driver.Value{{"aaron@email.com"}},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users ORDER BY email DESC`,
+		[][]driver.Value{{"hT2impsabc345c"}, {"hT2impsOPUREcVPc"}, {"9Ip1aKbeZe2njCDM"}},
+	)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
@@ -27,6 +28,19 @@ func newRewriter(stmt *rel.SqlSelect) *rewrite {
 	return m
 }
 
+// projectNeeded add the columns of the table the query reads that the
+// rewritten select doesn't, ie of an order by or a where that is evaluated
+// after the scan, so only those columns, but all of them, are read.
+func projectNeeded(sel *rel.SqlSelect, tbl *schema.Table, needed []string) {
+	have := sel.ColIndexes()
+	for _, col := range needed {
+		if _, ok := have[col]; ok || !tbl.HasField(col) {
+			continue
+		}
+		sel.AddColumn(*rel.NewColumn(col))
+	}
+}
+
 // WalkSourceSelect An interface implemented by this connection allowing the planner
 // to push down as much logic into mongo as possible
 func (m *rewrite) rewrite() (string, error) {
@@ -123,6 +137,12 @@ func (m *rewrite) walkSelectList() error {
 			// 	return nil, value.NewStringValue(curNode.Text), nil
 			case *expr.IdentityNode:
 				//u.Debugf("likely a projection, not agg T:%T  %v", curNode, curNode)
+			case *expr.CaseNode:
+				newNode, err := m.walkCase(curNode)
+				if err != nil {
+					return err
+				}
+				col.Expr = newNode
 			default:
 				u.Warnf("unrecognized not agg T:%T  %v", curNode, curNode)
 				//panic("Unrecognized node type")
@@ -214,6 +234,8 @@ func (m *rewrite) walkNode(cur expr.Node) (expr.Node, error) {
 		return curNode, nil
 	case *expr.ArrayNode:
 		return m.walkArrayNode(curNode)
+	case *expr.CaseNode:
+		return m.walkCase(curNode)
 	default:
 		u.Debugf("unrecognized T:%T  %v", cur, cur)
 	}
@@ -221,6 +243,21 @@ func (m *rewrite) walkNode(cur expr.Node) (expr.Node, error) {
 	return cur, nil
 }
 
+// Case Nodes are native to sqlite (searched and simple), so pass them through
+// after rewriting the when conditions.
+//
+//	CASE WHEN x != NULL THEN 1 ELSE 0 END  =>  CASE WHEN x IS NOT NULL THEN 1 ELSE 0 END
+func (m *rewrite) walkCase(node *expr.CaseNode) (expr.Node, error) {
+	for i, when := range node.Whens {
+		newNode, err := m.walkNode(when)
+		if err != nil {
+			return nil, err
+		}
+		node.Whens[i] = newNode
+	}
+	return node, nil
+}
+
 // Tri Nodes expressions:
 //
 //     <expression> [NOT] BETWEEN <expression> AND <expression>
@@ -0,0 +1,99 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Analyze)(nil)
+)
+
+// Analyze is executeable task for ANALYZE TABLE, runs the aggregate query
+// of the statistics of each field and stores them on the schema.Table for
+// the planner, the rows affected are the rows of the table.
+type Analyze struct {
+	*TaskBase
+	p *plan.Analyze
+}
+
+// NewAnalyze creates new analyze exec task.
+func NewAnalyze(ctx *plan.Context, p *plan.Analyze) *Analyze {
+	return &Analyze{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+}
+
+// Run Analyze
+func (m *Analyze) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	rows, err := runSubQuery(m.p.SubCtx, m.p.SubPlan)
+	if err != nil {
+		return err
+	}
+	// count(*), then count, approx_count_distinct, min, max of each field
+	if len(rows) != 1 || len(rows[0]) != 1+4*len(m.p.Fields) {
+		return fmt.Errorf("unexpected result of %s", m.p.Sub)
+	}
+	row := rows[0]
+
+	stats := &schema.TableStats{
+		Rows:     statCount(row[0]),
+		Fields:   make(map[string]*schema.FieldStats, len(m.p.Fields)),
+		Analyzed: time.Now(),
+	}
+	for i, name := range m.p.Fields {
+		vals := row[1+4*i : 5+4*i]
+		nonNull := statCount(vals[0])
+		fs := &schema.FieldStats{
+			Nulls:    stats.Rows - nonNull,
+			Distinct: statCount(vals[1]),
+		}
+		// the sketch is an estimate, never more than the values counted
+		if fs.Distinct > nonNull {
+			fs.Distinct = nonNull
+		}
+		fs.Min = m.statValue(name, vals[2])
+		fs.Max = m.statValue(name, vals[3])
+		stats.Fields[name] = fs
+	}
+	m.p.Tbl.SetStats(stats)
+
+	waited := time.Now()
+	m.msgOutCh <- &datasource.SqlDriverMessage{Vals: []driver.Value{int64(0), stats.Rows}, IdVal: 1}
+	m.sent(waited)
+	return nil
+}
+
+func statCount(v driver.Value) int64 {
+	if v == nil {
+		return 0
+	}
+	n, _ := value.ValueToInt64(value.NewValue(v))
+	return n
+}
+
+// statValue the min or max value of a field, as the type of the field as
+// sources may read (ie csv) all values as strings.
+func (m *Analyze) statValue(name string, v driver.Value) value.Value {
+	if v == nil {
+		return nil
+	}
+	val := value.NewValue(v)
+	if f, ok := m.p.Tbl.FieldMap[name]; ok && f.ValueType() != val.Type() {
+		if cv, err := value.Cast(f.ValueType(), val); err == nil {
+			return cv
+		}
+	}
+	return val
+}
@@ -0,0 +1,146 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// DefaultRecursionLimit is the default max number of iterations of a
+	// WITH RECURSIVE common table expression before it is an error.
+	// Override per query with plan.Context.RecursionLimit.
+	DefaultRecursionLimit = 100
+
+	// Ensure our cte scanner is a scanner
+	_ schema.ConnScanner = (*cteScanner)(nil)
+)
+
+// cteScanner scans the rows of a WITH common table expression.  The
+// cte query is run on first Next() of any source referencing it, rows
+// are materialized on the plan.Cte so are shared by all references.
+type cteScanner struct {
+	ctx  *plan.Context
+	cte  *plan.Cte
+	rows [][]driver.Value
+	pos  int
+	done bool
+}
+
+func newCteScanner(ctx *plan.Context, cte *plan.Cte) *cteScanner {
+	return &cteScanner{ctx: ctx, cte: cte}
+}
+
+func (m *cteScanner) Close() error { return nil }
+
+func (m *cteScanner) Next() schema.Message {
+	if !m.done {
+		m.done = true
+		rows, err := m.cte.Rows(func() ([][]driver.Value, error) {
+			return runCte(m.cte)
+		})
+		if err != nil {
+			m.ctx.AddError(err)
+			return nil
+		}
+		m.rows = rows
+	}
+	if m.pos >= len(m.rows) {
+		return nil
+	}
+	m.pos++
+	return datasource.NewSqlDriverMessageMapVals(uint64(m.pos), m.rows[m.pos-1], m.cte.Cols)
+}
+
+// runCte run the query of a common table expression.  If recursive the
+// anchor is run, then the step run against the rows of the last iteration
+// until it produces no new rows.
+func runCte(cte *plan.Cte) ([][]driver.Value, error) {
+
+	if cte.Analyze != nil {
+		return runExplainAnalyze(cte.Analyze)
+	}
+
+	rows, err := runSubQuery(cte.Ctx, cte.Plan)
+	if err != nil || !cte.Recursive() {
+		return rows, err
+	}
+
+	limit := cte.Ctx.RecursionLimit
+	if limit <= 0 {
+		limit = DefaultRecursionLimit
+	}
+
+	var seen map[string]struct{}
+	if !cte.All {
+		seen = make(map[string]struct{}, len(rows))
+		rows = distinctRows(rows, seen)
+	}
+
+	work := rows
+	for i := 0; len(work) > 0; i++ {
+		if i >= limit {
+			return nil, fmt.Errorf("recursive WITH %q exceeded the recursion limit of %d iterations", cte.Name, limit)
+		}
+		// The step is re-planned each iteration, as planning re-writes it
+		step, err := rel.ParseSqlSelectResolver(cte.Step.String(), cte.Ctx.Funcs)
+		if err != nil {
+			return nil, err
+		}
+		ctx := cte.Ctx.SubQueryContext(step)
+		ctx.AddCte(cte.WorkTable(work))
+		p, err := plan.WalkStmt(ctx, step, plan.NewPlanner(ctx))
+		if err != nil {
+			return nil, err
+		}
+		work, err = runSubQuery(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if seen != nil {
+			work = distinctRows(work, seen)
+		}
+		rows = append(rows, work...)
+	}
+	return rows, nil
+}
+
+// runExplainAnalyze run the statement of an EXPLAIN ANALYZE, discarding
+// its rows, the rows are of its plan with the statistics of each task.
+func runExplainAnalyze(ea *plan.ExplainAnalyze) ([][]driver.Value, error) {
+	job, _, err := runPlan(ea.Ctx, ea.Plan)
+	if err != nil {
+		return nil, err
+	}
+	return ea.Rows(func(t plan.Task) *plan.ExplainStats {
+		st, ok := job.PlanStats(t)
+		if !ok {
+			return nil
+		}
+		return &plan.ExplainStats{
+			RowsIn:       st.RowsIn,
+			RowsOut:      st.RowsOut,
+			Wall:         st.Wall,
+			Blocked:      st.Blocked,
+			PeakBuffered: st.PeakBuffered,
+		}
+	})
+}
+
+// distinctRows the rows not already seen, adding them to seen.
+func distinctRows(rows [][]driver.Value, seen map[string]struct{}) [][]driver.Value {
+	out := rows[:0]
+	for _, row := range rows {
+		key := rowHashKey(row)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, row)
+	}
+	return out
+}
@@ -0,0 +1,287 @@
+package exec
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Distinct)(nil)
+
+	// number of hash partitions rows are spilled into, each partition
+	// is de-duped on its own so must fit in memory.
+	distinctSpillPartitions = 16
+)
+
+// Distinct removes duplicate rows (SELECT DISTINCT) from the final
+// projected rows.  Rows are streamed through in arrival order (so an
+// upstream ORDER BY is preserved) with a hash set of rows seen so far.
+//
+// If the hash set grows beyond the memory limit, new rows not already
+// seen are spilled to disk hash-partitioned by row.  After input completes
+// each partition is de-duped, then the partitions are merged back
+// together in original arrival order.
+//
+// Also applies the LIMIT and OFFSET as the projection can't know how many
+// rows will be removed.
+type Distinct struct {
+	*TaskBase
+	p      *plan.Distinct
+	closed bool
+}
+
+// NewDistinct create distinct task.
+func NewDistinct(ctx *plan.Context, p *plan.Distinct) *Distinct {
+	return &Distinct{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+}
+
+// Close the task, channels, cleanup.
+func (m *Distinct) Close() error {
+	m.Lock()
+	if m.closed {
+		m.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.Unlock()
+	return m.TaskBase.Close()
+}
+
+// Run the distinct task.
+func (m *Distinct) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	inCh := m.MessageIn()
+
+	limit := m.p.Stmt.Limit
+	if limit == 0 {
+		limit = math.MaxInt32
+	}
+	offset := m.p.Stmt.Offset
+	memLimit := m.Ctx.MemoryLimit
+	if memLimit <= 0 {
+		memLimit = DefaultMemoryLimit
+	}
+
+	var (
+		seen     = make(map[string]struct{})
+		memUsed  int64
+		seq      uint64
+		rowCt    int
+		colIndex map[string]int
+		sp       *distinctSpill
+	)
+	defer func() {
+		if sp != nil {
+			sp.Close()
+		}
+	}()
+
+	emit := func(msg schema.Message) bool {
+		if rowCt >= limit {
+			return false
+		}
+		if offset > 0 {
+			// the first distinct rows are skipped
+			offset--
+			return true
+		}
+		rowCt++
+		waited := time.Now()
+		select {
+		case m.msgOutCh <- msg:
+			m.sent(waited)
+		case <-m.SigChan():
+			return false
+		}
+		if rowCt >= limit {
+			// Sending nil message is a message to downstream to shutdown
+			select {
+			case m.msgOutCh <- nil:
+			case <-m.SigChan():
+			}
+			m.Quit()
+			return false
+		}
+		return true
+	}
+
+msgReadLoop:
+	for {
+		waited := time.Now()
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-inCh:
+			m.received(waited, msg)
+			if !ok || msg == nil {
+				break msgReadLoop
+			}
+			sdm, isSdm := msg.(*datasource.SqlDriverMessageMap)
+			if !isSdm {
+				u.Errorf("unrecognized msg %T", msg)
+				return fmt.Errorf("To use Distinct must use SqlDriverMessageMap but got %T", msg)
+			}
+			seq++
+			vals := sdm.Values()
+			key := rowHashKey(vals)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			if sp == nil {
+				size := rowMemSize(vals) + int64(len(key))
+				if memUsed+size <= memLimit {
+					memUsed += size
+					seen[key] = struct{}{}
+					if !emit(sdm) {
+						return nil
+					}
+					continue
+				}
+				u.Debugf("distinct exceeded memory limit %d, spilling to disk", memLimit)
+				var err error
+				if sp, err = newDistinctSpill(distinctSpillPartitions); err != nil {
+					return err
+				}
+				colIndex = sdm.ColIndex
+			}
+			if err := sp.Add(&spillRow{Seq: seq, Key: key, Vals: vals}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if sp == nil {
+		return nil
+	}
+	// no longer needed, the spilled rows don't overlap with seen rows
+	seen = nil
+	return sp.Merge(func(row *spillRow) bool {
+		return emit(datasource.NewSqlDriverMessageMap(row.Seq, row.Vals, colIndex))
+	})
+}
+
+// distinctSpill spilled rows, hash partitioned on row key such that
+// all duplicates of a row are in the same partition.
+type distinctSpill struct {
+	*partitionSpill
+}
+
+func newDistinctSpill(partCt int) (*distinctSpill, error) {
+	sp, err := newPartitionSpill("distinct", partCt, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &distinctSpill{sp}, nil
+}
+
+// Merge de-dupe each partition (keeping first arrival) into a new
+// file, then merge the de-duped partitions by arrival order.
+func (m *distinctSpill) Merge(emit func(row *spillRow) bool) error {
+
+	readers := make([]*spillReader, 0, len(m.parts))
+	for i, part := range m.parts {
+		deduped, err := dedupeSpillFile(part)
+		if err != nil {
+			return err
+		}
+		part.Close()
+		m.parts[i] = deduped
+		rdr, err := deduped.Rewind()
+		if err != nil {
+			return err
+		}
+		readers = append(readers, rdr)
+	}
+
+	h := &spillRowHeap{}
+	for i, rdr := range readers {
+		row, err := rdr.Next()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		heap.Push(h, &spillRowSrc{row: row, src: i})
+	}
+	for h.Len() > 0 {
+		next := heap.Pop(h).(*spillRowSrc)
+		if !emit(next.row) {
+			return nil
+		}
+		row, err := readers[next.src].Next()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		heap.Push(h, &spillRowSrc{row: row, src: next.src})
+	}
+	return nil
+}
+
+// dedupeSpillFile write the first occurrence of each row in the file
+// to a new file, rows stay in arrival order.
+func dedupeSpillFile(sf *spillFile) (*spillFile, error) {
+	out, err := newSpillFile("distinct")
+	if err != nil {
+		return nil, err
+	}
+	rdr, err := sf.Rewind()
+	if err != nil {
+		out.Close()
+		return nil, err
+	}
+	seen := make(map[string]struct{}, sf.ct)
+	for {
+		row, err := rdr.Next()
+		if err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			out.Close()
+			return nil, err
+		}
+		if _, exists := seen[row.Key]; exists {
+			continue
+		}
+		seen[row.Key] = struct{}{}
+		if err = out.Write(row); err != nil {
+			out.Close()
+			return nil, err
+		}
+	}
+}
+
+type spillRowSrc struct {
+	row *spillRow
+	src int
+}
+
+// spillRowHeap a min-heap of rows by arrival order.
+type spillRowHeap []*spillRowSrc
+
+func (h spillRowHeap) Len() int            { return len(h) }
+func (h spillRowHeap) Less(i, j int) bool  { return h[i].row.Seq < h[j].row.Seq }
+func (h spillRowHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *spillRowHeap) Push(x interface{}) { *h = append(*h, x.(*spillRowSrc)) }
+func (h *spillRowHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
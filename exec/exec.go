@@ -102,9 +102,12 @@ type (
 		WalkJoin(p *plan.JoinMerge) (Task, error)
 		WalkJoinKey(p *plan.JoinKey) (Task, error)
 		WalkWhere(p *plan.Where) (Task, error)
+		WalkSemiJoin(p *plan.SemiJoin) (Task, error)
 		WalkHaving(p *plan.Having) (Task, error)
 		WalkGroupBy(p *plan.GroupBy) (Task, error)
 		WalkOrder(p *plan.Order) (Task, error)
+		WalkWindow(p *plan.Window) (Task, error)
+		WalkDistinct(p *plan.Distinct) (Task, error)
 		WalkProjection(p *plan.Projection) (Task, error)
 		// Other Statements
 		WalkCommand(p *plan.Command) (Task, error)
@@ -113,6 +116,7 @@ type (
 		WalkCreate(p *plan.Create) (Task, error)
 		WalkDrop(p *plan.Drop) (Task, error)
 		WalkAlter(p *plan.Alter) (Task, error)
+		WalkAnalyze(p *plan.Analyze) (Task, error)
 	}
 
 	// ExecutorSource Sources can often do their own execution-plan for sub-select statements
@@ -122,4 +126,10 @@ type (
 		// WalkExecSource given our plan, turn that into a Task.
 		WalkExecSource(p *plan.Source) (Task, error)
 	}
+
+	// ExecutorUnion is an optional interface for executors that can run
+	// set operations (UNION, INTERSECT, EXCEPT).
+	ExecutorUnion interface {
+		WalkUnion(p *plan.Union) (Task, error)
+	}
 )
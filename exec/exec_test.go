@@ -1,8 +1,12 @@
 package exec_test
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -13,16 +17,42 @@ import (
 	"github.com/araddon/qlbridge/datasource/mockcsv"
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/testutil"
+	"github.com/araddon/qlbridge/value"
 )
 
 func init() {
 	testutil.Setup()
 	// load our mock data sources "users", "articles"
 	td.LoadTestDataOnce()
+	expr.FuncAdd("product", &productAgg{})
 }
 
+// productAgg a custom aggregate, the product of the values of a group.
+type productAgg struct {
+	n float64
+}
+
+func (m *productAgg) Type() value.ValueType { return value.NumberType }
+func (m *productAgg) IsAgg() bool           { return true }
+func (m *productAgg) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return expr.EmptyEvalFunc, nil
+}
+func (m *productAgg) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	return &productAgg{n: 1}, nil
+}
+func (m *productAgg) Accumulate(args []value.Value) {
+	if fv, ok := value.ValueToFloat64(args[0]); ok {
+		m.n *= fv
+	}
+}
+func (m *productAgg) Merge(p *expr.AggPartial)  { m.n *= p.N }
+func (m *productAgg) Partial() *expr.AggPartial { return &expr.AggPartial{N: m.n} }
+func (m *productAgg) Finalize() value.Value     { return value.NewNumberValue(m.n) }
+
 func TestStatements(t *testing.T) {
 	testutil.RunTestSuite(t)
 }
@@ -192,6 +222,221 @@ func TestExecGroupBy(t *testing.T) {
 	assert.True(t, int(row[0].(float64)) == 14, "expected avg(len(email))=14 but got %v", int(row[0].(float64)))
 }
 
+func TestExecJobStats(t *testing.T) {
+
+	ctx := td.TestContext(`SELECT user_id, count(*) FROM orders GROUP BY user_id`)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.True(t, err == nil, "no error %v", err)
+
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+	err = job.Setup()
+	assert.True(t, err == nil)
+	err = job.Run()
+	assert.True(t, err == nil, "no error %v", err)
+	assert.Equal(t, 2, len(msgs))
+
+	stats := job.Stats()
+	assert.True(t, len(stats) > 2, "%v", stats)
+	assert.Equal(t, 0, stats[0].Depth)
+	assert.Equal(t, int64(2), stats[0].RowsOut)
+	assert.True(t, stats[0].Wall > 0, "%v", stats[0])
+
+	var gb *exec.JobTaskStats
+	for i := range stats {
+		if _, ok := stats[i].Plan.(*plan.GroupBy); ok {
+			gb = &stats[i]
+		}
+	}
+	assert.True(t, gb != nil, "%v", stats)
+	assert.Equal(t, 1, gb.Depth)
+	assert.Equal(t, int64(3), gb.RowsIn)
+	assert.Equal(t, int64(2), gb.RowsOut)
+	// the groups held, not the rows
+	assert.Equal(t, int64(2), gb.PeakBuffered)
+	assert.True(t, gb.Wall > 0 && gb.Blocked <= gb.Wall, "%v", gb)
+
+	p := stats[0].Plan.(*plan.Select)
+	ps, ok := job.PlanStats(p.Children()[0])
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), ps.RowsOut)
+}
+
+func TestExecCancel(t *testing.T) {
+
+	var buf bytes.Buffer
+	buf.WriteString("id,user_id\n")
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&buf, "%d,user%d\n", i, i%10)
+	}
+	mockcsv.LoadTable(mockcsv.SchemaName, "cancel_events", buf.String())
+
+	run := func(goCtx context.Context) error {
+		ctx := td.TestContext(`SELECT id, user_id FROM cancel_events WHERE user_id != "x"`)
+		ctx.Context = goCtx
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+		msgs := make([]schema.Message, 0)
+		job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+		assert.Equal(t, nil, job.Setup())
+		return job.Run()
+	}
+
+	assert.Equal(t, nil, run(context.Background()))
+
+	// cancelled stops every task, returning the context error
+	goCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, run(goCtx))
+
+	goCtx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, run(goCtx))
+}
+
+func TestExecCustomAggregate(t *testing.T) {
+
+	ctx := td.TestContext(`SELECT user_id, product(price) FROM orders GROUP BY user_id`)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.True(t, err == nil, "no error %v", err)
+
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+	err = job.Setup()
+	assert.True(t, err == nil)
+	err = job.Run()
+	assert.True(t, err == nil, "no error %v", err)
+	assert.Equal(t, 2, len(msgs))
+	products := make(map[string]interface{})
+	for _, msg := range msgs {
+		row := msg.(*datasource.SqlDriverMessageMap).Values()
+		products[row[0].(string)] = row[1]
+	}
+	assert.Equal(t, 843.75, products["9Ip1aKbeZe2njCDM"])
+	assert.Equal(t, 22.5, products["abcabcabc"])
+}
+
+func TestExecAggregates(t *testing.T) {
+
+	ctx := td.TestContext(`SELECT user_id, min(price), max(order_date), stddev_pop(price),
+			percentile(price, 0.5), group_concat(item_id, "|"), array_agg(order_id),
+			count(*) FILTER (WHERE price > 30) AS big_ct, count(DISTINCT item_count),
+			approx_count_distinct(item_id), approx_percentile(price, 0.5)
+		FROM orders GROUP BY user_id`)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.True(t, err == nil, "no error %v", err)
+
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+	err = job.Setup()
+	assert.True(t, err == nil)
+	err = job.Run()
+	assert.True(t, err == nil, "no error %v", err)
+	assert.Equal(t, 2, len(msgs))
+	rows := make(map[string][]driver.Value)
+	for _, msg := range msgs {
+		row := msg.(*datasource.SqlDriverMessageMap).Values()
+		rows[row[0].(string)] = row
+	}
+	assert.Equal(t, []driver.Value{"9Ip1aKbeZe2njCDM", "22.50", "2013-10-24T17:29:39.738Z", 7.5, 30.0, "1|2",
+		[]value.Value{value.NewStringValue("1"), value.NewStringValue("2")}, int64(1), int64(1), int64(2), 30.0}, rows["9Ip1aKbeZe2njCDM"])
+	assert.Equal(t, []driver.Value{"abcabcabc", "22.50", "2013-10-24T17:29:39.738Z", 0.0, 22.5, "1",
+		[]value.Value{value.NewStringValue("3")}, int64(0), int64(1), int64(1), 22.5}, rows["abcabcabc"])
+}
+
+func TestExecAggregateExpressions(t *testing.T) {
+
+	run := func(sqlText string) map[string][]driver.Value {
+		ctx := td.TestContext(sqlText)
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+
+		msgs := make([]schema.Message, 0)
+		job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+		err = job.Setup()
+		assert.True(t, err == nil)
+		err = job.Run()
+		assert.True(t, err == nil, "no error %v", err)
+		rows := make(map[string][]driver.Value)
+		for _, msg := range msgs {
+			row := msg.(*datasource.SqlDriverMessageMap).Values()
+			rows[row[0].(string)] = row
+		}
+		return rows
+	}
+
+	// expressions inside, and wrapping, aggregates
+	rows := run(`SELECT user_id, sum(price * 2) AS double_price, sum(price) / count(*) AS avg_price,
+			sqrt(count(*) * 4), 2 * count(*)
+		FROM orders GROUP BY user_id`)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, []driver.Value{"9Ip1aKbeZe2njCDM", 120.0, 30.0, 2.8284271247461903, int64(4)}, rows["9Ip1aKbeZe2njCDM"])
+	assert.Equal(t, []driver.Value{"abcabcabc", 45.0, 22.5, 2.0, int64(2)}, rows["abcabcabc"])
+
+	// HAVING on aggregates which are not in the select list
+	rows = run(`SELECT user_id FROM orders GROUP BY user_id HAVING sum(price) > 50`)
+	assert.Equal(t, map[string][]driver.Value{"9Ip1aKbeZe2njCDM": {"9Ip1aKbeZe2njCDM"}}, rows)
+
+	rows = run(`SELECT user_id, count(*) AS ct FROM orders GROUP BY user_id HAVING count(*) > 1 AND max(price) > 30`)
+	assert.Equal(t, map[string][]driver.Value{"9Ip1aKbeZe2njCDM": {"9Ip1aKbeZe2njCDM", int64(2)}}, rows)
+}
+
+func TestExecGroupingSets(t *testing.T) {
+
+	run := func(sqlText string) map[string][]driver.Value {
+		ctx := td.TestContext(sqlText)
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+
+		msgs := make([]schema.Message, 0)
+		job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+		err = job.Setup()
+		assert.True(t, err == nil)
+		err = job.Run()
+		assert.True(t, err == nil, "no error %v", err)
+		// rows by their group by values, nil of those rolled up
+		rows := make(map[string][]driver.Value)
+		for _, msg := range msgs {
+			row := msg.(*datasource.SqlDriverMessageMap).Values()
+			rows[fmt.Sprintf("%v/%v", row[0], row[1])] = row
+		}
+		return rows
+	}
+
+	rows := run(`SELECT user_id, item_id, sum(price) AS total, grouping(user_id, item_id) AS g
+		FROM orders GROUP BY ROLLUP (user_id, item_id)`)
+	assert.Equal(t, map[string][]driver.Value{
+		"9Ip1aKbeZe2njCDM/1":     {"9Ip1aKbeZe2njCDM", "1", 22.5, int64(0)},
+		"9Ip1aKbeZe2njCDM/2":     {"9Ip1aKbeZe2njCDM", "2", 37.5, int64(0)},
+		"9Ip1aKbeZe2njCDM/<nil>": {"9Ip1aKbeZe2njCDM", nil, 60.0, int64(1)},
+		"abcabcabc/1":            {"abcabcabc", "1", 22.5, int64(0)},
+		"abcabcabc/<nil>":        {"abcabcabc", nil, 22.5, int64(1)},
+		"<nil>/<nil>":            {nil, nil, 82.5, int64(3)},
+	}, rows)
+
+	rows = run(`SELECT user_id, item_id, count(*) FROM orders GROUP BY CUBE (user_id, item_id)`)
+	assert.Equal(t, 8, len(rows))
+	assert.Equal(t, []driver.Value{nil, "1", int64(2)}, rows["<nil>/1"])
+	assert.Equal(t, []driver.Value{nil, nil, int64(3)}, rows["<nil>/<nil>"])
+
+	rows = run(`SELECT count(*), CASE WHEN grouping(user_id) = 1 THEN "all" ELSE user_id END AS who
+		FROM orders GROUP BY GROUPING SETS ((user_id), ())`)
+	assert.Equal(t, map[string][]driver.Value{
+		"2/9Ip1aKbeZe2njCDM": {int64(2), "9Ip1aKbeZe2njCDM"},
+		"1/abcabcabc":        {int64(1), "abcabcabc"},
+		"3/all":              {int64(3), "all"},
+	}, rows)
+
+	// the grand total of no rows
+	rows = run(`SELECT user_id, count(*) FROM orders WHERE price > 1000 GROUP BY ROLLUP (user_id)`)
+	assert.Equal(t, map[string][]driver.Value{"<nil>/0": {nil, int64(0)}}, rows)
+}
+
 func TestExecHaving(t *testing.T) {
 	sqlText := `
 		select 
@@ -223,6 +468,163 @@ func TestExecHaving(t *testing.T) {
 	assert.True(t, int(row[1].(int64)) == 2, "expected 2 orders for %v", row)
 }
 
+func TestExecOrder(t *testing.T) {
+
+	run := func(sqlText string, memLimit int64) [][]driver.Value {
+		ctx := td.TestContext(sqlText)
+		ctx.MemoryLimit = memLimit
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+
+		msgs := make([]schema.Message, 0)
+		job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+		err = job.Setup()
+		assert.True(t, err == nil)
+		err = job.Run()
+		assert.True(t, err == nil, "no error %v", err)
+		rows := make([][]driver.Value, len(msgs))
+		for i, msg := range msgs {
+			rows[i] = msg.(*datasource.SqlDriverMessageMap).Values()
+		}
+		return rows
+	}
+
+	// Same results whether held in memory, spilled in runs of a couple
+	// of rows, or every row spilled (tiny memory limit)
+	for _, memLimit := range []int64{0, 100, 1} {
+		rows := run("SELECT order_id, price FROM orders ORDER BY price DESC, order_id", memLimit)
+		assert.Equal(t, [][]driver.Value{{"2", "37.50"}, {"1", "22.50"}, {"3", "22.50"}}, rows, "memlimit=%d", memLimit)
+
+		// top-n, of equal prices the first
+		rows = run("SELECT order_id, price FROM orders ORDER BY price LIMIT 2", memLimit)
+		assert.Equal(t, [][]driver.Value{{"1", "22.50"}, {"3", "22.50"}}, rows, "memlimit=%d", memLimit)
+
+		rows = run("SELECT order_id FROM orders ORDER BY price DESC LIMIT 1", memLimit)
+		assert.Equal(t, [][]driver.Value{{"2"}}, rows, "memlimit=%d", memLimit)
+
+		rows = run("SELECT email FROM users ORDER BY email DESC LIMIT 5", memLimit)
+		assert.Equal(t, [][]driver.Value{{"not_an_email_2"}, {"bob@email.com"}, {"aaron@email.com"}}, rows, "memlimit=%d", memLimit)
+	}
+
+	// strings of numbers order as numbers, before the other strings
+	mockcsv.LoadTable(mockcsv.SchemaName, "order_mixed", "id,v\n1,abc\n2,10\n3,9\n4,1a\n5,5")
+	rows := run("SELECT v FROM order_mixed ORDER BY v", 0)
+	assert.Equal(t, [][]driver.Value{{"5"}, {"9"}, {"10"}, {"1a"}, {"abc"}}, rows)
+	rows = run("SELECT v FROM order_mixed ORDER BY v DESC LIMIT 3", 0)
+	assert.Equal(t, [][]driver.Value{{"abc"}, {"1a"}, {"10"}}, rows)
+}
+
+func TestExecGroupBySpill(t *testing.T) {
+
+	run := func(sqlText string, memLimit int64) []string {
+		ctx := td.TestContext(sqlText)
+		ctx.MemoryLimit = memLimit
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+
+		msgs := make([]schema.Message, 0)
+		job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+
+		err = job.Setup()
+		assert.True(t, err == nil)
+		err = job.Run()
+		assert.True(t, err == nil, "no error %v", err)
+		rows := make([]string, len(msgs))
+		for i, msg := range msgs {
+			rows[i] = fmt.Sprintf("%v", msg.(*datasource.SqlDriverMessageMap).Values())
+		}
+		return rows
+	}
+
+	// Same groups whether held in memory, or every group after the first
+	// spilled (tiny memory limit) and re-aggregated
+	for _, memLimit := range []int64{0, 1} {
+		rows := run("SELECT user_id, count(*), sum(price) FROM orders GROUP BY user_id", memLimit)
+		sort.Strings(rows)
+		assert.Equal(t, []string{"[9Ip1aKbeZe2njCDM 2 60]", "[abcabcabc 1 22.5]"}, rows, "memlimit=%d", memLimit)
+
+		rows = run("SELECT user_id, item_id, count(*) FROM orders GROUP BY ROLLUP (user_id, item_id)", memLimit)
+		sort.Strings(rows)
+		assert.Equal(t, []string{
+			"[9Ip1aKbeZe2njCDM 1 1]",
+			"[9Ip1aKbeZe2njCDM 2 1]",
+			"[9Ip1aKbeZe2njCDM <nil> 2]",
+			"[<nil> <nil> 3]",
+			"[abcabcabc 1 1]",
+			"[abcabcabc <nil> 1]",
+		}, rows, "memlimit=%d", memLimit)
+
+		rows = run("SELECT count(*) FROM orders WHERE 1 = 0", memLimit)
+		assert.Equal(t, []string{"[0]"}, rows, "memlimit=%d", memLimit)
+		rows = run("SELECT sum(price), avg(price) FROM orders WHERE 1 = 0", memLimit)
+		assert.Equal(t, []string{"[<nil> <nil>]"}, rows, "memlimit=%d", memLimit)
+
+		// aggregators holding their values count towards the limit
+		rows = run("SELECT user_id, count(DISTINCT item_id), group_concat(order_id) FROM orders GROUP BY user_id", memLimit)
+		sort.Strings(rows)
+		assert.Equal(t, 2, len(rows), "memlimit=%d", memLimit)
+		assert.Equal(t, "[abcabcabc 1 3]", rows[1], "memlimit=%d", memLimit)
+	}
+
+	// Input sorted on the group by, each group emitted as it completes so
+	// in the order of the input
+	rows := run(`SELECT user_id, count(*) FROM (SELECT user_id FROM orders ORDER BY user_id DESC) AS o
+		GROUP BY user_id`, 0)
+	assert.Equal(t, []string{"[abcabcabc 1]", "[9Ip1aKbeZe2njCDM 2]"}, rows)
+
+	rows = run(`SELECT user_id, item_id, sum(price) FROM (SELECT user_id, item_id, price FROM orders ORDER BY item_id, user_id) AS o
+		GROUP BY user_id, item_id`, 0)
+	assert.Equal(t, []string{"[9Ip1aKbeZe2njCDM 1 22.5]", "[abcabcabc 1 22.5]", "[9Ip1aKbeZe2njCDM 2 37.5]"}, rows)
+}
+
+func TestExecDistinct(t *testing.T) {
+
+	run := func(sqlText string, memLimit int64) []schema.Message {
+		ctx := td.TestContext(sqlText)
+		ctx.MemoryLimit = memLimit
+		job, err := exec.BuildSqlJob(ctx)
+		assert.True(t, err == nil, "no error %v", err)
+
+		msgs := make([]schema.Message, 0)
+		resultWriter := exec.NewResultBuffer(ctx, &msgs)
+		job.RootTask.Add(resultWriter)
+
+		err = job.Setup()
+		assert.True(t, err == nil)
+		err = job.Run()
+		assert.True(t, err == nil, "no error %v", err)
+		return msgs
+	}
+	rowVals := func(msgs []schema.Message) [][]driver.Value {
+		rows := make([][]driver.Value, len(msgs))
+		for i, msg := range msgs {
+			rows[i] = msg.(*datasource.SqlDriverMessageMap).Values()
+		}
+		return rows
+	}
+
+	// Same results whether held in memory, or all rows spilled
+	// to disk (tiny memory limit)
+	for _, memLimit := range []int64{0, 1} {
+		msgs := run("SELECT DISTINCT user_id FROM orders ORDER BY user_id DESC", memLimit)
+		assert.Equal(t, [][]driver.Value{{"abcabcabc"}, {"9Ip1aKbeZe2njCDM"}}, rowVals(msgs), "memlimit=%d", memLimit)
+
+		msgs = run("SELECT DISTINCT user_id, item_count FROM orders ORDER BY user_id LIMIT 1", memLimit)
+		assert.Equal(t, [][]driver.Value{{"9Ip1aKbeZe2njCDM", "82"}}, rowVals(msgs), "memlimit=%d", memLimit)
+
+		msgs = run("SELECT DISTINCT user_id FROM orders ORDER BY user_id DESC LIMIT 1 OFFSET 1", memLimit)
+		assert.Equal(t, [][]driver.Value{{"9Ip1aKbeZe2njCDM"}}, rowVals(msgs), "memlimit=%d", memLimit)
+
+		msgs = run("SELECT DISTINCT email FROM users ORDER BY email LIMIT 5 OFFSET 2", memLimit)
+		assert.Equal(t, [][]driver.Value{{"not_an_email_2"}}, rowVals(msgs), "memlimit=%d", memLimit)
+
+		msgs = run("SELECT DISTINCT email FROM users ORDER BY email", memLimit)
+		assert.Equal(t, [][]driver.Value{{"aaron@email.com"}, {"bob@email.com"}, {"not_an_email_2"}},
+			rowVals(msgs), "memlimit=%d", memLimit)
+	}
+}
+
 type UserEvent struct {
 	Id     string
 	UserId string
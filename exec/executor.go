@@ -15,7 +15,8 @@ var (
 	_ JobRunner = (*JobExecutor)(nil)
 
 	// Ensure that we implement the plan.Planner interface for our job
-	_ Executor = (*JobExecutor)(nil)
+	_ Executor      = (*JobExecutor)(nil)
+	_ ExecutorUnion = (*JobExecutor)(nil)
 	//_ plan.SourcePlanner = (*SourceBuilder)(nil)
 )
 
@@ -30,6 +31,16 @@ type JobExecutor struct {
 	Ctx      *plan.Context
 	distinct bool
 	children []Task
+	tasks    map[plan.Task]Task // the task running each task of the plan
+}
+
+// JobTaskStats the execution statistics of a task of a job, and the task
+// of the plan it runs, if any (ie a result writer only exists to execute).
+type JobTaskStats struct {
+	Task  Task
+	Plan  plan.Task
+	Depth int
+	TaskStats
 }
 
 // NewExecutor creates a new Job Executor.
@@ -65,15 +76,21 @@ func BuildSqlJobPlanned(planner plan.Planner, executor Executor, ctx *plan.Conte
 	if ctx.Raw == "" {
 		return nil, fmt.Errorf("no sql provided")
 	}
-	stmt, err := rel.ParseSql(ctx.Raw)
-	if err != nil {
-		u.Debugf("could not parse sql : %v", err)
-		return nil, err
-	}
+	// A statement already parsed (and bound) such as by a prepared
+	// statement is planned as is.
+	stmt := ctx.Stmt
 	if stmt == nil {
-		return nil, fmt.Errorf("Not statement for parse? %v", ctx.Raw)
+		var err error
+		stmt, err = rel.ParseSql(ctx.Raw)
+		if err != nil {
+			u.Debugf("could not parse sql : %v", err)
+			return nil, err
+		}
+		if stmt == nil {
+			return nil, fmt.Errorf("Not statement for parse? %v", ctx.Raw)
+		}
+		ctx.Stmt = stmt
 	}
-	ctx.Stmt = stmt
 
 	pln, err := plan.WalkStmt(ctx, stmt, planner)
 
@@ -107,6 +124,14 @@ func (m *JobExecutor) NewTask(p plan.Task) Task {
 
 // WalkPlan Main Entry point to take a Plan, and convert into Execution DAG
 func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
+	t, err := m.walkPlan(p)
+	if err == nil {
+		m.planned(p, t)
+	}
+	return t, err
+}
+
+func (m *JobExecutor) walkPlan(p plan.Task) (Task, error) {
 	switch p := p.(type) {
 	case *plan.PreparedStatement:
 		return m.Executor.WalkPreparedStatement(p)
@@ -118,6 +143,11 @@ func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
 			p.Stmt.SetSystemQry()
 		}
 		return m.Executor.WalkSelect(p)
+	case *plan.Union:
+		if ue, ok := m.Executor.(ExecutorUnion); ok {
+			return ue.WalkUnion(p)
+		}
+		return nil, ErrNotImplemented
 	case *plan.Upsert:
 		return m.Executor.WalkUpsert(p)
 	case *plan.Insert:
@@ -136,6 +166,8 @@ func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
 		return m.Executor.WalkDrop(p)
 	case *plan.Alter:
 		return m.Executor.WalkAlter(p)
+	case *plan.Analyze:
+		return m.Executor.WalkAnalyze(p)
 	}
 	panic(fmt.Sprintf("Not implemented for %T", p))
 }
@@ -149,7 +181,38 @@ func (m *JobExecutor) WalkPreparedStatement(p *plan.PreparedStatement) (Task, er
 
 // WalkSelect create dag of plan Select.
 func (m *JobExecutor) WalkSelect(p *plan.Select) (Task, error) {
+	for _, sq := range p.SubQueries {
+		sq.Node.Eval = NewScalarSubQuery(m.Ctx, sq).Eval
+	}
+	root := m.NewTask(p)
+	return root, m.WalkChildren(p, root)
+}
+
+// WalkUnion create dag of set operation (UNION, INTERSECT, EXCEPT), both
+// sides run in parallel into the union task, followed by order, limit.
+func (m *JobExecutor) WalkUnion(p *plan.Union) (Task, error) {
 	root := m.NewTask(p)
+	execTask := NewTaskParallel(m.Ctx)
+	l, err := m.WalkPlan(p.Left)
+	if err != nil {
+		return nil, err
+	}
+	if err = execTask.Add(l); err != nil {
+		return nil, err
+	}
+	r, err := m.WalkPlan(p.Right)
+	if err != nil {
+		return nil, err
+	}
+	if err = execTask.Add(r); err != nil {
+		return nil, err
+	}
+	if err = execTask.Add(NewUnion(m.Ctx, l.(TaskRunner), r.(TaskRunner), p)); err != nil {
+		return nil, err
+	}
+	if err = root.Add(execTask); err != nil {
+		return nil, err
+	}
 	return root, m.WalkChildren(p, root)
 }
 func (m *JobExecutor) WalkUpsert(p *plan.Upsert) (Task, error) {
@@ -169,7 +232,9 @@ func (m *JobExecutor) WalkDelete(p *plan.Delete) (Task, error) {
 	return root, root.Add(NewDelete(m.Ctx, p))
 }
 func (m *JobExecutor) WalkSource(p *plan.Source) (Task, error) {
-	if len(p.Static) > 0 {
+	if p.Cte != nil {
+		return NewSourceScanner(m.Ctx, p, newCteScanner(m.Ctx, p.Cte)), nil
+	} else if len(p.Static) > 0 {
 		static := membtree.NewStaticData("static")
 		static.SetColumns(p.Cols)
 		_, err := static.Put(nil, nil, p.Static)
@@ -221,15 +286,28 @@ func (m *JobExecutor) WalkSourceExec(p *plan.Source) (Task, error) {
 func (m *JobExecutor) WalkWhere(p *plan.Where) (Task, error) {
 	return NewWhere(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkSemiJoin(p *plan.SemiJoin) (Task, error) {
+	return NewSemiJoin(m.Ctx, p), nil
+}
 func (m *JobExecutor) WalkHaving(p *plan.Having) (Task, error) {
 	return NewHaving(m.Ctx, p), nil
 }
 func (m *JobExecutor) WalkGroupBy(p *plan.GroupBy) (Task, error) {
+	if p.Merge != nil {
+		// the source computed the partials of each group
+		return NewGroupByFinal(m.Ctx, p), nil
+	}
 	return NewGroupBy(m.Ctx, p), nil
 }
 func (m *JobExecutor) WalkOrder(p *plan.Order) (Task, error) {
 	return NewOrder(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkWindow(p *plan.Window) (Task, error) {
+	return NewWindow(m.Ctx, p), nil
+}
+func (m *JobExecutor) WalkDistinct(p *plan.Distinct) (Task, error) {
+	return NewDistinct(m.Ctx, p), nil
+}
 func (m *JobExecutor) WalkProjection(p *plan.Projection) (Task, error) {
 	return NewProjection(m.Ctx, p), nil
 }
@@ -260,6 +338,7 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 	if err != nil {
 		return nil, err
 	}
+	m.planned(p, jm)
 	return execTask, nil
 }
 func (m *JobExecutor) WalkJoinKey(p *plan.JoinKey) (Task, error) {
@@ -284,18 +363,32 @@ func (m *JobExecutor) WalkPlanAll(p plan.Task) (Task, error) {
 	return root, m.WalkChildren(p, root)
 }
 func (m *JobExecutor) WalkPlanTask(p plan.Task) (Task, error) {
+	t, err := m.walkPlanTask(p)
+	if err == nil {
+		m.planned(p, t)
+	}
+	return t, err
+}
+
+func (m *JobExecutor) walkPlanTask(p plan.Task) (Task, error) {
 	//u.Debugf("WalkPlanTask: %p  %T", p, p)
 	switch p := p.(type) {
 	case *plan.Source:
 		return m.Executor.WalkSource(p)
 	case *plan.Where:
 		return m.Executor.WalkWhere(p)
+	case *plan.SemiJoin:
+		return m.Executor.WalkSemiJoin(p)
 	case *plan.Having:
 		return m.Executor.WalkHaving(p)
 	case *plan.GroupBy:
 		return m.Executor.WalkGroupBy(p)
 	case *plan.Order:
 		return m.Executor.WalkOrder(p)
+	case *plan.Window:
+		return m.Executor.WalkWindow(p)
+	case *plan.Distinct:
+		return m.Executor.WalkDistinct(p)
 	case *plan.Projection:
 		return m.Executor.WalkProjection(p)
 	case *plan.JoinMerge:
@@ -334,6 +427,12 @@ func (m *JobExecutor) WalkAlter(p *plan.Alter) (Task, error) {
 	return root, root.Add(NewAlter(m.Ctx, p))
 }
 
+// WalkAnalyze walks the Analyze plan.
+func (m *JobExecutor) WalkAnalyze(p *plan.Analyze) (Task, error) {
+	root := m.NewTask(p)
+	return root, root.Add(NewAnalyze(m.Ctx, p))
+}
+
 // WalkChildren walk dag of plan tasks creating execution tasks
 func (m *JobExecutor) WalkChildren(p plan.Task, root Task) error {
 	for _, t := range p.Children() {
@@ -384,9 +483,81 @@ func (m *JobExecutor) Setup() error {
 	return m.RootTask.Setup(0)
 }
 
-// Run this task
+// Run this task, cancelling the go context of the job (or reaching its
+// deadline) closes every task of the dag, stopping sources, and the
+// context error is returned.
 func (m *JobExecutor) Run() error {
-	return m.RootTask.Run()
+	ctx := m.Ctx.Context
+	if ctx == nil || ctx.Done() == nil {
+		return runTask(m.RootTask)
+	}
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.Ctx.AddError(ctx.Err())
+			m.Close()
+		case <-finished:
+		}
+	}()
+	err := runTask(m.RootTask)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// planned record the task running a task of the plan, the first recorded
+// is the one doing the work, ie the merge of a join not the parallel task
+// wrapping it.
+func (m *JobExecutor) planned(p plan.Task, t Task) {
+	if m.tasks == nil {
+		m.tasks = make(map[plan.Task]Task)
+	}
+	if _, ok := m.tasks[p]; !ok {
+		m.tasks[p] = t
+	}
+}
+
+// Stats the execution statistics of each task of the job, in order of the
+// dag of tasks, complete once Run() has returned.
+func (m *JobExecutor) Stats() []JobTaskStats {
+	if m.RootTask == nil {
+		return nil
+	}
+	plans := make(map[Task]plan.Task, len(m.tasks))
+	for p, t := range m.tasks {
+		plans[t] = p
+	}
+	var stats []JobTaskStats
+	var walk func(t Task, depth int)
+	walk = func(t Task, depth int) {
+		ts := JobTaskStats{Task: t, Plan: plans[t], Depth: depth}
+		if st, ok := t.(taskStatser); ok {
+			ts.TaskStats = st.Stats()
+		}
+		stats = append(stats, ts)
+		for _, child := range t.Children() {
+			walk(child, depth+1)
+		}
+	}
+	walk(m.RootTask, 0)
+	return stats
+}
+
+// PlanStats the execution statistics of the task that ran task p of the
+// plan, complete once Run() has returned.
+func (m *JobExecutor) PlanStats(p plan.Task) (TaskStats, bool) {
+	t, ok := m.tasks[p]
+	if !ok {
+		return TaskStats{}, false
+	}
+	st, ok := t.(taskStatser)
+	if !ok {
+		return TaskStats{}, false
+	}
+	return st.Stats(), true
 }
 
 // Close the normal close of root task
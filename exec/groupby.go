@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/gob"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,16 +26,32 @@ var (
 	_ TaskRunner = (*GroupBy)(nil)
 )
 
+const (
+	// number of hash partitions the rows of a group by are spilled into,
+	// and the max depth of re-partitioning a partition still too large.
+	groupBySpillPartitions = 16
+	groupBySpillDepth      = 4
+
+	// rough estimate of the in-memory size of an aggregator
+	aggMemSize = int64(64)
+)
+
 func init() {
 	gob.Register(AggPartial{})
+	// the values held by partials, ie of min(ts)
+	gob.Register(time.Time{})
 }
 
 // Group by a Sql Group By task which creates a hashable key from row
 // commposed of key = {each,value,of,column,in,groupby}
 //
-// A very stupid naive parallel groupby holds values in memory.  This
-// is a toy implementation that is only useful for small cardinality
-// group-bys, small number of rows.
+// Rows are aggregated into their group as they arrive, so only the
+// aggregate state of each group is held, see hashAgg.  Past the memory
+// limit (plan.Context.MemoryLimit) the rows of new groups are spilled to
+// disk partitioned by key and re-aggregated after the input completes.
+//
+// If the input is sorted on the group by (plan.GroupBy.Stream) each group
+// is emitted as soon as the key changes, holding only a single group.
 type GroupBy struct {
 	*TaskBase
 	closed bool
@@ -76,26 +94,59 @@ func (m *GroupBy) Run() error {
 	outCh := m.MessageOut()
 	inCh := m.MessageIn()
 
-	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
+	outIndex := groupByColIndex(m.p.Stmt)
 
-	aggs, err := buildAggs(m.p)
-	if err != nil {
+	// validate the aggregates once, rather than on the first group
+	if _, err := buildAggs(m.p); err != nil {
 		u.Warnf("Group By statement not supported? %v", err)
 		return err
 	}
 
-	// are are going to hold entire row in memory while we are calculating
-	//  so obviously not scalable.
-	gb := make(map[string][]*datasource.SqlDriverMessageMap)
+	memLimit := m.Ctx.MemoryLimit
+	if memLimit <= 0 {
+		memLimit = DefaultMemoryLimit
+	}
+
+	// each row is in a group of each grouping set, of ROLLUP, CUBE etc
+	sets := m.p.Stmt.Groupings()
+	ha := newHashAgg(m.p, sets, memLimit, 0)
+	defer ha.Close()
+
+	i := uint64(0)
+	emit := func(key string, aggs []Aggregator) bool {
+		row := make([]driver.Value, len(aggs))
+		for i, agg := range aggs {
+			row[i] = driver.Value(agg.Result())
+			//u.Debugf("agg result: %#v  %v", row[i], row[i])
+		}
+
+		if m.p.Partial {
+			// Partial results, append key at end?  shouldn't be able to be fit in message itself?
+			row = append(row, key)
+			//u.Debugf("GroupBy output row? key:%s %#v", key, row)
+		}
+		waited := time.Now()
+		select {
+		case outCh <- datasource.NewSqlDriverMessageMap(i, row, outIndex):
+			m.sent(waited)
+		case <-m.SigChan():
+			return false
+		}
+		i++
+		return true
+	}
 
+	lastKey := ""
 msgReadLoop:
 	for {
 
+		waited := time.Now()
 		select {
 		case <-m.SigChan():
 			return nil
 		case msg, ok := <-inCh:
+			m.received(waited, msg)
 			if !ok {
 				break msgReadLoop
 			} else {
@@ -119,90 +170,90 @@ msgReadLoop:
 
 				// We are going to use VM Engine to create a value for each statement in group by
 				// then join each value together to create a unique key.
-				keys := make([]string, len(m.p.Stmt.GroupBy))
+				vals := make([]string, len(m.p.Stmt.GroupBy))
 				for i, col := range m.p.Stmt.GroupBy {
 					if key, ok := vm.Eval(sdm, col.Expr); ok {
-						keys[i] = key.ToString()
+						vals[i] = key.ToString()
+					}
+				}
+				for setIdx, set := range sets {
+					keys := make([]string, len(set))
+					for i, colIdx := range set {
+						keys[i] = vals[colIdx]
+					}
+					key := groupingKey(setIdx, len(sets), strings.Join(keys, ","))
+					if m.p.Stream && len(sets) == 1 && key != lastKey {
+						// input is sorted on the group by, the previous
+						// group is complete
+						if !ha.Emit(emit) {
+							return nil
+						}
+						lastKey = key
+					}
+					if err := ha.Add(key, setIdx, sdm); err != nil {
+						return err
 					}
 				}
-				key := strings.Join(keys, ",")
-				gb[key] = append(gb[key], sdm)
+				m.buffered(len(ha.groups))
 			}
 		}
 	}
 
-	i := uint64(0)
-	for key, v := range gb {
-		//u.Debugf("got %s:%v msgs", k, len(v))
-
-		for _, mm := range v {
-			for i, col := range columns {
-				//u.Debugf("col: idx:%v sidx: %v pidx:%v key:%v   %s", col.Index, col.SourceIndex, col.ParentIndex, col.Key(), col.Expr)
-
-				if col.Expr == nil {
-					u.Warnf("wat?   nil col expr? %#v", col)
-				} else {
-					v, ok := vm.Eval(mm, col.Expr)
-					//u.Infof("mt: %T  mm %#v", mm, mm)
-					if !ok || v == nil {
-						//u.Debugf("evaled nil? key=%v  val=%v expr:%s", col.Key(), v, col.Expr.String())
-						//u.Infof("mt: %T  mm %#v", mm, mm)
-						aggs[i].Do(value.NewNilValue())
-					} else {
-						//u.Debugf("evaled: key=%v  val=%v", col.Key(), v.Value())
-						aggs[i].Do(v)
-					}
+	if ha.Len() == 0 && !m.p.Partial {
+		// aggregates without a group by, or the grand total of a ROLLUP,
+		// are a single row even if there was no input:
+		//    SELECT count(*) FROM orders WHERE 1 = 0  => 0
+		for setIdx, set := range sets {
+			if len(set) == 0 {
+				if _, err := ha.group(groupingKey(setIdx, len(sets), ""), setIdx); err != nil {
+					return err
 				}
 			}
 		}
-
-		row := make([]driver.Value, len(columns))
-		for i, agg := range aggs {
-			row[i] = driver.Value(agg.Result())
-			agg.Reset()
-			//u.Debugf("agg result: %#v  %v", row[i], row[i])
-		}
-
-		if m.p.Partial {
-			// Partial results, append key at end?  shouldn't be able to be fit in message itself?
-			row = append(row, key)
-			//u.Debugf("GroupBy output row? key:%s %#v", key, row)
-		}
-		//u.Debugf("row: %v  cols:%v", row, colIndex)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
-		i++
 	}
 
-	return nil
+	if !ha.Emit(emit) {
+		return nil
+	}
+	return ha.EmitSpilled(emit)
 }
 
 // Run group-by-final Runs standard task interface.
 func (m *GroupByFinal) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
+	defer func() {
+		m.isComplete = true
+		close(m.complete)
+	}()
 
 	outCh := m.MessageOut()
 	inCh := m.MessageIn()
 
-	columns := m.p.Stmt.Columns
-	colIndex := m.p.Stmt.ColIndexes()
+	colIndex := groupByColIndex(m.p.Stmt)
+	sets := m.p.Stmt.Groupings()
 
-	m.p.Partial = false
-	aggs, err := buildAggs(m.p)
+	// the aggregators of the results, which merge the partials
+	final := *m.p
+	final.Partial = false
+	aggs, err := buildAggs(&final)
 	if err != nil {
 		return err
 	}
 
 	gb := make(map[string][][]driver.Value)
+	held := 0
 
 msgReadLoop:
 	for {
 
+		waited := time.Now()
 		select {
 		case <-m.SigChan():
 			u.Warnf("got signal quit")
 			return nil
 		case msg, ok := <-inCh:
+			m.received(waited, msg)
 			if !ok {
 				//u.Debugf("GroupByFinal, got closed channel shutdown")
 				break msgReadLoop
@@ -210,16 +261,25 @@ msgReadLoop:
 				//u.Infof("got gbfinal message %#v", msg)
 				switch mt := msg.(type) {
 				case *datasource.SqlDriverMessageMap:
-					if len(mt.Vals) != len(columns)+1 {
-						u.Warnf("Wrong number of values? %#v", mt)
-					}
-					key, ok := mt.Vals[len(mt.Vals)-1].(string)
-					if !ok {
-						u.Warnf("expected key?  %#v", mt.Vals)
+					var key string
+					var vals []driver.Value
+					if m.p.Merge != nil {
+						// a group computed by the source
+						vals, key = sourcePartials(m.p.Merge, aggs, mt.Vals)
+					} else {
+						if len(mt.Vals) != len(aggs)+1 {
+							u.Warnf("Wrong number of values? %#v", mt)
+						}
+						key, ok = mt.Vals[len(mt.Vals)-1].(string)
+						if !ok {
+							u.Warnf("expected key?  %#v", mt.Vals)
+						}
+						vals = mt.Vals[0 : len(mt.Vals)-1]
 					}
-					vals := mt.Vals[0 : len(mt.Vals)-1]
 					//u.Infof("found key:%s for %#v", key, mt.Vals)
 					gb[key] = append(gb[key], vals)
+					held++
+					m.buffered(held)
 				default:
 					err := fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
 					u.Errorf("unrecognized msg %T", msg)
@@ -230,53 +290,114 @@ msgReadLoop:
 		}
 	}
 
+	if len(gb) == 0 && len(m.p.Stmt.GroupBy) == 0 {
+		// aggregates without a group by are a single row even if there
+		// were no partials, see GroupBy.Run()
+		gb[""] = nil
+	}
+
 	i := uint64(0)
-	for _, vals := range gb {
+	for key, vals := range gb {
 		//u.Debugf("got %s:%v msgs", key, vals)
 
+		setGrouping(aggs, rolledUp(m.p.Stmt, sets[groupingOfKey(key, len(sets))]))
 		for _, dv := range vals {
-			for i, col := range columns {
-				//u.Debugf("col: idx:%v sidx: %v pidx:%v key:%v   %s", col.Index, col.SourceIndex, col.ParentIndex, col.Key(), col.Expr)
-				if i-1 >= len(dv) {
+			// the columns of the select, then the hidden aggregates of the having
+			for i := range aggs {
+				if i >= len(dv) {
 					u.Errorf("what??? %v  dv: %d   %#v", i, len(dv), dv)
+					break
 				}
-				if col.Expr == nil {
-					u.Warnf("wat?   nil col expr? %#v", col)
-				} else {
-					v := dv[i]
-					switch vt := v.(type) {
-					case *AggPartial:
-						//u.Debugf("evaled: key=%v  val=%v", col.Key(), v.Value())
-						aggs[i].Merge(vt)
-					case AggPartial:
-						aggs[i].Merge(&vt)
-					case int64:
-						aggs[i].Merge(&AggPartial{Ct: vt})
-					case string:
-						aggs[i] = &groupByFunc{vt}
-					default:
-						u.Warnf("unhandled type: %#v", v)
-					}
+				v := dv[i]
+				if gbf, ok := aggs[i].(*groupByFunc); ok && v != nil {
+					// a group by value, of any type
+					gbf.last = v
+					continue
+				}
+				switch vt := v.(type) {
+				case *AggPartial:
+					//u.Debugf("evaled: key=%v  val=%v", col.Key(), v.Value())
+					aggs[i].Merge(vt)
+				case AggPartial:
+					aggs[i].Merge(&vt)
+				case int64:
+					aggs[i].Merge(&AggPartial{Ct: vt})
+				case string:
+					aggs[i] = &groupByFunc{last: vt, gb: -1}
+				case nil:
+					// a group by value not of the grouping set
+				default:
+					u.Warnf("unhandled type: %#v", v)
 				}
 			}
 		}
 
-		row := make([]driver.Value, len(columns))
+		row := make([]driver.Value, len(aggs))
 		for i, agg := range aggs {
 			row[i] = driver.Value(agg.Result())
 			agg.Reset()
 			//u.Debugf("agg result: %#v  %v", row[i], row[i])
 		}
 		//u.Debugf("GroupBy output row? %v", row)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
+		waited := time.Now()
+		select {
+		case outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex):
+			m.sent(waited)
+		case <-m.SigChan():
+			return nil
+		}
 		i++
 	}
-
-	m.isComplete = true
-	close(m.complete)
 	return nil
 }
 
+// sourcePartials the values to merge of a row of a partial group by
+// computed by a source (see schema.ConnAggregate), the group by values then
+// the partial of each of p.Aggs, and the key of its group.
+func sourcePartials(p *plan.GroupBy, aggs []Aggregator, row []driver.Value) ([]driver.Value, string) {
+	ngb := len(p.Stmt.GroupBy)
+	partial := func(fn *expr.FuncNode) *AggPartial {
+		for i, agg := range p.Aggs {
+			if ngb+i >= len(row) || !agg.Equal(fn) {
+				continue
+			}
+			switch pt := row[ngb+i].(type) {
+			case *AggPartial:
+				return pt
+			case AggPartial:
+				return &pt
+			}
+		}
+		return nil
+	}
+	keys := make([]string, ngb)
+	for i := 0; i < ngb && i < len(row); i++ {
+		keys[i] = value.NewValue(row[i]).ToString()
+	}
+	vals := make([]driver.Value, len(aggs))
+	for i, agg := range aggs {
+		switch at := agg.(type) {
+		case *groupByFunc:
+			if at.gb >= 0 && at.gb < len(row) {
+				vals[i] = row[at.gb]
+			}
+		case *aggFunc:
+			if pt := partial(at.fn); pt != nil {
+				vals[i] = pt
+			}
+		case *exprAgg:
+			parts := make([]*AggPartial, len(at.aggs))
+			for j, a := range at.aggs {
+				if af, ok := a.(*aggFunc); ok {
+					parts[j] = partial(af.fn)
+				}
+			}
+			vals[i] = &AggPartial{Parts: parts}
+		}
+	}
+	return vals, strings.Join(keys, ",")
+}
+
 // Close the task, channels, cleanup.
 func (m *GroupBy) Close() error {
 	m.Lock()
@@ -314,124 +435,601 @@ func (m *GroupByFinal) Close() error {
 	return m.TaskBase.Close()
 }
 
-// AggPartial is a struct to represent the partial aggregation
-// that will be reduced on finalizer.  IE, for consistent-hash based
-// group-bys calculated across multiple nodes this holds info that
-// needs to be further calculated it only represents this hash.
-type AggPartial struct {
-	Ct int64
-	N  float64
+// hashAgg the groups of a group by, keyed by group key, each group
+// aggregating its rows as they arrive.
+//
+// Once the groups held pass the memory limit the rows of any new group
+// are spilled to disk, hash partitioned by group key such that all rows of
+// a group are in the same partition; groups already held keep aggregating.
+// After the input completes each partition is re-aggregated on its own,
+// spilling again (re-partitioned) if still too large.
+type hashAgg struct {
+	p        *plan.GroupBy
+	sets     [][]int
+	groups   map[string]*aggGroup
+	memLimit int64
+	memUsed  int64
+	depth    int
+	sp       *partitionSpill
+	colIndex map[string]int
+}
+
+// aggGroup the aggregators of a group, and the size of their state.
+type aggGroup struct {
+	aggs []Aggregator
+	size int64
 }
 
-type AggFunc func(v value.Value)
-type resultFunc func() interface{}
+func newHashAgg(p *plan.GroupBy, sets [][]int, memLimit int64, depth int) *hashAgg {
+	return &hashAgg{
+		p:        p,
+		sets:     sets,
+		groups:   make(map[string]*aggGroup),
+		memLimit: memLimit,
+		depth:    depth,
+	}
+}
+
+// Len the number of groups held in memory.
+func (m *hashAgg) Len() int { return len(m.groups) }
+
+// Add the row to the group of key, of grouping set setIdx.
+func (m *hashAgg) Add(key string, setIdx int, sdm *datasource.SqlDriverMessageMap) error {
+	g, ok := m.groups[key]
+	if !ok {
+		if m.sp == nil && m.memUsed > m.memLimit && m.depth < groupBySpillDepth {
+			u.Debugf("group by exceeded memory limit %d, spilling to disk", m.memLimit)
+			sp, err := newPartitionSpill("groupby", groupBySpillPartitions, byte(m.depth))
+			if err != nil {
+				return err
+			}
+			m.sp = sp
+			m.colIndex = sdm.ColIndex
+		}
+		if m.sp != nil {
+			return m.sp.Add(&spillRow{Seq: sdm.Id(), Key: key, Vals: sdm.Vals})
+		}
+		var err error
+		if g, err = m.group(key, setIdx); err != nil {
+			return err
+		}
+		// the last row is held by the group by values
+		m.memUsed += rowMemSize(sdm.Vals)
+	}
+	for _, agg := range g.aggs {
+		agg.Do(sdm)
+	}
+	// aggregators holding values, ie array_agg, grow with their rows
+	if size := aggsMemSize(g.aggs); size != g.size {
+		m.memUsed += size - g.size
+		g.size = size
+	}
+	return nil
+}
+
+// group create the group of key, of grouping set setIdx.
+func (m *hashAgg) group(key string, setIdx int) (*aggGroup, error) {
+	aggs, err := buildAggs(m.p)
+	if err != nil {
+		return nil, err
+	}
+	setGrouping(aggs, rolledUp(m.p.Stmt, m.sets[setIdx]))
+	g := &aggGroup{aggs: aggs}
+	m.groups[key] = g
+	m.memUsed += int64(len(key)) + int64(len(aggs))*aggMemSize
+	return g, nil
+}
+
+// Emit the groups held in memory, and release them.  False if emit
+// was stopped.
+func (m *hashAgg) Emit(emit func(key string, aggs []Aggregator) bool) bool {
+	for key, g := range m.groups {
+		if !emit(key, g.aggs) {
+			return false
+		}
+	}
+	m.groups = make(map[string]*aggGroup)
+	m.memUsed = 0
+	return true
+}
+
+// EmitSpilled re-aggregate and emit the groups of each spilled partition.
+func (m *hashAgg) EmitSpilled(emit func(key string, aggs []Aggregator) bool) error {
+	if m.sp == nil {
+		return nil
+	}
+	for _, part := range m.sp.parts {
+		rdr, err := part.Rewind()
+		if err != nil {
+			return err
+		}
+		ha := newHashAgg(m.p, m.sets, m.memLimit, m.depth+1)
+		for {
+			row, err := rdr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				ha.Close()
+				return err
+			}
+			sdm := datasource.NewSqlDriverMessageMap(row.Seq, row.Vals, m.colIndex)
+			if err := ha.Add(row.Key, groupingOfKey(row.Key, len(m.sets)), sdm); err != nil {
+				ha.Close()
+				return err
+			}
+		}
+		// the partition is no longer needed
+		part.Close()
+		if !ha.Emit(emit) {
+			ha.Close()
+			return nil
+		}
+		err = ha.EmitSpilled(emit)
+		ha.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close remove any spill files.
+func (m *hashAgg) Close() {
+	if m.sp != nil {
+		m.sp.Close()
+		m.sp = nil
+	}
+}
+
+// AggPartial is the partial state of an aggregate that will be reduced
+// on the finalizer, see expr.AggPartial.
+type AggPartial = expr.AggPartial
+
+// Aggregator computes a column of a group by over the rows of each group,
+// either an aggregate function or a value of the group by.
 type Aggregator interface {
-	Do(v value.Value)
+	Do(row expr.EvalContext)
 	Result() interface{}
 	Reset()
 	Merge(*AggPartial)
 }
-type agg struct {
-	do     AggFunc
-	result resultFunc
+
+// sizedAggregator an Aggregator whose state grows with the rows of its
+// group, see expr.AggregatorSizer.
+type sizedAggregator interface {
+	memSize() int64
+}
+
+func aggsMemSize(aggs []Aggregator) int64 {
+	var n int64
+	for _, agg := range aggs {
+		if sa, ok := agg.(sizedAggregator); ok {
+			n += sa.memSize()
+		}
+	}
+	return n
 }
+
+// groupingAggregator an Aggregator whose result depends on the grouping set
+// of the group, of GROUP BY ROLLUP, CUBE or GROUPING SETS.
+type groupingAggregator interface {
+	// setGrouping the bits of the group by columns not in the grouping set
+	// of the group, ie rolled up
+	setGrouping(rolledUp uint64)
+}
+
 type groupByFunc struct {
-	last interface{}
+	col      *rel.Column
+	gb       int // index of the group by column, -1 if not one
+	rolledUp bool
+	last     interface{}
 }
 
-func (m *groupByFunc) Do(v value.Value)    { m.last = v.Value() }
-func (m *groupByFunc) Result() interface{} { return m.last }
+func (m *groupByFunc) Do(row expr.EvalContext) {
+	if v, ok := vm.Eval(row, m.col.Expr); ok && v != nil {
+		m.last = v.Value()
+	}
+}
+func (m *groupByFunc) Result() interface{} {
+	if m.rolledUp {
+		return nil
+	}
+	return m.last
+}
 func (m *groupByFunc) Reset()              { m.last = nil }
 func (m *groupByFunc) Merge(a *AggPartial) {}
+func (m *groupByFunc) setGrouping(rolledUp uint64) {
+	m.rolledUp = m.gb >= 0 && rolledUp&(1<<uint(m.gb)) != 0
+}
 func NewGroupByValue(col *rel.Column) Aggregator {
-	return &groupByFunc{}
+	return &groupByFunc{col: col, gb: -1}
 }
 
-type sum struct {
+// aggFunc an aggregate function column, the arguments of the function are
+// evaluated per row and accumulated by the Aggregator of the registered
+// expr.AggregateFunc, only of rows passing its FILTER (WHERE ...) if any.
+type aggFunc struct {
+	fn      *expr.FuncNode
+	af      expr.AggregateFunc
+	args    []expr.Node
+	vals    []value.Value
 	partial bool
-	ct      int64
-	n       float64
+	state   expr.Aggregator
 }
 
-func (m *sum) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
+// NewAggFunc the aggregate column of an aggregate function, which must be
+// registered as an expr.AggregateFunc.
+func NewAggFunc(fn *expr.FuncNode, partial bool) (Aggregator, error) {
+	af, ok := fn.F.CustomFunc.(expr.AggregateFunc)
+	if !ok {
+		return nil, fmt.Errorf("Not implemented groupby for function: %s", fn)
+	}
+	state, err := af.NewAggregator(fn)
+	if err != nil {
+		return nil, err
 	}
+	args := make([]expr.Node, len(fn.Args))
+	for i, arg := range fn.Args {
+		// count(DISTINCT x) accumulates x
+		if dn, ok := arg.(*expr.FuncNode); ok && strings.ToLower(dn.Name) == "distinct" && len(dn.Args) == 1 {
+			arg = dn.Args[0]
+		}
+		args[i] = arg
+	}
+	return &aggFunc{
+		fn:      fn,
+		af:      af,
+		args:    args,
+		vals:    make([]value.Value, len(args)),
+		partial: partial,
+		state:   state,
+	}, nil
 }
-func (m *sum) Result() interface{} {
-	if !m.partial {
-		return m.n
+
+func (m *aggFunc) Do(row expr.EvalContext) {
+	if m.fn.Filter != nil {
+		v, ok := vm.Eval(row, m.fn.Filter)
+		if !ok {
+			return
+		}
+		if bv, isBool := v.(value.BoolValue); !isBool || !bv.Val() {
+			return
+		}
 	}
-	return &AggPartial{
-		m.ct,
-		m.n,
+	for i, arg := range m.args {
+		v, ok := vm.Eval(row, arg)
+		if !ok || v == nil {
+			v = value.NewNilValue()
+		}
+		m.vals[i] = v
 	}
+	m.state.Accumulate(m.vals)
 }
-func (m *sum) Reset() { m.n = 0 }
-func (m *sum) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
+func (m *aggFunc) Result() interface{} {
+	if m.partial {
+		return m.state.Partial()
+	}
+	return m.state.Finalize().Value()
 }
-func NewSum(col *rel.Column, partial bool) Aggregator {
-	return &sum{partial: partial}
+func (m *aggFunc) Reset() {
+	// the args were validated by the first init
+	m.state, _ = m.af.NewAggregator(m.fn)
+}
+func (m *aggFunc) Merge(a *AggPartial) { m.state.Merge(a) }
+func (m *aggFunc) memSize() int64 {
+	if sz, ok := m.state.(expr.AggregatorSizer); ok {
+		return sz.MemSize()
+	}
+	return 0
 }
 
-type avg struct {
-	partial bool
-	ct      int64
-	n       float64
+// exprAgg a column of an expression over aggregates, each aggregate is
+// computed over the group then the expression evaluated of their results,
+// and of the values of the last row for any group by columns it uses.
+//
+//	round(sum(price) / count(*), 2)
+type exprAgg struct {
+	expr      expr.Node // with the aggregates replaced by identities of their keys
+	keys      []string
+	aggs      []Aggregator
+	groupBy   rel.Columns
+	groupings []groupingCall
+	rolledUp  uint64
+	partial   bool
+	last      expr.ContextReader
+}
+
+// groupingCall a grouping(arg, ...) of an expression, the index in the
+// group by of each arg.
+type groupingCall struct {
+	key  string
+	args []int
+}
+
+// newExprAgg the aggregate column of an expression over aggregates, and
+// grouping() of the group by columns.
+func newExprAgg(node expr.Node, groupBy rel.Columns, partial bool) (Aggregator, error) {
+	m := &exprAgg{groupBy: groupBy, partial: partial}
+	var err error
+	m.expr = replaceAggregates(node, func(fn *expr.FuncNode) string {
+		if isGrouping(fn) {
+			gc, gerr := newGroupingCall(fn, groupBy, len(m.groupings))
+			if gerr != nil {
+				err = gerr
+			}
+			m.groupings = append(m.groupings, gc)
+			return gc.key
+		}
+		agg, aerr := NewAggFunc(fn, partial)
+		if aerr != nil {
+			err = aerr
+		}
+		key := fmt.Sprintf("$agg%d", len(m.keys))
+		m.keys = append(m.keys, key)
+		m.aggs = append(m.aggs, agg)
+		return key
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *exprAgg) Do(row expr.EvalContext) {
+	for _, agg := range m.aggs {
+		agg.Do(row)
+	}
+	m.last = row
+}
+func (m *exprAgg) Result() interface{} {
+	if m.partial {
+		// the partials of each aggregate, the final evaluates the expression
+		parts := make([]*AggPartial, len(m.aggs))
+		for i, agg := range m.aggs {
+			parts[i], _ = agg.Result().(*AggPartial)
+		}
+		return &AggPartial{Parts: parts}
+	}
+	results := make(map[string]value.Value, len(m.aggs))
+	for i, agg := range m.aggs {
+		results[m.keys[i]] = value.NewValue(agg.Result())
+	}
+	for _, gc := range m.groupings {
+		results[gc.key] = value.NewIntValue(gc.value(m.rolledUp))
+	}
+	for i, col := range m.groupBy {
+		// the rolled up columns are null, not those of the last row
+		if in, ok := col.Expr.(*expr.IdentityNode); ok && m.rolledUp&(1<<uint(i)) != 0 {
+			results[in.Text] = value.NewNilValue()
+		}
+	}
+	readers := []expr.ContextReader{datasource.NewContextSimpleData(results)}
+	if m.last != nil {
+		readers = append(readers, m.last)
+	}
+	v, ok := vm.Eval(datasource.NewNestedContextReader(readers, time.Now()), m.expr)
+	if !ok || v == nil {
+		return nil
+	}
+	return v.Value()
+}
+func (m *exprAgg) Reset() {
+	for _, agg := range m.aggs {
+		agg.Reset()
+	}
+	m.last = nil
+}
+func (m *exprAgg) Merge(a *AggPartial) {
+	for i, part := range a.Parts {
+		if i < len(m.aggs) && part != nil {
+			m.aggs[i].Merge(part)
+		}
+	}
+}
+func (m *exprAgg) setGrouping(rolledUp uint64) { m.rolledUp = rolledUp }
+func (m *exprAgg) memSize() int64              { return aggsMemSize(m.aggs) }
+
+func newGroupingCall(fn *expr.FuncNode, groupBy rel.Columns, i int) (groupingCall, error) {
+	gc := groupingCall{key: fmt.Sprintf("$grouping%d", i), args: make([]int, len(fn.Args))}
+argLoop:
+	for argIdx, arg := range fn.Args {
+		for gbIdx, gb := range groupBy {
+			in, isIdent := arg.(*expr.IdentityNode)
+			if (gb.Expr != nil && gb.Expr.Equal(arg)) || (isIdent && gb.As == in.Text) {
+				gc.args[argIdx] = gbIdx
+				continue argLoop
+			}
+		}
+		return gc, fmt.Errorf("Argument %s of grouping() is not a column of the group by", arg)
+	}
+	return gc, nil
 }
 
-func (m *avg) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
+// value the bits of the args of the grouping() rolled up, the first the highest.
+func (m *groupingCall) value(rolledUp uint64) int64 {
+	v := int64(0)
+	for i, gbIdx := range m.args {
+		if rolledUp&(1<<uint(gbIdx)) != 0 {
+			v |= 1 << uint(len(m.args)-1-i)
+		}
 	}
+	return v
 }
-func (m *avg) Result() interface{} {
-	if !m.partial {
-		return m.n / float64(m.ct)
+
+// isGrouping is the function grouping(), of the grouping set of a group.
+func isGrouping(fn *expr.FuncNode) bool {
+	return strings.ToLower(fn.Name) == "grouping"
+}
+
+// hasGrouping does the expression use grouping()
+func hasGrouping(node expr.Node) bool {
+	switch n := node.(type) {
+	case *expr.FuncNode:
+		if isGrouping(n) {
+			return true
+		}
+	case *expr.WindowNode, *expr.SubQueryNode:
+		return false
+	case *expr.UnaryNode:
+		return hasGrouping(n.Arg)
 	}
-	return &AggPartial{
-		m.ct,
-		m.n,
+	if na, ok := node.(expr.NodeArgs); ok {
+		for _, arg := range na.ChildrenArgs() {
+			if hasGrouping(arg) {
+				return true
+			}
+		}
 	}
+	return false
 }
-func (m *avg) Reset() { m.n = 0; m.ct = 0 }
-func (m *avg) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
+
+// groupingKey the key of a group, of the grouping set setIdx of sets.
+func groupingKey(setIdx, sets int, key string) string {
+	if sets == 1 {
+		return key
+	}
+	return fmt.Sprintf("%d:%s", setIdx, key)
 }
-func NewAvg(col *rel.Column, partial bool) Aggregator {
-	return &avg{partial: partial}
+
+// groupingOfKey the grouping set of the key of a group, see groupingKey.
+func groupingOfKey(key string, sets int) int {
+	if sets == 1 {
+		return 0
+	}
+	setIdx, _ := strconv.Atoi(key[:strings.IndexByte(key, ':')])
+	return setIdx
 }
 
-type count struct {
-	n int64
+// rolledUp the bits of the group by columns not in the grouping set.
+func rolledUp(stmt *rel.SqlSelect, set []int) uint64 {
+	bits := uint64(0)
+	for i := range stmt.GroupBy {
+		bits |= 1 << uint(i)
+	}
+	for _, idx := range set {
+		bits &^= 1 << uint(idx)
+	}
+	return bits
 }
 
-func (m *count) Do(v value.Value) {
-	if v == nil || v.Nil() {
-		return
+// setGrouping the grouping set of the group each aggregator is computing.
+func setGrouping(aggs []Aggregator, rolledUp uint64) {
+	for _, agg := range aggs {
+		if ga, ok := agg.(groupingAggregator); ok {
+			ga.setGrouping(rolledUp)
+		}
 	}
-	m.n++
 }
-func (m *count) Result() interface{} {
-	return m.n
+
+// replaceAggregates a copy of the expression with each of its aggregates
+// (see expr.FindAggregates), and grouping(), replaced by an identity named
+// by key, to be evaluated against the results of the aggregates.  Those of
+// an empty key are not replaced.
+func replaceAggregates(node expr.Node, key func(*expr.FuncNode) string) expr.Node {
+	// copy through its protobuf, as rel.SqlSelect.Copy() does
+	return replaceAggs(expr.NodeFromNodePb(node.NodePb()), key)
 }
-func (m *count) Reset() { m.n = 0 }
-func (m *count) Merge(a *AggPartial) {
-	m.n += a.Ct
+
+func replaceAggs(node expr.Node, key func(*expr.FuncNode) string) expr.Node {
+	switch n := node.(type) {
+	case *expr.FuncNode:
+		if n.F.Aggregate || isGrouping(n) {
+			if k := key(n); k != "" {
+				return expr.NewIdentityNodeVal(k)
+			}
+		}
+	case *expr.WindowNode, *expr.SubQueryNode:
+		return node
+	case *expr.UnaryNode:
+		n.Arg = replaceAggs(n.Arg, key)
+		return n
+	case *expr.CaseNode:
+		if n.Operand != nil {
+			n.Operand = replaceAggs(n.Operand, key)
+		}
+		for i := range n.Whens {
+			n.Whens[i] = replaceAggs(n.Whens[i], key)
+			n.Thens[i] = replaceAggs(n.Thens[i], key)
+		}
+		if n.Else != nil {
+			n.Else = replaceAggs(n.Else, key)
+		}
+		return n
+	}
+	if na, ok := node.(expr.NodeArgs); ok {
+		args := na.ChildrenArgs()
+		for i, arg := range args {
+			args[i] = replaceAggs(arg, key)
+		}
+	}
+	return node
 }
-func NewCount(col *rel.Column) Aggregator {
-	return &count{}
+
+// havingAggs the aggregates of the HAVING of a statement which are not
+// columns of the select, they are computed by the group by as hidden
+// columns after those of the select, named by havingKey.
+//
+//	SELECT user_id FROM orders GROUP BY user_id HAVING sum(price) > 50
+func havingAggs(stmt *rel.SqlSelect) []*expr.FuncNode {
+	if stmt.Having == nil {
+		return nil
+	}
+	var aggs []*expr.FuncNode
+nextAgg:
+	for _, fn := range expr.FindAggregates(stmt.Having) {
+		if havingColumn(stmt, fn) != nil {
+			continue
+		}
+		for _, seen := range aggs {
+			if seen.Equal(fn) {
+				continue nextAgg
+			}
+		}
+		aggs = append(aggs, fn)
+	}
+	return aggs
+}
+
+func havingKey(i int) string { return fmt.Sprintf("$having%d", i) }
+
+// havingColumn the column of the select which is the aggregate, if any.
+func havingColumn(stmt *rel.SqlSelect, fn *expr.FuncNode) *rel.Column {
+	for _, col := range stmt.Columns {
+		if col.Expr != nil && col.Expr.Equal(fn) {
+			return col
+		}
+	}
+	return nil
+}
+
+// havingFilter the HAVING of a statement evaluated against the rows of its
+// group by, each aggregate is replaced by the column holding its result.
+func havingFilter(stmt *rel.SqlSelect) expr.Node {
+	hidden := havingAggs(stmt)
+	return replaceAggregates(stmt.Having, func(fn *expr.FuncNode) string {
+		if !fn.F.Aggregate {
+			return ""
+		}
+		if col := havingColumn(stmt, fn); col != nil {
+			return col.Key()
+		}
+		for i, h := range hidden {
+			if h.Equal(fn) {
+				return havingKey(i)
+			}
+		}
+		return fn.String()
+	})
+}
+
+// groupByColIndex the column index of the rows of a group by, the columns
+// of the select then the hidden aggregates of the having.
+func groupByColIndex(stmt *rel.SqlSelect) map[string]int {
+	cols := stmt.ColIndexes()
+	for i := range havingAggs(stmt) {
+		cols[havingKey(i)] = len(stmt.Columns) + i
+	}
+	return cols
 }
 
 func buildAggs(p *plan.GroupBy) ([]Aggregator, error) {
@@ -439,7 +1037,7 @@ func buildAggs(p *plan.GroupBy) ([]Aggregator, error) {
 	aggs := make([]Aggregator, len(p.Stmt.Columns))
 colLoop:
 	for colIdx, col := range p.Stmt.Columns {
-		for _, gb := range p.Stmt.GroupBy {
+		for gbIdx, gb := range p.Stmt.GroupBy {
 			if gb.As == col.As || (col.Expr != nil && col.Expr.Equal(gb.Expr)) {
 				// simple Non Aggregate Value  gb.As == col.AS
 				//   SELECT domain, count(*) FROM users GROUP BY domain;
@@ -447,36 +1045,49 @@ colLoop:
 				// aliased column
 				// SELECT `users`.`name` AS usernames FROM `users` GROUP BY `users`.`name`
 				//   gb.String() == "`users`.`name`"  && col.Expr.String() == "`users`.`name`"
-				aggs[colIdx] = NewGroupByValue(col)
+				aggs[colIdx] = &groupByFunc{col: col, gb: gbIdx}
 				continue colLoop
 			}
 		}
 
-		// Since we made it here, it is an aggregate func
-		//  move to a registry of some kind to allow extension
-		switch n := col.Expr.(type) {
-		case *expr.FuncNode:
-
-			// TODO:  extract to a UDF Registry Similar to builtins
-			switch strings.ToLower(n.Name) {
-			case "avg":
-				aggs[colIdx] = NewAvg(col, p.Partial)
-			case "count":
-				aggs[colIdx] = NewCount(col)
-			case "sum":
-				aggs[colIdx] = NewSum(col, p.Partial)
-			default:
-				return nil, fmt.Errorf("Not implemented groupby for function: %s", col.Expr)
+		if col.Expr != nil && hasWindow(col.Expr) {
+			// the window task computes it over the grouped rows
+			aggs[colIdx] = NewGroupByValue(col)
+			continue
+		}
+
+		// Since we made it here, it is an aggregate func, of the registry
+		// of functions, see expr.AggregateFunc, or an expression over them
+		if fn, ok := col.Expr.(*expr.FuncNode); ok && fn.F.Aggregate {
+			agg, err := NewAggFunc(fn, p.Partial)
+			if err != nil {
+				return nil, err
 			}
-		case *expr.BinaryNode:
-			// expression logic?
-			return nil, fmt.Errorf("Not implemented groupby for expression column: %s", col.Expr)
+			aggs[colIdx] = agg
+			continue
+		}
+		if col.Expr != nil && (len(expr.FindAggregates(col.Expr)) > 0 || hasGrouping(col.Expr)) {
+			agg, err := newExprAgg(col.Expr, p.Stmt.GroupBy, p.Partial)
+			if err != nil {
+				return nil, err
+			}
+			aggs[colIdx] = agg
+			continue
+		}
+		switch col.Expr.(type) {
 		case *expr.IdentityNode:
 			// We can have a naked group by which basically means distinct? should have been caught above
 			return nil, fmt.Errorf("Not implemented groupby for identity column %s", col.Expr)
 		default:
-			return nil, fmt.Errorf("Not implemented groupby for %T column: %s", col.Expr, col.Expr)
+			return nil, fmt.Errorf("Not implemented groupby for expression column: %s", col.Expr)
+		}
+	}
+	for _, fn := range havingAggs(p.Stmt) {
+		agg, err := NewAggFunc(fn, p.Partial)
+		if err != nil {
+			return nil, err
 		}
+		aggs = append(aggs, agg)
 	}
 	return aggs, nil
 }
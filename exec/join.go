@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -22,6 +26,10 @@ var (
 	_ TaskRunner = (*JoinMerge)(nil)
 )
 
+// joinNullKey is the key given to rows whose join key evaluates to NULL,
+// these never match any other row but are kept for outer joins.
+const joinNullKey = "\x00null\x00"
+
 type KeyEvaluator func(msg schema.Message) driver.Value
 
 // Evaluate messages to create JoinKey based message, where the
@@ -61,11 +69,13 @@ func (m *JoinKey) Run() error {
 
 	for {
 
+		waited := time.Now()
 		select {
 		case <-m.SigChan():
 			//u.Debugf("got signal quit")
 			return nil
 		case msg, ok := <-inCh:
+			m.received(waited, msg)
 			if !ok {
 				//u.Debugf("NICE, got msg shutdown")
 				return nil
@@ -79,8 +89,12 @@ func (m *JoinKey) Run() error {
 				for i, node := range joinNodes {
 					joinVal, ok := vm.Eval(mt, node)
 					//u.Debugf("evaluating: ok?%v T:%T result=%v node '%v'", ok, joinVal, joinVal.ToString(), node.String())
-					if !ok {
-						u.Errorf("could not evaluate: %T %#v   %v", joinVal, joinVal, msg)
+					if !ok || joinVal == nil || joinVal.Nil() {
+						// NULL never joins, pass along for outer joins
+						mt.SetKeyHashed(joinNullKey)
+						waited := time.Now()
+						outCh <- mt
+						m.sent(waited)
 						break msgTypeSwitch
 					}
 					vals[i] = joinVal.ToString()
@@ -88,7 +102,9 @@ func (m *JoinKey) Run() error {
 				//u.Infof("joinkey: %v row:%v", vals, mt)
 				key := strings.Join(vals, string(byte(0)))
 				mt.SetKeyHashed(key)
+				waited := time.Now()
 				outCh <- mt
+				m.sent(waited)
 			default:
 				return fmt.Errorf("To use JoinKey must use SqlDriverMessageMap but got %T", msg)
 			}
@@ -101,11 +117,14 @@ func (m *JoinKey) Run() error {
 //
 type JoinMerge struct {
 	*TaskBase
-	leftStmt  *rel.SqlSource
-	rightStmt *rel.SqlSource
-	ltask     TaskRunner
-	rtask     TaskRunner
-	colIndex  map[string]int
+	p          *plan.JoinMerge
+	leftStmt   *rel.SqlSource
+	rightStmt  *rel.SqlSource
+	ltask      TaskRunner
+	rtask      TaskRunner
+	colIndex   map[string]int
+	leftOuter  bool // keep un-matched left rows (LEFT, FULL)
+	rightOuter bool // keep un-matched right rows (RIGHT, FULL)
 }
 
 // A very stupid naive parallel join merge, uses Key() as value to merge
@@ -127,10 +146,14 @@ type JoinMerge struct {
 //   source2b  -> key-hash-route |-> --  join  -->
 //   source2n  ->                |-> --  join  -->
 //
+// Equality joins match on the (composite) Key(), other joins (CROSS, or
+// non-equi ON expressions) compare every pair of rows.  Any residual join
+// conditions are evaluated on each matched pair.
 func NewJoinNaiveMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *JoinMerge {
 
 	m := &JoinMerge{
 		TaskBase: NewTaskBase(ctx),
+		p:        p,
 		colIndex: p.ColIndex,
 	}
 
@@ -139,6 +162,17 @@ func NewJoinNaiveMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *J
 	m.leftStmt = p.LeftFrom
 	m.rightStmt = p.RightFrom
 
+	// the join type is on the right side of the join
+	switch p.RightFrom.LeftOrRight {
+	case lex.TokenLeft:
+		m.leftOuter = true
+	case lex.TokenRight:
+		m.rightOuter = true
+	case lex.TokenFull:
+		m.leftOuter = true
+		m.rightOuter = true
+	}
+
 	return m
 }
 
@@ -151,124 +185,179 @@ func (m *JoinMerge) Run() error {
 	leftIn := m.ltask.MessageOut()
 	rightIn := m.rtask.MessageOut()
 
-	lh := make(map[driver.Value][]*datasource.SqlDriverMessageMap)
-	rh := make(map[driver.Value][]*datasource.SqlDriverMessageMap)
+	hashed := m.p.Strategy == plan.JoinHash
+	lrows := make([]*datasource.SqlDriverMessageMap, 0)
+	rrows := make([]*datasource.SqlDriverMessageMap, 0)
 
 	wg := new(sync.WaitGroup)
-	wg.Add(1)
+	// the first error of either side stops both
 	var fatalErr error
-	go func() {
+	var failOnce sync.Once
+	fail := func(err error) {
+		failOnce.Do(func() {
+			fatalErr = err
+			m.Quit()
+		})
+	}
+	collect := func(in <-chan schema.Message, rows *[]*datasource.SqlDriverMessageMap) {
+		defer wg.Done()
 		for {
-			//u.Infof("In source Scanner msg %#v", msg)
+			waited := time.Now()
 			select {
 			case <-m.SigChan():
 				u.Debugf("got signal quit")
-				wg.Done()
-				wg.Done()
 				return
-			case msg, ok := <-leftIn:
+			case msg, ok := <-in:
+				m.received(waited, msg)
 				if !ok {
-					//u.Debugf("NICE, got left shutdown")
-					wg.Done()
 					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						lh[key] = append(lh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
+				}
+				switch mt := msg.(type) {
+				case *datasource.SqlDriverMessageMap:
+					if hashed && mt.Key() == "" {
+						err := fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
+						u.Errorf("no key? %#v  %v", mt, err)
+						fail(err)
 						return
 					}
+					*rows = append(*rows, mt)
+					m.buffered(len(*rows))
+				default:
+					u.Errorf("unrecognized msg %T", msg)
+					fail(fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg))
+					return
 				}
 			}
+		}
+	}
+	wg.Add(2)
+	go collect(leftIn, &lrows)
+	go collect(rightIn, &rrows)
+	wg.Wait()
+	if fatalErr != nil {
+		return fatalErr
+	}
 
+	i := uint64(0)
+	emit := func(msg *datasource.SqlDriverMessageMap) bool {
+		msg.IdVal = i
+		i++
+		waited := time.Now()
+		select {
+		case outCh <- msg:
+			m.sent(waited)
+			return true
+		case <-m.SigChan():
+			return false
 		}
-	}()
-	wg.Add(1)
-	go func() {
-		for {
+	}
 
-			//u.Infof("In source Scanner iter %#v", item)
-			select {
-			case <-m.SigChan():
-				u.Debugf("got quit signal join source 1")
-				wg.Done()
-				wg.Done()
-				return
-			case msg, ok := <-rightIn:
-				if !ok {
-					//u.Debugf("NICE, got right shutdown")
-					wg.Done()
-					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						rh[key] = append(rh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
-						return
-					}
-				}
+	// Index the build side by join key, probing it with each row of the
+	// other side, the build side is the right unless the planner found the
+	// left to be smaller.  NULL keys never match.
+	build, probe := rrows, lrows
+	buildOuter, probeOuter := m.rightOuter, m.leftOuter
+	if m.p.BuildLeft {
+		build, probe = lrows, rrows
+		buildOuter, probeOuter = m.leftOuter, m.rightOuter
+	}
+	sides := func(pm, bm *datasource.SqlDriverMessageMap) (lm, rm *datasource.SqlDriverMessageMap) {
+		if m.p.BuildLeft {
+			return bm, pm
+		}
+		return pm, bm
+	}
+	var index map[driver.Value][]int
+	if hashed {
+		index = make(map[driver.Value][]int)
+		for bi, bm := range build {
+			if key := bm.Key(); key != joinNullKey {
+				index[key] = append(index[key], bi)
 			}
+		}
+	}
+	bmatched := make([]bool, len(build))
 
+	for _, pm := range probe {
+		matched := false
+		// hash joins only probe build rows with same key, nested loop all
+		n := len(build)
+		var idx []int
+		if hashed {
+			idx = index[pm.Key()]
+			n = len(idx)
 		}
-	}()
-	wg.Wait()
-	//u.Info("leaving source scanner")
-	i := uint64(0)
-	for keyLeft, valLeft := range lh {
-		//u.Debugf("compare:  key:%v  left:%#v  right:%#v  rh: %#v", keyLeft, valLeft, rh[keyLeft], rh)
-		if valRight, ok := rh[keyLeft]; ok {
-			//u.Debugf("found match?\n\t%d left=%#v\n\t%d right=%#v", len(valLeft), valLeft, len(valRight), valRight)
-			msgs := m.mergeValueMessages(valLeft, valRight)
-			//u.Debugf("msgsct: %v   msgs:%#v", len(msgs), msgs)
-			for _, msg := range msgs {
-				//outCh <- datasource.NewUrlValuesMsg(i, msg)
-				//u.Debugf("i:%d   msg:%#v", i, msg)
-				msg.IdVal = i
-				i++
-				outCh <- msg
+		for j := 0; j < n; j++ {
+			bi := j
+			if hashed {
+				bi = idx[j]
+			}
+			lm, rm := sides(pm, build[bi])
+			if !m.residualMatches(lm, rm) {
+				continue
+			}
+			matched = true
+			bmatched[bi] = true
+			if !emit(m.mergeMessages(lm, rm)) {
+				return nil
+			}
+		}
+		if !matched && probeOuter {
+			// no match, pad build side with nulls
+			if !emit(m.mergeMessages(sides(pm, nil))) {
+				return nil
+			}
+		}
+	}
+	if buildOuter {
+		for bi, bm := range build {
+			if bmatched[bi] {
+				continue
+			}
+			// no match, pad probe side with nulls
+			if !emit(m.mergeMessages(sides(nil, bm))) {
+				return nil
 			}
 		}
 	}
 	return nil
 }
 
-func (m *JoinMerge) mergeValueMessages(lmsgs, rmsgs []*datasource.SqlDriverMessageMap) []*datasource.SqlDriverMessageMap {
-	// m.leftStmt.Columns, m.rightStmt.Columns, nil
-	//func mergeValuesMsgs(lmsgs, rmsgs []datasource.Message, lcols, rcols []*rel.Column, cols map[string]*rel.Column) []*datasource.SqlDriverMessageMap {
-	out := make([]*datasource.SqlDriverMessageMap, 0)
-	//u.Infof("merge values: %v:%v", len(lcols), len(rcols))
-	for _, lm := range lmsgs {
-		//u.Warnf("nice SqlDriverMessageMap: %#v", lmt)
-		for _, rm := range rmsgs {
-			vals := make([]driver.Value, len(m.colIndex))
-			vals = m.valIndexing(vals, lm.Values(), m.leftStmt.Source.Columns)
-			vals = m.valIndexing(vals, rm.Values(), m.rightStmt.Source.Columns)
-			newMsg := datasource.NewSqlDriverMessageMap(0, vals, m.colIndex)
-			//u.Infof("out: %+v", newMsg)
-			out = append(out, newMsg)
+// residualMatches evaluate the join conditions that were not part of the
+// join key against a pair of rows.
+func (m *JoinMerge) residualMatches(lm, rm *datasource.SqlDriverMessageMap) bool {
+	if len(m.p.Residual) == 0 {
+		return true
+	}
+	reader := &joinPairReader{
+		lalias: joinAlias(m.leftStmt),
+		ralias: joinAlias(m.rightStmt),
+		left:   lm,
+		right:  rm,
+	}
+	for _, node := range m.p.Residual {
+		val, ok := vm.Eval(reader, node)
+		if !ok {
+			return false
+		}
+		if bv, isBool := val.(value.BoolValue); !isBool || !bv.Val() {
+			return false
 		}
 	}
-	return out
+	return true
+}
+
+// mergeMessages merge a left and right row into a single row, either side
+// may be nil for un-matched rows of an outer join leaving its values nil.
+func (m *JoinMerge) mergeMessages(lm, rm *datasource.SqlDriverMessageMap) *datasource.SqlDriverMessageMap {
+	vals := make([]driver.Value, len(m.colIndex))
+	if lm != nil {
+		vals = m.valIndexing(vals, lm.Values(), m.leftStmt.Source.Columns)
+	}
+	if rm != nil {
+		vals = m.valIndexing(vals, rm.Values(), m.rightStmt.Source.Columns)
+	}
+	return datasource.NewSqlDriverMessageMap(0, vals, m.colIndex)
 }
 
 func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Column) []driver.Value {
@@ -294,3 +383,43 @@ func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Co
 	}
 	return valOut
 }
+
+func joinAlias(from *rel.SqlSource) string {
+	if from.Alias != "" {
+		return from.Alias
+	}
+	return from.Name
+}
+
+// joinPairReader reads a pair of left/right rows using the source alias
+// to choose the row, ie o.price reads price from the orders row.
+type joinPairReader struct {
+	lalias, ralias string
+	left, right    *datasource.SqlDriverMessageMap
+}
+
+func (m *joinPairReader) Get(key string) (value.Value, bool) {
+	if left, right, hasLeft := expr.LeftRight(key); hasLeft {
+		switch left {
+		case m.lalias:
+			return m.left.Get(right)
+		case m.ralias:
+			return m.right.Get(right)
+		}
+	}
+	if v, ok := m.left.Get(key); ok {
+		return v, true
+	}
+	return m.right.Get(key)
+}
+func (m *joinPairReader) Row() map[string]value.Value {
+	row := make(map[string]value.Value)
+	for k, v := range m.left.Row() {
+		row[m.lalias+"."+k] = v
+	}
+	for k, v := range m.right.Row() {
+		row[m.ralias+"."+k] = v
+	}
+	return row
+}
+func (m *joinPairReader) Ts() time.Time { return time.Time{} }
@@ -3,6 +3,7 @@ package exec
 import (
 	"database/sql/driver"
 	"fmt"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -85,10 +86,13 @@ func NewDelete(ctx *plan.Context, p *plan.Delete) *DeletionTask {
 }
 
 func (m *Upsert) Close() error {
+	m.Lock()
 	if m.closed {
+		m.Unlock()
 		return nil
 	}
 	m.closed = true
+	m.Unlock()
 	if closer, ok := m.db.(schema.Source); ok {
 		if err := closer.Close(); err != nil {
 			return err
@@ -119,13 +123,17 @@ func (m *Upsert) Run() error {
 		u.Warnf("errored, should not complete %v", err)
 		vals[0] = err.Error()
 		vals[1] = -1
+		waited := time.Now()
 		m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+		m.sent(waited)
 		return err
 	}
 	vals[0] = int64(0) // status?
 	vals[1] = affectedCt
 	u.Infof("affected? %v", affectedCt)
+	waited := time.Now()
 	m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+	m.sent(waited)
 	return nil
 }
 
@@ -239,14 +247,18 @@ func (m *DeletionTask) Run() error {
 		u.Errorf("Could not delete values: %v", err)
 		vals[0] = err.Error()
 		vals[1] = int64(0)
+		waited := time.Now()
 		m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+		m.sent(waited)
 		return err
 	}
 	m.deleted = deletedCt
 
 	vals[0] = int64(0)
 	vals[1] = int64(deletedCt)
+	waited := time.Now()
 	m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+	m.sent(waited)
 
 	return nil
 }
@@ -268,13 +280,17 @@ func (m *DeletionScanner) Run() error {
 
 				vals[0] = err.Error()
 				vals[1] = int64(0)
+				waited := time.Now()
 				m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+				m.sent(waited)
 				return err
 			}
 			m.deleted = deletedCt
 			vals[0] = int64(0)
 			vals[1] = int64(deletedCt)
+			waited := time.Now()
 			m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+			m.sent(waited)
 		}
 	}
 	return nil
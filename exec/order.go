@@ -1,10 +1,10 @@
 package exec
 
 import (
+	"container/heap"
 	"fmt"
-	"github.com/araddon/qlbridge/value"
+	"io"
 	"sort"
-	"strings"
 	"time"
 
 	u "github.com/araddon/gou"
@@ -12,10 +12,15 @@ import (
 	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
-// Order
+// Order sorts the rows by the ORDER BY of the statement.  Rows are held
+// in memory up to the memory limit (plan.Context.MemoryLimit), beyond it
+// they are sorted and spilled to disk as runs, which are merged k-way after
+// the input completes.  With a LIMIT only the first rows are held, in a
+// bounded heap, instead of sorting all of them.
 type Order struct {
 	*TaskBase
 	p          *plan.Order
@@ -66,20 +71,43 @@ func (m *Order) Run() error {
 	inCh := m.MessageIn()
 
 	colIndex := m.p.Stmt.ColIndexes()
-	orderCt := len(m.p.Stmt.OrderBy)
 
-	// are are going to hold entire row in memory while we are calculating
-	//  so obviously not scalable.
+	// ORDER BY ... LIMIT n only needs the first n rows, held in a bounded
+	// heap, except of DISTINCT which removes rows after the order.
+	topN := 0
+	if m.p.Stmt.Limit > 0 && !m.p.Stmt.Distinct {
+		topN = m.p.Stmt.Limit + m.p.Stmt.Offset
+	}
+	memLimit := m.Ctx.MemoryLimit
+	if memLimit <= 0 {
+		memLimit = DefaultMemoryLimit
+	}
+
+	// rows are held in memory until they exceed the memory limit, then
+	// are sorted and spilled to disk as a run, runs are merged at the end.
 	sl := NewOrderMessages(m.p)
+	var (
+		memUsed     int64
+		seq         uint64
+		runs        []*spillFile
+		runColIndex map[string]int
+	)
+	defer func() {
+		for _, run := range runs {
+			run.Close()
+		}
+	}()
 
 msgReadLoop:
 	for {
 
+		waited := time.Now()
 		select {
 		case <-m.SigChan():
 			u.Warnf("got signal quit")
 			return nil
 		case msg, ok := <-inCh:
+			m.received(waited, msg)
 			if !ok {
 				//u.Debugf("NICE, got closed channel shutdown")
 				break msgReadLoop
@@ -102,34 +130,56 @@ msgReadLoop:
 					sdm = datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex)
 				}
 
-				// We are going to use VM Engine to create a value for each statement in group by
-				//  then join each value together to create a unique key.
-				keys := make([]value.Value, orderCt)
-				for i, col := range m.p.Stmt.OrderBy {
-					if col.Expr != nil {
-						if key, ok := vm.Eval(sdm, col.Expr); ok {
-							//u.Debugf("msgtype:%T  key:%q for-expr:%s", sdm, key, col.Expr)
-							keys[i] = key
-						} else {
-							// Is this an error?
-							//u.Warnf("no key?  %s for %+v", col.Expr, sdm)
-						}
-					} else {
-						//u.Warnf("no col.expr? %#v", col)
+				seq++
+				mk := &msgkey{keys: sl.keys(sdm), msg: sdm, seq: seq}
+				memUsed += rowMemSize(sdm.Vals)
+				if topN > 0 {
+					if evicted := sl.pushTop(mk, topN); evicted != nil {
+						memUsed -= rowMemSize(evicted.msg.Vals)
 					}
+				} else {
+					sl.l = append(sl.l, mk)
 				}
+				m.buffered(len(sl.l))
 
-				//u.Infof("found key:%s for %+v", key, sdm)
-				sl.l = append(sl.l, &msgkey{keys, sdm})
+				if memUsed > memLimit {
+					u.Debugf("order exceeded memory limit %d, spilling %d rows to disk", memLimit, len(sl.l))
+					run, err := sl.spillRun()
+					if err != nil {
+						return err
+					}
+					runs = append(runs, run)
+					runColIndex = sdm.ColIndex
+					sl.l = sl.l[:0]
+					memUsed = 0
+				}
 			}
 		}
 	}
 
 	sort.Sort(sl)
 
-	for _, m := range sl.l {
+	emitted := 0
+	emit := func(mk *msgkey) bool {
+		if topN > 0 && emitted >= topN {
+			return false
+		}
+		emitted++
 		//u.Debugf("got %s:%v msgs", key, vals)
-		outCh <- m.msg
+		waited := time.Now()
+		outCh <- mk.msg
+		m.sent(waited)
+		return true
+	}
+
+	if len(runs) == 0 {
+		for _, mk := range sl.l {
+			if !emit(mk) {
+				break
+			}
+		}
+	} else if err := sl.mergeRuns(runs, runColIndex, emit); err != nil {
+		return err
 	}
 
 	m.isComplete = true
@@ -141,17 +191,21 @@ msgReadLoop:
 type msgkey struct {
 	keys []value.Value
 	msg  *datasource.SqlDriverMessageMap
+	seq  uint64 // arrival order, of rows with equal keys
 }
 type OrderMessages struct {
 	l      []*msgkey
+	exprs  []expr.Node
 	invert []bool
 }
 
 func NewOrderMessages(p *plan.Order) *OrderMessages {
+	exprs := make([]expr.Node, len(p.Stmt.OrderBy))
 	invert := make([]bool, len(p.Stmt.OrderBy))
 	for i, col := range p.Stmt.OrderBy {
 		//u.Debugf("invert?  %s ORDER %v", col.Expr, col.Order)
 		if col.Expr != nil {
+			exprs[i] = col.Expr
 			if !col.Asc() {
 				invert[i] = true
 			}
@@ -159,6 +213,7 @@ func NewOrderMessages(p *plan.Order) *OrderMessages {
 	}
 	return &OrderMessages{
 		l:      make([]*msgkey, 0),
+		exprs:  exprs,
 		invert: invert,
 	}
 }
@@ -166,23 +221,161 @@ func (m *OrderMessages) Len() int {
 	return len(m.l)
 }
 func (m *OrderMessages) Less(i, j int) bool {
-	for ki, key := range m.l[i].keys {
-		var cmp int
-		nm, ok := key.(value.NumericValue)
-		if ok {
-			cmp = nm.Compare(m.l[j].keys[ki].(value.NumericValue))
-		} else {
-			cmp = strings.Compare(key.ToString(), m.l[j].keys[ki].ToString())
-		}
+	return m.less(m.l[i], m.l[j])
+}
+func (m *OrderMessages) Swap(i, j int) {
+	m.l[i], m.l[j] = m.l[j], m.l[i]
+}
 
+// less does a sort before b, of equal keys the first to arrive.
+func (m *OrderMessages) less(a, b *msgkey) bool {
+	for ki, key := range a.keys {
+		cmp := value.Compare(key, b.keys[ki])
 		if cmp < 0 {
 			return !m.invert[ki]
 		} else if cmp > 0 {
 			return m.invert[ki]
 		}
 	}
-	return false
+	return a.seq < b.seq
 }
-func (m *OrderMessages) Swap(i, j int) {
-	m.l[i], m.l[j] = m.l[j], m.l[i]
+
+// keys the order by values of a row.
+func (m *OrderMessages) keys(sdm *datasource.SqlDriverMessageMap) []value.Value {
+	// We are going to use VM Engine to create a value for each statement in group by
+	//  then join each value together to create a unique key.
+	keys := make([]value.Value, len(m.exprs))
+	for i, ex := range m.exprs {
+		if ex != nil {
+			if key, ok := vm.Eval(sdm, ex); ok {
+				//u.Debugf("msgtype:%T  key:%q for-expr:%s", sdm, key, ex)
+				keys[i] = key
+			}
+		}
+	}
+	return keys
+}
+
+// pushTop add the row to the rows, a max-heap of the first n, returns the
+// row which is no longer of the first n if any, possibly this one.
+func (m *OrderMessages) pushTop(mk *msgkey, n int) *msgkey {
+	h := orderTopHeap{m}
+	if len(m.l) < n {
+		heap.Push(h, mk)
+		return nil
+	}
+	if !m.less(mk, m.l[0]) {
+		return mk
+	}
+	evicted := m.l[0]
+	m.l[0] = mk
+	heap.Fix(h, 0)
+	return evicted
+}
+
+// spillRun sort the rows and write them to a temp file.
+func (m *OrderMessages) spillRun() (*spillFile, error) {
+	sort.Sort(m)
+	run, err := newSpillFile("order")
+	if err != nil {
+		return nil, err
+	}
+	for _, mk := range m.l {
+		if err := run.Write(&spillRow{Seq: mk.seq, Vals: mk.msg.Vals}); err != nil {
+			run.Close()
+			return nil, err
+		}
+	}
+	return run, nil
+}
+
+// mergeRuns k-way merge of the spilled runs and the sorted rows in memory,
+// until emit returns false.
+func (m *OrderMessages) mergeRuns(runs []*spillFile, colIndex map[string]int, emit func(*msgkey) bool) error {
+
+	srcs := make([]func() (*msgkey, error), 0, len(runs)+1)
+	for _, run := range runs {
+		rdr, err := run.Rewind()
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, func() (*msgkey, error) {
+			row, err := rdr.Next()
+			if err != nil {
+				return nil, err
+			}
+			sdm := datasource.NewSqlDriverMessageMap(row.Seq, row.Vals, colIndex)
+			return &msgkey{keys: m.keys(sdm), msg: sdm, seq: row.Seq}, nil
+		})
+	}
+	mem := m.l
+	srcs = append(srcs, func() (*msgkey, error) {
+		if len(mem) == 0 {
+			return nil, io.EOF
+		}
+		mk := mem[0]
+		mem = mem[1:]
+		return mk, nil
+	})
+
+	h := &orderMergeHeap{less: m.less}
+	for i, src := range srcs {
+		mk, err := src()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		heap.Push(h, &orderMergeSrc{mk: mk, src: i})
+	}
+	for h.Len() > 0 {
+		next := heap.Pop(h).(*orderMergeSrc)
+		if !emit(next.mk) {
+			return nil
+		}
+		mk, err := srcs[next.src]()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		heap.Push(h, &orderMergeSrc{mk: mk, src: next.src})
+	}
+	return nil
+}
+
+// orderTopHeap the rows as a max-heap, the last in order on top.
+type orderTopHeap struct {
+	*OrderMessages
+}
+
+func (h orderTopHeap) Less(i, j int) bool { return h.less(h.l[j], h.l[i]) }
+func (h orderTopHeap) Push(x interface{}) { h.l = append(h.l, x.(*msgkey)) }
+func (h orderTopHeap) Pop() interface{} {
+	n := len(h.l)
+	x := h.l[n-1]
+	h.l = h.l[:n-1]
+	return x
+}
+
+type orderMergeSrc struct {
+	mk  *msgkey
+	src int
+}
+
+// orderMergeHeap a min-heap of the next row of each sorted run.
+type orderMergeHeap struct {
+	less  func(a, b *msgkey) bool
+	items []*orderMergeSrc
+}
+
+func (h *orderMergeHeap) Len() int           { return len(h.items) }
+func (h *orderMergeHeap) Less(i, j int) bool { return h.less(h.items[i].mk, h.items[j].mk) }
+func (h *orderMergeHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *orderMergeHeap) Push(x interface{}) { h.items = append(h.items, x.(*orderMergeSrc)) }
+func (h *orderMergeHeap) Pop() interface{} {
+	n := len(h.items)
+	x := h.items[n-1]
+	h.items = h.items[:n-1]
+	return x
 }
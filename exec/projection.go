@@ -3,6 +3,7 @@ package exec
 import (
 	"database/sql/driver"
 	"math"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -116,9 +117,15 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
 	limit := m.p.Stmt.Limit
-	if limit == 0 {
+	if limit == 0 || (isFinal && m.p.Stmt.Distinct) {
+		// Distinct applies the limit after removing duplicates
 		limit = math.MaxInt32
 	}
+	offset := 0
+	if isFinal && !m.p.Stmt.Distinct {
+		// Distinct applies the offset itself after removing duplicates
+		offset = m.p.Stmt.Offset
+	}
 	colCt := len(columns)
 	// If we have a projection, use that as col count
 	if m.p.Proj != nil {
@@ -299,11 +306,18 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 			m.Quit()   // should close rest of dag as well
 			return false
 		}
+		if offset > 0 {
+			// the first rows are skipped
+			offset--
+			return true
+		}
 		rowCt++
 
 		//u.Debugf("row:%d  completed projection for: %p %#v", rowCt, out, outMsg)
+		waited := time.Now()
 		select {
 		case out <- outMsg:
+			m.sent(waited)
 			return true
 		case <-m.SigChan():
 			return false
@@ -331,20 +345,17 @@ func (m *Projection) limitEvaluator() MessageHandler {
 		}
 
 		if rowCt >= limit {
-			if rowCt == limit {
-				//u.Debugf("%p Projection reaching Limit!!! rowct:%v  limit:%v", m, rowCt, limit)
-				out <- nil // Sending nil message is a message to downstream to shutdown
-				//m.Close()
-				m.Quit()
-			}
-			rowCt++
-			//return false
-			return true // swallow it
+			//u.Debugf("%p Projection reaching Limit!!! rowct:%v  limit:%v", m, rowCt, limit)
+			out <- nil // Sending nil message is a message to downstream to shutdown
+			m.Quit()   // stop reading, so the upstream tasks and sources are shut down
+			return false
 		}
 		rowCt++
 
+		waited := time.Now()
 		select {
 		case out <- msg:
+			m.sent(waited)
 			return true
 		case <-m.SigChan():
 			return false
@@ -3,6 +3,7 @@ package exec
 import (
 	"database/sql/driver"
 	"io"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -102,7 +103,14 @@ func NewResultBuffer(ctx *plan.Context, writeTo *[]schema.Message) *ResultBuffer
 		TaskBase: NewTaskBase(ctx),
 	}
 	m.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if msg == nil {
+			// nil is a shutdown notice (ie limit reached)
+			return false
+		}
+		// the buffer is the output of this task
 		*writeTo = append(*writeTo, msg)
+		m.sent(time.Now())
+		m.buffered(len(*writeTo))
 		//u.Infof("write to msgs: %v", len(*writeTo))
 		return true
 	}
@@ -164,12 +172,18 @@ func (m *ResultBuffer) Close() error {
 
 // Next his is implementation of the sql/driver Rows() Next() interface
 func (m *ResultWriter) Next(dest []driver.Value) error {
+	waited := time.Now()
 	select {
 	case <-m.SigChan():
 		return ErrShuttingDown
 	case err := <-m.ErrChan():
 		return err
 	case msg, ok := <-m.MessageIn():
+		m.received(waited, msg)
+		// errors recorded while evaluating expressions (scalar sub-queries)
+		if err := m.Ctx.FirstError(); err != nil {
+			return err
+		}
 		if !ok {
 			return io.EOF
 		}
@@ -211,8 +225,10 @@ func resultWrite(m *ResultWriter) MessageHandler {
 		// 	u.Errorf("could not convert to message reader: %T", msg.Body())
 		// }
 
+		waited := time.Now()
 		select {
 		case out <- msg:
+			m.sent(waited)
 			return true
 		case <-m.SigChan():
 			return false
@@ -259,8 +275,10 @@ func msgToRow(msg schema.Message, cols []string, dest []driver.Value) error {
 				//u.Infof("key=%v   val=%v", key, val)
 			} else if val == nil {
 				u.Errorf("could not evaluate? %v  %#v", key, mt)
+				dest[i] = nil
 			} else {
-				u.Warnf("missing value? %v %T %v", key, val.Value(), val.Value())
+				// NULL, ie outer join.  dest is re-used across rows so must be cleared
+				dest[i] = nil
 			}
 		}
 		//u.Debugf("got msg in row result writer: %#v", dest)
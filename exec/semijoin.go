@@ -0,0 +1,235 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*SemiJoin)(nil)
+)
+
+// SemiJoin filters rows on a where clause sub-query predicate
+//
+//    x [NOT] IN (SELECT ...)
+//    [NOT] EXISTS (SELECT ...)
+//
+// The sub-query rows are read into a hash keyed on correlation keys
+// (if de-correlated) holding the IN values, then each input row is probed.
+// Correlated sub-queries that could not be de-correlated are run once per
+// distinct value of their outer references and cached.
+//
+// IN follows sql three valued logic:  a NULL left side, or no match in
+// a sub-query that returned a NULL, is unknown and the row is filtered out
+// for both IN and NOT IN.
+type SemiJoin struct {
+	*TaskBase
+	p      *plan.SemiJoin
+	groups map[string]*semiGroup
+	cols   map[string]int
+}
+
+// semiGroup sub-query rows for a single correlation key.
+type semiGroup struct {
+	vals    map[string]struct{}
+	hasNull bool
+	rows    int
+}
+
+func (m *semiGroup) add(row []driver.Value, exists bool) {
+	m.rows++
+	if exists {
+		return
+	}
+	if row[0] == nil {
+		m.hasNull = true
+		return
+	}
+	m.vals[rowHashKey(row[:1])] = struct{}{}
+}
+
+// NewSemiJoin create semi-join (IN, EXISTS) or anti-join (NOT IN, NOT EXISTS) task.
+func NewSemiJoin(ctx *plan.Context, p *plan.SemiJoin) *SemiJoin {
+	return &SemiJoin{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		groups:   make(map[string]*semiGroup),
+		cols:     p.Stmt.ColIndexes(),
+	}
+}
+
+// Run the semi-join task.
+func (m *SemiJoin) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	if !m.p.Correlated {
+		rows, err := runSubQuery(m.p.Ctx, m.p.SubPlan)
+		if err != nil {
+			m.Quit()
+			return err
+		}
+		for _, row := range rows {
+			// IN sub-queries have the IN column first then the keys
+			keyVals := row[:len(m.p.Outer)]
+			if !m.p.Exists {
+				keyVals = row[1:]
+			}
+			if containsNil(keyVals) {
+				// a NULL correlation key never equals an outer row
+				continue
+			}
+			m.group(rowHashKey(keyVals)).add(row, m.p.Exists)
+		}
+	}
+
+	inCh := m.MessageIn()
+	for {
+		waited := time.Now()
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-inCh:
+			m.received(waited, msg)
+			if !ok || msg == nil {
+				return nil
+			}
+			keep, err := m.filter(msg)
+			if err != nil {
+				m.Quit()
+				return err
+			}
+			if !keep {
+				continue
+			}
+			waited := time.Now()
+			select {
+			case m.msgOutCh <- msg:
+				m.sent(waited)
+			case <-m.SigChan():
+				return nil
+			}
+		}
+	}
+}
+
+func (m *SemiJoin) group(key string) *semiGroup {
+	g, ok := m.groups[key]
+	if !ok {
+		g = &semiGroup{vals: make(map[string]struct{})}
+		m.groups[key] = g
+	}
+	return g
+}
+
+// filter should this input row be kept.
+func (m *SemiJoin) filter(msg schema.Message) (bool, error) {
+
+	var reader expr.ContextReader
+	switch mt := msg.(type) {
+	case *datasource.SqlDriverMessage:
+		reader = mt.ToMsgMap(m.cols)
+	case expr.ContextReader:
+		reader = mt
+	default:
+		return false, fmt.Errorf("could not convert to message reader: %T", msg)
+	}
+
+	var g *semiGroup
+	if m.p.Correlated {
+		var err error
+		if g, err = m.correlatedGroup(reader); err != nil {
+			return false, err
+		}
+	} else {
+		keyVals := make([]driver.Value, len(m.p.Outer))
+		for i, node := range m.p.Outer {
+			if v, ok := vm.Eval(reader, node); ok && v != nil && !v.Nil() {
+				keyVals[i] = v.Value()
+			} else {
+				// a NULL outer key matches no sub-query rows
+				g = nil
+				keyVals = nil
+				break
+			}
+		}
+		if keyVals != nil {
+			g = m.groups[rowHashKey(keyVals)]
+		}
+	}
+
+	if m.p.Exists {
+		matched := g != nil && g.rows > 0
+		return matched != m.p.Anti, nil
+	}
+	if g == nil || g.rows == 0 {
+		// IN on an empty set is false, NOT IN true even if arg is NULL
+		return m.p.Anti, nil
+	}
+	v, ok := vm.Eval(reader, m.p.Arg)
+	if !ok || v == nil || v.Nil() {
+		return false, nil
+	}
+	if _, found := g.vals[rowHashKey([]driver.Value{v.Value()})]; found {
+		return !m.p.Anti, nil
+	}
+	if g.hasNull {
+		return false, nil
+	}
+	return m.p.Anti, nil
+}
+
+// correlatedGroup run the correlated sub-query for the values of the outer
+// references in this row, results are cached per distinct set of values.
+func (m *SemiJoin) correlatedGroup(reader expr.ContextReader) (*semiGroup, error) {
+
+	vals := make([]value.Value, len(m.p.Refs))
+	keyVals := make([]driver.Value, len(m.p.Refs))
+	for i, ref := range m.p.Refs {
+		if v, ok := vm.Eval(reader, ref); ok && v != nil {
+			vals[i] = v
+			keyVals[i] = v.Value()
+		}
+	}
+	key := rowHashKey(keyVals)
+	if g, ok := m.groups[key]; ok {
+		return g, nil
+	}
+
+	sub, err := substituteRefs(m.p.Sub, m.p.Refs, vals)
+	if err != nil {
+		return nil, err
+	}
+	ctx := m.Ctx.SubQueryContext(sub)
+	subPlan, err := plan.WalkStmt(ctx, sub, plan.NewPlanner(ctx))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := runSubQuery(ctx, subPlan)
+	if err != nil {
+		return nil, err
+	}
+	g := m.group(key)
+	for _, row := range rows {
+		g.add(row, m.p.Exists)
+	}
+	return g, nil
+}
+
+func containsNil(vals []driver.Value) bool {
+	for _, v := range vals {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"fmt"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -131,15 +132,29 @@ func (m *Source) Run() error {
 
 	sigChan := m.SigChan()
 
+	// The connection took the limit, stop at it even if the scanner
+	// has more rows, closing the connection.
+	limit := 0
+	if m.p != nil {
+		limit = m.p.Limit
+	}
+	ct := 0
+
 	for item := m.Scanner.Next(); item != nil; item = m.Scanner.Next() {
 
+		waited := time.Now()
 		select {
 		case <-sigChan:
 			return nil
 		case m.msgOutCh <- item:
+			m.sent(waited)
 			// continue
 		}
 
+		ct++
+		if limit > 0 && ct >= limit {
+			return m.closeSource()
+		}
 	}
 	return nil
 }
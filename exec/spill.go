@@ -0,0 +1,139 @@
+package exec
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"encoding/gob"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultMemoryLimit is the default max bytes (estimated) a buffering
+	// task such as Distinct will hold in memory before spilling to disk.
+	// Override per query with plan.Context.MemoryLimit.
+	DefaultMemoryLimit = int64(64 * 1024 * 1024)
+)
+
+func init() {
+	// Value types that may be in a row, the basic types (string, int64,
+	// float64, bool, []byte) are already known to gob.
+	gob.Register(time.Time{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// spillRow is a single row written to a spill file, seq is the
+// original arrival order so spilled rows can be merged back in order.
+type spillRow struct {
+	Seq  uint64
+	Key  string
+	Vals []driver.Value
+}
+
+// spillFile a temp file of gob encoded rows, written sequentially then
+// read back sequentially after Rewind().
+type spillFile struct {
+	f   *os.File
+	w   *bufio.Writer
+	enc *gob.Encoder
+	ct  int
+}
+
+func newSpillFile(name string) (*spillFile, error) {
+	f, err := ioutil.TempFile("", "qlbridge-"+name)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &spillFile{f: f, w: w, enc: gob.NewEncoder(w)}, nil
+}
+
+func (m *spillFile) Write(row *spillRow) error {
+	m.ct++
+	return m.enc.Encode(row)
+}
+
+// Rewind flush writes and return a reader from start of the file.
+func (m *spillFile) Rewind() (*spillReader, error) {
+	if err := m.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := m.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &spillReader{dec: gob.NewDecoder(bufio.NewReader(m.f))}, nil
+}
+
+// Close and remove the file.
+func (m *spillFile) Close() error {
+	err := m.f.Close()
+	os.Remove(m.f.Name())
+	return err
+}
+
+type spillReader struct {
+	dec *gob.Decoder
+}
+
+// Next row, returns io.EOF when done.
+func (m *spillReader) Next() (*spillRow, error) {
+	row := &spillRow{}
+	if err := m.dec.Decode(row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// partitionSpill spilled rows, hash partitioned on their key such that
+// all rows of a key are in the same partition.  The salt varies the hash,
+// to re-partition the rows of a partition.
+type partitionSpill struct {
+	salt  byte
+	parts []*spillFile
+}
+
+func newPartitionSpill(name string, partCt int, salt byte) (*partitionSpill, error) {
+	sp := &partitionSpill{salt: salt, parts: make([]*spillFile, 0, partCt)}
+	for i := 0; i < partCt; i++ {
+		f, err := newSpillFile(name)
+		if err != nil {
+			sp.Close()
+			return nil, err
+		}
+		sp.parts = append(sp.parts, f)
+	}
+	return sp, nil
+}
+
+func (m *partitionSpill) Add(row *spillRow) error {
+	h := fnv.New32a()
+	h.Write([]byte{m.salt})
+	h.Write([]byte(row.Key))
+	return m.parts[h.Sum32()%uint32(len(m.parts))].Write(row)
+}
+
+// Close and remove all spill files.
+func (m *partitionSpill) Close() {
+	for _, part := range m.parts {
+		part.Close()
+	}
+	m.parts = nil
+}
+
+// rowMemSize rough estimate of the in-memory size of a row.
+func rowMemSize(vals []driver.Value) int64 {
+	n := int64(24 + 16*len(vals))
+	for _, v := range vals {
+		switch vt := v.(type) {
+		case string:
+			n += int64(len(vt))
+		case []byte:
+			n += int64(len(vt))
+		}
+	}
+	return n
+}
@@ -1,16 +1,12 @@
 package exec
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"errors"
 	"fmt"
 	"io"
-	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	u "github.com/araddon/gou"
 
@@ -18,17 +14,23 @@ import (
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
 	// Ensure our driver implements appropriate database/sql interfaces
-	_ driver.Conn    = (*qlbConn)(nil)
-	_ driver.Driver  = (*qlbdriver)(nil)
-	_ driver.Execer  = (*qlbConn)(nil)
-	_ driver.Queryer = (*qlbConn)(nil)
-	_ driver.Result  = (*qlbResult)(nil)
-	_ driver.Rows    = (*qlbRows)(nil)
-	_ driver.Stmt    = (*qlbStmt)(nil)
+	_ driver.Conn               = (*qlbConn)(nil)
+	_ driver.Driver             = (*qlbdriver)(nil)
+	_ driver.Execer             = (*qlbConn)(nil)
+	_ driver.Queryer            = (*qlbConn)(nil)
+	_ driver.ExecerContext      = (*qlbConn)(nil)
+	_ driver.QueryerContext     = (*qlbConn)(nil)
+	_ driver.ConnPrepareContext = (*qlbConn)(nil)
+	_ driver.Result             = (*qlbResult)(nil)
+	_ driver.Rows               = (*qlbRows)(nil)
+	_ driver.Stmt               = (*qlbStmt)(nil)
+	_ driver.StmtExecContext    = (*qlbStmt)(nil)
+	_ driver.StmtQueryContext   = (*qlbStmt)(nil)
 	//_ driver.Tx      = (*driverConn)(nil)
 
 	// Create an instance of our driver
@@ -113,21 +115,56 @@ type qlbConn struct {
 // Execer implementation. To be used for queries that do not return any rows
 // such as Create Index, Insert, Upset, Delete etc
 func (m *qlbConn) Exec(query string, args []driver.Value) (driver.Result, error) {
-	stmt := &qlbStmt{conn: m, query: query}
-	return stmt.Exec(args)
+	return m.ExecContext(context.Background(), query, namedValues(args))
+}
+
+// ExecContext is the ExecerContext implementation of Exec, cancelling ctx
+// stops the running statement.
+func (m *qlbConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args)
 }
 
 // Queryer implementation
 // Query may return ErrSkip
 //
 func (m *qlbConn) Query(query string, args []driver.Value) (driver.Rows, error) {
-	stmt := &qlbStmt{conn: m, query: query}
-	return stmt.Query(args)
+	return m.QueryContext(context.Background(), query, namedValues(args))
+}
+
+// QueryContext is the QueryerContext implementation of Query, cancelling
+// ctx stops the running query and its error is returned by the rows.
+func (m *qlbConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args)
 }
 
 // Prepare returns a prepared statement, bound to this connection.
+//
+// The statement is parsed once, its parameters (positional ? or numbered $1)
+// are bound to the arguments of each execution.
 func (m *qlbConn) Prepare(query string) (driver.Stmt, error) {
-	return nil, expr.ErrNotImplemented
+	return m.prepare(query)
+}
+
+// PrepareContext the ConnPrepareContext implementation of Prepare, parsing
+// is not cancelled.
+func (m *qlbConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return m.prepare(query)
+}
+
+func (m *qlbConn) prepare(query string) (*qlbStmt, error) {
+	stmt, err := rel.ParseSql(query)
+	if err != nil {
+		return nil, err
+	}
+	return &qlbStmt{conn: m, query: query, stmt: stmt, numInput: rel.NumParams(stmt)}, nil
 }
 
 // Close invalidates and potentially stops any current
@@ -162,9 +199,11 @@ func (conn *qlbTx) Rollback() error { return expr.ErrNotImplemented }
 // used by multiple goroutines concurrently.
 //
 type qlbStmt struct {
-	job   *JobExecutor
-	query string
-	conn  *qlbConn
+	job      *JobExecutor
+	query    string
+	stmt     rel.SqlStatement // parsed statement, unbound
+	numInput int              // number of parameters
+	conn     *qlbConn
 }
 
 // Close closes the statement.
@@ -187,96 +226,125 @@ func (m *qlbStmt) Close() error {
 // NumInput may also return -1, if the driver doesn't know
 // its number of placeholders. In that case, the sql package
 // will not sanity check Exec or Query argument counts.
-func (m *qlbStmt) NumInput() int { return 0 }
+func (m *qlbStmt) NumInput() int { return m.numInput }
+
+// newJob bind the args to a copy of the parsed statement and create a Job,
+// which is Dag of Tasks that Run(), cancelled by ctx.
+func (m *qlbStmt) newJob(ctx context.Context, args []driver.NamedValue) (*JobExecutor, error) {
+	vals := make([]value.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("named parameters are not supported: %q", arg.Name)
+		}
+		v := arg.Value
+		if by, ok := v.([]byte); ok {
+			v = string(by)
+		}
+		vals[i] = value.NewValue(v)
+	}
+	stmt, err := rel.BindParams(m.stmt, vals)
+	if err != nil {
+		return nil, err
+	}
+	planCtx := plan.NewContext(m.query)
+	planCtx.Context = ctx
+	planCtx.Schema = m.conn.schema
+	planCtx.Stmt = stmt
+	job, err := BuildSqlJob(planCtx)
+	if err != nil {
+		return nil, err
+	}
+	m.job = job
+	return job, nil
+}
 
 // Exec executes a query that doesn't return rows, such
 // as an INSERT, UPDATE, DELETE
 func (m *qlbStmt) Exec(args []driver.Value) (driver.Result, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
-	}
+	return m.ExecContext(context.Background(), namedValues(args))
+}
 
-	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
-	job, err := BuildSqlJob(ctx)
+// ExecContext the StmtExecContext implementation of Exec, cancelling ctx
+// (or reaching its deadline) stops the statement and returns its error.
+func (m *qlbStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	job, err := m.newJob(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	m.job = job
 
-	resultWriter := NewResultExecWriter(ctx)
+	resultWriter := NewResultExecWriter(job.Ctx)
 	job.RootTask.Add(resultWriter)
 
 	job.Setup()
 	//u.Infof("in qlbdriver.Exec about to run")
 	err = job.Run()
 	//u.Debugf("After qlb driver.Run() in Exec()")
+	// the job is done with its sources once run, close them before the
+	// statement is closed or executed again
+	if closeErr := job.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = job.Ctx.FirstError()
+	}
 	if err != nil {
-		u.Errorf("error on Query.Run(): %v", err)
-		//resultWriter.ErrChan() <- err
-		//job.Close()
+		return nil, err
 	}
 	return resultWriter.Result(), nil
 }
 
 // Query executes a query that may return rows, such as a SELECT
 func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
-	}
+	return m.QueryContext(context.Background(), namedValues(args))
+}
+
+// QueryContext the StmtQueryContext implementation of Query, the job runs
+// in the background until the rows are read or closed, cancelling ctx
+// (or reaching its deadline) stops it and the error is returned by the rows.
+func (m *qlbStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	u.Debugf("query: %v", m.query)
 
-	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
-	job, err := BuildSqlJob(ctx)
+	job, err := m.newJob(ctx, args)
 	if err != nil {
 		u.Warnf("return error? %v", err)
 		return nil, err
 	}
-	m.job = job
 
 	// The only type of stmt that makes sense for Query is SELECT
-	//  and we need list of columns that requires casing
-	sqlSelect, ok := job.Ctx.Stmt.(*rel.SqlSelect)
-	if !ok {
+	//  (or set operation of selects) and we need list of columns that requires casing
+	var cols []string
+	switch stmt := job.Ctx.Stmt.(type) {
+	case *rel.SqlSelect:
+		cols = stmt.Columns.AliasedFieldNames()
+	case *rel.SqlUnion:
+		unionCols := stmt.Columns()
+		cols = unionCols.AliasedFieldNames()
+	default:
 		u.Warnf("ctx? %v", job.Ctx)
 		return nil, fmt.Errorf("We could not recognize that as a select query: %T", job.Ctx.Stmt)
 	}
 
 	// Prepare a result writer, we manually append this task to end
 	// of job?
-	resultWriter := NewResultRows(ctx, sqlSelect.Columns.AliasedFieldNames())
+	resultWriter := NewResultRows(job.Ctx, cols)
 
 	job.RootTask.Add(resultWriter)
 
 	job.Setup()
 
-	// TODO:   this can't run in parallel-buffered mode?
-	// how to open in go-routine and still be able to send error to rows?
+	rows := &qlbRows{ResultWriter: resultWriter, job: job, done: make(chan struct{})}
 	go func() {
+		defer close(rows.done)
 		//u.Debugf("Start Job.Run")
-		err = job.Run()
-		//u.Debugf("After job.Run()")
-		if err != nil {
-			u.Errorf("error on Query.Run(): %v", err)
-			//resultWriter.ErrChan() <- err
-			//job.Close()
+		if err := job.Run(); err != nil {
+			// returned by the rows once they have been read
+			job.Ctx.AddError(err)
 		}
 		job.Close()
 		//u.Debugf("exiting Background Query")
 	}()
 
-	return resultWriter, nil
+	return rows, nil
 }
 
 // driver.ColumnConverter Interface implementation.
@@ -289,33 +357,48 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 // column index.  If the type of a specific column isn't known
 // or shouldn't be handled specially, DefaultValueConverter
 // can be returned.
-func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter { return nil }
+func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return driver.DefaultParameterConverter
+}
 
 // driver.Rows Interface implementation.
 //
-// Rows is an iterator over an executed query's results.
-//
-type qlbRows struct{}
-
-// Columns returns the names of the columns. The number of
-// columns of the result is inferred from the length of the
-// slice.  If a particular column name isn't known, an empty
-// string should be returned for that entry.
-func (conn *qlbRows) Columns() []string { return nil }
+// Rows is an iterator over an executed query's results, read from the
+// result writer of the running job.
+type qlbRows struct {
+	*ResultWriter
+	job  *JobExecutor
+	done chan struct{} // closed once the job has finished running
+}
 
-// Close closes the rows iterator.
-func (conn *qlbRows) Close() error { return expr.ErrNotImplemented }
+// Close closes the rows iterator, stopping the job and waiting for its
+// tasks to finish, closing its sources.
+func (m *qlbRows) Close() error {
+	err := m.job.Close()
+	<-m.done
+	return err
+}
 
 // Next is called to populate the next row of data into
 // the provided slice. The provided slice will be the same
 // size as the Columns() are wide.
 //
-// The dest slice may be populated only with
-// a driver Value type, but excluding string.
-// All string values must be converted to []byte.
-//
-// Next should return io.EOF when there are no more rows.
-func (conn *qlbRows) Next(dest []driver.Value) error { return expr.ErrNotImplemented }
+// Next should return io.EOF when there are no more rows, once the rows
+// are exhausted (or the job stopped) the error of running the job, if any,
+// is returned instead.
+func (m *qlbRows) Next(dest []driver.Value) error {
+	err := m.ResultWriter.Next(dest)
+	if err != io.EOF && err != ErrShuttingDown {
+		return err
+	}
+	// closing the result writer lets the rest of the job finish
+	m.ResultWriter.Close()
+	<-m.done
+	if jobErr := m.job.Ctx.FirstError(); jobErr != nil {
+		return jobErr
+	}
+	return err
+}
 
 // driver.Result Interface implementation.
 //
@@ -336,109 +419,12 @@ func (r *qlbResult) LastInsertId() (int64, error) { return r.lastId, r.err }
 // query.
 func (r *qlbResult) RowsAffected() (int64, error) { return r.affected, r.err }
 
-func join(a []string) string {
-	n := 0
-	for _, s := range a {
-		n += len(s)
-	}
-	b := make([]byte, n)
-	n = 0
-	for _, s := range a {
-		n += copy(b[n:], s)
-	}
-	return string(b)
-}
-
-func queryArgsConvert(query string, args []driver.Value) (string, error) {
-	if len(args) == 0 {
-		return query, nil
-	}
-	// a tiny, tiny, tiny bit of string sanitization
-	if strings.ContainsAny(query, `'"`) {
-		return "", nil
-	}
-	q := make([]string, 2*len(args)+1)
-	n := 0
-	for _, a := range args {
-		i := strings.IndexRune(query, '?')
-		if i == -1 {
-			return "", errors.New("number of parameters doesn't match number of placeholders")
-		}
-		var s string
-		switch v := a.(type) {
-		case nil:
-			s = "NULL"
-		case string:
-			s = "'" + escapeString(v) + "'"
-		case []byte:
-			s = "'" + escapeString(string(v)) + "'"
-		case int64:
-			s = strconv.FormatInt(v, 10)
-		case time.Time:
-			s = "'" + v.Format(MysqlTimeFormat) + "'"
-		case bool:
-			if v {
-				s = "1"
-			} else {
-				s = "0"
-			}
-		case float64:
-			s = strconv.FormatFloat(v, 'e', 12, 64)
-		default:
-			panic(fmt.Sprintf("%v (%T) can't be handled by godrv", v, v))
-		}
-		q[n] = query[:i]
-		q[n+1] = s
-		query = query[i+1:]
-		n += 2
-	}
-	q[n] = query
-	return join(q), nil
-}
-
-func escapeString(txt string) string {
-	var (
-		esc string
-		buf bytes.Buffer
-	)
-	last := 0
-	for ii, bb := range txt {
-		switch bb {
-		case 0:
-			esc = `\0`
-		case '\n':
-			esc = `\n`
-		case '\r':
-			esc = `\r`
-		case '\\':
-			esc = `\\`
-		case '\'':
-			esc = `\'`
-		case '"':
-			esc = `\"`
-		case '\032':
-			esc = `\Z`
-		default:
-			continue
-		}
-		io.WriteString(&buf, txt[last:ii])
-		io.WriteString(&buf, esc)
-		last = ii + 1
-	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
-}
-
-func escapeQuotes(txt string) string {
-	var buf bytes.Buffer
-	last := 0
-	for ii, bb := range txt {
-		if bb == '\'' {
-			io.WriteString(&buf, txt[last:ii])
-			io.WriteString(&buf, `''`)
-			last = ii + 1
-		}
+// namedValues the args of the legacy (non-context) interfaces as ordinal
+// named values.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
 	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
+	return named
 }
@@ -1,7 +1,14 @@
 package exec_test
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,7 +16,13 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/mockcsv"
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var _ = u.EMPTY
@@ -169,6 +182,110 @@ func TestSqlCsvDriverJoinWithWhere1(t *testing.T) {
 	assert.True(t, uo1.Price == 22.5, "? %#v", uo1)
 }
 
+func TestSqlCsvDriverOuterJoin(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql    string
+		users  int // rows with a user
+		orders int // rows with an order
+		rowCt  int
+	}{
+		// bob, notbob have no orders
+		{`SELECT u.user_id, o.order_id FROM users AS u
+			LEFT JOIN orders AS o ON u.user_id = o.user_id`, 4, 2, 4},
+		// order 3 has no user
+		{`SELECT u.user_id, o.order_id FROM users AS u
+			RIGHT OUTER JOIN orders AS o ON u.user_id = o.user_id`, 2, 3, 3},
+		{`SELECT u.user_id, o.order_id FROM users AS u
+			FULL OUTER JOIN orders AS o ON u.user_id = o.user_id`, 4, 3, 5},
+		// where on the null supplying side is evaluated after the join
+		{`SELECT u.user_id, o.order_id FROM users AS u
+			LEFT JOIN orders AS o ON u.user_id = o.user_id
+			WHERE o.price > 30`, 1, 1, 1},
+		{`SELECT u.user_id, o.order_id FROM users AS u
+			LEFT JOIN orders AS o ON u.user_id = o.user_id
+			WHERE u.email = "bob@email.com"`, 1, 0, 1},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		rowCt, userCt, orderCt := 0, 0, 0
+		for rows.Next() {
+			var userId, orderId sql.NullString
+			err = rows.Scan(&userId, &orderId)
+			assert.True(t, err == nil, "no error: %v", err)
+			rowCt++
+			if userId.Valid {
+				userCt++
+			}
+			if orderId.Valid {
+				orderCt++
+			}
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		assert.Equal(t, tt.rowCt, rowCt, tt.sql)
+		assert.Equal(t, tt.users, userCt, tt.sql)
+		assert.Equal(t, tt.orders, orderCt, tt.sql)
+	}
+}
+
+func TestSqlCsvDriverNonEquiJoin(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql   string
+		pairs []string
+	}{
+		{`SELECT u.user_id, o.order_id FROM users AS u CROSS JOIN orders AS o`, nil},
+		// composite key
+		{`SELECT a.order_id, b.price FROM orders AS a
+			INNER JOIN orders AS b ON a.user_id = b.user_id AND a.item_id = b.item_id`,
+			[]string{"1:22.50", "2:37.50", "3:22.50"}},
+		// key plus residual
+		{`SELECT a.order_id, b.price FROM orders AS a
+			INNER JOIN orders AS b ON a.user_id = b.user_id AND tonumber(a.price) < tonumber(b.price)`,
+			[]string{"1:37.50"}},
+		// no equality, nested loop
+		{`SELECT a.order_id, b.price FROM orders AS a
+			INNER JOIN orders AS b ON tonumber(a.price) < tonumber(b.price)`,
+			[]string{"1:37.50", "3:37.50"}},
+		{`SELECT a.order_id, b.price FROM orders AS a
+			INNER JOIN orders AS b ON tonumber(a.price) BETWEEN tonumber(b.price) AND 40`,
+			[]string{"2:22.50", "2:22.50"}},
+		{`SELECT a.order_id, b.price FROM orders AS a
+			LEFT JOIN orders AS b ON tonumber(a.price) < tonumber(b.price)`,
+			[]string{"1:37.50", "2:", "3:37.50"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		pairs := make([]string, 0)
+		for rows.Next() {
+			var l, r sql.NullString
+			err = rows.Scan(&l, &r)
+			assert.True(t, err == nil, "no error: %v", err)
+			pairs = append(pairs, l.String+":"+r.String)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		if tt.pairs == nil {
+			// cross join, 3 users x 3 orders
+			assert.Equal(t, 9, len(pairs), tt.sql)
+			continue
+		}
+		sort.Strings(pairs)
+		assert.Equal(t, tt.pairs, pairs, tt.sql)
+	}
+}
+
 func TestSqlCsvDriverJoinWithWhere2(t *testing.T) {
 
 	// Where Statement on join on column (o.item_count) that isn't in query
@@ -295,6 +412,193 @@ func TestSqlCsvDriverSubQuery(t *testing.T) {
 	// `
 }
 
+func TestSqlCsvDriverWhereSubQuery(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql    string
+		emails []string
+	}{
+		{`SELECT email FROM users WHERE user_id IN (SELECT user_id FROM orders)`,
+			[]string{"aaron@email.com"}},
+		{`SELECT email FROM users WHERE user_id NOT IN (SELECT user_id FROM orders)`,
+			[]string{"bob@email.com", "not_an_email_2"}},
+		{`SELECT email FROM users WHERE user_id IN (SELECT user_id FROM orders WHERE tonumber(price) > 30)`,
+			[]string{"aaron@email.com"}},
+		{`SELECT email FROM users
+			WHERE email != "bob@email.com" AND user_id NOT IN (SELECT user_id FROM orders)`,
+			[]string{"not_an_email_2"}},
+		// un-correlated exists
+		{`SELECT email FROM users WHERE EXISTS (SELECT order_id FROM orders WHERE item_id = "1")`,
+			[]string{"aaron@email.com", "bob@email.com", "not_an_email_2"}},
+		{`SELECT email FROM users WHERE NOT EXISTS (SELECT order_id FROM orders)`,
+			nil},
+		// correlated, de-correlated into a hash on orders.user_id
+		{`SELECT email FROM users
+			WHERE EXISTS (SELECT order_id FROM orders WHERE orders.user_id = users.user_id)`,
+			[]string{"aaron@email.com"}},
+		{`SELECT email FROM users
+			WHERE NOT EXISTS (SELECT order_id FROM orders WHERE users.user_id = orders.user_id)`,
+			[]string{"bob@email.com", "not_an_email_2"}},
+		// correlated non-equality, run per outer row
+		{`SELECT email FROM users
+			WHERE EXISTS (SELECT order_id FROM orders
+				WHERE orders.user_id = users.user_id AND tonumber(orders.price) * 2 > tonumber(users.referral_count))`,
+			nil},
+		{`SELECT email FROM users
+			WHERE EXISTS (SELECT order_id FROM orders
+				WHERE orders.user_id = users.user_id AND tonumber(orders.price) * 3 > tonumber(users.referral_count))`,
+			[]string{"aaron@email.com"}},
+		// sub-query on a join
+		{`SELECT u.email, o.order_id FROM users AS u
+			INNER JOIN orders AS o ON u.user_id = o.user_id
+			WHERE o.item_id IN (SELECT item_id FROM orders WHERE tonumber(price) > 30)`,
+			[]string{"aaron@email.com"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		cols, _ := rows.Columns()
+		var emails []string
+		for rows.Next() {
+			var email string
+			dest := []interface{}{&email}
+			for i := 1; i < len(cols); i++ {
+				var ignore interface{}
+				dest = append(dest, &ignore)
+			}
+			err = rows.Scan(dest...)
+			assert.True(t, err == nil, "no error: %v", err)
+			emails = append(emails, email)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(emails)
+		assert.Equal(t, tt.emails, emails, tt.sql)
+	}
+
+	// sub-queries must be a where predicate
+	_, err = db.Query(`SELECT email FROM users WHERE user_id = "x" OR user_id IN (SELECT user_id FROM orders)`)
+	assert.True(t, err != nil, "sub-query under OR not supported")
+}
+
+func TestSqlCsvDriverScalarSubQuery(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		rows []string
+	}{
+		// correlated, in select list
+		{`SELECT email, (SELECT count(*) FROM orders o WHERE o.user_id = u.user_id) AS n FROM users u`,
+			[]string{"aaron@email.com:2", "bob@email.com:0", "not_an_email_2:0"}},
+		// un-correlated, in select list
+		{`SELECT email, (SELECT count(*) FROM orders) AS n FROM users`,
+			[]string{"aaron@email.com:3", "bob@email.com:3", "not_an_email_2:3"}},
+		// in where
+		{`SELECT email, user_id FROM users u WHERE (SELECT count(*) FROM orders o WHERE o.user_id = u.user_id) > 1`,
+			[]string{"aaron@email.com:9Ip1aKbeZe2njCDM"}},
+		{`SELECT email, user_id FROM users WHERE user_id = (SELECT user_id FROM orders WHERE order_id = "1")`,
+			[]string{"aaron@email.com:9Ip1aKbeZe2njCDM"}},
+		// outer references in the group by, a join condition, a nested sub-query
+		{`SELECT email, (SELECT count(*) FROM orders o
+			GROUP BY o.user_id = u.user_id HAVING max(o.user_id) = u.user_id) AS n
+			FROM users u WHERE email = "aaron@email.com"`,
+			[]string{"aaron@email.com:2"}},
+		{`SELECT email, (SELECT count(*) FROM orders AS o
+			INNER JOIN orders AS o2 ON o2.order_id = o.order_id AND o2.user_id = u.user_id) AS n FROM users u`,
+			[]string{"aaron@email.com:2", "bob@email.com:0", "not_an_email_2:0"}},
+		{`SELECT email, (SELECT count(*) FROM orders o
+			WHERE o.order_id IN (SELECT order_id FROM orders o2 WHERE o2.user_id = u.user_id)) AS n FROM users u`,
+			[]string{"aaron@email.com:2", "bob@email.com:0", "not_an_email_2:0"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var email, n string
+			err = rows.Scan(&email, &n)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, email+":"+n)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		assert.Equal(t, tt.rows, got, tt.sql)
+	}
+
+	// order by the sub-query column
+	rows, err := db.Query(`SELECT email, (SELECT count(*) FROM orders o WHERE o.user_id = u.user_id) AS n
+		FROM users u ORDER BY n DESC`)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.True(t, rows.Next())
+	var email string
+	var n int64
+	assert.Equal(t, nil, rows.Scan(&email, &n))
+	assert.Equal(t, "aaron@email.com", email)
+	assert.Equal(t, int64(2), n)
+	rows.Close()
+
+	// more than one row is an error
+	rows, err = db.Query(`SELECT email, (SELECT order_id FROM orders o WHERE o.user_id = u.user_id) AS oid FROM users u`)
+	assert.True(t, err == nil, "no error: %v", err)
+	for rows.Next() {
+	}
+	assert.True(t, rows.Err() != nil, "scalar sub-query returning > 1 row must error")
+	rows.Close()
+}
+
+func TestSqlCsvDriverCase(t *testing.T) {
+
+	mockcsv.LoadTable(mockcsv.SchemaName, "shifts", "id,start,end\n1,1,5\n2,3,9")
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		rows []string
+	}{
+		// searched case
+		{`SELECT email, CASE WHEN referral_count > 50 THEN "high" WHEN referral_count > 10 THEN "mid" ELSE "low" END AS bucket FROM users`,
+			[]string{"aaron@email.com:high", "bob@email.com:mid", "not_an_email_2:mid"}},
+		// simple case, no else is NULL
+		{`SELECT email, CASE interests WHEN "fishing" THEN "water" WHEN "swimming" THEN "pool" END AS place FROM users`,
+			[]string{"aaron@email.com:water", "bob@email.com:pool", "not_an_email_2:"}},
+		// in where
+		{`SELECT email, user_id FROM users WHERE CASE WHEN referral_count > 50 THEN true ELSE false END`,
+			[]string{"aaron@email.com:9Ip1aKbeZe2njCDM"}},
+		// end, when ... are only keywords of a case, may be column names
+		{`SELECT id, end FROM shifts WHERE end > 6`, []string{"2:9"}},
+		{`SELECT b.id, b.end FROM shifts AS b WHERE b.end < 6`, []string{"1:5"}},
+		{`SELECT id, CASE WHEN b.end > 6 THEN "late" ELSE "early" END AS s FROM shifts AS b`,
+			[]string{"1:early", "2:late"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var email string
+			var v sql.NullString
+			err = rows.Scan(&email, &v)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, email+":"+v.String)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		assert.Equal(t, tt.rows, got, tt.sql)
+	}
+}
+
 func TestSqlDbConnFailure(t *testing.T) {
 	// Where Statement on join on column (o.item_count) that isn't in query
 	sqlText := `
@@ -374,3 +678,906 @@ func TestSqlDbConnFailure(t *testing.T) {
 	assert.True(t, uo1.Price == 22.5, "? %#v", uo1)
 	rows2.Close()
 }
+
+func TestSqlCsvDriverCte(t *testing.T) {
+
+	mockcsv.LoadTable(mockcsv.SchemaName, "org", "id,parent\n1,0\n2,1\n3,1\n4,2\n5,9")
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		rows []string
+	}{
+		{`WITH big AS (SELECT user_id, price FROM orders WHERE price > 10)
+			SELECT u.email, b.price FROM users AS u INNER JOIN big AS b ON u.user_id = b.user_id`,
+			[]string{"aaron@email.com:22.50", "aaron@email.com:37.50"}},
+		// referenced twice, and a cte referencing a cte before it
+		{`WITH o AS (SELECT user_id, item_id FROM orders), one AS (SELECT user_id, item_id FROM o WHERE item_id = 1)
+			SELECT a.user_id, b.item_id FROM one AS a INNER JOIN o AS b ON a.user_id = b.user_id`,
+			[]string{"9Ip1aKbeZe2njCDM:1", "9Ip1aKbeZe2njCDM:2", "abcabcabc:1"}},
+		// column names
+		{`WITH c (id, n) AS (SELECT user_id, count(*) FROM orders GROUP BY user_id)
+			SELECT id, n FROM c WHERE n > 1`,
+			[]string{"9Ip1aKbeZe2njCDM:2"}},
+		{`WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM nums WHERE n < 5)
+			SELECT "n", n FROM nums`,
+			[]string{"n:1", "n:2", "n:3", "n:4", "n:5"}},
+		// UNION (not ALL) stops once no new rows are found
+		{`WITH RECURSIVE c (n) AS (SELECT 1 UNION SELECT 1 FROM c) SELECT "n", n FROM c`,
+			[]string{"n:1"}},
+		// joined by name, without an alias
+		{`WITH a AS (SELECT user_id FROM users WHERE email = "aaron@email.com")
+			SELECT a.user_id, o.order_id FROM a INNER JOIN orders AS o ON a.user_id = o.user_id`,
+			[]string{"9Ip1aKbeZe2njCDM:1", "9Ip1aKbeZe2njCDM:2"}},
+		// self join
+		{`WITH a AS (SELECT order_id, user_id, price FROM orders)
+			SELECT a.order_id, b.price FROM a
+			INNER JOIN a AS b ON a.user_id = b.user_id AND tonumber(a.price) < tonumber(b.price)`,
+			[]string{"1:37.50"}},
+		// recursive, the step joined to the previous iteration
+		{`WITH RECURSIVE c (id, parent) AS (SELECT id, parent FROM org WHERE id = 1
+				UNION ALL SELECT t.id, t.parent FROM org AS t INNER JOIN c ON t.parent = c.id)
+			SELECT id, parent FROM c`,
+			[]string{"1:0", "2:1", "3:1", "4:2"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var a, b string
+			err = rows.Scan(&a, &b)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, a+":"+b)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		assert.Equal(t, tt.rows, got, tt.sql)
+	}
+
+	// never ending recursion is an error once past the recursion limit
+	limit := exec.DefaultRecursionLimit
+	exec.DefaultRecursionLimit = 3
+	defer func() { exec.DefaultRecursionLimit = limit }()
+	rows, err := db.Query(`WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM nums) SELECT "n", n FROM nums`)
+	assert.True(t, err == nil, "no error: %v", err)
+	for rows.Next() {
+	}
+	assert.True(t, rows.Err() != nil, "recursion past the limit must error")
+	rows.Close()
+}
+
+func TestSqlCsvDriverWindow(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		rows []string
+	}{
+		{`SELECT order_id, row_number() OVER (PARTITION BY user_id ORDER BY price DESC) AS rn FROM orders ORDER BY order_id`,
+			[]string{"1:2", "2:1", "3:1"}},
+		{`SELECT order_id, rank() OVER (ORDER BY price) AS r FROM orders ORDER BY order_id`,
+			[]string{"1:1", "2:3", "3:1"}},
+		{`SELECT order_id, dense_rank() OVER (ORDER BY price) AS r FROM orders ORDER BY order_id`,
+			[]string{"1:1", "2:2", "3:1"}},
+		// order by the window
+		{`SELECT order_id, row_number() OVER (ORDER BY order_id DESC) AS rn FROM orders ORDER BY rn`,
+			[]string{"3:1", "2:2", "1:3"}},
+		{`SELECT order_id, lag(price, 1, 0) OVER (ORDER BY order_id) AS prev FROM orders ORDER BY order_id`,
+			[]string{"1:0", "2:22.50", "3:37.50"}},
+		{`SELECT order_id, lead(order_id) OVER (ORDER BY order_id) AS next FROM orders ORDER BY order_id`,
+			[]string{"1:2", "2:3", "3:"}},
+		{`SELECT order_id, first_value(order_id) OVER (PARTITION BY user_id ORDER BY price DESC) AS f FROM orders ORDER BY order_id`,
+			[]string{"1:2", "2:2", "3:3"}},
+		// running sum
+		{`SELECT order_id, sum(price) OVER (ORDER BY order_id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS s
+			FROM orders ORDER BY order_id`,
+			[]string{"1:22.5", "2:60", "3:82.5"}},
+		{`SELECT order_id, count(*) OVER (ORDER BY order_id RANGE BETWEEN 1 PRECEDING AND 1 FOLLOWING) AS ct
+			FROM orders ORDER BY order_id`,
+			[]string{"1:2", "2:3", "3:2"}},
+		// default frame is up to the last peer of the row
+		{`SELECT order_id, avg(price) OVER (ORDER BY price) AS a FROM orders ORDER BY order_id`,
+			[]string{"1:22.5", "2:27.5", "3:22.5"}},
+		// filtered in an outer query
+		{`SELECT order_id, rn FROM (SELECT order_id, row_number() OVER (PARTITION BY user_id ORDER BY price DESC) AS rn FROM orders) AS o
+			WHERE rn = 1 ORDER BY order_id`,
+			[]string{"2:1", "3:1"}},
+		{`WITH r AS (SELECT order_id, row_number() OVER (PARTITION BY user_id ORDER BY price DESC) AS rn FROM orders)
+			SELECT order_id, rn FROM r WHERE rn = 1 ORDER BY order_id`,
+			[]string{"2:1", "3:1"}},
+		// over the grouped rows of an aggregate query
+		{`SELECT user_id, sum(price) AS total, rank() OVER (ORDER BY sum(price) DESC) AS r FROM orders GROUP BY user_id ORDER BY r`,
+			[]string{"9Ip1aKbeZe2njCDM:60:1", "abcabcabc:22.5:2"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		cols, _ := rows.Columns()
+		var got []string
+		for rows.Next() {
+			vals := make([]sql.NullString, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range vals {
+				dest[i] = &vals[i]
+			}
+			err = rows.Scan(dest...)
+			assert.True(t, err == nil, "no error: %v", err)
+			row := make([]string, len(vals))
+			for i, v := range vals {
+				row[i] = v.String
+			}
+			got = append(got, strings.Join(row, ":"))
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		assert.Equal(t, tt.rows, got, tt.sql)
+	}
+}
+
+func TestSqlCsvDriverGroupingSets(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	tests := []struct {
+		sql  string
+		rows []string
+	}{
+		// the rolled up columns are null
+		{`SELECT user_id, item_id, count(*), grouping(item_id) FROM orders GROUP BY ROLLUP (user_id, item_id)`,
+			[]string{"9Ip1aKbeZe2njCDM:1:1:0", "9Ip1aKbeZe2njCDM:2:1:0", "9Ip1aKbeZe2njCDM::2:1", "::3:1",
+				"abcabcabc:1:1:0", "abcabcabc::1:1"}},
+		{`SELECT user_id, item_id, count(*) FROM orders GROUP BY CUBE (user_id, item_id)`,
+			[]string{"9Ip1aKbeZe2njCDM:1:1", "9Ip1aKbeZe2njCDM:2:1", "9Ip1aKbeZe2njCDM::2", ":1:2", ":2:1", "::3",
+				"abcabcabc:1:1", "abcabcabc::1"}},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query(tt.sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		cols, _ := rows.Columns()
+		var got []string
+		for rows.Next() {
+			vals := make([]sql.NullString, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range vals {
+				dest[i] = &vals[i]
+			}
+			err = rows.Scan(dest...)
+			assert.True(t, err == nil, "no error: %v", err)
+			row := make([]string, len(vals))
+			for i, v := range vals {
+				row[i] = v.String
+			}
+			got = append(got, strings.Join(row, ":"))
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		assert.Equal(t, tt.rows, got, tt.sql)
+	}
+}
+
+func TestSqlCsvDriverExplain(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	explain := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		cols, err := rows.Columns()
+		assert.True(t, err == nil, "no error: %v", err)
+		assert.Equal(t, []string{"plan"}, cols)
+		var got []string
+		for rows.Next() {
+			var line string
+			err = rows.Scan(&line)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, line)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		return got
+	}
+
+	got := explain(`EXPLAIN SELECT user_id, price FROM orders WHERE price > 10 ORDER BY price`)
+	assert.Equal(t, []string{
+		"Select (sequential): SELECT user_id, price FROM orders WHERE price > 10 ORDER BY price",
+		"    Source: orders",
+		"        Where: price > 10",
+		"    Where: price > 10",
+		"    Order: price",
+		"    Projection: user_id, price",
+	}, got)
+
+	// the sides of a join are run in parallel
+	got = explain(`EXPLAIN SELECT u.email, o.price FROM users AS u INNER JOIN orders AS o ON u.user_id = o.user_id`)
+	assert.True(t, len(got) > 2, "%v", got)
+	assert.Equal(t, "    JoinMerge (parallel): hash ON u.user_id = o.user_id", got[1])
+
+	got = explain(`EXPLAIN FORMAT=JSON SELECT user_id, count(*) FROM orders GROUP BY user_id`)
+	assert.Equal(t, 1, len(got))
+	var e plan.ExplainTask
+	err = json.Unmarshal([]byte(got[0]), &e)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, "Select", e.Task)
+	assert.Equal(t, 2, len(e.Children))
+	assert.Equal(t, "Source", e.Children[0].Task)
+	assert.Equal(t, "GroupBy", e.Children[1].Task)
+	assert.Equal(t, "user_id", e.Children[1].Detail)
+
+	// grouping input sorted on the group by
+	got = explain(`EXPLAIN SELECT user_id, count(*) FROM (SELECT user_id FROM orders ORDER BY user_id) AS o GROUP BY user_id`)
+	assert.Equal(t, "    GroupBy: user_id (sorted)", got[len(got)-1], "%v", got)
+}
+
+func TestSqlCsvDriverExplainAnalyze(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	explain := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var line string
+			err = rows.Scan(&line)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, line)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		return got
+	}
+
+	// timings vary, so only the rows are compared
+	got := explain(`EXPLAIN ANALYZE SELECT user_id, price FROM orders WHERE price > 30 ORDER BY price`)
+	assert.Equal(t, 6, len(got), "%v", got)
+	for i, want := range []string{
+		"Select (sequential): SELECT user_id, price FROM orders WHERE price > 30 ORDER BY price  (rows in=0 out=1,",
+		"    Source: orders  (rows in=0 out=3,",
+		"        Where: price > 30  (rows in=3 out=1,",
+		"    Where: price > 30  (rows in=1 out=1,",
+		"    Order: price  (rows in=1 out=1,",
+		"    Projection: user_id, price  (rows in=1 out=1,",
+	} {
+		assert.True(t, strings.HasPrefix(got[i], want), "want %q got %q", want, got[i])
+		assert.True(t, strings.Contains(got[i], "wall="), "%q", got[i])
+	}
+
+	got = explain(`EXPLAIN ANALYZE FORMAT=JSON SELECT user_id, count(*) FROM orders GROUP BY user_id`)
+	assert.Equal(t, 1, len(got))
+	var e plan.ExplainTask
+	err = json.Unmarshal([]byte(got[0]), &e)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, 2, len(e.Children))
+	gb := e.Children[1]
+	assert.Equal(t, "GroupBy", gb.Task)
+	assert.True(t, gb.Stats != nil)
+	assert.Equal(t, int64(3), gb.Stats.RowsIn)
+	assert.Equal(t, int64(2), gb.Stats.RowsOut)
+	assert.True(t, gb.Stats.Wall > 0, "%v", gb.Stats)
+}
+
+func TestSqlCsvDriverPrepared(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	query := func(stmt *sql.Stmt, args ...interface{}) []string {
+		rows, err := stmt.Query(args...)
+		assert.True(t, err == nil, "no error: %v", err)
+		if err != nil {
+			return nil
+		}
+		var got []string
+		for rows.Next() {
+			var oid string
+			err = rows.Scan(&oid)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, oid)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		return got
+	}
+
+	// parsed once, executed with different args
+	stmt, err := db.Prepare(`SELECT order_id FROM orders WHERE user_id = ? AND price > ?`)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, []string{"1", "2"}, query(stmt, "9Ip1aKbeZe2njCDM", 10))
+	assert.Equal(t, []string{"2"}, query(stmt, "9Ip1aKbeZe2njCDM", 30.0))
+	assert.Equal(t, []string{"3"}, query(stmt, "abcabcabc", 0))
+	// a string arg is a value, never sql
+	assert.Equal(t, []string(nil), query(stmt, `x" OR user_id != "x`, 0))
+	// wrong number of args
+	_, err = stmt.Query("abcabcabc")
+	assert.True(t, err != nil, "must error on missing arg")
+	stmt.Close()
+
+	// numbered parameters may be re-used, in a sub-query too
+	stmt, err = db.Prepare(`SELECT order_id FROM orders WHERE item_id = $1
+		AND user_id IN (SELECT user_id FROM orders WHERE item_id = $1 AND price < $2)`)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, []string{"1", "3"}, query(stmt, 1, 30))
+	assert.Equal(t, []string(nil), query(stmt, 2, 30))
+	stmt.Close()
+
+	// without parameters, planning each execution must not change the
+	// prepared statement (its sub-query predicates are removed)
+	stmt, err = db.Prepare(`SELECT order_id FROM orders
+		WHERE user_id IN (SELECT user_id FROM users WHERE email != "x")`)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, []string{"1", "2"}, query(stmt))
+	assert.Equal(t, []string{"1", "2"}, query(stmt))
+	stmt.Close()
+	stmt, err = db.Prepare(`SELECT order_id FROM orders AS o
+		WHERE EXISTS (SELECT user_id FROM users AS u WHERE u.user_id = o.user_id AND u.email = "aaron@email.com")`)
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, []string{"1", "2"}, query(stmt))
+	assert.Equal(t, []string{"1", "2"}, query(stmt))
+	stmt.Close()
+
+	// mixing the kinds is a parse error
+	_, err = db.Prepare(`SELECT order_id FROM orders WHERE user_id = ? AND price > $1`)
+	assert.True(t, err != nil, "must not mix ? and $n")
+
+	// insert, bound per exec
+	mockcsv.LoadTable(mockcsv.SchemaName, "user_event4", "id,user_id,event\n1,abcabcabc,signup")
+	ins, err := db.Prepare(`INSERT INTO user_event4 (id, user_id, event) VALUES (?, ?, ?)`)
+	assert.True(t, err == nil, "no error: %v", err)
+	for _, id := range []string{"2", "3"} {
+		result, err := ins.Exec(id, "9Ip1aKbeZe2njCDM", "logon")
+		assert.True(t, err == nil, "no error: %v", err)
+		ct, _ := result.RowsAffected()
+		assert.Equal(t, int64(1), ct)
+	}
+	ins.Close()
+	conn, err := schema.OpenConn("mockcsv", "user_event4")
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.Equal(t, 3, conn.(*mockcsv.Table).Length())
+}
+
+func TestSqlCsvDriverContext(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	var buf strings.Builder
+	buf.WriteString("id,user_id\n")
+	for i := 0; i < 5000; i++ {
+		buf.WriteString(fmt.Sprintf("%d,user%d\n", i, i%10))
+	}
+	mockcsv.LoadTable(mockcsv.SchemaName, "ctx_events", buf.String())
+
+	// cancelled part way through the rows
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, err := db.QueryContext(ctx, `SELECT id FROM ctx_events WHERE user_id = ?`, "user1")
+	assert.True(t, err == nil, "no error: %v", err)
+	assert.True(t, rows.Next())
+	cancel()
+	n := 1
+	for rows.Next() {
+		n++
+	}
+	assert.Equal(t, context.Canceled, rows.Err())
+	assert.True(t, n < 500, "stopped early %d", n)
+	rows.Close()
+
+	// an expired deadline
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	_, err = db.ExecContext(ctx, `DELETE FROM ctx_events WHERE user_id = "user2"`)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestSqlCsvDriverAnalyze(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	var buf strings.Builder
+	buf.WriteString("id,kind_id,amount\n")
+	for i := 1; i <= 30; i++ {
+		buf.WriteString(fmt.Sprintf("%d,%d,%d\n", i, i%3, i*10))
+	}
+	mockcsv.LoadTable(mockcsv.SchemaName, "stat_orders", buf.String())
+	mockcsv.LoadTable(mockcsv.SchemaName, "stat_kinds", "id,name\n0,zero\n1,one\n2,two")
+
+	// the first scan of a just loaded mock table may miss rows, read each
+	// once so the statistics are of all of the rows
+	for _, tbl := range []string{"stat_orders", "stat_kinds"} {
+		var ct int64
+		err = db.QueryRow(`SELECT count(*) FROM ` + tbl).Scan(&ct)
+		assert.True(t, err == nil, "no error: %v", err)
+	}
+	for tbl, n := range map[string]int64{"stat_orders": 30, "stat_kinds": 3} {
+		res, err := db.Exec(`ANALYZE TABLE ` + tbl)
+		assert.True(t, err == nil, "no error: %v", err)
+		ct, err := res.RowsAffected()
+		assert.True(t, err == nil, "no error: %v", err)
+		assert.Equal(t, n, ct, tbl)
+	}
+
+	s, ok := schema.DefaultRegistry().Schema(mockcsv.SchemaName)
+	assert.True(t, ok)
+	tbl, err := s.Table("stat_orders")
+	assert.True(t, err == nil, "no error: %v", err)
+	stats := tbl.Stats()
+	assert.True(t, stats != nil)
+	assert.Equal(t, int64(30), stats.Rows)
+	kind := stats.FieldStats("kind_id")
+	assert.True(t, kind != nil)
+	assert.Equal(t, int64(3), kind.Distinct)
+	assert.Equal(t, int64(0), kind.Nulls)
+	amount := stats.FieldStats("amount")
+	assert.Equal(t, int64(30), amount.Distinct)
+	assert.Equal(t, int64(10), amount.Min.Value())
+	assert.Equal(t, int64(300), amount.Max.Value())
+
+	_, err = db.Exec(`ANALYZE TABLE not_a_table`)
+	assert.NotEqual(t, nil, err)
+
+	explain := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var line string
+			err = rows.Scan(&line)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, line)
+		}
+		rows.Close()
+		return got
+	}
+	count := func(sql string) int {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		return n
+	}
+
+	// the smaller side of the join is hashed, whichever side it is on
+	sql := `SELECT o.id, k.name FROM stat_kinds AS k INNER JOIN stat_orders AS o ON k.id = o.kind_id`
+	got := explain(`EXPLAIN ` + sql)
+	assert.True(t, len(got) > 2, "%v", got)
+	assert.Equal(t, "    JoinMerge (parallel): hash build left ON k.id = o.kind_id", got[1])
+	assert.Equal(t, 30, count(sql))
+
+	sql = `SELECT o.id, k.name FROM stat_orders AS o INNER JOIN stat_kinds AS k ON k.id = o.kind_id WHERE k.name = "one"`
+	got = explain(`EXPLAIN ` + sql)
+	assert.True(t, len(got) > 2, "%v", got)
+	assert.Equal(t, "    JoinMerge (parallel): hash ON k.id = o.kind_id", got[1])
+	assert.Equal(t, 10, count(sql))
+}
+
+// kvSource a key-value like source which filters its rows by equality of
+// a column to a string, leaving any other conditions to the where, only
+// reads the columns the query needs, stops at a limit, sorted by its key
+// if asked, and counts the rows of the groups of a column.
+type kvSource struct {
+	tbl       *schema.Table
+	rows      [][]driver.Value
+	projected []string // columns the last query read
+	returned  int64    // rows returned by the last query
+}
+
+func newKvSource() *kvSource {
+	tbl := schema.NewTable("kv_users")
+	for _, col := range []string{"user_id", "name", "city"} {
+		tbl.AddField(schema.NewFieldBase(col, value.StringType, 64, "string"))
+	}
+	tbl.SetColumnsFromFields()
+	return &kvSource{tbl: tbl, rows: [][]driver.Value{
+		{"1", "ann", "paris"},
+		{"2", "bob", "paris"},
+		{"3", "cat", "rome"},
+		{"4", "dan", "paris"},
+	}}
+}
+
+func (m *kvSource) Init()                                    {}
+func (m *kvSource) Setup(*schema.Schema) error               { return nil }
+func (m *kvSource) Close() error                             { return nil }
+func (m *kvSource) Tables() []string                         { return []string{m.tbl.Name} }
+func (m *kvSource) Table(name string) (*schema.Table, error) { return m.tbl, nil }
+func (m *kvSource) Open(name string) (schema.Conn, error) {
+	m.projected = nil
+	atomic.StoreInt64(&m.returned, 0)
+	return &kvConn{src: m, rows: m.rows, eq: make(map[int]string), group: -1}, nil
+}
+
+type kvConn struct {
+	src   *kvSource
+	rows  [][]driver.Value
+	eq    map[int]string
+	keep  map[int]bool
+	limit int
+	i     int
+	ct    int
+	group int // position of the group by column, -1 for a single group
+	aggs  []*expr.FuncNode
+}
+
+func (m *kvConn) Close() error      { return nil }
+func (m *kvConn) Columns() []string { return m.src.tbl.Columns() }
+func (m *kvConn) Filter(conds []expr.Node) []bool {
+	took := make([]bool, len(conds))
+	for i, cond := range conds {
+		bn, ok := cond.(*expr.BinaryNode)
+		if !ok || (bn.Operator.T != lex.TokenEqual && bn.Operator.T != lex.TokenEqualEqual) {
+			continue
+		}
+		in, isIdent := bn.Args[0].(*expr.IdentityNode)
+		sn, isString := bn.Args[1].(*expr.StringNode)
+		if !isIdent || !isString {
+			continue
+		}
+		_, col, _ := in.LeftRight()
+		if pos, ok := m.src.tbl.FieldPositions[col]; ok {
+			m.eq[pos] = sn.Text
+			took[i] = true
+		}
+	}
+	return took
+}
+func (m *kvConn) Project(cols []string) {
+	m.src.projected = cols
+	m.keep = make(map[int]bool, len(cols))
+	for _, col := range cols {
+		if pos, ok := m.src.tbl.FieldPositions[col]; ok {
+			m.keep[pos] = true
+		}
+	}
+}
+func (m *kvConn) LimitRows(n int, orderBy []expr.Node, desc []bool) bool {
+	if len(orderBy) > 0 {
+		in, ok := orderBy[0].(*expr.IdentityNode)
+		if len(orderBy) > 1 || !ok || in.Text != "user_id" {
+			return false
+		}
+		rows := append([][]driver.Value(nil), m.rows...)
+		sort.SliceStable(rows, func(i, j int) bool {
+			if desc[0] {
+				return rows[i][0].(string) > rows[j][0].(string)
+			}
+			return rows[i][0].(string) < rows[j][0].(string)
+		})
+		m.rows = rows
+	}
+	m.limit = n
+	return true
+}
+func (m *kvConn) Aggregate(groupBy []expr.Node, aggs []*expr.FuncNode) bool {
+	if len(groupBy) > 1 {
+		return false
+	}
+	if len(groupBy) == 1 {
+		in, ok := groupBy[0].(*expr.IdentityNode)
+		if !ok {
+			return false
+		}
+		pos, ok := m.src.tbl.FieldPositions[in.Text]
+		if !ok {
+			return false
+		}
+		m.group = pos
+	}
+	for _, fn := range aggs {
+		// count(*) or count(col), every column has a value
+		if strings.ToLower(fn.Name) != "count" || fn.Filter != nil || len(fn.Args) != 1 {
+			return false
+		}
+		switch arg := fn.Args[0].(type) {
+		case *expr.IdentityNode:
+		case *expr.StringNode:
+			if arg.Text != "*" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	m.aggs = aggs
+	return true
+}
+
+// groups replace the rows by a row per group, the group by value then the
+// partial count of each aggregate.
+func (m *kvConn) groups() {
+	var rows [][]driver.Value
+	idx := make(map[interface{}]int)
+rows:
+	for _, row := range m.rows {
+		for pos, v := range m.eq {
+			if row[pos] != v {
+				continue rows
+			}
+		}
+		var key driver.Value
+		if m.group >= 0 {
+			key = row[m.group]
+		}
+		i, ok := idx[key]
+		if !ok {
+			i = len(rows)
+			idx[key] = i
+			var group []driver.Value
+			if m.group >= 0 {
+				group = append(group, key)
+			}
+			for range m.aggs {
+				group = append(group, &expr.AggPartial{})
+			}
+			rows = append(rows, group)
+		}
+		for _, v := range rows[i][len(rows[i])-len(m.aggs):] {
+			v.(*expr.AggPartial).Ct++
+		}
+	}
+	m.rows, m.eq, m.keep, m.aggs = rows, nil, nil, nil
+}
+
+func (m *kvConn) Next() schema.Message {
+	if m.limit > 0 && m.ct >= m.limit {
+		return nil
+	}
+	if m.aggs != nil {
+		m.groups()
+	}
+rows:
+	for m.i < len(m.rows) {
+		row := m.rows[m.i]
+		m.i++
+		for pos, v := range m.eq {
+			if row[pos] != v {
+				continue rows
+			}
+		}
+		if m.keep != nil {
+			vals := make([]driver.Value, len(row))
+			for pos := range m.keep {
+				vals[pos] = row[pos]
+			}
+			row = vals
+		}
+		m.ct++
+		atomic.AddInt64(&m.src.returned, 1)
+		return datasource.NewSqlDriverMessageMap(uint64(m.i), row, m.src.tbl.FieldPositions)
+	}
+	return nil
+}
+
+func TestSqlCsvDriverFilterPushdown(t *testing.T) {
+
+	src := newKvSource()
+	err := schema.RegisterSourceAsSchema("kvtest", src)
+	assert.True(t, err == nil, "no error: %v", err)
+
+	db, err := sql.Open("qlbridge", "kvtest")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	query := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var line string
+			err = rows.Scan(&line)
+			assert.True(t, err == nil, "no error: %v", err)
+			got = append(got, line)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		return got
+	}
+
+	// the source takes the equality, the rest is evaluated on its rows
+	sql := `SELECT user_id FROM kv_users WHERE city = "paris" AND name != "bob"`
+	assert.Equal(t, []string{"1", "4"}, query(sql))
+	got := query(`EXPLAIN ` + sql)
+	explain := strings.Join(got, "\n")
+	assert.True(t, strings.Contains(explain, `Source: kv_users filter: city = "paris"`), explain)
+	assert.True(t, strings.Contains(explain, `Where: name != "bob"`), explain)
+	assert.True(t, !strings.Contains(explain, `Where: city`), explain)
+
+	sql = `SELECT user_id FROM kv_users WHERE city = "rome"`
+	assert.Equal(t, []string{"3"}, query(sql))
+	got = query(`EXPLAIN ` + sql)
+	explain = strings.Join(got, "\n")
+	assert.True(t, strings.Contains(explain, `Source: kv_users filter: city = "rome"`), explain)
+	assert.True(t, !strings.Contains(explain, "Where:"), explain)
+
+	// nothing it can take
+	sql = `SELECT user_id FROM kv_users WHERE name != "bob"`
+	assert.Equal(t, []string{"1", "3", "4"}, query(sql))
+	got = query(`EXPLAIN ` + sql)
+	explain = strings.Join(got, "\n")
+	assert.True(t, !strings.Contains(explain, "filter:"), explain)
+	assert.True(t, strings.Contains(explain, `Where: name != "bob"`), explain)
+}
+
+func TestSqlCsvDriverProjectionPushdown(t *testing.T) {
+
+	src := newKvSource()
+	err := schema.RegisterSourceAsSchema("kvtest_project", src)
+	assert.True(t, err == nil, "no error: %v", err)
+
+	db, err := sql.Open("qlbridge", "kvtest_project")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	sql := `SELECT name FROM kv_users WHERE city != "rome" ORDER BY user_id DESC`
+	rows, err := db.Query(sql)
+	assert.True(t, err == nil, "no error: %v", err)
+	var names []string
+	for rows.Next() {
+		var name string
+		assert.Equal(t, nil, rows.Scan(&name))
+		names = append(names, name)
+	}
+	rows.Close()
+	assert.Equal(t, []string{"dan", "bob", "ann"}, names)
+	assert.Equal(t, []string{"name", "city", "user_id"}, src.projected)
+
+	rows, err = db.Query(`EXPLAIN ` + sql)
+	assert.True(t, err == nil, "no error: %v", err)
+	var explain []string
+	for rows.Next() {
+		var line string
+		assert.Equal(t, nil, rows.Scan(&line))
+		explain = append(explain, line)
+	}
+	rows.Close()
+	assert.Contains(t, strings.Join(explain, "\n"), "Source: kv_users columns: name, city, user_id")
+}
+
+func TestSqlCsvDriverLimitPushdown(t *testing.T) {
+
+	src := newKvSource()
+	for i := 5; i <= 1000; i++ {
+		src.rows = append(src.rows, []driver.Value{fmt.Sprintf("%04d", i), "eve", "oslo"})
+	}
+	err := schema.RegisterSourceAsSchema("kvtest_limit", src)
+	assert.True(t, err == nil, "no error: %v", err)
+
+	db, err := sql.Open("qlbridge", "kvtest_limit")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	query := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		var got []string
+		for rows.Next() {
+			var line string
+			assert.Equal(t, nil, rows.Scan(&line))
+			got = append(got, line)
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		return got
+	}
+
+	explain := func(sql string) string {
+		return strings.Join(query("EXPLAIN "+sql), "\n")
+	}
+
+	// the source took the where, its rows are the result
+	sql := `SELECT name FROM kv_users WHERE city = "oslo" LIMIT 3`
+	assert.Equal(t, []string{"eve", "eve", "eve"}, query(sql))
+	assert.Equal(t, int64(3), atomic.LoadInt64(&src.returned))
+	assert.Contains(t, explain(sql), "limit: 3")
+
+	// top-n, sorted by the source
+	sql = `SELECT user_id FROM kv_users ORDER BY user_id DESC LIMIT 2`
+	assert.Equal(t, []string{"4", "3"}, query(sql))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&src.returned))
+	plan := explain(sql)
+	assert.Contains(t, plan, "limit: 2 order by: user_id DESC")
+	assert.NotContains(t, plan, "Order:")
+
+	// the source can't sort by name, the engine sorts all of the rows
+	sql = `SELECT user_id FROM kv_users ORDER BY name, user_id LIMIT 2`
+	assert.Equal(t, []string{"1", "2"}, query(sql))
+	assert.Equal(t, int64(1000), atomic.LoadInt64(&src.returned))
+	plan = explain(sql)
+	assert.NotContains(t, plan, "limit:")
+	assert.Contains(t, plan, "Order: name, user_id")
+
+	// rows are filtered after the source, it is shut down once the limit
+	// is reached rather than read to the end
+	sql = `SELECT user_id FROM kv_users WHERE name != "ann" LIMIT 2`
+	assert.Equal(t, []string{"2", "3"}, query(sql))
+	assert.True(t, atomic.LoadInt64(&src.returned) < 1000, "read %d rows", atomic.LoadInt64(&src.returned))
+	assert.NotContains(t, explain(sql), "limit:")
+}
+
+func TestSqlCsvDriverAggregatePushdown(t *testing.T) {
+
+	src := newKvSource()
+	err := schema.RegisterSourceAsSchema("kvtest_agg", src)
+	assert.True(t, err == nil, "no error: %v", err)
+
+	db, err := sql.Open("qlbridge", "kvtest_agg")
+	assert.True(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	// the rows as "col:col", sorted as groups are in no order
+	query := func(sql string) []string {
+		rows, err := db.Query(sql)
+		assert.True(t, err == nil, "no error: %v", err)
+		cols, _ := rows.Columns()
+		var got []string
+		for rows.Next() {
+			vals := make([]string, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range vals {
+				dest[i] = &vals[i]
+			}
+			assert.Equal(t, nil, rows.Scan(dest...))
+			got = append(got, strings.Join(vals, ":"))
+		}
+		assert.True(t, rows.Err() == nil, "no error: %v", rows.Err())
+		rows.Close()
+		sort.Strings(got)
+		return got
+	}
+	explain := func(sql string) string {
+		return strings.Join(query("EXPLAIN "+sql), "\n")
+	}
+
+	// the source counts each group, returning a row per group
+	sql := `SELECT city, count(*) AS ct FROM kv_users GROUP BY city`
+	assert.Equal(t, []string{"paris:3", "rome:1"}, query(sql))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&src.returned))
+	plan := explain(sql)
+	assert.Contains(t, plan, "Source: kv_users columns: city aggregate: count(*) group by: city")
+	assert.Contains(t, plan, "GroupBy: city (merge partials)")
+
+	// a single group, of the rows the source filtered
+	sql = `SELECT count(*) FROM kv_users WHERE city = "paris"`
+	assert.Equal(t, []string{"3"}, query(sql))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&src.returned))
+
+	// expressions over the aggregates, and those of the having
+	sql = `SELECT city, count(*) * 10 FROM kv_users GROUP BY city HAVING count(name) > 1`
+	assert.Equal(t, []string{"paris:30"}, query(sql))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&src.returned))
+	assert.Contains(t, explain(sql), "aggregate: count(*), count(name) group by: city")
+
+	// an aggregate the source can't compute, the engine aggregates its rows
+	sql = `SELECT city, max(name) FROM kv_users GROUP BY city`
+	assert.Equal(t, []string{"paris:dan", "rome:cat"}, query(sql))
+	assert.Equal(t, int64(4), atomic.LoadInt64(&src.returned))
+	assert.NotContains(t, explain(sql), "aggregate:")
+
+	// rows are filtered after the source, they are aggregated by the engine
+	sql = `SELECT city, count(*) FROM kv_users WHERE name != "bob" GROUP BY city`
+	assert.Equal(t, []string{"paris:2", "rome:1"}, query(sql))
+	assert.NotContains(t, explain(sql), "aggregate:")
+}
@@ -0,0 +1,276 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+// ScalarSubQuery evaluates a sub-query used as a value in an expression
+//
+//    SELECT name, (SELECT count(*) FROM orders o WHERE o.user_id = u.id) AS n FROM users u
+//
+// it is bound as the evaluator of the expr.SubQueryNode.  The sub-query is
+// run by its own JobExecutor, once if un-correlated, else per distinct value
+// of its outer references with results cached.  A sub-query returning no rows
+// is NULL, more than one row is an error recorded on the statement context.
+type ScalarSubQuery struct {
+	ctx   *plan.Context
+	p     *plan.ScalarSubQuery
+	mu    sync.Mutex
+	cache map[string]value.Value
+}
+
+// NewScalarSubQuery create the evaluator for a scalar sub-query of the
+// statement of ctx.
+func NewScalarSubQuery(ctx *plan.Context, p *plan.ScalarSubQuery) *ScalarSubQuery {
+	return &ScalarSubQuery{
+		ctx:   ctx,
+		p:     p,
+		cache: make(map[string]value.Value),
+	}
+}
+
+// Eval evaluate the sub-query for the current row, implements expr.SubQueryEvaluator
+func (m *ScalarSubQuery) Eval(ctx expr.EvalContext) (value.Value, bool) {
+	v, err := m.eval(ctx)
+	if err != nil {
+		u.Warnf("scalar sub-query error %v", err)
+		m.ctx.AddError(err)
+		return nil, false
+	}
+	return v, true
+}
+
+func (m *ScalarSubQuery) eval(ctx expr.EvalContext) (value.Value, error) {
+
+	vals := make([]value.Value, len(m.p.Refs))
+	keyVals := make([]driver.Value, len(m.p.Refs))
+	for i, ref := range m.p.Refs {
+		if v, ok := vm.Eval(ctx, ref); ok && v != nil {
+			vals[i] = v
+			keyVals[i] = v.Value()
+		}
+	}
+	key := rowHashKey(keyVals)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.cache[key]; ok {
+		return v, nil
+	}
+
+	subCtx, subPlan := m.p.Ctx, m.p.SubPlan
+	if subPlan == nil {
+		sub, err := substituteRefs(m.p.Sub, m.p.Refs, vals)
+		if err != nil {
+			return nil, err
+		}
+		subCtx = m.ctx.SubQueryContext(sub)
+		if subPlan, err = plan.WalkStmt(subCtx, sub, plan.NewPlanner(subCtx)); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := runSubQuery(subCtx, subPlan)
+	if err != nil {
+		return nil, err
+	}
+
+	var v value.Value
+	switch len(rows) {
+	case 0:
+		v = value.NewNilValue()
+	case 1:
+		if len(rows[0]) != 1 {
+			return nil, fmt.Errorf("scalar sub-query must return exactly one column: %s", m.p.Sub)
+		}
+		v = value.NewValue(rows[0][0])
+	default:
+		return nil, fmt.Errorf("scalar sub-query returned more than one row: %s", m.p.Sub)
+	}
+	m.cache[key] = v
+	return v, nil
+}
+
+// substituteRefs create a copy of the correlated sub-query with its outer
+// references replaced by the literal values from the current outer row.
+func substituteRefs(sub *rel.SqlSelect, refs []*expr.IdentityNode, vals []value.Value) (*rel.SqlSelect, error) {
+	literals := make(map[string]expr.Node, len(refs))
+	for i, ref := range refs {
+		literals[ref.Text] = literalNode(vals[i])
+	}
+	cp, err := rel.ParseSqlSelect(sub.String())
+	if err != nil {
+		return nil, err
+	}
+	replaceStmtRefs(cp, literals)
+	// re-parse so the statement is as if written with the literals
+	return rel.ParseSqlSelect(cp.String())
+}
+
+// replaceStmtRefs replace the outer references in each clause of a
+// statement, and of the sub-queries nested in it, see plan allOuterRefs.
+// A statement's own sources hide outer ones of the same name.
+func replaceStmtRefs(stmt *rel.SqlSelect, literals map[string]expr.Node) {
+	visible := make(map[string]expr.Node, len(literals))
+	for text, lit := range literals {
+		left, _, _ := expr.LeftRight(text)
+		if !isSourceOf(stmt, left) {
+			visible[text] = lit
+		}
+	}
+	for _, cols := range []rel.Columns{stmt.Columns, stmt.GroupBy, stmt.OrderBy} {
+		for _, col := range cols {
+			if col.Expr != nil {
+				col.Expr = replaceIdentities(col.Expr, visible)
+			}
+		}
+	}
+	if stmt.Where != nil {
+		if stmt.Where.Expr != nil {
+			stmt.Where.Expr = replaceIdentities(stmt.Where.Expr, visible)
+		}
+		if stmt.Where.Source != nil {
+			replaceStmtRefs(stmt.Where.Source, visible)
+		}
+	}
+	for _, from := range stmt.From {
+		if from.JoinExpr != nil {
+			from.JoinExpr = replaceIdentities(from.JoinExpr, visible)
+		}
+		if from.SubQuery != nil {
+			replaceStmtRefs(from.SubQuery, visible)
+		}
+	}
+	if stmt.Having != nil {
+		stmt.Having = replaceIdentities(stmt.Having, visible)
+	}
+}
+
+// isSourceOf is name (case-insensitive) a name or alias of a source of stmt.
+func isSourceOf(stmt *rel.SqlSelect, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, from := range stmt.From {
+		if strings.EqualFold(from.Name, name) || strings.EqualFold(from.Alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func replaceIdentities(node expr.Node, literals map[string]expr.Node) expr.Node {
+	switch n := node.(type) {
+	case *expr.IdentityNode:
+		if lit, ok := literals[n.Text]; ok {
+			return lit
+		}
+	case *expr.SubQueryNode:
+		if sel, ok := n.Stmt.(*rel.SqlSelect); ok {
+			replaceStmtRefs(sel, literals)
+		}
+	case *expr.UnaryNode:
+		n.Arg = replaceIdentities(n.Arg, literals)
+	case *expr.CaseNode:
+		n.Operand = replaceIdentities(n.Operand, literals)
+		for i := range n.Whens {
+			n.Whens[i] = replaceIdentities(n.Whens[i], literals)
+			n.Thens[i] = replaceIdentities(n.Thens[i], literals)
+		}
+		n.Else = replaceIdentities(n.Else, literals)
+	case *expr.WindowNode:
+		for _, args := range [][]expr.Node{n.Func.Args, n.Partition, n.OrderBy} {
+			for i, arg := range args {
+				args[i] = replaceIdentities(arg, literals)
+			}
+		}
+	case expr.NodeArgs:
+		args := n.ChildrenArgs()
+		for i, arg := range args {
+			args[i] = replaceIdentities(arg, literals)
+		}
+	}
+	return node
+}
+
+// literalNode create the expression literal for a value.
+func literalNode(v value.Value) expr.Node {
+	if v == nil || v.Nil() {
+		return expr.NewNull(lex.Token{T: lex.TokenNull, V: "NULL"})
+	}
+	switch vt := v.(type) {
+	case value.IntValue, value.NumberValue:
+		if nn, err := expr.NewNumberStr(vt.ToString()); err == nil {
+			return nn
+		}
+	case value.BoolValue:
+		return expr.NewIdentityNodeVal(vt.ToString())
+	}
+	return expr.NewStringNode(v.ToString())
+}
+
+// runSubQuery run a planned sub-query to completion and return its rows.
+func runSubQuery(ctx *plan.Context, p plan.Task) ([][]driver.Value, error) {
+	_, rows, err := runPlan(ctx, p)
+	return rows, err
+}
+
+// runPlan run a plan to completion, returning the job that ran it (ie for
+// its statistics) and its rows.
+func runPlan(ctx *plan.Context, p plan.Task) (*JobExecutor, [][]driver.Value, error) {
+
+	job := NewExecutor(ctx, plan.NewPlanner(ctx))
+	task, err := job.WalkPlan(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, ok := task.(TaskRunner)
+	if !ok {
+		return nil, nil, fmt.Errorf("Expected TaskRunner but was %T", task)
+	}
+	job.RootTask = root
+
+	msgs := make([]schema.Message, 0)
+	if err = root.Add(NewResultBuffer(ctx, &msgs)); err != nil {
+		return nil, nil, err
+	}
+	if err = job.Setup(); err != nil {
+		return nil, nil, err
+	}
+	err = job.Run()
+	job.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = ctx.FirstError(); err != nil {
+		// ie a nested scalar sub-query error
+		return nil, nil, err
+	}
+
+	rows := make([][]driver.Value, 0, len(msgs))
+	for _, msg := range msgs {
+		switch mt := msg.Body().(type) {
+		case *datasource.SqlDriverMessageMap:
+			rows = append(rows, mt.Values())
+		case []driver.Value:
+			rows = append(rows, mt)
+		default:
+			u.Warnf("unrecognized sub-query msg %T", mt)
+			return nil, nil, fmt.Errorf("unrecognized sub-query message %T", mt)
+		}
+	}
+	return job, rows, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -32,6 +34,17 @@ type TaskBase struct {
 	errCh    ErrChan
 	sigCh    SigChan // notify of quit/stop
 	errors   []error
+	stats    TaskStats
+}
+
+// TaskStats the execution statistics of a task, counted as it runs so
+// they are complete once Run() of its job has returned.
+type TaskStats struct {
+	RowsIn       int64         // messages received
+	RowsOut      int64         // messages sent downstream
+	Wall         time.Duration // time from start to end of Run()
+	Blocked      time.Duration // time waiting on input, or for a full output channel
+	PeakBuffered int64         // most messages held by the task or on its output channel
 }
 
 func NewTaskBase(ctx *plan.Context) *TaskBase {
@@ -97,11 +110,74 @@ func (m *TaskBase) Close() error {
 }
 func (m *TaskBase) CloseFinal() error { return nil }
 
+// Stats the execution statistics of this task.
+func (m *TaskBase) Stats() TaskStats {
+	return TaskStats{
+		RowsIn:       atomic.LoadInt64(&m.stats.RowsIn),
+		RowsOut:      atomic.LoadInt64(&m.stats.RowsOut),
+		Wall:         time.Duration(atomic.LoadInt64((*int64)(&m.stats.Wall))),
+		Blocked:      time.Duration(atomic.LoadInt64((*int64)(&m.stats.Blocked))),
+		PeakBuffered: atomic.LoadInt64(&m.stats.PeakBuffered),
+	}
+}
+
+// received record a message received, after waiting since waited.  A nil
+// message, the input closing, is not a row.
+func (m *TaskBase) received(waited time.Time, msg schema.Message) {
+	atomic.AddInt64((*int64)(&m.stats.Blocked), int64(time.Since(waited)))
+	if msg != nil {
+		atomic.AddInt64(&m.stats.RowsIn, 1)
+	}
+}
+
+// sent record a message sent downstream, after waiting since waited.
+func (m *TaskBase) sent(waited time.Time) {
+	atomic.AddInt64((*int64)(&m.stats.Blocked), int64(time.Since(waited)))
+	atomic.AddInt64(&m.stats.RowsOut, 1)
+	m.buffered(len(m.msgOutCh))
+}
+
+// buffered record the number of messages the task is holding.
+func (m *TaskBase) buffered(n int) {
+	for {
+		peak := atomic.LoadInt64(&m.stats.PeakBuffered)
+		if int64(n) <= peak || atomic.CompareAndSwapInt64(&m.stats.PeakBuffered, peak, int64(n)) {
+			return
+		}
+	}
+}
+
+func (m *TaskBase) ran(wall time.Duration) {
+	atomic.StoreInt64((*int64)(&m.stats.Wall), int64(wall))
+}
+
+// taskStatser a task keeping statistics, all tasks embedding TaskBase.
+type taskStatser interface {
+	Stats() TaskStats
+	sent(waited time.Time)
+	ran(wall time.Duration)
+}
+
+// runTask run a task, recording how long it ran.
+func runTask(task TaskRunner) error {
+	start := time.Now()
+	err := task.Run()
+	if ts, ok := task.(taskStatser); ok {
+		ts.ran(time.Since(start))
+	}
+	return err
+}
+
 func MakeHandler(task TaskRunner) MessageHandler {
 	out := task.MessageOut()
+	ts, _ := task.(taskStatser)
 	return func(ctx *plan.Context, msg schema.Message) bool {
+		waited := time.Now()
 		select {
 		case out <- msg:
+			if ts != nil {
+				ts.sent(waited)
+			}
 			return true
 		case <-task.SigChan():
 			return false
@@ -140,8 +216,10 @@ msgLoop:
 		}
 
 		//
+		waited := time.Now()
 		select {
 		case msg, ok = <-m.msgInCh:
+			m.received(waited, msg)
 			if ok {
 				//u.Debugf("sending to handler: %T  %+v", msg, msg)
 				m.Handler(m.Ctx, msg)
@@ -135,9 +135,10 @@ func (m *TaskParallel) Run() error {
 		go func(taskId int) {
 			task := m.runners[taskId]
 			//u.Infof("starting task %d-%d %T in:%p  out:%p", m.depth, taskId, task, task.MessageIn(), task.MessageOut())
-			if err := task.Run(); err != nil {
+			if err := runTask(task); err != nil {
 				u.Errorf("%T.Run() errored %v", task, err)
-				// TODO:  what do we do with this error?   send to error channel?
+				// recorded on the context, to be returned by the results
+				m.Ctx.AddError(err)
 			}
 			//u.Debugf("exiting taskId: %v %T", taskId, task)
 			wg.Done()
@@ -115,6 +115,22 @@ func (m *TaskSequential) Add(task Task) error {
 
 func (m *TaskSequential) Children() []Task { return m.tasks }
 
+// Stats the execution statistics of the sequence, its rows are those into
+// the first task and out of the last.
+func (m *TaskSequential) Stats() TaskStats {
+	stats := m.TaskBase.Stats()
+	if len(m.runners) == 0 {
+		return stats
+	}
+	if first, ok := m.runners[0].(taskStatser); ok {
+		stats.RowsIn = first.Stats().RowsIn
+	}
+	if last, ok := m.runners[len(m.runners)-1].(taskStatser); ok {
+		stats.RowsOut = last.Stats().RowsOut
+	}
+	return stats
+}
+
 func (m *TaskSequential) Run() (err error) {
 	defer m.Ctx.Recover() // Our context can recover panics, save error msg
 	defer func() {
@@ -148,7 +164,7 @@ func (m *TaskSequential) Run() (err error) {
 		go func(taskId int) {
 			task := m.runners[taskId]
 			//u.Infof("starting task %d-%d %T in:%p  out:%p", m.depth, taskId, task, task.MessageIn(), task.MessageOut())
-			if taskErr := task.Run(); taskErr != nil {
+			if taskErr := runTask(task); taskErr != nil {
 				u.Errorf("%T.Run() errored %v", task, taskErr)
 				// TODO:  what do we do with this error?   send to error channel?
 				err = taskErr
@@ -0,0 +1,179 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Union)(nil)
+)
+
+// Union combines the output of 2 tasks using a set operation
+// (UNION, INTERSECT, EXCEPT).
+//
+//	left   ->
+//	          \
+//	            --  union  -->
+//	          /
+//	right  ->
+//
+//   - UNION ALL streams rows from left, then right without buffering
+//   - UNION de-duplicates using a hash of each row
+//   - INTERSECT, EXCEPT hash the right side, then stream the left side
+//     filtered by it, the ALL variants keep duplicate counts.
+type Union struct {
+	*TaskBase
+	p        *plan.Union
+	ltask    TaskRunner
+	rtask    TaskRunner
+	colIndex map[string]int
+	rowId    uint64
+}
+
+// NewUnion create set operation task reading from left, right tasks.
+func NewUnion(ctx *plan.Context, l, r TaskRunner, p *plan.Union) *Union {
+	return &Union{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		ltask:    l,
+		rtask:    r,
+		colIndex: p.ColIndex,
+	}
+}
+
+// Run the set operation
+func (m *Union) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	leftIn := m.ltask.MessageOut()
+	rightIn := m.rtask.MessageOut()
+
+	switch m.p.Stmt.Op {
+	case lex.TokenUnion:
+		if m.p.Stmt.All {
+			emit := func(vals []driver.Value) bool { return m.send(vals) }
+			if err := m.readAll(leftIn, emit); err != nil {
+				return err
+			}
+			return m.readAll(rightIn, emit)
+		}
+		seen := make(map[string]struct{})
+		emit := func(vals []driver.Value) bool {
+			key := rowHashKey(vals)
+			if _, exists := seen[key]; exists {
+				return true
+			}
+			seen[key] = struct{}{}
+			return m.send(vals)
+		}
+		if err := m.readAll(leftIn, emit); err != nil {
+			return err
+		}
+		return m.readAll(rightIn, emit)
+
+	case lex.TokenIntersect, lex.TokenExcept:
+		rightCts := make(map[string]int)
+		err := m.readAll(rightIn, func(vals []driver.Value) bool {
+			rightCts[rowHashKey(vals)]++
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		intersect := m.p.Stmt.Op == lex.TokenIntersect
+		emitted := make(map[string]struct{})
+		return m.readAll(leftIn, func(vals []driver.Value) bool {
+			key := rowHashKey(vals)
+			ct := rightCts[key]
+			if m.p.Stmt.All {
+				if ct > 0 {
+					rightCts[key] = ct - 1
+				}
+				if intersect == (ct > 0) {
+					return m.send(vals)
+				}
+				return true
+			}
+			if _, exists := emitted[key]; exists {
+				return true
+			}
+			if intersect == (ct > 0) {
+				emitted[key] = struct{}{}
+				return m.send(vals)
+			}
+			return true
+		})
+	}
+	return fmt.Errorf("unsupported set operation %q", m.p.Stmt.Op)
+}
+
+// readAll read all messages from input channel until closed or quit,
+// passing the row values to handler.
+func (m *Union) readAll(inCh MessageChan, handler func(vals []driver.Value) bool) error {
+	for {
+		waited := time.Now()
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-inCh:
+			m.received(waited, msg)
+			if !ok {
+				return nil
+			}
+			if msg == nil {
+				// nil is a shutdown notice from a limit projection
+				continue
+			}
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessageMap:
+				if !handler(mt.Values()) {
+					return nil
+				}
+			default:
+				u.Errorf("unrecognized msg %T", msg)
+				return fmt.Errorf("To use %s must use SqlDriverMessageMap but got %T", m.p.Stmt.Op, msg)
+			}
+		}
+	}
+}
+
+func (m *Union) send(vals []driver.Value) bool {
+	row := make([]driver.Value, len(m.colIndex))
+	copy(row, vals)
+	m.rowId++
+	waited := time.Now()
+	select {
+	case m.msgOutCh <- datasource.NewSqlDriverMessageMap(m.rowId, row, m.colIndex):
+		m.sent(waited)
+		return true
+	case <-m.SigChan():
+		return false
+	}
+}
+
+// rowHashKey create a key for the row such that rows with equal
+// values (ie int 1 and float 1.0) are the same key.  Used by the
+// set operations and distinct to de-dupe rows.
+func rowHashKey(vals []driver.Value) string {
+	keys := make([]string, len(vals))
+	for i, v := range vals {
+		if v == nil {
+			keys[i] = string(byte(1))
+			continue
+		}
+		keys[i] = value.NewValue(v).ToString()
+	}
+	return strings.Join(keys, string(byte(0)))
+}
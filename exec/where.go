@@ -1,6 +1,8 @@
 package exec
 
 import (
+	"time"
+
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
@@ -25,6 +27,10 @@ func NewWhere(ctx *plan.Context, p *plan.Where) *Where {
 	if p.Final {
 		return NewWhereFinal(ctx, p)
 	}
+	if p.Residual != nil {
+		// the source took some of the where
+		return newWhereFilter(ctx, p.Stmt, p.Residual)
+	}
 	return NewWhereFilter(ctx, p.Stmt)
 }
 
@@ -34,6 +40,9 @@ func NewWhereFinal(ctx *plan.Context, p *plan.Where) *Where {
 		sel:      p.Stmt,
 		filter:   p.Stmt.Where.Expr,
 	}
+	if p.Residual != nil {
+		s.filter = p.Residual
+	}
 	cols := make(map[string]int)
 
 	if len(p.Stmt.From) == 1 {
@@ -59,7 +68,7 @@ func NewWhereFinal(ctx *plan.Context, p *plan.Where) *Where {
 
 	//u.Debugf("found where columns: %d", len(cols))
 
-	s.Handler = whereFilter(s.filter, s, cols)
+	s.Handler = whereFilter(s.filter, s, cols, 0)
 	return s
 }
 
@@ -67,25 +76,54 @@ func NewWhereFinal(ctx *plan.Context, p *plan.Where) *Where {
 func NewWhereFilter(ctx *plan.Context, sql *rel.SqlSelect) *Where {
 	s := &Where{
 		TaskBase: NewTaskBase(ctx),
-		filter:   sql.Where.Expr,
 	}
-	cols := sql.ColIndexes()
-	s.Handler = whereFilter(s.filter, s, cols)
+	if sql.Where == nil || sql.Where.Expr == nil {
+		// The where was only sub-query predicates, which are evaluated
+		// by semi-joins after this.
+		s.Handler = MakeHandler(s)
+		return s
+	}
+	return newWhereFilter(ctx, sql, sql.Where.Expr)
+}
+
+func newWhereFilter(ctx *plan.Context, sql *rel.SqlSelect, filter expr.Node) *Where {
+	s := &Where{
+		TaskBase: NewTaskBase(ctx),
+		filter:   filter,
+	}
+	s.Handler = whereFilter(s.filter, s, sql.ColIndexes(), 0)
 	return s
 }
 
-// NewHaving Filter
+// NewHaving Filter of the rows of a group by, its aggregates are those of
+// the select columns, or hidden columns after them which are dropped.
 func NewHaving(ctx *plan.Context, p *plan.Having) *Where {
 	s := &Where{
 		TaskBase: NewTaskBase(ctx),
-		filter:   p.Stmt.Having,
+		filter:   havingFilter(p.Stmt),
+	}
+	width := 0
+	if len(havingAggs(p.Stmt)) > 0 {
+		width = len(p.Stmt.Columns)
 	}
-	s.Handler = whereFilter(p.Stmt.Having, s, p.Stmt.ColIndexes())
+	s.Handler = whereFilter(s.filter, s, groupByColIndex(p.Stmt), width)
 	return s
 }
 
-func whereFilter(filter expr.Node, task TaskRunner, cols map[string]int) MessageHandler {
+// whereFilter the handler of a filter, only rows where it is true are sent,
+// of width > 0 the rows sent are only the first width values.
+func whereFilter(filter expr.Node, task TaskRunner, cols map[string]int, width int) MessageHandler {
 	out := task.MessageOut()
+	ts, _ := task.(taskStatser)
+	var widthCols map[string]int
+	if width > 0 {
+		widthCols = make(map[string]int, width)
+		for name, i := range cols {
+			if i < width {
+				widthCols[name] = i
+			}
+		}
+	}
 
 	//u.Debugf("prepare filter %s", filter)
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -136,9 +174,17 @@ func whereFilter(filter expr.Node, task TaskRunner, cols map[string]int) Message
 			}
 		}
 
+		if mm, ok := msg.(*datasource.SqlDriverMessageMap); ok && width > 0 && len(mm.Vals) > width {
+			mm.Vals, mm.ColIndex = mm.Vals[:width], widthCols
+		}
+
 		//u.Debugf("about to send from where to forward: %#v", msg)
+		waited := time.Now()
 		select {
 		case out <- msg:
+			if ts != nil {
+				ts.sent(waited)
+			}
 			return true
 		case <-task.SigChan():
 			return false
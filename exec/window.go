@@ -0,0 +1,486 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+// Window computes the window functions, fn() OVER (...), of a select.  As a
+// window function sees every row of its partition all rows are held in
+// memory, then each row is sent on with the result of each window added
+// under the window's text so the order by and projection can read it.
+//
+//	SELECT user_id, row_number() OVER (PARTITION BY user_id ORDER BY price DESC)
+//	FROM orders
+type Window struct {
+	*TaskBase
+	p          *plan.Window
+	complete   chan bool
+	closed     bool
+	isComplete bool
+}
+
+// NewWindow create new window function exec task
+func NewWindow(ctx *plan.Context, p *plan.Window) *Window {
+	return &Window{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		complete: make(chan bool),
+	}
+}
+
+func (m *Window) Close() error {
+	m.Lock()
+	if m.closed {
+		m.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.Unlock()
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C:
+		u.Warnf("window timeout???? ")
+	case <-m.complete:
+	}
+
+	return m.TaskBase.Close()
+}
+
+func (m *Window) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+	defer func() {
+		m.isComplete = true
+		close(m.complete)
+	}()
+
+	outCh := m.MessageOut()
+	inCh := m.MessageIn()
+
+	var rows []*datasource.SqlDriverMessageMap
+
+msgReadLoop:
+	for {
+		waited := time.Now()
+		select {
+		case <-m.SigChan():
+			u.Warnf("got signal quit")
+			return nil
+		case msg, ok := <-inCh:
+			m.received(waited, msg)
+			if !ok {
+				break msgReadLoop
+			}
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessageMap:
+				rows = append(rows, mt)
+			case expr.ContextReader:
+				rows = append(rows, messageFromRow(msg.Id(), mt.Row()))
+			default:
+				err := fmt.Errorf("To use Window must use SqlDriverMessageMap but got %T", msg)
+				m.Ctx.AddError(err)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+			m.buffered(len(rows))
+		}
+	}
+
+	nodes := m.p.Nodes()
+	results := make([][]driver.Value, len(nodes))
+	for i, n := range nodes {
+		vals, err := evalWindow(n, rows)
+		if err != nil {
+			m.Ctx.AddError(err)
+			return err
+		}
+		results[i] = vals
+	}
+
+	// of an aggregate query there is no final projection, the columns
+	// using a window are evaluated here into the grouped row
+	var windowCols []int
+	if m.p.Stmt.IsAggQuery() {
+		for i, col := range m.p.Stmt.Columns {
+			if col.Expr != nil && hasWindow(col.Expr) {
+				windowCols = append(windowCols, i)
+			}
+		}
+	}
+
+	for ri, row := range rows {
+		colIndex := make(map[string]int, len(row.ColIndex)+len(nodes))
+		for k, idx := range row.ColIndex {
+			colIndex[k] = idx
+		}
+		vals := make([]driver.Value, len(row.Vals), len(row.Vals)+len(nodes))
+		copy(vals, row.Vals)
+		for i, n := range nodes {
+			key := n.String()
+			if idx, exists := colIndex[key]; exists {
+				vals[idx] = results[i][ri]
+				continue
+			}
+			colIndex[key] = len(vals)
+			vals = append(vals, results[i][ri])
+		}
+		msg := datasource.NewSqlDriverMessageMap(row.Id(), vals, colIndex)
+		for _, ci := range windowCols {
+			col := m.p.Stmt.Columns[ci]
+			idx, ok := colIndex[col.Key()]
+			if !ok {
+				continue
+			}
+			if v, ok := vm.Eval(msg, col.Expr); ok && v != nil {
+				vals[idx] = v.Value()
+			} else {
+				vals[idx] = nil
+			}
+		}
+		waited := time.Now()
+		select {
+		case outCh <- msg:
+			m.sent(waited)
+		case <-m.SigChan():
+			return nil
+		}
+	}
+	return nil
+}
+
+// messageFromRow a SqlDriverMessageMap of the values of a message.
+func messageFromRow(id uint64, row map[string]value.Value) *datasource.SqlDriverMessageMap {
+	cols := make([]string, 0, len(row))
+	for k := range row {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
+	vals := make([]driver.Value, len(cols))
+	for i, k := range cols {
+		if row[k] != nil {
+			vals[i] = row[k].Value()
+		}
+	}
+	return datasource.NewSqlDriverMessageMapVals(id, vals, cols)
+}
+
+// hasWindow does the expression use a window function.
+func hasWindow(node expr.Node) bool {
+	switch n := node.(type) {
+	case *expr.WindowNode:
+		return true
+	case *expr.SubQueryNode:
+		return false
+	case *expr.UnaryNode:
+		return hasWindow(n.Arg)
+	case expr.NodeArgs:
+		for _, arg := range n.ChildrenArgs() {
+			if hasWindow(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// windowRow a row of a partition of a window.
+type windowRow struct {
+	idx  int           // position of the row in the input
+	keys []value.Value // the order by values
+	arg  value.Value   // the first arg of the window function
+}
+
+// evalWindow compute the value of a window function for each of the rows.
+func evalWindow(n *expr.WindowNode, rows []*datasource.SqlDriverMessageMap) ([]driver.Value, error) {
+
+	// rows of each partition in the order of its first row
+	var parts [][]*windowRow
+	partIdx := make(map[string]int)
+	for i, row := range rows {
+		pvals := make([]driver.Value, len(n.Partition))
+		for pi, pn := range n.Partition {
+			if v, ok := vm.Eval(row, pn); ok && v != nil {
+				pvals[pi] = v.Value()
+			}
+		}
+		wr := &windowRow{idx: i, keys: make([]value.Value, len(n.OrderBy))}
+		for oi, on := range n.OrderBy {
+			if v, ok := vm.Eval(row, on); ok {
+				wr.keys[oi] = v
+			}
+		}
+		if len(n.Func.Args) > 0 {
+			if v, ok := vm.Eval(row, n.Func.Args[0]); ok {
+				wr.arg = v
+			}
+		}
+		key := rowHashKey(pvals)
+		pi, ok := partIdx[key]
+		if !ok {
+			pi = len(parts)
+			partIdx[key] = pi
+			parts = append(parts, nil)
+		}
+		parts[pi] = append(parts[pi], wr)
+	}
+
+	out := make([]driver.Value, len(rows))
+	for _, part := range parts {
+		sort.SliceStable(part, func(i, j int) bool {
+			return compareRows(n, part[i].keys, part[j].keys) < 0
+		})
+		if err := evalPartition(n, part, rows, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func evalPartition(n *expr.WindowNode, part []*windowRow, rows []*datasource.SqlDriverMessageMap, out []driver.Value) error {
+
+	name := strings.ToLower(n.Func.Name)
+	rank, denseRank := 0, 0
+	for i, wr := range part {
+		peer := i > 0 && compareRows(n, part[i-1].keys, wr.keys) == 0
+		if !peer {
+			rank = i + 1
+			denseRank++
+		}
+		switch name {
+		case "row_number":
+			out[wr.idx] = int64(i + 1)
+		case "rank":
+			out[wr.idx] = int64(rank)
+		case "dense_rank":
+			out[wr.idx] = int64(denseRank)
+		case "lag", "lead":
+			offset := int64(1)
+			if len(n.Func.Args) > 1 {
+				v, _ := vm.Eval(rows[wr.idx], n.Func.Args[1])
+				iv, ok := value.ValueToInt64(v)
+				if !ok {
+					return fmt.Errorf("the offset of %s must be a number", n.Func)
+				}
+				offset = iv
+			}
+			if name == "lag" {
+				offset = -offset
+			}
+			if j := int64(i) + offset; j >= 0 && j < int64(len(part)) {
+				out[wr.idx] = valueOf(part[j].arg)
+			} else if len(n.Func.Args) > 2 {
+				if v, ok := vm.Eval(rows[wr.idx], n.Func.Args[2]); ok {
+					out[wr.idx] = valueOf(v)
+				}
+			}
+		default:
+			start, end := windowFrame(n, part, i)
+			var frame []*windowRow
+			if start <= end {
+				frame = part[start : end+1]
+			}
+			v, err := frameAgg(name, n.Func, frame)
+			if err != nil {
+				return err
+			}
+			out[wr.idx] = v
+		}
+	}
+	return nil
+}
+
+// frameAgg compute an aggregate function of the rows of a window frame.
+func frameAgg(name string, fn *expr.FuncNode, frame []*windowRow) (driver.Value, error) {
+	switch name {
+	case "first_value":
+		if len(frame) == 0 {
+			return nil, nil
+		}
+		return valueOf(frame[0].arg), nil
+	case "last_value":
+		if len(frame) == 0 {
+			return nil, nil
+		}
+		return valueOf(frame[len(frame)-1].arg), nil
+	case "count":
+		ct := int64(0)
+		star := false
+		if len(fn.Args) > 0 {
+			in, ok := fn.Args[0].(*expr.IdentityNode)
+			star = ok && in.Text == "*"
+		}
+		for _, wr := range frame {
+			if star || valueOf(wr.arg) != nil {
+				ct++
+			}
+		}
+		return ct, nil
+	case "sum", "avg":
+		ct, sum := 0, float64(0)
+		for _, wr := range frame {
+			if f, ok := value.ValueToFloat64(wr.arg); ok {
+				ct++
+				sum += f
+			}
+		}
+		if ct == 0 {
+			return nil, nil
+		}
+		if name == "avg" {
+			return sum / float64(ct), nil
+		}
+		return sum, nil
+	case "min", "max":
+		var m value.Value
+		for _, wr := range frame {
+			if valueOf(wr.arg) == nil {
+				continue
+			}
+			if m == nil {
+				m = wr.arg
+				continue
+			}
+			cmp := value.Compare(wr.arg, m)
+			if (name == "min" && cmp < 0) || (name == "max" && cmp > 0) {
+				m = wr.arg
+			}
+		}
+		return valueOf(m), nil
+	}
+	if af, ok := fn.F.CustomFunc.(expr.AggregateFunc); ok {
+		// any other aggregate, ie stddev(price) OVER (...)
+		agg, err := af.NewAggregator(fn)
+		if err != nil {
+			return nil, err
+		}
+		for _, wr := range frame {
+			agg.Accumulate([]value.Value{wr.arg})
+		}
+		return valueOf(agg.Finalize()), nil
+	}
+	return nil, fmt.Errorf("%s is not a window function", fn)
+}
+
+func valueOf(v value.Value) driver.Value {
+	if v == nil || v.Nil() {
+		return nil
+	}
+	return v.Value()
+}
+
+// compareRows compare the order by values of two rows of a window.
+func compareRows(n *expr.WindowNode, a, b []value.Value) int {
+	for i := range a {
+		cmp := value.Compare(a[i], b[i])
+		if cmp != 0 {
+			if n.Desc[i] {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return 0
+}
+
+// windowFrame the first and last position, inclusive, of the rows of the
+// frame of row i of a sorted partition.  Without a frame it is all rows
+// up to the last peer of the row if ordered, else the whole partition.
+func windowFrame(n *expr.WindowNode, part []*windowRow, i int) (int, int) {
+	f := n.Frame
+	if f == nil {
+		if len(n.OrderBy) == 0 {
+			return 0, len(part) - 1
+		}
+		f = &expr.WindowFrame{Range: true, Start: expr.FrameUnboundedPreceding, End: expr.FrameCurrentRow}
+	}
+	start := frameBound(n, part, i, f.Range, f.Start, f.StartN, true)
+	end := frameBound(n, part, i, f.Range, f.End, f.EndN, false)
+	if start < 0 {
+		start = 0
+	}
+	if end > len(part)-1 {
+		end = len(part) - 1
+	}
+	return start, end
+}
+
+func frameBound(n *expr.WindowNode, part []*windowRow, i int, isRange bool, b expr.FrameBound, offset int64, isStart bool) int {
+	switch b {
+	case expr.FrameUnboundedPreceding:
+		return 0
+	case expr.FrameUnboundedFollowing:
+		return len(part) - 1
+	case expr.FramePreceding:
+		offset = -offset
+	case expr.FrameCurrentRow:
+		offset = 0
+	}
+	if !isRange {
+		return i + int(offset)
+	}
+
+	// a range is of the rows whose order by value is within offset of the
+	// current row's, in the direction of the order
+	cur, isNum := value.ValueToFloat64(part[i].keys[0])
+	if !isNum || offset == 0 {
+		// only the peers of the row
+		j := i
+		if isStart {
+			for j > 0 && compareRows(n, part[j-1].keys, part[i].keys) == 0 {
+				j--
+			}
+		} else {
+			for j < len(part)-1 && compareRows(n, part[j+1].keys, part[i].keys) == 0 {
+				j++
+			}
+		}
+		return j
+	}
+	target := cur + float64(offset)
+	dir := float64(1)
+	if n.Desc[0] {
+		target = cur - float64(offset)
+		dir = -1
+	}
+	// within the range: (v - target) * dir <= 0 for the end, >= 0 for the start
+	within := func(j int) bool {
+		f, ok := value.ValueToFloat64(part[j].keys[0])
+		if !ok {
+			return false
+		}
+		d := (f - target) * dir
+		if isStart {
+			return d >= 0
+		}
+		return d <= 0
+	}
+	if isStart {
+		for j := 0; j < len(part); j++ {
+			if within(j) {
+				return j
+			}
+		}
+		return len(part)
+	}
+	for j := len(part) - 1; j >= 0; j-- {
+		if within(j) {
+			return j
+		}
+	}
+	return -1
+}
@@ -3,11 +3,20 @@ package builtins
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/value"
 )
 
+const (
+	// rough estimates of the bytes held by an aggregator for a string, and
+	// a value, beyond the bytes of their text
+	stringOverhead = 16
+	valueOverhead  = 32
+)
+
 // Avg average of values.  Note, this function DOES NOT persist state doesn't aggregate
 // across multiple calls.  That would be responsibility of write context.
 //
@@ -26,6 +35,14 @@ func (m *Avg) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 }
 func (m *Avg) IsAgg() bool { return true }
 
+// NewAggregator the running average of a group.
+func (m *Avg) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for avg(arg) aggregate but got %s", n)
+	}
+	return &sumAgg{avg: true}, nil
+}
+
 func avgEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
 	avg := float64(0)
 	ct := 0
@@ -79,6 +96,14 @@ func (m *Sum) Type() value.ValueType { return value.NumberType }
 // IsAgg yes sum is an agg.
 func (m *Sum) IsAgg() bool { return true }
 
+// NewAggregator the running sum of a group.
+func (m *Sum) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for sum(arg) aggregate but got %s", n)
+	}
+	return &sumAgg{}, nil
+}
+
 func (m *Sum) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 	if len(n.Args) < 1 {
 		return nil, fmt.Errorf("Expected 1 or more args for Sum(arg, arg, ...) but got %s", n)
@@ -142,6 +167,25 @@ type Count struct{}
 func (m *Count) Type() value.ValueType { return value.IntType }
 func (m *Count) IsAgg() bool           { return true }
 
+// NewAggregator the running count of a group, of non-null values, or
+// all rows for count(*), or the distinct values of count(DISTINCT x).
+func (m *Count) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for count(arg) aggregate but got %s", n)
+	}
+	switch arg := n.Args[0].(type) {
+	case *expr.StringNode:
+		if arg.Text == "*" {
+			return &countAgg{star: true}, nil
+		}
+	case *expr.FuncNode:
+		if strings.ToLower(arg.Name) == "distinct" {
+			return &countDistinctAgg{vals: make(map[string]struct{})}, nil
+		}
+	}
+	return &countAgg{}, nil
+}
+
 func (m *Count) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 	if len(n.Args) != 1 {
 		return nil, fmt.Errorf("Expected max 1 arg for count(arg) but got %s", n)
@@ -155,3 +199,583 @@ func incrementEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool)
 	}
 	return value.NewIntValue(1), true
 }
+
+// sumAgg the running sum, or average, of the numeric values of a group.
+type sumAgg struct {
+	avg bool
+	ct  int64
+	n   float64
+}
+
+func (m *sumAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	if fv, ok := value.ValueToFloat64(args[0]); ok && !math.IsNaN(fv) {
+		m.ct++
+		m.n += fv
+	}
+}
+func (m *sumAgg) Merge(p *expr.AggPartial) {
+	m.ct += p.Ct
+	m.n += p.N
+}
+func (m *sumAgg) Partial() *expr.AggPartial { return &expr.AggPartial{Ct: m.ct, N: m.n} }
+func (m *sumAgg) Finalize() value.Value {
+	if m.ct == 0 {
+		// of no non-null values, as sql
+		return value.NewNilValue()
+	}
+	if m.avg {
+		return value.NewNumberValue(m.n / float64(m.ct))
+	}
+	return value.NewNumberValue(m.n)
+}
+
+// countAgg the count of non-null values, or rows, of a group.
+type countAgg struct {
+	star bool
+	n    int64
+}
+
+func (m *countAgg) Accumulate(args []value.Value) {
+	if m.star || (args[0] != nil && !args[0].Nil()) {
+		m.n++
+	}
+}
+func (m *countAgg) Merge(p *expr.AggPartial)  { m.n += p.Ct }
+func (m *countAgg) Partial() *expr.AggPartial { return &expr.AggPartial{Ct: m.n} }
+func (m *countAgg) Finalize() value.Value     { return value.NewIntValue(m.n) }
+
+// countDistinctAgg count(distinct x) holds a hash set of the values seen,
+// partials are the set of keys for the final aggregator to union.
+type countDistinctAgg struct {
+	vals map[string]struct{}
+	size int64
+}
+
+func (m *countDistinctAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	m.add(args[0].ToString())
+}
+func (m *countDistinctAgg) add(k string) {
+	if _, ok := m.vals[k]; !ok {
+		m.vals[k] = struct{}{}
+		m.size += stringOverhead + int64(len(k))
+	}
+}
+func (m *countDistinctAgg) Merge(p *expr.AggPartial) {
+	for _, k := range p.Keys {
+		m.add(k)
+	}
+}
+func (m *countDistinctAgg) Partial() *expr.AggPartial {
+	keys := make([]string, 0, len(m.vals))
+	for k := range m.vals {
+		keys = append(keys, k)
+	}
+	return &expr.AggPartial{Ct: int64(len(keys)), Keys: keys}
+}
+func (m *countDistinctAgg) Finalize() value.Value { return value.NewIntValue(int64(len(m.vals))) }
+func (m *countDistinctAgg) MemSize() int64        { return m.size }
+
+// Min the least of values, numbers compare numerically, times by time
+// and anything else as strings.  As an aggregate the least value of
+// the group.
+//
+//	min(1, 2, 3) => 1, true
+//	min("b", "a") => "a", true
+type Min struct{}
+
+// Type is unknown, the type of the values
+func (m *Min) Type() value.ValueType { return value.UnknownType }
+func (m *Min) IsAgg() bool           { return true }
+func (m *Min) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for min(arg, arg, ...) but got %s", n)
+	}
+	return minEval, nil
+}
+
+// NewAggregator the least value of a group.
+func (m *Min) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for min(arg) aggregate but got %s", n)
+	}
+	return &minMaxAgg{}, nil
+}
+
+func minEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	agg := &minMaxAgg{}
+	for _, val := range vals {
+		agg.Accumulate([]value.Value{val})
+	}
+	return agg.Finalize(), agg.v != nil
+}
+
+// Max the greatest of values, numbers compare numerically, times by time
+// and anything else as strings.  As an aggregate the greatest value of
+// the group.
+//
+//	max(1, 2, 3) => 3, true
+//	max("b", "a") => "b", true
+type Max struct{}
+
+// Type is unknown, the type of the values
+func (m *Max) Type() value.ValueType { return value.UnknownType }
+func (m *Max) IsAgg() bool           { return true }
+func (m *Max) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for max(arg, arg, ...) but got %s", n)
+	}
+	return maxEval, nil
+}
+
+// NewAggregator the greatest value of a group.
+func (m *Max) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for max(arg) aggregate but got %s", n)
+	}
+	return &minMaxAgg{max: true}, nil
+}
+
+func maxEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	agg := &minMaxAgg{max: true}
+	for _, val := range vals {
+		agg.Accumulate([]value.Value{val})
+	}
+	return agg.Finalize(), agg.v != nil
+}
+
+// StdDev the standard deviation of values, of the sample, or of the
+// population for stddev_pop.
+//
+//	stddev(2, 4, 4, 4, 5, 5, 7, 9) => 2.138, true
+//	stddev_pop(2, 4, 4, 4, 5, 5, 7, 9) => 2.0, true
+type StdDev struct {
+	pop bool
+}
+
+// Type is number
+func (m *StdDev) Type() value.ValueType { return value.NumberType }
+func (m *StdDev) IsAgg() bool           { return true }
+func (m *StdDev) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for %s(arg, arg, ...) but got %s", n.Name, n)
+	}
+	return varianceEval(!m.pop, true), nil
+}
+
+// NewAggregator the running standard deviation of a group.
+func (m *StdDev) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for %s(arg) aggregate but got %s", n.Name, n)
+	}
+	return &varianceAgg{samp: !m.pop, sqrt: true}, nil
+}
+
+// Variance the variance of values, of the sample, or of the population
+// for var_pop.
+//
+//	variance(2, 4, 4, 4, 5, 5, 7, 9) => 4.571, true
+//	var_pop(2, 4, 4, 4, 5, 5, 7, 9) => 4.0, true
+type Variance struct {
+	pop bool
+}
+
+// Type is number
+func (m *Variance) Type() value.ValueType { return value.NumberType }
+func (m *Variance) IsAgg() bool           { return true }
+func (m *Variance) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for %s(arg, arg, ...) but got %s", n.Name, n)
+	}
+	return varianceEval(!m.pop, false), nil
+}
+
+// NewAggregator the running variance of a group.
+func (m *Variance) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for %s(arg) aggregate but got %s", n.Name, n)
+	}
+	return &varianceAgg{samp: !m.pop}, nil
+}
+
+func varianceEval(samp, sqrt bool) expr.EvaluatorFunc {
+	return func(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+		agg := &varianceAgg{samp: samp, sqrt: sqrt}
+		for _, val := range vals {
+			agg.Accumulate([]value.Value{val})
+		}
+		v := agg.Finalize()
+		return v, !v.Nil()
+	}
+}
+
+// Percentile the continuous percentile, interpolated between the closest
+// values, of the values of a group, the fraction must be 0 to 1.
+//
+//	percentile(price, 0.5)  => the median price
+//	percentile(price, 0.95)
+type Percentile struct{}
+
+// Type is number
+func (m *Percentile) Type() value.ValueType { return value.NumberType }
+func (m *Percentile) IsAgg() bool           { return true }
+func (m *Percentile) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if _, err := percentileFraction(n); err != nil {
+		return nil, err
+	}
+	return percentileEval, nil
+}
+
+// NewAggregator the values of a group, sorted to find the percentile
+// once all are known.
+func (m *Percentile) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	p, err := percentileFraction(n)
+	if err != nil {
+		return nil, err
+	}
+	return &percentileAgg{p: p}, nil
+}
+
+func percentileFraction(n *expr.FuncNode) (float64, error) {
+	if len(n.Args) != 2 {
+		return 0, fmt.Errorf("Expected 2 args for percentile(arg, fraction) but got %s", n)
+	}
+	nn, ok := n.Args[1].(*expr.NumberNode)
+	if !ok {
+		return 0, fmt.Errorf("percentile fraction must be a number 0 to 1 but got %s", n)
+	}
+	p := nn.Float64
+	if nn.IsInt && !nn.IsFloat {
+		p = float64(nn.Int64)
+	}
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("percentile fraction must be a number 0 to 1 but got %s", n)
+	}
+	return p, nil
+}
+
+// percentile of a single value is itself
+func percentileEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if fv, ok := value.ValueToFloat64(vals[0]); ok && !math.IsNaN(fv) {
+		return value.NewNumberValue(fv), true
+	}
+	return value.NumberNaNValue, false
+}
+
+// ArrayAgg the non-null values of a group as an array, in the order they
+// were aggregated.
+//
+//	array_agg(item)  => ["shoes", "socks"]
+type ArrayAgg struct{}
+
+// Type is a slice of values
+func (m *ArrayAgg) Type() value.ValueType { return value.SliceValueType }
+func (m *ArrayAgg) IsAgg() bool           { return true }
+func (m *ArrayAgg) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for array_agg(arg) but got %s", n)
+	}
+	return arrayAggEval, nil
+}
+
+// NewAggregator the values of a group.
+func (m *ArrayAgg) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for array_agg(arg) aggregate but got %s", n)
+	}
+	return &arrayAgg{}, nil
+}
+
+func arrayAggEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if vals[0] == nil || vals[0].Nil() {
+		return value.NewNilValue(), false
+	}
+	return value.NewSliceValues([]value.Value{vals[0]}), true
+}
+
+// GroupConcat the non-null values of a group as a string, joined by the
+// separator, default ",", in the order they were aggregated.
+//
+//	group_concat(item)        => "shoes,socks"
+//	group_concat(item, " | ") => "shoes | socks"
+type GroupConcat struct{}
+
+// Type is string
+func (m *GroupConcat) Type() value.ValueType { return value.StringType }
+func (m *GroupConcat) IsAgg() bool           { return true }
+func (m *GroupConcat) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if _, err := groupConcatSep(n); err != nil {
+		return nil, err
+	}
+	return groupConcatEval, nil
+}
+
+// NewAggregator the values of a group, to be joined.
+func (m *GroupConcat) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	sep, err := groupConcatSep(n)
+	if err != nil {
+		return nil, err
+	}
+	return &groupConcatAgg{sep: sep}, nil
+}
+
+func groupConcatSep(n *expr.FuncNode) (string, error) {
+	switch len(n.Args) {
+	case 1:
+		return ",", nil
+	case 2:
+		if sn, ok := n.Args[1].(*expr.StringNode); ok {
+			return sn.Text, nil
+		}
+		return "", fmt.Errorf("group_concat separator must be a string but got %s", n)
+	}
+	return "", fmt.Errorf("Expected 1 or 2 args for group_concat(arg [, separator]) but got %s", n)
+}
+
+func groupConcatEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if vals[0] == nil || vals[0].Nil() {
+		return value.NewNilValue(), false
+	}
+	return value.NewStringValue(vals[0].ToString()), true
+}
+
+// minMaxAgg the least, or greatest, non-null value of a group.
+type minMaxAgg struct {
+	max bool
+	v   value.Value
+}
+
+func (m *minMaxAgg) Accumulate(args []value.Value) {
+	v := args[0]
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	if m.v == nil {
+		m.v = v
+		return
+	}
+	cmp := value.Compare(v, m.v)
+	if (m.max && cmp > 0) || (!m.max && cmp < 0) {
+		m.v = v
+	}
+}
+func (m *minMaxAgg) Merge(p *expr.AggPartial) {
+	for _, v := range p.Vals {
+		m.Accumulate([]value.Value{value.NewValue(v)})
+	}
+}
+func (m *minMaxAgg) Partial() *expr.AggPartial {
+	if m.v == nil {
+		return &expr.AggPartial{}
+	}
+	return &expr.AggPartial{Ct: 1, Vals: []interface{}{m.v.Value()}}
+}
+func (m *minMaxAgg) Finalize() value.Value {
+	if m.v == nil {
+		return value.NewNilValue()
+	}
+	return m.v
+}
+
+// varianceAgg the running variance, or standard deviation, of the numeric
+// values of a group using Welford's algorithm, partials are merged by the
+// parallel algorithm of Chan et al.
+type varianceAgg struct {
+	samp bool // of the sample, else of the population
+	sqrt bool // standard deviation
+	ct   int64
+	mean float64
+	m2   float64
+}
+
+func (m *varianceAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	fv, ok := value.ValueToFloat64(args[0])
+	if !ok || math.IsNaN(fv) {
+		return
+	}
+	m.ct++
+	delta := fv - m.mean
+	m.mean += delta / float64(m.ct)
+	m.m2 += delta * (fv - m.mean)
+}
+func (m *varianceAgg) Merge(p *expr.AggPartial) {
+	if p.Ct == 0 {
+		return
+	}
+	ct := m.ct + p.Ct
+	delta := p.N/float64(p.Ct) - m.mean
+	m.m2 += p.M2 + delta*delta*float64(m.ct)*float64(p.Ct)/float64(ct)
+	m.mean += delta * float64(p.Ct) / float64(ct)
+	m.ct = ct
+}
+func (m *varianceAgg) Partial() *expr.AggPartial {
+	return &expr.AggPartial{Ct: m.ct, N: m.mean * float64(m.ct), M2: m.m2}
+}
+func (m *varianceAgg) Finalize() value.Value {
+	n := m.ct
+	if m.samp {
+		n--
+	}
+	if n <= 0 {
+		return value.NewNilValue()
+	}
+	v := m.m2 / float64(n)
+	if m.sqrt {
+		v = math.Sqrt(v)
+	}
+	return value.NewNumberValue(v)
+}
+
+// percentileAgg holds the numeric values of a group, partials are the
+// values for the final aggregator to combine.
+type percentileAgg struct {
+	p    float64
+	vals []float64
+}
+
+func (m *percentileAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	if fv, ok := value.ValueToFloat64(args[0]); ok && !math.IsNaN(fv) {
+		m.vals = append(m.vals, fv)
+	}
+}
+func (m *percentileAgg) Merge(p *expr.AggPartial) {
+	for _, v := range p.Vals {
+		m.Accumulate([]value.Value{value.NewValue(v)})
+	}
+}
+func (m *percentileAgg) Partial() *expr.AggPartial {
+	vals := make([]interface{}, len(m.vals))
+	for i, v := range m.vals {
+		vals[i] = v
+	}
+	return &expr.AggPartial{Ct: int64(len(vals)), Vals: vals}
+}
+func (m *percentileAgg) Finalize() value.Value {
+	if len(m.vals) == 0 {
+		return value.NewNilValue()
+	}
+	sort.Float64s(m.vals)
+	pos := m.p * float64(len(m.vals)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	v := m.vals[lo] + (m.vals[hi]-m.vals[lo])*(pos-float64(lo))
+	return value.NewNumberValue(v)
+}
+func (m *percentileAgg) MemSize() int64 { return int64(8 * len(m.vals)) }
+
+// arrayAgg the non-null values of a group.
+type arrayAgg struct {
+	vals []value.Value
+	size int64
+}
+
+func (m *arrayAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	m.vals = append(m.vals, args[0])
+	m.size += valueMemSize(args[0])
+}
+func (m *arrayAgg) Merge(p *expr.AggPartial) {
+	for _, v := range p.Vals {
+		m.Accumulate([]value.Value{value.NewValue(v)})
+	}
+}
+func (m *arrayAgg) Partial() *expr.AggPartial {
+	vals := make([]interface{}, len(m.vals))
+	for i, v := range m.vals {
+		vals[i] = v.Value()
+	}
+	return &expr.AggPartial{Ct: int64(len(vals)), Vals: vals}
+}
+func (m *arrayAgg) Finalize() value.Value {
+	if len(m.vals) == 0 {
+		return value.NewNilValue()
+	}
+	return value.NewSliceValues(m.vals)
+}
+func (m *arrayAgg) MemSize() int64 { return m.size }
+
+// groupConcatAgg the non-null values of a group as strings, to be joined.
+type groupConcatAgg struct {
+	sep  string
+	vals []string
+	size int64
+}
+
+func (m *groupConcatAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	m.add(args[0].ToString())
+}
+func (m *groupConcatAgg) add(s string) {
+	m.vals = append(m.vals, s)
+	m.size += stringOverhead + int64(len(s))
+}
+func (m *groupConcatAgg) Merge(p *expr.AggPartial) {
+	for _, v := range p.Vals {
+		if s, ok := v.(string); ok {
+			m.add(s)
+		}
+	}
+}
+func (m *groupConcatAgg) Partial() *expr.AggPartial {
+	vals := make([]interface{}, len(m.vals))
+	for i, v := range m.vals {
+		vals[i] = v
+	}
+	return &expr.AggPartial{Ct: int64(len(vals)), Vals: vals}
+}
+func (m *groupConcatAgg) Finalize() value.Value {
+	if len(m.vals) == 0 {
+		return value.NewNilValue()
+	}
+	return value.NewStringValue(strings.Join(m.vals, m.sep))
+}
+func (m *groupConcatAgg) MemSize() int64 { return m.size }
+
+// valueMemSize a rough estimate of the bytes of a value held by an
+// aggregator, the interface and the bytes of strings.
+func valueMemSize(v value.Value) int64 {
+	switch vt := v.(type) {
+	case value.StringValue:
+		return valueOverhead + int64(len(vt.Val()))
+	case value.ByteSliceValue:
+		return valueOverhead + int64(len(vt.Val()))
+	}
+	return valueOverhead
+}
+
+// Grouping of a GROUP BY ROLLUP, CUBE or GROUPING SETS, which of its args
+// are not in the grouping set of the row, ie are rolled up into a subtotal,
+// a bit for each arg, the first arg the highest bit.  It is computed by the
+// group by task, outside of one it is 0.
+//
+//	GROUP BY ROLLUP (year, month)
+//	grouping(year, month)  => 0 of each month, 1 of each year, 3 of the total
+type Grouping struct{}
+
+// Type is IntType
+func (m *Grouping) Type() value.ValueType { return value.IntType }
+func (m *Grouping) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 || len(n.Args) > 63 {
+		return nil, fmt.Errorf("Expected 1 to 63 args for grouping(arg, ...) but got %s", n)
+	}
+	return groupingEval, nil
+}
+
+func groupingEval(ctx expr.EvalContext, args []value.Value) (value.Value, bool) {
+	return value.NewIntValue(0), true
+}
@@ -0,0 +1,729 @@
+package builtins
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/dchest/siphash"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
+)
+
+const (
+	// hllDefaultError the default relative error of approx_count_distinct,
+	// a HyperLogLog of 2^14 registers (16kb)
+	hllDefaultError = 0.01
+	hllMinPrecision = 4
+	hllMaxPrecision = 18
+
+	// tdigestDefaultCompression the default compression of approx_percentile,
+	// higher is more accurate with more centroids held
+	tdigestDefaultCompression = 100
+)
+
+// ApproxCountDistinct the approximate count of distinct values of a group,
+// using a HyperLogLog of the given relative (standard) error, default 0.01.
+// Its partial state is the sketch, so partials are merged exactly.
+//
+//    approx_count_distinct(user_id)
+//    approx_count_distinct(user_id, 0.02)
+//
+type ApproxCountDistinct struct{}
+
+// Type is integer
+func (m *ApproxCountDistinct) Type() value.ValueType { return value.IntType }
+func (m *ApproxCountDistinct) IsAgg() bool           { return true }
+func (m *ApproxCountDistinct) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if _, err := hllPrecision(n); err != nil {
+		return nil, err
+	}
+	return incrementEval, nil
+}
+
+// NewAggregator the sketch of the distinct values of a group.
+func (m *ApproxCountDistinct) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	p, err := hllPrecision(n)
+	if err != nil {
+		return nil, err
+	}
+	return &hllAgg{h: newHll(p)}, nil
+}
+
+// HllSketch the HyperLogLog sketch of the distinct values of a group, as
+// bytes, to be stored in a rollup and merged later by hll_merge, or
+// counted by hll_count.
+//
+//    hll_sketch(user_id)
+//    hll_sketch(user_id, 0.02)
+//
+type HllSketch struct{}
+
+// Type is bytes
+func (m *HllSketch) Type() value.ValueType { return value.ByteSliceType }
+func (m *HllSketch) IsAgg() bool           { return true }
+func (m *HllSketch) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	p, err := hllPrecision(n)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+		agg := &hllAgg{h: newHll(p), sketch: true}
+		agg.Accumulate(vals)
+		return agg.Finalize(), true
+	}, nil
+}
+
+// NewAggregator the sketch of the distinct values of a group.
+func (m *HllSketch) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	p, err := hllPrecision(n)
+	if err != nil {
+		return nil, err
+	}
+	return &hllAgg{h: newHll(p), sketch: true}, nil
+}
+
+// HllMerge the union of the HyperLogLog sketches (of hll_sketch) of a
+// group, as a sketch.  Sketches of different error are merged at the
+// lower precision.
+//
+//    hll_count(hll_merge(users_sketch))
+//
+type HllMerge struct{}
+
+// Type is bytes
+func (m *HllMerge) Type() value.ValueType { return value.ByteSliceType }
+func (m *HllMerge) IsAgg() bool           { return true }
+func (m *HllMerge) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for hll_merge(sketch) but got %s", n)
+	}
+	return func(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+		agg := &hllAgg{merge: true, sketch: true}
+		agg.Accumulate(vals)
+		return agg.Finalize(), agg.h != nil
+	}, nil
+}
+
+// NewAggregator the union of the sketches of a group.
+func (m *HllMerge) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for hll_merge(sketch) aggregate but got %s", n)
+	}
+	return &hllAgg{merge: true, sketch: true}, nil
+}
+
+// HllCount the estimated count of distinct values of a HyperLogLog sketch.
+//
+//    hll_count(users_sketch)  => 1234
+//
+type HllCount struct{}
+
+// Type is integer
+func (m *HllCount) Type() value.ValueType { return value.IntType }
+func (m *HllCount) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for hll_count(sketch) but got %s", n)
+	}
+	return hllCountEval, nil
+}
+
+func hllCountEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	by, ok := sketchBytes(vals[0])
+	if !ok {
+		return value.NewIntValue(0), false
+	}
+	h, err := decodeHll(by)
+	if err != nil {
+		return value.NewIntValue(0), false
+	}
+	return value.NewIntValue(h.estimate()), true
+}
+
+// ApproxPercentile the approximate percentile of the values of a group, the
+// fraction must be 0 to 1, using a t-digest of the given compression,
+// default 100, higher is more accurate.  Its partial state is the digest.
+//
+//    approx_percentile(latency, 0.99)
+//    approx_percentile(latency, 0.5, 200)
+//
+type ApproxPercentile struct{}
+
+// Type is number
+func (m *ApproxPercentile) Type() value.ValueType { return value.NumberType }
+func (m *ApproxPercentile) IsAgg() bool           { return true }
+func (m *ApproxPercentile) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if _, _, err := approxPercentileArgs(n); err != nil {
+		return nil, err
+	}
+	return percentileEval, nil
+}
+
+// NewAggregator the digest of the values of a group.
+func (m *ApproxPercentile) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	p, compression, err := approxPercentileArgs(n)
+	if err != nil {
+		return nil, err
+	}
+	return &tdigestAgg{td: newTdigest(compression), p: p}, nil
+}
+
+func approxPercentileArgs(n *expr.FuncNode) (float64, float64, error) {
+	if len(n.Args) < 2 || len(n.Args) > 3 {
+		return 0, 0, fmt.Errorf("Expected 2 or 3 args for approx_percentile(arg, fraction [, compression]) but got %s", n)
+	}
+	p, ok := numberArg(n, 1)
+	if !ok || p < 0 || p > 1 {
+		return 0, 0, fmt.Errorf("approx_percentile fraction must be a number 0 to 1 but got %s", n)
+	}
+	compression, err := tdigestCompression(n, 2)
+	return p, compression, err
+}
+
+// TdigestSketch the t-digest of the values of a group, as bytes, to be
+// stored in a rollup and merged later by tdigest_merge, or queried by
+// tdigest_percentile.
+//
+//    tdigest_sketch(latency)
+//    tdigest_sketch(latency, 200)
+//
+type TdigestSketch struct{}
+
+// Type is bytes
+func (m *TdigestSketch) Type() value.ValueType { return value.ByteSliceType }
+func (m *TdigestSketch) IsAgg() bool           { return true }
+func (m *TdigestSketch) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 || len(n.Args) > 2 {
+		return nil, fmt.Errorf("Expected 1 or 2 args for tdigest_sketch(arg [, compression]) but got %s", n)
+	}
+	compression, err := tdigestCompression(n, 1)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+		agg := &tdigestAgg{td: newTdigest(compression), sketch: true}
+		agg.Accumulate(vals)
+		return agg.Finalize(), true
+	}, nil
+}
+
+// NewAggregator the digest of the values of a group.
+func (m *TdigestSketch) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) < 1 || len(n.Args) > 2 {
+		return nil, fmt.Errorf("Expected 1 or 2 args for tdigest_sketch(arg [, compression]) but got %s", n)
+	}
+	compression, err := tdigestCompression(n, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &tdigestAgg{td: newTdigest(compression), sketch: true}, nil
+}
+
+// TdigestMerge the union of the t-digests (of tdigest_sketch) of a group,
+// as a digest.
+//
+//    tdigest_percentile(tdigest_merge(latency_digest), 0.99)
+//
+type TdigestMerge struct{}
+
+// Type is bytes
+func (m *TdigestMerge) Type() value.ValueType { return value.ByteSliceType }
+func (m *TdigestMerge) IsAgg() bool           { return true }
+func (m *TdigestMerge) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for tdigest_merge(sketch) but got %s", n)
+	}
+	return func(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+		agg := &tdigestAgg{merge: true, sketch: true}
+		agg.Accumulate(vals)
+		return agg.Finalize(), agg.td != nil
+	}, nil
+}
+
+// NewAggregator the union of the digests of a group.
+func (m *TdigestMerge) NewAggregator(n *expr.FuncNode) (expr.Aggregator, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for tdigest_merge(sketch) aggregate but got %s", n)
+	}
+	return &tdigestAgg{merge: true, sketch: true}, nil
+}
+
+// TdigestPercentile the approximate percentile of the values of a t-digest,
+// the fraction must be 0 to 1.
+//
+//    tdigest_percentile(latency_digest, 0.99)
+//
+type TdigestPercentile struct{}
+
+// Type is number
+func (m *TdigestPercentile) Type() value.ValueType { return value.NumberType }
+func (m *TdigestPercentile) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 2 {
+		return nil, fmt.Errorf("Expected 2 args for tdigest_percentile(sketch, fraction) but got %s", n)
+	}
+	return tdigestPercentileEval, nil
+}
+
+func tdigestPercentileEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	p, ok := value.ValueToFloat64(vals[1])
+	if !ok || p < 0 || p > 1 {
+		return value.NumberNaNValue, false
+	}
+	by, ok := sketchBytes(vals[0])
+	if !ok {
+		return value.NumberNaNValue, false
+	}
+	td, err := decodeTdigest(by)
+	if err != nil || td.count == 0 {
+		return value.NumberNaNValue, false
+	}
+	return value.NewNumberValue(td.quantile(p)), true
+}
+
+// numberArg the value of the i'th arg which must be a number literal.
+func numberArg(n *expr.FuncNode, i int) (float64, bool) {
+	nn, ok := n.Args[i].(*expr.NumberNode)
+	if !ok {
+		return 0, false
+	}
+	if nn.IsInt && !nn.IsFloat {
+		return float64(nn.Int64), true
+	}
+	return nn.Float64, true
+}
+
+// hllPrecision the precision (log2 of the registers) of the HyperLogLog of
+// the relative error of the optional second arg.
+func hllPrecision(n *expr.FuncNode) (uint8, error) {
+	if len(n.Args) < 1 || len(n.Args) > 2 {
+		return 0, fmt.Errorf("Expected 1 or 2 args for %s(arg [, relative_error]) but got %s", n.Name, n)
+	}
+	relErr := hllDefaultError
+	if len(n.Args) == 2 {
+		var ok bool
+		if relErr, ok = numberArg(n, 1); !ok || relErr <= 0 || relErr >= 1 {
+			return 0, fmt.Errorf("%s relative error must be a number between 0 and 1 but got %s", n.Name, n)
+		}
+	}
+	// the standard error of a HyperLogLog of m registers is 1.04/sqrt(m)
+	p := int(math.Ceil(2 * math.Log2(1.04/relErr)))
+	if p < hllMinPrecision {
+		p = hllMinPrecision
+	}
+	if p > hllMaxPrecision {
+		return 0, fmt.Errorf("%s relative error is too small, the least is %.4f: %s",
+			n.Name, 1.04/math.Sqrt(float64(uint(1)<<hllMaxPrecision)), n)
+	}
+	return uint8(p), nil
+}
+
+// tdigestCompression the compression of the optional i'th arg.
+func tdigestCompression(n *expr.FuncNode, i int) (float64, error) {
+	if len(n.Args) <= i {
+		return tdigestDefaultCompression, nil
+	}
+	compression, ok := numberArg(n, i)
+	if !ok || compression < 10 || compression > 10000 {
+		return 0, fmt.Errorf("%s compression must be a number 10 to 10000 but got %s", n.Name, n)
+	}
+	return compression, nil
+}
+
+// sketchBytes the bytes of a sketch value, which when stored in a string
+// column are base64 encoded.
+func sketchBytes(v value.Value) ([]byte, bool) {
+	switch v := v.(type) {
+	case value.ByteSliceValue:
+		return v.Val(), len(v.Val()) > 0
+	case value.StringValue:
+		by, err := base64.StdEncoding.DecodeString(v.Val())
+		return by, err == nil && len(by) > 0
+	}
+	return nil, false
+}
+
+// hllAgg the HyperLogLog of the values, or the union of sketches, of a
+// group, partials are the sketch for the final aggregator to union.
+type hllAgg struct {
+	h      *hll
+	merge  bool // args are sketches to union
+	sketch bool // the result is the sketch, not the count
+}
+
+func (m *hllAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	if m.merge {
+		if by, ok := sketchBytes(args[0]); ok {
+			m.mergeSketch(by)
+		}
+		return
+	}
+	m.h.add(siphash.Hash(0, 1, []byte(args[0].ToString())))
+}
+func (m *hllAgg) mergeSketch(by []byte) {
+	h, err := decodeHll(by)
+	if err != nil {
+		return
+	}
+	if m.h == nil {
+		m.h = h
+		return
+	}
+	m.h = m.h.merge(h)
+}
+func (m *hllAgg) Merge(p *expr.AggPartial) {
+	if len(p.State) > 0 {
+		m.mergeSketch(p.State)
+	}
+}
+func (m *hllAgg) Partial() *expr.AggPartial {
+	if m.h == nil {
+		return &expr.AggPartial{}
+	}
+	return &expr.AggPartial{Ct: m.h.estimate(), State: m.h.bytes()}
+}
+func (m *hllAgg) Finalize() value.Value {
+	switch {
+	case m.h == nil && m.sketch:
+		return value.NewNilValue()
+	case m.h == nil:
+		return value.NewIntValue(0)
+	case m.sketch:
+		return value.NewByteSliceValue(m.h.bytes())
+	}
+	return value.NewIntValue(m.h.estimate())
+}
+func (m *hllAgg) MemSize() int64 {
+	if m.h == nil {
+		return 0
+	}
+	return int64(len(m.h.regs))
+}
+
+// tdigestAgg the t-digest of the values, or the union of digests, of a
+// group, partials are the digest for the final aggregator to union.
+type tdigestAgg struct {
+	td     *tdigest
+	p      float64
+	merge  bool // args are digests to union
+	sketch bool // the result is the digest, not the percentile
+}
+
+func (m *tdigestAgg) Accumulate(args []value.Value) {
+	if args[0] == nil || args[0].Nil() {
+		return
+	}
+	if m.merge {
+		if by, ok := sketchBytes(args[0]); ok {
+			m.mergeSketch(by)
+		}
+		return
+	}
+	if fv, ok := value.ValueToFloat64(args[0]); ok && !math.IsNaN(fv) {
+		m.td.add(fv, 1)
+	}
+}
+func (m *tdigestAgg) mergeSketch(by []byte) {
+	td, err := decodeTdigest(by)
+	if err != nil {
+		return
+	}
+	if m.td == nil {
+		m.td = td
+		return
+	}
+	m.td.merge(td)
+}
+func (m *tdigestAgg) Merge(p *expr.AggPartial) {
+	if len(p.State) > 0 {
+		m.mergeSketch(p.State)
+	}
+}
+func (m *tdigestAgg) Partial() *expr.AggPartial {
+	if m.td == nil {
+		return &expr.AggPartial{}
+	}
+	return &expr.AggPartial{Ct: int64(m.td.count), State: m.td.bytes()}
+}
+func (m *tdigestAgg) Finalize() value.Value {
+	switch {
+	case m.td == nil || (m.td.count == 0 && !m.sketch):
+		return value.NewNilValue()
+	case m.sketch:
+		return value.NewByteSliceValue(m.td.bytes())
+	}
+	return value.NewNumberValue(m.td.quantile(m.p))
+}
+func (m *tdigestAgg) MemSize() int64 {
+	if m.td == nil {
+		return 0
+	}
+	return int64(16 * (len(m.td.centroids) + len(m.td.buf)))
+}
+
+// hll a HyperLogLog, the registers of 2^p buckets of hashes, each holding
+// the longest run of leading zeros seen of the rest of the hash.
+//
+//    http://algo.inria.fr/flajolet/Publications/FlFuGaMe07.pdf
+type hll struct {
+	p    uint8
+	regs []uint8
+}
+
+func newHll(p uint8) *hll {
+	return &hll{p: p, regs: make([]uint8, 1<<p)}
+}
+
+func (m *hll) add(hash uint64) {
+	idx := hash >> (64 - m.p)
+	// the remaining bits, with a sentinel so the run is at most 64-p
+	w := hash<<m.p | 1<<(m.p-1)
+	if rho := uint8(bits.LeadingZeros64(w) + 1); rho > m.regs[idx] {
+		m.regs[idx] = rho
+	}
+}
+
+// merge the union of two sketches, at the lower precision of the two.
+func (m *hll) merge(o *hll) *hll {
+	if o.p < m.p {
+		m, o = o, m.fold(o.p)
+	} else if o.p > m.p {
+		o = o.fold(m.p)
+	}
+	for i, r := range o.regs {
+		if r > m.regs[i] {
+			m.regs[i] = r
+		}
+	}
+	return m
+}
+
+// fold the sketch to a lower precision, the index bits dropped become the
+// leading bits of the run.
+func (m *hll) fold(p uint8) *hll {
+	f := newHll(p)
+	shift := m.p - p
+	for i, r := range m.regs {
+		if r == 0 {
+			continue
+		}
+		dropped := uint64(i) & (1<<shift - 1)
+		rho := r + shift
+		if dropped != 0 {
+			rho = uint8(bits.LeadingZeros64(dropped<<(64-shift)) + 1)
+		}
+		if j := i >> shift; rho > f.regs[j] {
+			f.regs[j] = rho
+		}
+	}
+	return f
+}
+
+func (m *hll) estimate() int64 {
+	mf := float64(len(m.regs))
+	sum, zeros := 0.0, 0
+	for _, r := range m.regs {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	var alpha float64
+	switch len(m.regs) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/mf)
+	}
+	est := alpha * mf * mf / sum
+	if est <= 2.5*mf && zeros > 0 {
+		// small range correction, linear counting
+		est = mf * math.Log(mf/float64(zeros))
+	}
+	return int64(est + 0.5)
+}
+
+// bytes the sketch encoded as version, precision, registers.
+func (m *hll) bytes() []byte {
+	by := make([]byte, 2+len(m.regs))
+	by[0], by[1] = 1, m.p
+	copy(by[2:], m.regs)
+	return by
+}
+
+func decodeHll(by []byte) (*hll, error) {
+	if len(by) < 2 || by[0] != 1 {
+		return nil, fmt.Errorf("not a hyperloglog sketch")
+	}
+	p := by[1]
+	if p < hllMinPrecision || p > hllMaxPrecision || len(by) != 2+1<<p {
+		return nil, fmt.Errorf("invalid hyperloglog sketch of precision %d", p)
+	}
+	return &hll{p: p, regs: append([]uint8(nil), by[2:]...)}, nil
+}
+
+// tdigest a merging t-digest, the values as centroids (mean, weight) which
+// are small at the tails so extreme percentiles are accurate.
+//
+//    https://github.com/tdunning/t-digest/blob/master/docs/t-digest-paper/histo.pdf
+type tdigest struct {
+	compression float64
+	centroids   []centroid // compressed, sorted by mean
+	buf         []centroid // not yet compressed
+	count       float64
+	min, max    float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+func newTdigest(compression float64) *tdigest {
+	return &tdigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (m *tdigest) add(v, weight float64) {
+	m.buf = append(m.buf, centroid{v, weight})
+	m.count += weight
+	if v < m.min {
+		m.min = v
+	}
+	if v > m.max {
+		m.max = v
+	}
+	if len(m.buf) >= int(m.compression)*5 {
+		m.compress()
+	}
+}
+
+func (m *tdigest) merge(o *tdigest) {
+	o.compress()
+	for _, c := range o.centroids {
+		m.buf = append(m.buf, c)
+	}
+	m.count += o.count
+	m.min = math.Min(m.min, o.min)
+	m.max = math.Max(m.max, o.max)
+	m.compress()
+}
+
+// limit the most weight, of the centroids up to and including the next,
+// given the weight before it.  Of the scale function k(q), centroids may
+// span at most one unit of k.
+func (m *tdigest) limit(before float64) float64 {
+	k := m.compression/(2*math.Pi)*math.Asin(2*before/m.count-1) + 1
+	if k >= m.compression/4 {
+		return m.count
+	}
+	return m.count * (math.Sin(k*2*math.Pi/m.compression) + 1) / 2
+}
+
+func (m *tdigest) compress() {
+	if len(m.buf) == 0 {
+		return
+	}
+	all := append(m.centroids, m.buf...)
+	m.buf = nil
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	out := make([]centroid, 1, len(all))
+	out[0] = all[0]
+	before := 0.0 // weight of the centroids before the last
+	limit := m.limit(0)
+	for _, c := range all[1:] {
+		last := &out[len(out)-1]
+		if before+last.weight+c.weight <= limit {
+			last.weight += c.weight
+			last.mean += (c.mean - last.mean) * c.weight / last.weight
+			continue
+		}
+		before += last.weight
+		limit = m.limit(before)
+		out = append(out, c)
+	}
+	m.centroids = out
+}
+
+// quantile the value at q, interpolated between the centers of the
+// centroids either side of it.
+func (m *tdigest) quantile(q float64) float64 {
+	m.compress()
+	cs := m.centroids
+	if len(cs) == 0 {
+		return math.NaN()
+	}
+	if len(cs) == 1 {
+		return cs[0].mean
+	}
+	t := q * m.count
+	if first := cs[0]; t < first.weight/2 {
+		return m.min + (first.mean-m.min)*t/(first.weight/2)
+	}
+	cum := 0.0
+	for i := 0; i < len(cs)-1; i++ {
+		left := cum + cs[i].weight/2
+		right := cum + cs[i].weight + cs[i+1].weight/2
+		if t <= right {
+			return cs[i].mean + (cs[i+1].mean-cs[i].mean)*(t-left)/(right-left)
+		}
+		cum += cs[i].weight
+	}
+	last := cs[len(cs)-1]
+	center := m.count - last.weight/2
+	if t >= m.count {
+		return m.max
+	}
+	return last.mean + (m.max-last.mean)*(t-center)/(last.weight/2)
+}
+
+// bytes the digest encoded as version, compression, min, max and the
+// count then mean, weight of each centroid.
+func (m *tdigest) bytes() []byte {
+	m.compress()
+	by := make([]byte, 1, 1+3*8+binary.MaxVarintLen64+len(m.centroids)*16)
+	by[0] = 1
+	for _, f := range []float64{m.compression, m.min, m.max} {
+		by = appendFloat64(by, f)
+	}
+	var vb [binary.MaxVarintLen64]byte
+	by = append(by, vb[:binary.PutUvarint(vb[:], uint64(len(m.centroids)))]...)
+	for _, c := range m.centroids {
+		by = appendFloat64(appendFloat64(by, c.mean), c.weight)
+	}
+	return by
+}
+
+func appendFloat64(by []byte, f float64) []byte {
+	var fb [8]byte
+	binary.LittleEndian.PutUint64(fb[:], math.Float64bits(f))
+	return append(by, fb[:]...)
+}
+
+func decodeTdigest(by []byte) (*tdigest, error) {
+	if len(by) < 1+3*8 || by[0] != 1 {
+		return nil, fmt.Errorf("not a t-digest sketch")
+	}
+	float := func(i int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(by[i:])) }
+	m := &tdigest{compression: float(1), min: float(9), max: float(17)}
+	n, vl := binary.Uvarint(by[25:])
+	if vl <= 0 || m.compression <= 0 || uint64(len(by)-25-vl) != n*16 {
+		return nil, fmt.Errorf("invalid t-digest sketch")
+	}
+	m.centroids = make([]centroid, n)
+	for i := range m.centroids {
+		at := 25 + vl + i*16
+		m.centroids[i] = centroid{float(at), float(at + 8)}
+		m.count += m.centroids[i].weight
+	}
+	return m, nil
+}
@@ -29,6 +29,35 @@ func LoadAllBuiltins() {
 		expr.FuncAdd("count", &Count{})
 		expr.FuncAdd("avg", &Avg{})
 		expr.FuncAdd("sum", &Sum{})
+		expr.FuncAdd("min", &Min{})
+		expr.FuncAdd("max", &Max{})
+		expr.FuncAdd("stddev", &StdDev{})
+		expr.FuncAdd("stddev_samp", &StdDev{})
+		expr.FuncAdd("stddev_pop", &StdDev{pop: true})
+		expr.FuncAdd("variance", &Variance{})
+		expr.FuncAdd("var_samp", &Variance{})
+		expr.FuncAdd("var_pop", &Variance{pop: true})
+		expr.FuncAdd("percentile", &Percentile{})
+		expr.FuncAdd("array_agg", &ArrayAgg{})
+		expr.FuncAdd("group_concat", &GroupConcat{})
+		expr.FuncAdd("approx_count_distinct", &ApproxCountDistinct{})
+		expr.FuncAdd("approx_percentile", &ApproxPercentile{})
+		expr.FuncAdd("hll_sketch", &HllSketch{})
+		expr.FuncAdd("hll_merge", &HllMerge{})
+		expr.FuncAdd("hll_count", &HllCount{})
+		expr.FuncAdd("tdigest_sketch", &TdigestSketch{})
+		expr.FuncAdd("tdigest_merge", &TdigestMerge{})
+		expr.FuncAdd("tdigest_percentile", &TdigestPercentile{})
+		expr.FuncAdd("grouping", &Grouping{})
+
+		// window functions
+		expr.FuncAdd("row_number", &RowNumber{})
+		expr.FuncAdd("rank", &Rank{})
+		expr.FuncAdd("dense_rank", &DenseRank{})
+		expr.FuncAdd("lag", &Lag{})
+		expr.FuncAdd("lead", &Lead{})
+		expr.FuncAdd("first_value", &FirstValue{})
+		expr.FuncAdd("last_value", &LastValue{})
 
 		// logical
 		expr.FuncAdd("gt", &Gt{})
@@ -1,8 +1,10 @@
 package builtins
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"testing"
@@ -746,6 +748,9 @@ var builtinTests = []testBuiltins{
 	{`sum(nil)`, value.ErrValue},
 	{`sum("",0)`, value.ErrValue},
 
+	// grouping() of a grouping set is computed by the group by
+	{`grouping(event)`, value.NewIntValue(0)},
+
 	{`avg(1,2)`, value.NewNumberValue(1.5)},
 	{`avg(1,[2,3])`, value.NewNumberValue(2.0)},
 	{`avg(1,"2")`, value.NewNumberValue(1.5)},
@@ -847,6 +852,7 @@ var testValidation = []string{
 	`avg()`,                   // must have 1 args
 	`sum()`,                   // must have 1 args
 	`count()`, `count(a,b,c)`, // must have 1 arg
+	`grouping()`, // must have 1 or more args
 
 	// strings
 	`contains()`, `contains(a,b,c)`, // must be 2 args
@@ -944,6 +950,181 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestAggregators(t *testing.T) {
+	tests := []struct {
+		fn   string
+		vals []value.Value
+		want interface{}
+	}{
+		{`sum(x)`, []value.Value{value.NewIntValue(1), value.NewStringValue("2.5"), value.NewNilValue(), value.NewNumberValue(3)}, 6.5},
+		{`avg(x)`, []value.Value{value.NewIntValue(1), value.NewNilValue(), value.NewIntValue(2), value.NewIntValue(6)}, 3.0},
+		{`sum(x)`, []value.Value{value.NewNilValue(), value.NewNilValue()}, nil},
+		{`avg(x)`, []value.Value{}, nil},
+		{`avg(x)`, []value.Value{value.NewNilValue()}, nil},
+		{`count(x)`, []value.Value{value.NewIntValue(1), value.NewNilValue(), value.NewIntValue(1)}, int64(2)},
+		{`count(*)`, []value.Value{value.NewIntValue(1), value.NewNilValue(), value.NewIntValue(1)}, int64(3)},
+		{`count(DISTINCT x)`, []value.Value{value.NewIntValue(1), value.NewIntValue(2), value.NewNilValue(), value.NewIntValue(1)}, int64(2)},
+		{`min(x)`, []value.Value{value.NewIntValue(5), value.NewNumberValue(2.5), value.NewNilValue(), value.NewIntValue(3)}, 2.5},
+		{`max(x)`, []value.Value{value.NewStringValue("9"), value.NewStringValue("10"), value.NewNilValue()}, "10"},
+		{`max(x)`, []value.Value{value.NewStringValue("1a"), value.NewStringValue("9"), value.NewStringValue("10")}, "1a"},
+		{`max(x)`, []value.Value{value.NewStringValue("b"), value.NewStringValue("c"), value.NewStringValue("a")}, "c"},
+		{`var_pop(x)`, []value.Value{value.NewIntValue(1), value.NewIntValue(2), value.NewIntValue(3), value.NewIntValue(4)}, 1.25},
+		{`variance(x)`, []value.Value{value.NewIntValue(2), value.NewIntValue(4), value.NewNilValue(), value.NewIntValue(6)}, 4.0},
+		{`stddev_pop(x)`, []value.Value{value.NewIntValue(2), value.NewIntValue(4), value.NewIntValue(4), value.NewIntValue(4),
+			value.NewIntValue(5), value.NewIntValue(5), value.NewIntValue(7), value.NewIntValue(9)}, 2.0},
+		{`stddev(x)`, []value.Value{value.NewIntValue(1)}, nil},
+		{`percentile(x, 0.5)`, []value.Value{value.NewIntValue(4), value.NewIntValue(1), value.NewIntValue(3), value.NewIntValue(2)}, 2.5},
+		{`percentile(x, 1)`, []value.Value{value.NewIntValue(4), value.NewIntValue(1), value.NewIntValue(3)}, 4.0},
+		{`array_agg(x)`, []value.Value{value.NewStringValue("a"), value.NewStringValue("b"), value.NewNilValue(), value.NewStringValue("c")},
+			[]value.Value{value.NewStringValue("a"), value.NewStringValue("b"), value.NewStringValue("c")}},
+		{`group_concat(x)`, []value.Value{value.NewStringValue("a"), value.NewStringValue("b"), value.NewStringValue("c")}, "a,c,b"},
+		{`group_concat(x, " | ")`, []value.Value{value.NewStringValue("a"), value.NewIntValue(2)}, "a | 2"},
+	}
+	for _, tt := range tests {
+		fn := expr.MustParse(tt.fn).(*expr.FuncNode)
+		af, ok := fn.F.CustomFunc.(expr.AggregateFunc)
+		assert.True(t, ok, "%s is an aggregate", tt.fn)
+
+		// half the values on each of two aggregators, merged
+		final, err := af.NewAggregator(fn)
+		assert.Equal(t, nil, err)
+		partial, err := af.NewAggregator(fn)
+		assert.Equal(t, nil, err)
+		for i, v := range tt.vals {
+			if i%2 == 0 {
+				final.Accumulate([]value.Value{v})
+			} else {
+				partial.Accumulate([]value.Value{v})
+			}
+		}
+		final.Merge(partial.Partial())
+		assert.Equal(t, tt.want, final.Finalize().Value(), tt.fn)
+	}
+
+	_, err := (&Sum{}).NewAggregator(expr.MustParse(`sum(x, y)`).(*expr.FuncNode))
+	assert.NotEqual(t, nil, err)
+	_, err = expr.ParseExpression(`percentile(x, 2)`)
+	assert.NotEqual(t, nil, err)
+	_, err = expr.ParseExpression(`group_concat(x, y)`)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestAggregatorSize(t *testing.T) {
+	// aggregators holding values grow with them, others don't report a size
+	for _, fnText := range []string{`count(DISTINCT x)`, `percentile(x, 0.5)`, `array_agg(x)`,
+		`group_concat(x)`, `approx_percentile(x, 0.5)`} {
+		fn := expr.MustParse(fnText).(*expr.FuncNode)
+		agg, err := fn.F.CustomFunc.(expr.AggregateFunc).NewAggregator(fn)
+		assert.Equal(t, nil, err)
+		sz, ok := agg.(expr.AggregatorSizer)
+		assert.True(t, ok, fnText)
+		agg.Accumulate([]value.Value{value.NewIntValue(1)})
+		before := sz.MemSize()
+		for i := 2; i < 100; i++ {
+			agg.Accumulate([]value.Value{value.NewIntValue(int64(i))})
+		}
+		assert.True(t, sz.MemSize() > before, "%s grew %d to %d", fnText, before, sz.MemSize())
+	}
+
+	fn := expr.MustParse(`count(DISTINCT x)`).(*expr.FuncNode)
+	agg, _ := fn.F.CustomFunc.(expr.AggregateFunc).NewAggregator(fn)
+	agg.Accumulate([]value.Value{value.NewStringValue("a")})
+	before := agg.(expr.AggregatorSizer).MemSize()
+	agg.Accumulate([]value.Value{value.NewStringValue("a")})
+	assert.Equal(t, before, agg.(expr.AggregatorSizer).MemSize(), "values seen before are not held again")
+
+	fn = expr.MustParse(`sum(x)`).(*expr.FuncNode)
+	agg, _ = fn.F.CustomFunc.(expr.AggregateFunc).NewAggregator(fn)
+	_, ok := agg.(expr.AggregatorSizer)
+	assert.False(t, ok)
+}
+
+func TestApproxAggregators(t *testing.T) {
+
+	newAgg := func(fnText string) expr.Aggregator {
+		fn := expr.MustParse(fnText).(*expr.FuncNode)
+		agg, err := fn.F.CustomFunc.(expr.AggregateFunc).NewAggregator(fn)
+		assert.Equal(t, nil, err, fnText)
+		return agg
+	}
+	within := func(want, got, relErr float64, msg string) {
+		assert.True(t, math.Abs(got-want) <= want*relErr, "%s want %v got %v", msg, want, got)
+	}
+
+	// 10k distinct values, each twice, half on each of two aggregators
+	final, partial := newAgg(`approx_count_distinct(x)`), newAgg(`approx_count_distinct(x)`)
+	for i := 0; i < 20000; i++ {
+		v := []value.Value{value.NewIntValue(int64(i % 10000))}
+		if i%2 == 0 {
+			final.Accumulate(v)
+		} else {
+			partial.Accumulate(v)
+		}
+	}
+	final.Merge(partial.Partial())
+	within(10000, float64(final.Finalize().Value().(int64)), 0.03, "approx_count_distinct")
+
+	small := newAgg(`approx_count_distinct(x, 0.05)`)
+	for _, v := range []string{"a", "b", "a", "c"} {
+		small.Accumulate([]value.Value{value.NewStringValue(v)})
+	}
+	small.Accumulate([]value.Value{value.NewNilValue()})
+	assert.Equal(t, int64(3), small.Finalize().Value())
+	assert.Equal(t, 2+512, len(small.Partial().State), "0.05 error is 2^9 registers")
+
+	// sketches stored as rollups, of different error, then merged
+	day1, day2 := newAgg(`hll_sketch(x)`), newAgg(`hll_sketch(x, 0.05)`)
+	for i := 0; i < 5000; i++ {
+		day1.Accumulate([]value.Value{value.NewIntValue(int64(i))})
+		day2.Accumulate([]value.Value{value.NewIntValue(int64(i + 2500))})
+	}
+	rollup := newAgg(`hll_merge(x)`)
+	rollup.Accumulate([]value.Value{day1.Finalize()})
+	rollup.Accumulate([]value.Value{value.NewStringValue(base64.StdEncoding.EncodeToString(day2.Finalize().Value().([]byte)))})
+	ct, ok := hllCountEval(nil, []value.Value{rollup.Finalize()})
+	assert.True(t, ok)
+	within(7500, float64(ct.Value().(int64)), 0.15, "hll_merge")
+
+	final, partial = newAgg(`approx_percentile(x, 0.5)`), newAgg(`approx_percentile(x, 0.5)`)
+	p99 := newAgg(`approx_percentile(x, 0.99, 200)`)
+	for i := 1; i <= 10000; i++ {
+		v := []value.Value{value.NewIntValue(int64(i))}
+		if i%2 == 0 {
+			final.Accumulate(v)
+		} else {
+			partial.Accumulate(v)
+		}
+		p99.Accumulate(v)
+	}
+	final.Merge(partial.Partial())
+	within(5000.5, final.Finalize().Value().(float64), 0.01, "approx_percentile 0.5")
+	within(9900, p99.Finalize().Value().(float64), 0.001, "approx_percentile 0.99")
+
+	exact := newAgg(`approx_percentile(x, 0.5)`)
+	for _, v := range []int64{4, 1, 3, 2} {
+		exact.Accumulate([]value.Value{value.NewIntValue(v)})
+	}
+	assert.Equal(t, 2.5, exact.Finalize().Value())
+
+	digest1, digest2 := newAgg(`tdigest_sketch(x)`), newAgg(`tdigest_sketch(x)`)
+	for i := 1; i <= 1000; i++ {
+		digest1.Accumulate([]value.Value{value.NewIntValue(int64(i))})
+		digest2.Accumulate([]value.Value{value.NewIntValue(int64(i + 1000))})
+	}
+	rollup = newAgg(`tdigest_merge(x)`)
+	rollup.Accumulate([]value.Value{digest1.Finalize()})
+	rollup.Accumulate([]value.Value{digest2.Finalize()})
+	median, ok := tdigestPercentileEval(nil, []value.Value{rollup.Finalize(), value.NewNumberValue(0.5)})
+	assert.True(t, ok)
+	within(1000.5, median.Value().(float64), 0.01, "tdigest_merge")
+
+	for _, bad := range []string{`approx_percentile(x, 2)`, `approx_percentile(x, 0.5, 1)`,
+		`approx_count_distinct(x, 0)`, `approx_count_distinct(x, 0.0001)`} {
+		_, err := expr.ParseExpression(bad)
+		assert.NotEqual(t, nil, err, bad)
+	}
+}
+
 func TestBuiltins(t *testing.T) {
 
 	t1 := dateparse.MustParse("12/18/2015")
@@ -0,0 +1,108 @@
+package builtins
+
+import (
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
+)
+
+// Window functions are only meaningful in a window, ie
+// row_number() OVER (PARTITION BY x ORDER BY y), they are computed by the
+// window task of the executor across the rows of the partition.  Outside
+// of a window they evaluate to nil.
+
+// RowNumber the number of the row in its partition, starting at 1.
+//
+//	row_number() OVER (PARTITION BY user_id ORDER BY price DESC)
+type RowNumber struct{}
+
+// Type is IntType
+func (m *RowNumber) Type() value.ValueType { return value.IntType }
+func (m *RowNumber) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 0, 0)
+}
+
+// Rank the rank of the row in its partition, rows with equal ORDER BY
+// values (peers) have the same rank, leaving gaps after them.
+//
+//	rank() OVER (ORDER BY price DESC)  => 1, 1, 3
+type Rank struct{}
+
+// Type is IntType
+func (m *Rank) Type() value.ValueType { return value.IntType }
+func (m *Rank) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 0, 0)
+}
+
+// DenseRank the rank of the row in its partition, without gaps.
+//
+//	dense_rank() OVER (ORDER BY price DESC)  => 1, 1, 2
+type DenseRank struct{}
+
+// Type is IntType
+func (m *DenseRank) Type() value.ValueType { return value.IntType }
+func (m *DenseRank) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 0, 0)
+}
+
+// Lag the value of the expression offset (default 1) rows before this row
+// in its partition, or default (nil) if there is no such row.
+//
+//	lag(price) OVER (ORDER BY order_date)
+//	lag(price, 2, 0) OVER (ORDER BY order_date)
+type Lag struct{}
+
+// Type is unknown, the type of the expression
+func (m *Lag) Type() value.ValueType { return value.UnknownType }
+func (m *Lag) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 1, 3)
+}
+
+// Lead the value of the expression offset (default 1) rows after this row
+// in its partition, or default (nil) if there is no such row.
+//
+//	lead(price) OVER (ORDER BY order_date)
+type Lead struct{}
+
+// Type is unknown, the type of the expression
+func (m *Lead) Type() value.ValueType { return value.UnknownType }
+func (m *Lead) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 1, 3)
+}
+
+// FirstValue the value of the expression at the first row of the frame.
+//
+//	first_value(item) OVER (PARTITION BY user_id ORDER BY price DESC)
+type FirstValue struct{}
+
+// Type is unknown, the type of the expression
+func (m *FirstValue) Type() value.ValueType { return value.UnknownType }
+func (m *FirstValue) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 1, 1)
+}
+
+// LastValue the value of the expression at the last row of the frame.
+//
+//	last_value(item) OVER (ORDER BY price ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)
+type LastValue struct{}
+
+// Type is unknown, the type of the expression
+func (m *LastValue) Type() value.ValueType { return value.UnknownType }
+func (m *LastValue) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return windowValidate(n, 1, 1)
+}
+
+func windowValidate(n *expr.FuncNode, min, max int) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < min || len(n.Args) > max {
+		if min == max {
+			return nil, fmt.Errorf("Expected %d args for %s but got %s", min, n.Name, n)
+		}
+		return nil, fmt.Errorf("Expected %d to %d args for %s but got %s", min, max, n.Name, n)
+	}
+	return windowEval, nil
+}
+
+func windowEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	return nil, false
+}
@@ -28,6 +28,47 @@ type (
 	AggFunc interface {
 		IsAgg() bool
 	}
+	// AggregateFunc an aggregate function which provides the running state to
+	// compute it over the rows of each group of a GROUP BY.
+	AggregateFunc interface {
+		AggFunc
+		// NewAggregator init the state of the aggregate for one group, for
+		// this use (args) of the function.
+		NewAggregator(n *FuncNode) (Aggregator, error)
+	}
+	// Aggregator the running state of an aggregate function over the rows
+	// of one group.
+	Aggregator interface {
+		// Accumulate the evaluated arguments of the function for a row, of
+		// count(DISTINCT x) the argument is x.
+		Accumulate(args []value.Value)
+		// Merge the partial state of this aggregate over other rows of the
+		// group, ie computed on another node.
+		Merge(p *AggPartial)
+		// Partial the state so far, for a final aggregator to Merge.
+		Partial() *AggPartial
+		// Finalize the result of the aggregate.
+		Finalize() value.Value
+	}
+	// AggregatorSizer is an optional interface for an Aggregator whose state
+	// grows with the values it accumulates, ie the values of array_agg or
+	// the keys of count(DISTINCT x), for the memory limit of a group by.
+	AggregatorSizer interface {
+		// MemSize a rough estimate of the bytes of state held.
+		MemSize() int64
+	}
+	// AggPartial is the partial state of an aggregate, computed over some
+	// of the rows of a group, to be merged by a final aggregator.  IE, for
+	// consistent-hash based group-bys calculated across multiple nodes.
+	AggPartial struct {
+		Ct    int64
+		N     float64
+		M2    float64       // sum of squared differences from the mean, of variance
+		Keys  []string      // distinct value keys for count(distinct x)
+		Vals  []interface{} // values held, ie of min, max, percentile, array_agg
+		State []byte        // state of other aggregates, in their own encoding
+		Parts []*AggPartial // of each aggregate of an expression over aggregates
+	}
 	// FuncResolver is a function resolution interface that allows
 	// local/namespaced function resolution.
 	FuncResolver interface {
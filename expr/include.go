@@ -40,6 +40,34 @@ func inlineIncludesDepth(ctx Includer, arg Node, depth int) (Node, error) {
 	}
 
 	switch n := arg.(type) {
+	case *CaseNode:
+		// ChildrenArgs of a CASE is a copy, so replace the fields
+		var err error
+		if n.Operand, err = inlineIncludesDepth(ctx, n.Operand, depth+1); err != nil {
+			return nil, err
+		}
+		for i := range n.Whens {
+			if n.Whens[i], err = inlineIncludesDepth(ctx, n.Whens[i], depth+1); err != nil {
+				return nil, err
+			}
+			if n.Thens[i], err = inlineIncludesDepth(ctx, n.Thens[i], depth+1); err != nil {
+				return nil, err
+			}
+		}
+		if n.Else, err = inlineIncludesDepth(ctx, n.Else, depth+1); err != nil {
+			return nil, err
+		}
+		return arg, nil
+	case *WindowNode:
+		var err error
+		for _, args := range [][]Node{n.Func.Args, n.Partition, n.OrderBy} {
+			for i := range args {
+				if args[i], err = inlineIncludesDepth(ctx, args[i], depth+1); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return arg, nil
 	// FuncNode, BinaryNode, BooleanNode, TriNode, UnaryNode, ArrayNode
 	case NodeArgs:
 		args := n.ChildrenArgs()
@@ -118,6 +146,14 @@ func findAllIncludes(node Node, current []string) []string {
 		for _, arg := range n.Args {
 			current = findAllIncludes(arg, current)
 		}
+	case *CaseNode:
+		for _, arg := range n.ChildrenArgs() {
+			current = findAllIncludes(arg, current)
+		}
+	case *WindowNode:
+		for _, arg := range n.ChildrenArgs() {
+			current = findAllIncludes(arg, current)
+		}
 	}
 	return current
 }
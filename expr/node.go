@@ -57,6 +57,8 @@ var (
 	_ NodeArgs = (*FuncNode)(nil)
 	_ NodeArgs = (*UnaryNode)(nil)
 	_ NodeArgs = (*ArrayNode)(nil)
+	_ NodeArgs = (*CaseNode)(nil)
+	_ NodeArgs = (*WindowNode)(nil)
 )
 
 type (
@@ -205,6 +207,7 @@ type (
 		Eval    EvaluatorFunc // the evaluator function
 		Missing bool
 		Args    []Node // Arguments are them-selves nodes
+		Filter  Node   // aggregate FILTER (WHERE cond), only rows where cond is true
 	}
 
 	// IdentityNode will look up a value out of a env bag also identities of
@@ -232,6 +235,17 @@ type (
 	// NullNode is a simple NULL type node
 	NullNode struct{}
 
+	// ParamNode is a parameter of a prepared statement, positional ? or
+	// numbered $1, whose value is bound at execution.  Once bound it
+	// evaluates, and serializes, as the literal value.
+	//
+	//    SELECT * FROM orders WHERE user_id = ? AND price > ?
+	ParamNode struct {
+		Text  string      // ? or $1
+		Index int         // 1 based index of the arg bound to it
+		Value value.Value // bound value, nil if not bound
+	}
+
 	// NumberNode holds a number: signed or unsigned integer or float.
 	// The value is parsed and stored under all the types that can represent the value.
 	// This simulates in a small amount of code the behavior of Go's ideal constants.
@@ -309,8 +323,85 @@ type (
 		wraptype string //  (   or [
 		Args     []Node
 	}
+
+	// SubQuery is a statement nested inside of an expression, the
+	// sql statement types (rel.SqlSelect) implement this
+	SubQuery interface {
+		String() string
+		WriteDialect(w DialectWriter)
+	}
+
+	// SubQueryNode is a parenthesized sub-select used as an argument
+	//
+	//    user_id IN (SELECT user_id FROM orders)
+	//    EXISTS (SELECT 1 FROM orders WHERE price > 10)
+	//
+	SubQueryNode struct {
+		Stmt SubQuery
+		Eval SubQueryEvaluator // evaluator of a scalar sub-query, bound by executor
+	}
+
+	// SubQueryEvaluator evaluates a scalar sub-query for the current row
+	// (which has the values of any outer references) of ctx.
+	SubQueryEvaluator func(ctx EvalContext) (value.Value, bool)
+
+	// CaseNode is a searched (no Operand) or simple CASE expression, the
+	// Whens and Thens are pairs, the first matching When returns its Then.
+	//
+	//    CASE WHEN x > 5 THEN "big" WHEN x > 1 THEN "medium" ELSE "small" END
+	//    CASE x WHEN 1 THEN "one" WHEN 2 THEN "two" END
+	//
+	CaseNode struct {
+		Operand Node // optional, the value compared to each When
+		Whens   []Node
+		Thens   []Node
+		Else    Node // optional, if missing a CASE with no match is NULL
+	}
+
+	// WindowNode is a function evaluated over a window of the rows of a query,
+	// the rows of the same Partition ordered by OrderBy.  The window function
+	// is computed by the window task of the executor, so evaluating this node
+	// reads that result from the current row.
+	//
+	//    row_number() OVER (PARTITION BY user_id ORDER BY price DESC)
+	//    sum(price) OVER (ORDER BY item_id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)
+	//
+	WindowNode struct {
+		Func      *FuncNode
+		Partition []Node
+		OrderBy   []Node
+		Desc      []bool       // for each OrderBy, is it descending
+		Frame     *WindowFrame // optional, the rows an aggregate is evaluated over
+	}
+
+	// WindowFrame the rows of the partition, relative to the current row, a
+	// window aggregate is evaluated over.  ROWS offsets count rows, RANGE
+	// offsets are of the (single, numeric) ORDER BY value and CURRENT ROW
+	// includes all rows with an equal ORDER BY value (its peers).
+	WindowFrame struct {
+		Range  bool
+		Start  FrameBound
+		StartN int64 // offset of a PRECEDING/FOLLOWING Start
+		End    FrameBound
+		EndN   int64 // offset of a PRECEDING/FOLLOWING End
+	}
+
+	// FrameBound the start or end of a WindowFrame
+	FrameBound uint8
 )
 
+const (
+	FrameUnboundedPreceding FrameBound = iota
+	FramePreceding
+	FrameCurrentRow
+	FrameFollowing
+	FrameUnboundedFollowing
+)
+
+// SubQueryParser parses the sql text of a SubQueryNode back into a statement
+// when deserializing, it is registered by the package implementing SubQuery.
+var SubQueryParser func(sql string) (SubQuery, error)
+
 // Includer defines an interface used for resolving INCLUDE clauses into a
 // Indclude reference. Implementations should return an error if the name cannot
 // be resolved.
@@ -369,6 +460,34 @@ func FindAllIdentities(node Node) IdentityNodes {
 	in := make(IdentityNodes, 0)
 	return findIdentities(node, in)
 }
+
+// FindAggregates Recursively descend down a node looking for aggregate
+// functions, not those of window functions or sub-queries, nor any
+// within the args of an aggregate
+//
+//	round(sum(price) / count(*), 2) == {sum(price), count(*)}
+func FindAggregates(node Node) []*FuncNode {
+	return findAggregates(node, nil)
+}
+
+func findAggregates(node Node, l []*FuncNode) []*FuncNode {
+	switch n := node.(type) {
+	case *FuncNode:
+		if n.F.Aggregate {
+			return append(l, n)
+		}
+	case *WindowNode, *SubQueryNode:
+		return l
+	case *UnaryNode:
+		return findAggregates(n.Arg, l)
+	}
+	if na, ok := node.(NodeArgs); ok {
+		for _, arg := range na.ChildrenArgs() {
+			l = findAggregates(arg, l)
+		}
+	}
+	return l
+}
 func findIdentities(node Node, l IdentityNodes) IdentityNodes {
 	switch n := node.(type) {
 	case *IdentityNode:
@@ -395,6 +514,17 @@ func findIdentities(node Node, l IdentityNodes) IdentityNodes {
 		for _, arg := range n.Args {
 			l = findIdentities(arg, l)
 		}
+		if n.Filter != nil {
+			l = findIdentities(n.Filter, l)
+		}
+	case *CaseNode:
+		for _, arg := range n.ChildrenArgs() {
+			l = findIdentities(arg, l)
+		}
+	case *WindowNode:
+		for _, arg := range n.ChildrenArgs() {
+			l = findIdentities(arg, l)
+		}
 	}
 	return l
 }
@@ -497,8 +627,33 @@ func ValueTypeFromNode(n Node) value.ValueType {
 		return value.UnknownType
 	case *NumberNode:
 		return value.NumberType
+	case *ParamNode:
+		// the type of the value bound, if any
+		if nt.Value != nil {
+			return nt.Value.Type()
+		}
+		return value.UnknownType
 	case *BooleanNode:
 		return value.BoolType
+	case *CaseNode:
+		// if all of the results are the same type, that is the type
+		if len(nt.Thens) == 0 {
+			return value.UnknownType
+		}
+		vt := ValueTypeFromNode(nt.Thens[0])
+		for _, arg := range nt.Thens[1:] {
+			if ValueTypeFromNode(arg) != vt {
+				return value.UnknownType
+			}
+		}
+		if _, isNull := nt.Else.(*NullNode); nt.Else != nil && !isNull {
+			if ValueTypeFromNode(nt.Else) != vt {
+				return value.UnknownType
+			}
+		}
+		return vt
+	case *WindowNode:
+		return ValueTypeFromNode(nt.Func)
 	case *BinaryNode:
 		switch nt.Operator.T {
 		case lex.TokenLogicAnd, lex.TokenAnd, lex.TokenLogicOr, lex.TokenOr,
@@ -539,6 +694,11 @@ func (m *FuncNode) WriteDialect(w DialectWriter) {
 		arg.WriteDialect(w)
 	}
 	io.WriteString(w, ")")
+	if m.Filter != nil {
+		io.WriteString(w, " FILTER (WHERE ")
+		m.Filter.WriteDialect(w)
+		io.WriteString(w, ")")
+	}
 }
 func (m *FuncNode) Validate() error {
 
@@ -573,6 +733,9 @@ func (m *FuncNode) NodePb() *NodePb {
 	for i, a := range m.Args {
 		n.Args[i] = *a.NodePb()
 	}
+	if m.Filter != nil {
+		n.Filter = m.Filter.NodePb()
+	}
 	return &NodePb{Fn: n}
 }
 func (m *FuncNode) FromPB(n *NodePb) Node {
@@ -588,6 +751,9 @@ func (m *FuncNode) FromPB(n *NodePb) Node {
 		Args: NodesFromNodesPb(n.Fn.Args),
 		F:    fn,
 	}
+	if n.Fn.Filter != nil {
+		f.Filter = NodeFromNodePb(n.Fn.Filter)
+	}
 
 	if err := f.Validate(); err != nil {
 		u.Warnf("could not validate %v", err)
@@ -598,11 +764,9 @@ func (m *FuncNode) FromPB(n *NodePb) Node {
 
 // Expr convert the FuncNode to Expr
 func (m *FuncNode) Expr() *Expr {
-	fe := &Expr{Op: lex.TokenUdfExpr.String()}
-	if len(m.Args) > 0 {
-		fe.Args = []*Expr{{Identity: m.Name}}
-		fe.Args = append(fe.Args, ExprsFromNodes(m.Args)...)
-	}
+	// the name is always the first arg, so no-arg functions ie now() round-trip
+	fe := &Expr{Op: lex.TokenUdfExpr.String(), Args: []*Expr{{Identity: m.Name}}}
+	fe.Args = append(fe.Args, ExprsFromNodes(m.Args)...)
 	return fe
 }
 func (m *FuncNode) FromExpr(e *Expr) error {
@@ -662,6 +826,12 @@ func (m *FuncNode) Equal(n Node) bool {
 				return false
 			}
 		}
+		if (m.Filter == nil) != (nt.Filter == nil) {
+			return false
+		}
+		if m.Filter != nil && !m.Filter.Equal(nt.Filter) {
+			return false
+		}
 		return true
 	}
 	return false
@@ -1049,7 +1219,9 @@ func (m *IdentityNode) NodePb() *NodePb {
 }
 func (m *IdentityNode) FromPB(n *NodePb) Node {
 	q := n.In.Quote
-	return &IdentityNode{Text: n.In.Text, Quote: byte(*q)}
+	in := &IdentityNode{Text: n.In.Text, Quote: byte(*q)}
+	in.load()
+	return in
 }
 func (m *IdentityNode) Expr() *Expr {
 	if m.IsBooleanIdentity() {
@@ -1194,6 +1366,108 @@ func (m *NullNode) Equal(n Node) bool {
 	return false
 }
 
+// NewParamNode a parameter of text ? or $1, the index of a positional
+// parameter is assigned by the ParamPager.
+func NewParamNode(text string) (*ParamNode, error) {
+	n := &ParamNode{Text: text}
+	if text != "?" {
+		if !strings.HasPrefix(text, "$") {
+			return nil, fmt.Errorf("invalid parameter %q", text)
+		}
+		idx, err := strconv.Atoi(text[1:])
+		if err != nil || idx < 1 {
+			return nil, fmt.Errorf("invalid parameter %q", text)
+		}
+		n.Index = idx
+	}
+	return n, nil
+}
+func (m *ParamNode) NodeType() string { return "Param" }
+func (m *ParamNode) String() string {
+	w := NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+
+// WriteDialect the literal value once bound, otherwise as the numbered
+// parameter, so a copy parsed from the text is the same parameter.
+func (m *ParamNode) WriteDialect(w DialectWriter) {
+	if m.Value != nil {
+		paramLiteral(m.Value).WriteDialect(w)
+		return
+	}
+	io.WriteString(w, m.numbered())
+}
+func (m *ParamNode) numbered() string {
+	return fmt.Sprintf("$%d", m.Index)
+}
+func (m *ParamNode) Validate() error {
+	if m.Index < 1 {
+		return fmt.Errorf("Invalid ParamNode, no index %q", m.Text)
+	}
+	return nil
+}
+func (m *ParamNode) NodePb() *NodePb {
+	if m.Value != nil {
+		return paramLiteral(m.Value).NodePb()
+	}
+	return &NodePb{Paramn: &ParamNodePb{Text: m.Text, Index: int32(m.Index)}}
+}
+func (m *ParamNode) FromPB(n *NodePb) Node {
+	return &ParamNode{Text: n.Paramn.Text, Index: int(n.Paramn.Index)}
+}
+func (m *ParamNode) Expr() *Expr {
+	if m.Value != nil {
+		return paramLiteral(m.Value).Expr()
+	}
+	return &Expr{Op: "param", Value: m.numbered()}
+}
+func (m *ParamNode) FromExpr(e *Expr) error {
+	n, err := NewParamNode(e.Value)
+	if err != nil {
+		return err
+	}
+	*m = *n
+	return nil
+}
+func (m *ParamNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*ParamNode); ok {
+		return m.Index == nt.Index
+	}
+	return false
+}
+
+// Bind the value of the parameter.
+func (m *ParamNode) Bind(v value.Value) { m.Value = v }
+
+// paramLiteral the literal node of the value bound to a parameter.
+func paramLiteral(v value.Value) Node {
+	switch vt := v.(type) {
+	case value.NilValue, *value.NilValue:
+		return &NullNode{}
+	case value.IntValue:
+		n, _ := NewNumberStr(vt.ToString())
+		return n
+	case value.NumberValue:
+		n, _ := NewNumberStr(strconv.FormatFloat(vt.Val(), 'f', -1, 64))
+		return n
+	case value.BoolValue:
+		return NewIdentityNodeVal(vt.ToString())
+	case value.TimeValue:
+		return NewStringNode(vt.Val().Format(time.RFC3339Nano))
+	}
+	return NewStringNode(v.ToString())
+}
+
 /*
 binary_op  = "||" | "&&" | rel_op | add_op | mul_op .
 rel_op     = "==" | "!=" | "<" | "<=" | ">" | ">=" .
@@ -2018,6 +2292,571 @@ func (m *ArrayNode) Equal(n Node) bool {
 	return false
 }
 
+// NewSubQueryNode create a node for the given nested statement
+func NewSubQueryNode(stmt SubQuery) *SubQueryNode {
+	return &SubQueryNode{Stmt: stmt}
+}
+func (m *SubQueryNode) NodeType() string { return "SubQuery" }
+func (m *SubQueryNode) String() string {
+	w := NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *SubQueryNode) WriteDialect(w DialectWriter) {
+	io.WriteString(w, "(")
+	if m.Stmt != nil {
+		m.Stmt.WriteDialect(w)
+	}
+	io.WriteString(w, ")")
+}
+func (m *SubQueryNode) Validate() error {
+	if m.Stmt == nil {
+		return fmt.Errorf("Invalid SubQueryNode, no statement")
+	}
+	return nil
+}
+func (m *SubQueryNode) NodePb() *NodePb {
+	return &NodePb{Sqn: &SubQueryNodePb{Sql: m.Stmt.String()}}
+}
+func (m *SubQueryNode) FromPB(n *NodePb) Node {
+	sn := &SubQueryNode{}
+	if err := sn.parse(n.Sqn.Sql); err != nil {
+		u.Warnf("could not parse sub-query %q err=%v", n.Sqn.Sql, err)
+	}
+	return sn
+}
+func (m *SubQueryNode) Expr() *Expr {
+	return &Expr{Op: "select", Value: m.Stmt.String()}
+}
+func (m *SubQueryNode) FromExpr(e *Expr) error {
+	if e.Value == "" {
+		return fmt.Errorf("Invalid SubQueryNode, no statement %+v", e)
+	}
+	return m.parse(e.Value)
+}
+func (m *SubQueryNode) parse(sql string) error {
+	if SubQueryParser == nil {
+		return fmt.Errorf("no sub-query parser registered")
+	}
+	stmt, err := SubQueryParser(sql)
+	if err != nil {
+		return err
+	}
+	m.Stmt = stmt
+	return nil
+}
+func (m *SubQueryNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*SubQueryNode); ok {
+		if m.Stmt == nil || nt.Stmt == nil {
+			return m.Stmt == nil && nt.Stmt == nil
+		}
+		return m.Stmt.String() == nt.Stmt.String()
+	}
+	return false
+}
+
+// NewCaseNode create a CASE node, operand is nil for a searched CASE.
+func NewCaseNode(operand Node) *CaseNode {
+	return &CaseNode{Operand: operand}
+}
+func (m *CaseNode) NodeType() string { return "Case" }
+func (m *CaseNode) String() string {
+	w := NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *CaseNode) WriteDialect(w DialectWriter) {
+	io.WriteString(w, "CASE")
+	if m.Operand != nil {
+		io.WriteString(w, " ")
+		m.Operand.WriteDialect(w)
+	}
+	for i, when := range m.Whens {
+		io.WriteString(w, " WHEN ")
+		when.WriteDialect(w)
+		io.WriteString(w, " THEN ")
+		m.Thens[i].WriteDialect(w)
+	}
+	if m.Else != nil {
+		io.WriteString(w, " ELSE ")
+		m.Else.WriteDialect(w)
+	}
+	io.WriteString(w, " END")
+}
+func (m *CaseNode) Validate() error {
+	if len(m.Whens) == 0 {
+		return fmt.Errorf("Invalid CaseNode, expected at least one WHEN")
+	}
+	if len(m.Whens) != len(m.Thens) {
+		return fmt.Errorf("Invalid CaseNode, expected a THEN for each WHEN")
+	}
+	for _, n := range m.ChildrenArgs() {
+		if n == nil {
+			return fmt.Errorf("Invalid CaseNode, missing expression")
+		}
+		if err := n.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChildrenArgs the operand, when/then pairs and else of this CASE.
+func (m *CaseNode) ChildrenArgs() []Node {
+	args := make([]Node, 0, len(m.Whens)*2+2)
+	if m.Operand != nil {
+		args = append(args, m.Operand)
+	}
+	for i, when := range m.Whens {
+		args = append(args, when, m.Thens[i])
+	}
+	if m.Else != nil {
+		args = append(args, m.Else)
+	}
+	return args
+}
+func (m *CaseNode) NodePb() *NodePb {
+	n := &CaseNodePb{
+		Whens: make([]NodePb, len(m.Whens)),
+		Thens: make([]NodePb, len(m.Thens)),
+	}
+	if m.Operand != nil {
+		n.Arg = m.Operand.NodePb()
+	}
+	for i, arg := range m.Whens {
+		n.Whens[i] = *arg.NodePb()
+	}
+	for i, arg := range m.Thens {
+		n.Thens[i] = *arg.NodePb()
+	}
+	if m.Else != nil {
+		n.Else = m.Else.NodePb()
+	}
+	return &NodePb{Casen: n}
+}
+func (m *CaseNode) FromPB(n *NodePb) Node {
+	return &CaseNode{
+		Operand: NodeFromNodePb(n.Casen.Arg),
+		Whens:   NodesFromNodesPb(n.Casen.Whens),
+		Thens:   NodesFromNodesPb(n.Casen.Thens),
+		Else:    NodeFromNodePb(n.Casen.Else),
+	}
+}
+
+// Expr of a CASE, the when/then pairs and else are wrapped so the optional
+// operand is unambiguous.
+//
+//	{"op":"case","args":[<operand>,{"op":"when","args":[<when>,<then>]},{"op":"else","args":[<else>]}]}
+func (m *CaseNode) Expr() *Expr {
+	fe := &Expr{Op: "case"}
+	if m.Operand != nil {
+		fe.Args = append(fe.Args, m.Operand.Expr())
+	}
+	for i, when := range m.Whens {
+		fe.Args = append(fe.Args, &Expr{Op: "when", Args: []*Expr{when.Expr(), m.Thens[i].Expr()}})
+	}
+	if m.Else != nil {
+		fe.Args = append(fe.Args, &Expr{Op: "else", Args: []*Expr{m.Else.Expr()}})
+	}
+	return fe
+}
+func (m *CaseNode) FromExpr(e *Expr) error {
+	for i, arg := range e.Args {
+		switch strings.ToLower(arg.Op) {
+		case "when":
+			if len(arg.Args) != 2 {
+				return fmt.Errorf("Invalid CaseNode, expected WHEN, THEN args %+v", arg)
+			}
+			args, err := NodesFromExprs(arg.Args)
+			if err != nil {
+				return err
+			}
+			m.Whens = append(m.Whens, args[0])
+			m.Thens = append(m.Thens, args[1])
+		case "else":
+			if len(arg.Args) != 1 {
+				return fmt.Errorf("Invalid CaseNode, expected 1 ELSE arg %+v", arg)
+			}
+			n, err := NodeFromExpr(arg.Args[0])
+			if err != nil {
+				return err
+			}
+			m.Else = n
+		default:
+			if i != 0 {
+				return fmt.Errorf("Invalid CaseNode, unexpected arg %+v", arg)
+			}
+			n, err := NodeFromExpr(arg)
+			if err != nil {
+				return err
+			}
+			m.Operand = n
+		}
+	}
+	return m.Validate()
+}
+func (m *CaseNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	nt, ok := n.(*CaseNode)
+	if !ok {
+		return false
+	}
+	if (m.Operand == nil) != (nt.Operand == nil) || (m.Else == nil) != (nt.Else == nil) {
+		return false
+	}
+	if m.Operand != nil && !m.Operand.Equal(nt.Operand) {
+		return false
+	}
+	if m.Else != nil && !m.Else.Equal(nt.Else) {
+		return false
+	}
+	if len(m.Whens) != len(nt.Whens) || len(m.Thens) != len(nt.Thens) {
+		return false
+	}
+	for i, arg := range m.Whens {
+		if !arg.Equal(nt.Whens[i]) {
+			return false
+		}
+	}
+	for i, arg := range m.Thens {
+		if !arg.Equal(nt.Thens[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewWindowNode create a window function node, of fn OVER (...)
+func NewWindowNode(fn *FuncNode) *WindowNode {
+	return &WindowNode{Func: fn}
+}
+func (m *WindowNode) NodeType() string { return "Window" }
+func (m *WindowNode) String() string {
+	w := NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *WindowNode) WriteDialect(w DialectWriter) {
+	m.Func.WriteDialect(w)
+	io.WriteString(w, " OVER (")
+	space := ""
+	if len(m.Partition) > 0 {
+		io.WriteString(w, "PARTITION BY ")
+		for i, arg := range m.Partition {
+			if i > 0 {
+				io.WriteString(w, ", ")
+			}
+			arg.WriteDialect(w)
+		}
+		space = " "
+	}
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, space)
+		io.WriteString(w, "ORDER BY ")
+		for i, arg := range m.OrderBy {
+			if i > 0 {
+				io.WriteString(w, ", ")
+			}
+			arg.WriteDialect(w)
+			if m.Desc[i] {
+				io.WriteString(w, " DESC")
+			}
+		}
+		space = " "
+	}
+	if m.Frame != nil {
+		io.WriteString(w, space)
+		io.WriteString(w, m.Frame.String())
+	}
+	io.WriteString(w, ")")
+}
+func (m *WindowNode) Validate() error {
+	if m.Func == nil {
+		return fmt.Errorf("Invalid WindowNode, missing function")
+	}
+	if len(m.Desc) != len(m.OrderBy) {
+		return fmt.Errorf("Invalid WindowNode, expected a direction for each ORDER BY")
+	}
+	if m.Frame != nil {
+		if err := m.Frame.Validate(); err != nil {
+			return err
+		}
+		if m.Frame.Range && (m.Frame.Start == FramePreceding || m.Frame.Start == FrameFollowing ||
+			m.Frame.End == FramePreceding || m.Frame.End == FrameFollowing) && len(m.OrderBy) != 1 {
+			return fmt.Errorf("Invalid WindowNode, RANGE with an offset requires exactly one ORDER BY")
+		}
+	}
+	for _, n := range m.ChildrenArgs() {
+		if n == nil {
+			return fmt.Errorf("Invalid WindowNode, missing expression")
+		}
+		if err := n.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChildrenArgs the function args, partition and order by expressions of this window.
+func (m *WindowNode) ChildrenArgs() []Node {
+	args := make([]Node, 0, len(m.Func.Args)+len(m.Partition)+len(m.OrderBy))
+	args = append(args, m.Func.Args...)
+	args = append(args, m.Partition...)
+	return append(args, m.OrderBy...)
+}
+func (m *WindowNode) NodePb() *NodePb {
+	n := &WindowNodePb{
+		Fn:        m.Func.NodePb(),
+		Partition: make([]NodePb, len(m.Partition)),
+		Order:     make([]NodePb, len(m.OrderBy)),
+		Desc:      m.Desc,
+	}
+	for i, arg := range m.Partition {
+		n.Partition[i] = *arg.NodePb()
+	}
+	for i, arg := range m.OrderBy {
+		n.Order[i] = *arg.NodePb()
+	}
+	if m.Frame != nil {
+		n.Frame = 1
+		if m.Frame.Range {
+			n.Frame = 2
+		}
+		n.Start, n.Startn = int32(m.Frame.Start), m.Frame.StartN
+		n.End, n.Endn = int32(m.Frame.End), m.Frame.EndN
+	}
+	return &NodePb{Windown: n}
+}
+func (m *WindowNode) FromPB(n *NodePb) Node {
+	wn := &WindowNode{
+		Partition: NodesFromNodesPb(n.Windown.Partition),
+		OrderBy:   NodesFromNodesPb(n.Windown.Order),
+		Desc:      n.Windown.Desc,
+	}
+	if len(wn.Desc) < len(wn.OrderBy) {
+		// repeated bools of all false may be dropped
+		wn.Desc = append(wn.Desc, make([]bool, len(wn.OrderBy)-len(wn.Desc))...)
+	}
+	wn.Func, _ = NodeFromNodePb(n.Windown.Fn).(*FuncNode)
+	if n.Windown.Frame > 0 {
+		wn.Frame = &WindowFrame{
+			Range:  n.Windown.Frame == 2,
+			Start:  FrameBound(n.Windown.Start),
+			StartN: n.Windown.Startn,
+			End:    FrameBound(n.Windown.End),
+			EndN:   n.Windown.Endn,
+		}
+	}
+	return wn
+}
+
+// Expr of a window function, the partition, order by and frame are wrapped
+// so they are distinguishable from the function.
+//
+//	{"op":"over","args":[<func>,{"op":"partition by","args":[..]},{"op":"order by","args":[<x>,{"op":"desc","args":[<y>]}]},
+//	   {"op":"rows","args":[{"op":"preceding","val":"2"},{"op":"current row"}]}]}
+func (m *WindowNode) Expr() *Expr {
+	fe := &Expr{Op: "over", Args: []*Expr{m.Func.Expr()}}
+	if len(m.Partition) > 0 {
+		fe.Args = append(fe.Args, &Expr{Op: "partition by", Args: ExprsFromNodes(m.Partition)})
+	}
+	if len(m.OrderBy) > 0 {
+		oe := &Expr{Op: "order by"}
+		for i, arg := range m.OrderBy {
+			if m.Desc[i] {
+				oe.Args = append(oe.Args, &Expr{Op: "desc", Args: []*Expr{arg.Expr()}})
+			} else {
+				oe.Args = append(oe.Args, arg.Expr())
+			}
+		}
+		fe.Args = append(fe.Args, oe)
+	}
+	if m.Frame != nil {
+		op := "rows"
+		if m.Frame.Range {
+			op = "range"
+		}
+		fe.Args = append(fe.Args, &Expr{Op: op, Args: []*Expr{
+			m.Frame.Start.expr(m.Frame.StartN),
+			m.Frame.End.expr(m.Frame.EndN),
+		}})
+	}
+	return fe
+}
+func (m *WindowNode) FromExpr(e *Expr) error {
+	if len(e.Args) == 0 {
+		return fmt.Errorf("Invalid WindowNode, expected function arg %+v", e)
+	}
+	fn, err := NodeFromExpr(e.Args[0])
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if m.Func, ok = fn.(*FuncNode); !ok {
+		return fmt.Errorf("Invalid WindowNode, expected function got %+v", e.Args[0])
+	}
+	for _, arg := range e.Args[1:] {
+		switch op := strings.ToLower(arg.Op); op {
+		case "partition by":
+			if m.Partition, err = NodesFromExprs(arg.Args); err != nil {
+				return err
+			}
+		case "order by":
+			for _, oe := range arg.Args {
+				desc := strings.ToLower(oe.Op) == "desc"
+				if desc {
+					if len(oe.Args) != 1 {
+						return fmt.Errorf("Invalid WindowNode, expected 1 DESC arg %+v", oe)
+					}
+					oe = oe.Args[0]
+				}
+				n, err := NodeFromExpr(oe)
+				if err != nil {
+					return err
+				}
+				m.OrderBy = append(m.OrderBy, n)
+				m.Desc = append(m.Desc, desc)
+			}
+		case "rows", "range":
+			if len(arg.Args) != 2 {
+				return fmt.Errorf("Invalid WindowNode, expected start, end of frame %+v", arg)
+			}
+			m.Frame = &WindowFrame{Range: op == "range"}
+			if m.Frame.Start, m.Frame.StartN, err = frameBoundFromExpr(arg.Args[0]); err != nil {
+				return err
+			}
+			if m.Frame.End, m.Frame.EndN, err = frameBoundFromExpr(arg.Args[1]); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Invalid WindowNode, unexpected arg %+v", arg)
+		}
+	}
+	return m.Validate()
+}
+func (m *WindowNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	nt, ok := n.(*WindowNode)
+	if !ok {
+		return false
+	}
+	if !m.Func.Equal(nt.Func) {
+		return false
+	}
+	if (m.Frame == nil) != (nt.Frame == nil) || (m.Frame != nil && *m.Frame != *nt.Frame) {
+		return false
+	}
+	if len(m.Partition) != len(nt.Partition) || len(m.OrderBy) != len(nt.OrderBy) {
+		return false
+	}
+	for i, arg := range m.Partition {
+		if !arg.Equal(nt.Partition[i]) {
+			return false
+		}
+	}
+	for i, arg := range m.OrderBy {
+		if !arg.Equal(nt.OrderBy[i]) || m.Desc[i] != nt.Desc[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate the start of a frame is not after its end.
+func (m *WindowFrame) Validate() error {
+	if m.Start == FrameUnboundedFollowing || m.End == FrameUnboundedPreceding {
+		return fmt.Errorf("Invalid window frame %s", m)
+	}
+	if m.Start > m.End {
+		return fmt.Errorf("Invalid window frame %s, start is after end", m)
+	}
+	if m.Start == m.End && m.Start == FramePreceding && m.StartN < m.EndN {
+		return fmt.Errorf("Invalid window frame %s, start is after end", m)
+	}
+	if m.Start == m.End && m.Start == FrameFollowing && m.StartN > m.EndN {
+		return fmt.Errorf("Invalid window frame %s, start is after end", m)
+	}
+	return nil
+}
+func (m *WindowFrame) String() string {
+	kind := "ROWS"
+	if m.Range {
+		kind = "RANGE"
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", kind, m.Start.format(m.StartN), m.End.format(m.EndN))
+}
+
+func (m FrameBound) String() string {
+	switch m {
+	case FrameUnboundedPreceding:
+		return "UNBOUNDED PRECEDING"
+	case FramePreceding:
+		return "PRECEDING"
+	case FrameCurrentRow:
+		return "CURRENT ROW"
+	case FrameFollowing:
+		return "FOLLOWING"
+	case FrameUnboundedFollowing:
+		return "UNBOUNDED FOLLOWING"
+	}
+	return "unknown"
+}
+func (m FrameBound) format(n int64) string {
+	if m == FramePreceding || m == FrameFollowing {
+		return fmt.Sprintf("%d %s", n, m)
+	}
+	return m.String()
+}
+func (m FrameBound) expr(n int64) *Expr {
+	e := &Expr{Op: strings.ToLower(m.String())}
+	if m == FramePreceding || m == FrameFollowing {
+		e.Value = strconv.FormatInt(n, 10)
+	}
+	return e
+}
+func frameBoundFromExpr(e *Expr) (FrameBound, int64, error) {
+	for _, fb := range []FrameBound{FrameUnboundedPreceding, FramePreceding, FrameCurrentRow,
+		FrameFollowing, FrameUnboundedFollowing} {
+		if !strings.EqualFold(e.Op, fb.String()) {
+			continue
+		}
+		if fb != FramePreceding && fb != FrameFollowing {
+			return fb, 0, nil
+		}
+		n, err := strconv.ParseInt(e.Value, 10, 64)
+		if err != nil {
+			return fb, 0, fmt.Errorf("Invalid window frame offset %+v", e)
+		}
+		return fb, n, nil
+	}
+	return 0, 0, fmt.Errorf("Invalid window frame bound %+v", e)
+}
+
 // Node serialization helpers
 func tokenFromInt(iv int32) lex.Token {
 	t, ok := lex.TokenNameMap[lex.TokenType(iv)]
@@ -2077,6 +2916,18 @@ func NodeFromNodePb(n *NodePb) Node {
 		return in.FromPB(n)
 	case n.Niln != nil:
 		return &NullNode{}
+	case n.Sqn != nil:
+		var sn *SubQueryNode
+		return sn.FromPB(n)
+	case n.Casen != nil:
+		var cn *CaseNode
+		return cn.FromPB(n)
+	case n.Windown != nil:
+		var wn *WindowNode
+		return wn.FromPB(n)
+	case n.Paramn != nil:
+		var pn *ParamNode
+		return pn.FromPB(n)
 	}
 	return nil
 }
@@ -2201,6 +3052,15 @@ func NodeFromExpr(e *Expr) (Node, error) {
 			n = &UnaryNode{}
 		case "BETWEEN":
 			n = &TriNode{}
+		case "SELECT":
+			n = &SubQueryNode{}
+		case "CASE":
+			n = &CaseNode{}
+		case "OVER":
+			n = &WindowNode{}
+		case "PARAM":
+			n = &ParamNode{}
+			return n, n.FromExpr(e)
 		case "=", "-", "+", "++", "+=", "/", "%", "==", "<=", "!=", ">=", ">", "<", "*",
 			"LIKE", "CONTAINS", "INTERSECTS", "IN":
 
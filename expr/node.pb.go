@@ -8,21 +8,26 @@
 	It is generated from these files:
 		node.proto
 
-	It has these top-level messages:
-		ExprPb
-		NodePb
-		BinaryNodePb
-		BooleanNodePb
-		IncludeNodePb
-		UnaryNodePb
-		FuncNodePb
-		TriNodePb
-		ArrayNodePb
-		StringNodePb
-		IdentityNodePb
-		NumberNodePb
-		ValueNodePb
-		NullNodePb
+	node.proto
+
+It has these top-level messages:
+
+	ExprPb
+	NodePb
+	BinaryNodePb
+	BooleanNodePb
+	IncludeNodePb
+	UnaryNodePb
+	FuncNodePb
+	TriNodePb
+	ArrayNodePb
+	StringNodePb
+	IdentityNodePb
+	NumberNodePb
+	ValueNodePb
+	NullNodePb
+	SubQueryNodePb
+	ParamNodePb
 */
 package expr
 
@@ -77,6 +82,10 @@ type NodePb struct {
 	Sn               *StringNodePb   `protobuf:"bytes,13,opt,name=sn" json:"sn,omitempty"`
 	Incn             *IncludeNodePb  `protobuf:"bytes,14,opt,name=incn" json:"incn,omitempty"`
 	Niln             *NullNodePb     `protobuf:"bytes,15,opt,name=niln" json:"niln,omitempty"`
+	Sqn              *SubQueryNodePb `protobuf:"bytes,16,opt,name=sqn" json:"sqn,omitempty"`
+	Casen            *CaseNodePb     `protobuf:"bytes,17,opt,name=casen" json:"casen,omitempty"`
+	Windown          *WindowNodePb   `protobuf:"bytes,18,opt,name=windown" json:"windown,omitempty"`
+	Paramn           *ParamNodePb    `protobuf:"bytes,19,opt,name=paramn" json:"paramn,omitempty"`
 	XXX_unrecognized []byte          `json:"-"`
 }
 
@@ -140,6 +149,7 @@ func (*UnaryNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []
 type FuncNodePb struct {
 	Name             string   `protobuf:"bytes,1,req,name=name" json:"name"`
 	Args             []NodePb `protobuf:"bytes,2,rep,name=args" json:"args"`
+	Filter           *NodePb  `protobuf:"bytes,3,opt,name=filter" json:"filter,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -235,6 +245,62 @@ func (m *NullNodePb) String() string            { return proto.CompactTextString
 func (*NullNodePb) ProtoMessage()               {}
 func (*NullNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{13} }
 
+// SubQuery Node, the nested statement as sql text
+type SubQueryNodePb struct {
+	Sql              string `protobuf:"bytes,1,req,name=sql" json:"sql"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SubQueryNodePb) Reset()                    { *m = SubQueryNodePb{} }
+func (m *SubQueryNodePb) String() string            { return proto.CompactTextString(m) }
+func (*SubQueryNodePb) ProtoMessage()               {}
+func (*SubQueryNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{14} }
+
+// Case Node, optional operand, when/then pairs and optional else
+type CaseNodePb struct {
+	Arg              *NodePb  `protobuf:"bytes,1,opt,name=arg" json:"arg,omitempty"`
+	Whens            []NodePb `protobuf:"bytes,2,rep,name=whens" json:"whens"`
+	Thens            []NodePb `protobuf:"bytes,3,rep,name=thens" json:"thens"`
+	Else             *NodePb  `protobuf:"bytes,4,opt,name=else" json:"else,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *CaseNodePb) Reset()                    { *m = CaseNodePb{} }
+func (m *CaseNodePb) String() string            { return proto.CompactTextString(m) }
+func (*CaseNodePb) ProtoMessage()               {}
+func (*CaseNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{15} }
+
+// Window Node, function over a partition, order and frame
+type WindowNodePb struct {
+	Fn               *NodePb  `protobuf:"bytes,1,opt,name=fn" json:"fn,omitempty"`
+	Partition        []NodePb `protobuf:"bytes,2,rep,name=partition" json:"partition"`
+	Order            []NodePb `protobuf:"bytes,3,rep,name=order" json:"order"`
+	Desc             []bool   `protobuf:"varint,4,rep,name=desc" json:"desc,omitempty"`
+	Frame            int32    `protobuf:"varint,5,opt,name=frame" json:"frame"`
+	Start            int32    `protobuf:"varint,6,opt,name=start" json:"start"`
+	Startn           int64    `protobuf:"varint,7,opt,name=startn" json:"startn"`
+	End              int32    `protobuf:"varint,8,opt,name=end" json:"end"`
+	Endn             int64    `protobuf:"varint,9,opt,name=endn" json:"endn"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *WindowNodePb) Reset()                    { *m = WindowNodePb{} }
+func (m *WindowNodePb) String() string            { return proto.CompactTextString(m) }
+func (*WindowNodePb) ProtoMessage()               {}
+func (*WindowNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{16} }
+
+// Param Node, parameter of a prepared statement
+type ParamNodePb struct {
+	Text             string `protobuf:"bytes,1,req,name=text" json:"text"`
+	Index            int32  `protobuf:"varint,2,req,name=index" json:"index"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *ParamNodePb) Reset()                    { *m = ParamNodePb{} }
+func (m *ParamNodePb) String() string            { return proto.CompactTextString(m) }
+func (*ParamNodePb) ProtoMessage()               {}
+func (*ParamNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{17} }
+
 func init() {
 	proto.RegisterType((*ExprPb)(nil), "expr.ExprPb")
 	proto.RegisterType((*NodePb)(nil), "expr.NodePb")
@@ -250,6 +316,10 @@ func init() {
 	proto.RegisterType((*NumberNodePb)(nil), "expr.NumberNodePb")
 	proto.RegisterType((*ValueNodePb)(nil), "expr.ValueNodePb")
 	proto.RegisterType((*NullNodePb)(nil), "expr.NullNodePb")
+	proto.RegisterType((*SubQueryNodePb)(nil), "expr.SubQueryNodePb")
+	proto.RegisterType((*CaseNodePb)(nil), "expr.CaseNodePb")
+	proto.RegisterType((*WindowNodePb)(nil), "expr.WindowNodePb")
+	proto.RegisterType((*ParamNodePb)(nil), "expr.ParamNodePb")
 }
 func (m *ExprPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -456,6 +526,54 @@ func (m *NodePb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n12
 	}
+	if m.Sqn != nil {
+		data[i] = 0x82
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Sqn.Size()))
+		n13, err := m.Sqn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.Casen != nil {
+		data[i] = 0x8a
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Casen.Size()))
+		n14, err := m.Casen.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.Windown != nil {
+		data[i] = 0x92
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Windown.Size()))
+		n15, err := m.Windown.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if m.Paramn != nil {
+		data[i] = 0x9a
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Paramn.Size()))
+		n16, err := m.Paramn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -653,6 +771,16 @@ func (m *FuncNodePb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Filter != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Filter.Size()))
+		n, err := m.Filter.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -908,6 +1036,206 @@ func (m *NullNodePb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *SubQueryNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SubQueryNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintNode(data, i, uint64(len(m.Sql)))
+	i += copy(data[i:], m.Sql)
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ParamNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *ParamNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintNode(data, i, uint64(len(m.Text)))
+	i += copy(data[i:], m.Text)
+	data[i] = 0x10
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Index))
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CaseNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *CaseNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Arg != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Arg.Size()))
+		n1, err := m.Arg.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Whens) > 0 {
+		for _, msg := range m.Whens {
+			data[i] = 0x12
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, msg := range m.Thens {
+			data[i] = 0x1a
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Else != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Else.Size()))
+		n2, err := m.Else.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *WindowNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Fn != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Fn.Size()))
+		n1, err := m.Fn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Partition) > 0 {
+		for _, msg := range m.Partition {
+			data[i] = 0x12
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Order) > 0 {
+		for _, msg := range m.Order {
+			data[i] = 0x1a
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Desc) > 0 {
+		for _, b := range m.Desc {
+			data[i] = 0x20
+			i++
+			if b {
+				data[i] = 1
+			} else {
+				data[i] = 0
+			}
+			i++
+		}
+	}
+	data[i] = 0x28
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Frame))
+	data[i] = 0x30
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Start))
+	data[i] = 0x38
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Startn))
+	data[i] = 0x40
+	i++
+	i = encodeVarintNode(data, i, uint64(m.End))
+	data[i] = 0x48
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Endn))
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func encodeFixed64Node(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	data[offset+1] = uint8(v >> 8)
@@ -1021,6 +1349,22 @@ func (m *NodePb) Size() (n int) {
 		l = m.Niln.Size()
 		n += 1 + l + sovNode(uint64(l))
 	}
+	if m.Sqn != nil {
+		l = m.Sqn.Size()
+		n += 2 + l + sovNode(uint64(l))
+	}
+	if m.Casen != nil {
+		l = m.Casen.Size()
+		n += 2 + l + sovNode(uint64(l))
+	}
+	if m.Windown != nil {
+		l = m.Windown.Size()
+		n += 2 + l + sovNode(uint64(l))
+	}
+	if m.Paramn != nil {
+		l = m.Paramn.Size()
+		n += 2 + l + sovNode(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1097,6 +1441,10 @@ func (m *FuncNodePb) Size() (n int) {
 			n += 1 + l + sovNode(uint64(l))
 		}
 	}
+	if m.Filter != nil {
+		l = m.Filter.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1207,41 +1555,126 @@ func (m *NullNodePb) Size() (n int) {
 	return n
 }
 
-func sovNode(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *SubQueryNodePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Sql)
+	n += 1 + l + sovNode(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
-func sozNode(x uint64) (n int) {
-	return sovNode(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+func (m *ParamNodePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Text)
+	n += 1 + l + sovNode(uint64(l))
+	n += 1 + sovNode(uint64(m.Index))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
 }
-func (m *ExprPb) Unmarshal(data []byte) error {
-	l := len(data)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowNode
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := data[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
+
+func (m *CaseNodePb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Arg != nil {
+		l = m.Arg.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Whens) > 0 {
+		for _, e := range m.Whens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, e := range m.Thens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Else != nil {
+		l = m.Else.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowNodePb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Fn != nil {
+		l = m.Fn.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Partition) > 0 {
+		for _, e := range m.Partition {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.Order) > 0 {
+		for _, e := range m.Order {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.Desc) > 0 {
+		n += 2 * len(m.Desc)
+	}
+	n += 1 + sovNode(uint64(m.Frame))
+	n += 1 + sovNode(uint64(m.Start))
+	n += 1 + sovNode(uint64(m.Startn))
+	n += 1 + sovNode(uint64(m.End))
+	n += 1 + sovNode(uint64(m.Endn))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovNode(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozNode(x uint64) (n int) {
+	return sovNode(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ExprPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
 		if wireType == 4 {
 			return fmt.Errorf("proto: ExprPb: wiretype end group for non-group")
 		}
@@ -1867,6 +2300,138 @@ func (m *NodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sqn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sqn == nil {
+				m.Sqn = &SubQueryNodePb{}
+			}
+			if err := m.Sqn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Casen", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Casen == nil {
+				m.Casen = &CaseNodePb{}
+			}
+			if err := m.Casen.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Windown", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Windown == nil {
+				m.Windown = &WindowNodePb{}
+			}
+			if err := m.Windown.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paramn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Paramn == nil {
+				m.Paramn = &ParamNodePb{}
+			}
+			if err := m.Paramn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNode(data[iNdEx:])
@@ -2474,6 +3039,39 @@ func (m *FuncNodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filter", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Filter == nil {
+				m.Filter = &NodePb{}
+			}
+			if err := m.Filter.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNode(data[iNdEx:])
@@ -3283,6 +3881,637 @@ func (m *NullNodePb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *SubQueryNodePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubQueryNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubQueryNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sql", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sql = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParamNodePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Index |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000002)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CaseNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CaseNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CaseNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Arg == nil {
+				m.Arg = &NodePb{}
+			}
+			if err := m.Arg.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Whens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Whens = append(m.Whens, NodePb{})
+			if err := m.Whens[len(m.Whens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Thens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Thens = append(m.Thens, NodePb{})
+			if err := m.Thens[len(m.Thens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Else", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Else == nil {
+				m.Else = &NodePb{}
+			}
+			if err := m.Else.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fn == nil {
+				m.Fn = &NodePb{}
+			}
+			if err := m.Fn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partition", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Partition = append(m.Partition, NodePb{})
+			if err := m.Partition[len(m.Partition)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Order", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Order = append(m.Order, NodePb{})
+			if err := m.Order[len(m.Order)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desc", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Desc = append(m.Desc, bool(v != 0))
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Frame", wireType)
+			}
+			m.Frame = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Frame |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Start |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Startn", wireType)
+			}
+			m.Startn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Startn |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.End |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endn", wireType)
+			}
+			m.Endn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Endn |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipNode(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -3391,52 +4620,68 @@ var (
 func init() { proto.RegisterFile("node.proto", fileDescriptorNode) }
 
 var fileDescriptorNode = []byte{
-	// 741 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x54, 0xcf, 0x6f, 0xd3, 0x4a,
-	0x10, 0x8e, 0x1d, 0xa7, 0x4d, 0xc6, 0x69, 0xfb, 0xde, 0xbe, 0xea, 0xc9, 0xea, 0x21, 0xaf, 0xb2,
-	0x1e, 0x50, 0x21, 0x9a, 0x4a, 0x39, 0x70, 0xa7, 0x12, 0x45, 0x3d, 0x10, 0x50, 0xa1, 0xdc, 0xed,
-	0x64, 0x9d, 0xae, 0xe4, 0x8e, 0x83, 0x63, 0x9b, 0xf6, 0xc0, 0x9d, 0x23, 0x47, 0xfe, 0xa4, 0x1c,
-	0xf9, 0x0b, 0x10, 0x3f, 0xfe, 0x11, 0x76, 0x67, 0x6d, 0x67, 0x5d, 0x5a, 0x54, 0xd4, 0x43, 0xa4,
-	0xec, 0xf7, 0x7d, 0x9e, 0xd9, 0x99, 0x6f, 0x76, 0x00, 0x30, 0x99, 0xf2, 0xe1, 0x3c, 0x4d, 0xb2,
-	0x84, 0x39, 0xfc, 0x62, 0x9e, 0xee, 0xec, 0xcf, 0x44, 0x76, 0x96, 0x87, 0xc3, 0x49, 0x72, 0x7e,
-	0x30, 0x4b, 0x66, 0xc9, 0x01, 0x91, 0x61, 0x1e, 0xd1, 0x89, 0x0e, 0xf4, 0x4f, 0x7f, 0xe4, 0x2f,
-	0x2d, 0x58, 0x7b, 0x2a, 0xbf, 0x7b, 0x19, 0xb2, 0x6d, 0xb0, 0x93, 0xb9, 0x67, 0xed, 0x5a, 0x7b,
-	0x9d, 0x43, 0x67, 0xf9, 0xe5, 0x3f, 0xeb, 0x44, 0x9e, 0xd9, 0x7d, 0x70, 0x82, 0x74, 0xb6, 0xf0,
-	0xec, 0xdd, 0xf6, 0x9e, 0x3b, 0xea, 0x0f, 0x55, 0x92, 0xa1, 0xfe, 0xa2, 0x54, 0x11, 0xcf, 0x76,
-	0xa0, 0x23, 0xa6, 0x1c, 0x33, 0xcf, 0x91, 0x01, 0x7a, 0x25, 0xa5, 0x21, 0xf6, 0x2f, 0xb4, 0x8b,
-	0x20, 0xf6, 0x3a, 0x06, 0xa3, 0x00, 0xe6, 0x81, 0x23, 0x14, 0xb1, 0x26, 0x89, 0x76, 0x15, 0x4d,
-	0x94, 0x4c, 0xa8, 0x98, 0x75, 0xc9, 0x74, 0x2b, 0x26, 0x2c, 0x99, 0x48, 0x31, 0x5d, 0xc9, 0x58,
-	0x15, 0xa3, 0x10, 0xff, 0x83, 0x03, 0x6b, 0x63, 0xd9, 0x0e, 0x59, 0xca, 0x1e, 0xd8, 0x21, 0x52,
-	0x29, 0xee, 0x88, 0xe9, 0x2b, 0x1f, 0x0a, 0x0c, 0xd2, 0x4b, 0xcd, 0x57, 0xe5, 0x85, 0xc8, 0x0e,
-	0xa0, 0x13, 0x26, 0x49, 0x8c, 0xb2, 0x3e, 0x25, 0xfe, 0xa7, 0x14, 0x4b, 0x88, 0x07, 0xd8, 0x50,
-	0x6b, 0x1d, 0x7b, 0x00, 0x76, 0x8e, 0x5e, 0x9b, 0xd4, 0x7f, 0x6b, 0xf5, 0xe9, 0xaf, 0x91, 0x73,
-	0x94, 0x8d, 0xb3, 0x23, 0xa4, 0x6e, 0xb8, 0xa3, 0xbf, 0xb4, 0xf0, 0x28, 0xc7, 0x49, 0x53, 0x17,
-	0x21, 0xbb, 0x07, 0x76, 0x86, 0xd4, 0x1b, 0x77, 0xb4, 0xa5, 0x75, 0xaf, 0x53, 0xd1, 0x94, 0x65,
-	0x94, 0x37, 0x40, 0xea, 0x54, 0x9d, 0xf7, 0x49, 0x9a, 0x06, 0x57, 0xf2, 0x06, 0xa8, 0x6a, 0x47,
-	0xf4, 0xc0, 0xac, 0x7d, 0x9c, 0x9f, 0x87, 0x3c, 0x6d, 0x2a, 0x91, 0x42, 0x16, 0xe8, 0xb9, 0x66,
-	0xc8, 0x37, 0x41, 0x9c, 0xf3, 0xa6, 0xb0, 0x40, 0xf6, 0x10, 0x6c, 0x81, 0x5e, 0x9f, 0x84, 0xdb,
-	0x5a, 0x78, 0xac, 0x8c, 0x15, 0xd9, 0x95, 0xf4, 0x82, 0xd2, 0x2f, 0xd0, 0xdb, 0x30, 0xd3, 0xbf,
-	0xca, 0x52, 0x81, 0xb3, 0xa6, 0x72, 0x81, 0x6c, 0x5f, 0xba, 0x8f, 0x13, 0xf4, 0x36, 0xcd, 0xce,
-	0x1f, 0xe3, 0x24, 0xce, 0xa7, 0xcd, 0x2b, 0x90, 0x4c, 0x5e, 0xc2, 0x41, 0x21, 0x8d, 0xda, 0x32,
-	0x3b, 0x3a, 0xce, 0xe3, 0xb8, 0xa9, 0x55, 0x1a, 0xff, 0x0c, 0xfa, 0xa6, 0xdf, 0xf5, 0x68, 0xdb,
-	0xe5, 0x68, 0xb7, 0x68, 0xb4, 0xe5, 0xc8, 0xce, 0x83, 0x94, 0x6b, 0xef, 0xbb, 0x25, 0xa1, 0xa1,
-	0x7a, 0xec, 0xdb, 0xe6, 0xd8, 0x1b, 0x99, 0x5a, 0x7a, 0xec, 0xfd, 0xe7, 0xb0, 0xd1, 0x18, 0x96,
-	0x1b, 0x52, 0x5d, 0xfb, 0x8a, 0xae, 0x09, 0xf7, 0x1e, 0x36, 0x1a, 0x1d, 0xb8, 0x21, 0xdc, 0x00,
-	0xd6, 0x91, 0xcf, 0x82, 0x8c, 0x4f, 0x65, 0x44, 0xbb, 0xbe, 0x7b, 0x05, 0xb2, 0xc7, 0xd0, 0x15,
-	0xa5, 0x41, 0xb2, 0x02, 0xfb, 0xb7, 0xb6, 0xb5, 0x4e, 0x6a, 0xad, 0xcf, 0xc1, 0x3d, 0xbd, 0x53,
-	0xdb, 0xfe, 0x87, 0xb6, 0xac, 0xa3, 0xcc, 0x79, 0x5d, 0x99, 0x8a, 0xf6, 0xc7, 0x00, 0xab, 0xa7,
-	0xa0, 0x5e, 0x34, 0x06, 0xe7, 0x9c, 0xf2, 0xf4, 0xaa, 0x6e, 0x28, 0xe4, 0xd6, 0x5d, 0x3b, 0x86,
-	0x5e, 0xfd, 0x64, 0xee, 0x68, 0xc0, 0x0b, 0x70, 0x8d, 0x67, 0xa5, 0xee, 0xf6, 0x2e, 0x0d, 0xcc,
-	0x70, 0x72, 0xc4, 0x14, 0x72, 0xeb, 0x01, 0x99, 0x42, 0xdf, 0x9c, 0x7f, 0xb2, 0x2e, 0x79, 0x9b,
-	0x27, 0x19, 0xa7, 0xfd, 0x54, 0x2d, 0xb7, 0x0a, 0x54, 0xdd, 0xd5, 0xac, 0x6d, 0x2c, 0x62, 0x0d,
-	0xa9, 0xdb, 0x64, 0xfc, 0x22, 0xa3, 0xed, 0x53, 0x77, 0x4a, 0x21, 0xfe, 0x11, 0x6c, 0x36, 0xad,
-	0x5d, 0xc5, 0xb1, 0xfe, 0x24, 0xce, 0x47, 0x0b, 0xfa, 0xe6, 0xb6, 0xa0, 0xb5, 0xbe, 0x10, 0x72,
-	0xad, 0x5b, 0xa6, 0xd9, 0x04, 0xa9, 0x52, 0xc4, 0x22, 0x8a, 0x93, 0x20, 0x6b, 0x8c, 0x42, 0x05,
-	0x2a, 0x27, 0x44, 0x41, 0xb3, 0xd0, 0xae, 0x9c, 0x10, 0x85, 0x42, 0xa3, 0x42, 0xee, 0x45, 0xbb,
-	0x5c, 0xdf, 0x12, 0x8d, 0x8a, 0xfa, 0x4a, 0x1d, 0x73, 0x08, 0xe8, 0x4a, 0xcf, 0xc0, 0x35, 0xb6,
-	0x12, 0xf3, 0xa1, 0x57, 0xa8, 0x63, 0x76, 0x39, 0xe7, 0x0d, 0x97, 0x57, 0xb0, 0x4c, 0xd1, 0xa1,
-	0x03, 0x3d, 0x8e, 0xfe, 0x89, 0x3e, 0xf8, 0x8f, 0x00, 0x56, 0xeb, 0x82, 0x7c, 0x10, 0x71, 0x19,
-	0xc5, 0xaa, 0xa3, 0x54, 0xe0, 0xe1, 0xf6, 0xf2, 0xdb, 0xa0, 0xb5, 0xfc, 0x3e, 0xb0, 0x3e, 0xcb,
-	0xdf, 0x57, 0xf9, 0xfb, 0xf4, 0x63, 0xd0, 0xfa, 0x19, 0x00, 0x00, 0xff, 0xff, 0xe8, 0xbc, 0x7b,
-	0x5f, 0x70, 0x07, 0x00, 0x00,
+	// 1004 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xcd, 0x6e, 0x23, 0x45,
+	0x10, 0x76, 0x8f, 0x3d, 0x4e, 0x5c, 0x76, 0xb2, 0xbb, 0xbd, 0x11, 0x6a, 0x45, 0xc8, 0x8c, 0x46,
+	0xb0, 0x58, 0xab, 0x6c, 0x82, 0x72, 0xe0, 0x4e, 0x56, 0x2c, 0xca, 0x81, 0x10, 0xbc, 0x2c, 0x9c,
+	0x7b, 0x3c, 0x6d, 0xa7, 0xa5, 0x49, 0x8d, 0x33, 0x3f, 0x4e, 0xf6, 0xc0, 0x3b, 0x70, 0xe4, 0x29,
+	0x10, 0x8f, 0x11, 0x6e, 0x3c, 0x01, 0x82, 0xf0, 0x22, 0xa8, 0xab, 0x67, 0xc6, 0x3d, 0xd9, 0x24,
+	0x5a, 0xb4, 0x37, 0xcf, 0x57, 0x5f, 0x57, 0x55, 0x57, 0x7d, 0x55, 0x6d, 0x00, 0x4c, 0x63, 0xb5,
+	0xbf, 0xcc, 0xd2, 0x22, 0xe5, 0x3d, 0x75, 0xb5, 0xcc, 0x76, 0x5f, 0x2c, 0x74, 0x71, 0x56, 0x46,
+	0xfb, 0xb3, 0xf4, 0xfc, 0x60, 0x91, 0x2e, 0xd2, 0x03, 0x32, 0x46, 0xe5, 0x9c, 0xbe, 0xe8, 0x83,
+	0x7e, 0xd9, 0x43, 0xe1, 0x35, 0x83, 0xfe, 0xd7, 0x57, 0xcb, 0xec, 0x34, 0xe2, 0x3b, 0xe0, 0xa5,
+	0x4b, 0xc1, 0x02, 0x36, 0xf1, 0x8f, 0x7a, 0xd7, 0x7f, 0x7d, 0xc2, 0xa6, 0x5e, 0xba, 0xe4, 0xcf,
+	0xa0, 0x27, 0xb3, 0x45, 0x2e, 0xbc, 0xa0, 0x3b, 0x19, 0x1e, 0x8e, 0xf6, 0x4d, 0x90, 0x7d, 0x7b,
+	0xa2, 0x62, 0x91, 0x9d, 0xef, 0x82, 0xaf, 0x63, 0x85, 0x85, 0xe8, 0x05, 0x6c, 0x32, 0xa8, 0x4c,
+	0x16, 0xe2, 0x1f, 0x41, 0x77, 0x25, 0x13, 0xe1, 0x3b, 0x16, 0x03, 0x70, 0x01, 0x3d, 0x6d, 0x0c,
+	0xfd, 0x80, 0x4d, 0xba, 0xb5, 0x37, 0x5d, 0x59, 0x22, 0x63, 0xd9, 0x08, 0xd8, 0x64, 0xb3, 0xb6,
+	0x44, 0x95, 0x65, 0x6e, 0x2c, 0x9b, 0x01, 0x9b, 0xb0, 0xda, 0x62, 0x90, 0xf0, 0x0f, 0x1f, 0xfa,
+	0x27, 0x69, 0xac, 0x4e, 0x23, 0x3e, 0x01, 0x2f, 0x42, 0xba, 0xca, 0xf0, 0x90, 0xdb, 0x94, 0x8f,
+	0x34, 0xca, 0xec, 0xad, 0xb5, 0xd7, 0xd7, 0x8b, 0x90, 0x1f, 0x80, 0x1f, 0xa5, 0x69, 0x82, 0xc2,
+	0x23, 0xf2, 0xd3, 0x8a, 0x9c, 0xa6, 0x89, 0x92, 0xd8, 0x62, 0x5b, 0x1e, 0xff, 0x1c, 0xbc, 0x12,
+	0x45, 0x97, 0xd8, 0x4f, 0x2c, 0xfb, 0xcd, 0xbb, 0x9e, 0x4b, 0xe4, 0xcf, 0xc0, 0x9b, 0x23, 0x55,
+	0x63, 0x78, 0xf8, 0xd8, 0x12, 0x5f, 0x95, 0x38, 0x6b, 0xf3, 0xe6, 0xc8, 0x3f, 0x03, 0xaf, 0x40,
+	0xaa, 0xcd, 0xf0, 0xf0, 0x91, 0xe5, 0xfd, 0x90, 0xe9, 0x36, 0xad, 0xa0, 0xb8, 0x12, 0x45, 0xdf,
+	0x8d, 0xfb, 0x55, 0x96, 0xc9, 0x5b, 0x71, 0x25, 0x9a, 0xbb, 0x23, 0x0a, 0x70, 0xef, 0x7e, 0x52,
+	0x9e, 0x47, 0x2a, 0x6b, 0x33, 0x91, 0x5c, 0xae, 0x50, 0x0c, 0x5d, 0x97, 0x3f, 0xca, 0xa4, 0x54,
+	0x6d, 0xe2, 0x0a, 0xf9, 0x73, 0xf0, 0x34, 0x8a, 0x11, 0x11, 0x77, 0x2c, 0xf1, 0xd8, 0x34, 0x56,
+	0x17, 0xb7, 0xc2, 0x6b, 0x0a, 0x9f, 0xa3, 0xd8, 0x72, 0xc3, 0xbf, 0x2e, 0x32, 0x8d, 0x8b, 0x36,
+	0x33, 0x47, 0xfe, 0x02, 0x7a, 0x1a, 0x67, 0x28, 0xb6, 0xdd, 0xca, 0x1f, 0xe3, 0x2c, 0x29, 0xe3,
+	0x76, 0x0a, 0x44, 0xe3, 0xcf, 0xa1, 0x87, 0x3a, 0x41, 0xf1, 0xc8, 0xad, 0xe8, 0x49, 0x99, 0x24,
+	0x6d, 0xae, 0xe1, 0xf0, 0x3d, 0xe8, 0xe6, 0x17, 0x28, 0x1e, 0xbb, 0x19, 0xbf, 0x2e, 0xa3, 0xef,
+	0x4b, 0x75, 0xab, 0x51, 0x86, 0xc6, 0xf7, 0xc0, 0x9f, 0xc9, 0x5c, 0xa1, 0x78, 0xe2, 0xba, 0x7e,
+	0x29, 0xf3, 0x76, 0x1a, 0x96, 0xc4, 0x0f, 0x61, 0xe3, 0x52, 0x63, 0x9c, 0x5e, 0xa2, 0xe0, 0xee,
+	0x2d, 0x7f, 0x22, 0xb0, 0x75, 0xa2, 0x26, 0xf2, 0x03, 0xe8, 0x2f, 0x65, 0x26, 0xcf, 0x51, 0x3c,
+	0x75, 0xab, 0x7d, 0x6a, 0xb0, 0xd6, 0x89, 0x8a, 0x16, 0x9e, 0xc1, 0xc8, 0x15, 0x6c, 0x33, 0x9b,
+	0x5e, 0x35, 0x9b, 0x1d, 0x9a, 0xcd, 0x5d, 0xf0, 0x97, 0x32, 0x53, 0x56, 0xbc, 0x9b, 0x95, 0xc1,
+	0x42, 0xcd, 0xdc, 0x76, 0xdd, 0xb9, 0x75, 0x62, 0x75, 0xec, 0xdc, 0x86, 0xdf, 0xc2, 0x56, 0x4b,
+	0xed, 0xf7, 0x84, 0xba, 0x73, 0x0d, 0xdc, 0xe1, 0xee, 0x67, 0xd8, 0x6a, 0xb5, 0xf0, 0x1e, 0x77,
+	0x63, 0xd8, 0x40, 0xb5, 0x90, 0x85, 0x8a, 0x85, 0x17, 0x78, 0x4d, 0xee, 0x35, 0xc8, 0xbf, 0x84,
+	0x4d, 0x5d, 0x29, 0x4c, 0x74, 0x03, 0xef, 0x41, 0xdd, 0x75, 0xa6, 0x0d, 0x37, 0x54, 0x30, 0x7c,
+	0xf3, 0x41, 0x65, 0xfb, 0x14, 0xba, 0x32, 0x5b, 0x54, 0x31, 0xef, 0xba, 0xa6, 0x31, 0x87, 0x27,
+	0x00, 0xeb, 0x59, 0x36, 0x2b, 0x09, 0xe5, 0xb9, 0xa2, 0x38, 0x83, 0xba, 0x1a, 0x06, 0x79, 0xef,
+	0xaa, 0x1d, 0xc3, 0xa0, 0x99, 0xf9, 0x0f, 0x6c, 0xc0, 0x77, 0x30, 0x74, 0xf6, 0x82, 0xc9, 0xed,
+	0x32, 0x93, 0xae, 0x3b, 0x36, 0x25, 0xe4, 0xbd, 0x05, 0x12, 0xc3, 0xc8, 0x1d, 0x60, 0x6a, 0x5d,
+	0x7a, 0x51, 0xa6, 0x85, 0x12, 0xac, 0xa9, 0x1f, 0x9b, 0xd6, 0xa0, 0xa9, 0xae, 0xb5, 0x7a, 0xce,
+	0x4b, 0x62, 0x21, 0x93, 0x4d, 0xa1, 0xae, 0x0a, 0x5a, 0x9f, 0x4d, 0xa5, 0x0c, 0x12, 0xbe, 0x82,
+	0xed, 0x76, 0x6b, 0xd7, 0x7e, 0xd8, 0xff, 0xf1, 0xf3, 0x0b, 0x83, 0x91, 0xbb, 0xee, 0xe8, 0x5d,
+	0xca, 0x35, 0x16, 0x4e, 0xb2, 0x9d, 0xa9, 0x85, 0xcc, 0x55, 0x74, 0x3e, 0x4f, 0x52, 0x59, 0xb4,
+	0xa4, 0x50, 0x83, 0xa6, 0x13, 0x7a, 0x45, 0x5a, 0xe8, 0xd6, 0x9d, 0xd0, 0x2b, 0x83, 0xce, 0x57,
+	0xa2, 0x17, 0x78, 0xd5, 0xfb, 0xd3, 0x99, 0x7a, 0xf3, 0x55, 0x93, 0x92, 0xef, 0x8a, 0x80, 0x52,
+	0xfa, 0x06, 0x86, 0xce, 0x5a, 0xe5, 0x21, 0x0c, 0x56, 0xe6, 0xb3, 0x78, 0xbb, 0x54, 0xad, 0x2e,
+	0xaf, 0x61, 0xbe, 0x03, 0x3e, 0x7d, 0xd0, 0x70, 0x8c, 0xa6, 0xf6, 0x23, 0xdc, 0x03, 0x58, 0xef,
+	0x3b, 0xea, 0x83, 0x4e, 0x2a, 0x2f, 0xac, 0xf1, 0x52, 0x83, 0xe1, 0x04, 0xb6, 0xdb, 0x2b, 0xcf,
+	0x3c, 0xc3, 0xf9, 0x45, 0xd2, 0x92, 0xa9, 0x01, 0xc2, 0xdf, 0x18, 0xc0, 0x7a, 0xdb, 0xd5, 0x23,
+	0x60, 0x5f, 0xcf, 0x77, 0x75, 0xc1, 0x68, 0x04, 0xf8, 0x04, 0xfc, 0xcb, 0x33, 0x85, 0x0f, 0x09,
+	0xd2, 0x12, 0x0c, 0xb3, 0x20, 0xe6, 0xfd, 0x4a, 0xb3, 0x04, 0x23, 0x49, 0x95, 0xe4, 0xaa, 0x7a,
+	0x34, 0xef, 0x0a, 0x4d, 0xf6, 0xf0, 0x77, 0x0f, 0x46, 0xee, 0xba, 0xe5, 0x21, 0xbd, 0xb5, 0xf7,
+	0x67, 0x6c, 0xde, 0xd9, 0x2f, 0x60, 0xb0, 0x94, 0x59, 0xa1, 0x0b, 0x9d, 0xe2, 0x03, 0x49, 0xaf,
+	0x49, 0x26, 0xf1, 0x34, 0x8b, 0x55, 0xf6, 0x50, 0xe2, 0x44, 0x30, 0xcd, 0x8f, 0x55, 0x3e, 0x13,
+	0xbd, 0xa0, 0xdb, 0xa8, 0x88, 0x10, 0x23, 0xbf, 0x79, 0x66, 0x96, 0x83, 0xef, 0xf4, 0xc8, 0x42,
+	0xc6, 0x96, 0x17, 0x32, 0x2b, 0x44, 0xdf, 0xb5, 0x11, 0xc4, 0x3f, 0x86, 0x3e, 0xfd, 0x40, 0xfa,
+	0x0b, 0x54, 0xcb, 0xaf, 0xc2, 0x4c, 0x27, 0x15, 0xc6, 0x62, 0xd3, 0x39, 0x67, 0x00, 0x93, 0x87,
+	0xc2, 0x18, 0xc5, 0xc0, 0x39, 0x43, 0x48, 0xf8, 0x12, 0x86, 0xce, 0x6b, 0xd3, 0xa8, 0x95, 0xdd,
+	0x56, 0x2b, 0xcd, 0x0b, 0xc6, 0xea, 0x8a, 0xa4, 0xd7, 0x24, 0x45, 0xd0, 0xd1, 0xce, 0xf5, 0x3f,
+	0xe3, 0xce, 0xf5, 0xcd, 0x98, 0xfd, 0x79, 0x33, 0x66, 0x7f, 0xdf, 0x8c, 0xd9, 0xaf, 0xff, 0x8e,
+	0x3b, 0xff, 0x0d, 0x00, 0x79, 0xc1, 0xd4, 0x9f, 0x84, 0x0a, 0x00, 0x00,
 }
@@ -22,6 +22,12 @@ var pbTests = []string{
 	`AND ( EXISTS x, INCLUDE ref_name )`,
 	`company = "Toys R"" Us"`,
 	`providers.id != NULL`,
+	`CASE WHEN x > 5 THEN "big" ELSE "small" END`,
+	`CASE x WHEN 1 THEN "one" WHEN 2 THEN "two" END`,
+	`row_number() OVER (PARTITION BY dept ORDER BY salary DESC)`,
+	`sum(price) OVER (ORDER BY day ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)`,
+	`count(*) FILTER (WHERE price > 10)`,
+	`user_id = $1 AND price > $2`,
 }
 
 func TestNodePb(t *testing.T) {
@@ -38,6 +44,18 @@ func TestNodePb(t *testing.T) {
 		assert.True(t, exp.Equal(n2), "Expected Equal but got %v for %v", exp, n2)
 		u.Infof("pre/post: \n\t%s\n\t%s", exp, n2)
 	}
+
+	// identities read from pb are equal only to the same identity
+	fromPb := func(n expr.Node) expr.Node {
+		pbBytes, err := proto.Marshal(n.NodePb())
+		assert.Equal(t, nil, err)
+		n2, err := expr.NodeFromPb(pbBytes)
+		assert.Equal(t, nil, err)
+		return n2
+	}
+	itemId := fromPb(expr.NewIdentityNodeVal("item_id"))
+	assert.True(t, itemId.Equal(expr.NewIdentityNodeVal("item_id")))
+	assert.True(t, !itemId.Equal(fromPb(expr.NewIdentityNodeVal("user_id"))), "%s should not equal user_id", itemId)
 }
 
 func TestExprRoundTrip(t *testing.T) {
@@ -113,6 +131,12 @@ func TestIdentityNames(t *testing.T) {
 		6 > 5
 		toint(name)
 	)`)))
+
+	aggs := expr.FindAggregates(expr.MustParse(`sqrt(sum(price * qty) / count(*)) + max(x)`))
+	assert.Equal(t, 3, len(aggs))
+	assert.Equal(t, "sum(price * qty)", aggs[0].String())
+	assert.Equal(t, 0, len(expr.FindAggregates(expr.MustParse(`tolower(name)`))))
+
 	assert.Equal(t, "email", expr.FindFirstIdentity(expr.MustParse(`AND (
 		NOT EXISTS email
 		X between 4 and 5
@@ -48,6 +48,20 @@ type TokenPager interface {
 	ErrMsg(msg string) error
 }
 
+// SubQueryPager is a TokenPager which can also parse statements, allowing
+// sub-queries nested inside of expressions.  The pager is positioned on
+// the left paren opening the sub-query, and must consume the right paren.
+type SubQueryPager interface {
+	ParseSubQuery() (SubQuery, error)
+}
+
+// ParamPager is a TokenPager which numbers the parameters of a prepared
+// statement, the positional (?) parameters in order across all of the
+// expressions of the statement.
+type ParamPager interface {
+	AddParam(p *ParamNode) error
+}
+
 // SchemaInfo is interface for a Column type
 type SchemaInfo interface {
 	Key() string
@@ -65,6 +79,8 @@ type LexTokenPager struct {
 	tokens []lex.Token // list of all the tokens
 	cursor int
 	lex    *lex.Lexer
+	params int  // positional parameters so far
+	dollar bool // are numbered parameters used
 }
 
 func NewLexTokenPager(lex *lex.Lexer) *LexTokenPager {
@@ -133,6 +149,24 @@ func (m *LexTokenPager) Backup() {
 	}
 }
 
+// AddParam number the parameter, implements ParamPager.  A statement
+// may use positional (?) or numbered ($1) parameters, not both.
+func (m *LexTokenPager) AddParam(p *ParamNode) error {
+	if p.Text == "?" {
+		if m.dollar {
+			return fmt.Errorf("cannot mix ? and $n parameters")
+		}
+		m.params++
+		p.Index = m.params
+		return nil
+	}
+	if m.params > 0 {
+		return fmt.Errorf("cannot mix ? and $n parameters")
+	}
+	m.dollar = true
+	return nil
+}
+
 // Peek returns but does not consume the next token.
 func (m *LexTokenPager) Peek() lex.Token {
 	if len(m.tokens) <= m.cursor+1 && !m.done {
@@ -286,7 +320,7 @@ https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Operators/Oper
 http://www.postgresql.org/docs/9.4/static/sql-syntax-lexical.html#SQL-PRECEDENCE
 
 TODO:
- - if/else, case, for
+ - if/else, for
  - call stack & vars
 --------------------------------------
 O -> A {( "||" | OR  ) A}
@@ -295,8 +329,13 @@ C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=" | "LIKE" | "IN" | "CONTAINS" | "
 P -> M {( "+" | "-" ) M}
 M -> F {( "*" | "/" ) F}
 F -> v | "(" O ")" | "!" v | "-" O | "NOT" C | "EXISTS" v | "IS" O | "AND (" O ")" | "OR (" O ")"
-v -> value | Func | "INCLUDE" <identity>
+v -> value | Func [Filter] [Window] | Case | "INCLUDE" <identity>
+Case -> "CASE" [O] "WHEN" O "THEN" O {"WHEN" O "THEN" O} ["ELSE" O] "END"
 Func -> <identity> "(" value {"," value} ")"
+Filter -> "FILTER" "(" "WHERE" O ")"
+Window -> "OVER" "(" ["PARTITION BY" O {"," O}] ["ORDER BY" O ["ASC" | "DESC"] {"," O ["ASC" | "DESC"]}] [Frame] ")"
+Frame -> ("ROWS" | "RANGE") (Bound | "BETWEEN" Bound "AND" Bound)
+Bound -> "UNBOUNDED" ("PRECEDING" | "FOLLOWING") | number ("PRECEDING" | "FOLLOWING") | "CURRENT ROW"
 value -> number | "string" | O | <identity>
 
 
@@ -418,6 +457,10 @@ func (t *tree) cInner(n Node, depth int) Node {
 				}
 				return NewBinaryNode(cur, n, NewValueNode(val))
 			case lex.TokenLeftParenthesis:
+				if t.Peek().T == lex.TokenSelect {
+					// x IN (SELECT x FROM y)
+					return NewBinaryNode(cur, n, t.SubQuery(depth))
+				}
 				// This is a special type of Binary? its 2nd argument is a array node
 				return NewBinaryNode(cur, n, t.ArrayNode(depth))
 			case lex.TokenUdfExpr:
@@ -616,6 +659,20 @@ func (t *tree) v(depth int) Node {
 	case lex.TokenNull:
 		t.Next()
 		return NewNull(cur)
+	case lex.TokenParam:
+		n, err := NewParamNode(cur.V)
+		if err != nil {
+			t.error(err)
+		}
+		if pp, ok := t.TokenPager.(ParamPager); ok {
+			if err := pp.AddParam(n); err != nil {
+				t.error(err)
+			}
+		} else if n.Index == 0 {
+			t.unexpected(cur, "positional parameter not supported")
+		}
+		t.Next()
+		return n
 	case lex.TokenStar:
 		n := NewStringNoQuoteNode(cur.V)
 		t.Next()
@@ -632,8 +689,20 @@ func (t *tree) v(depth int) Node {
 		return n
 	case lex.TokenUdfExpr:
 		t.Next() // consume Function Name
-		return t.Func(depth, cur)
+		fn := t.Func(depth, cur)
+		if t.Cur().T == lex.TokenFilter {
+			t.Filter(depth, fn)
+		}
+		if t.Cur().T == lex.TokenOver {
+			return t.Window(depth, fn)
+		}
+		return fn
+	case lex.TokenCase:
+		return t.Case(depth)
 	case lex.TokenLeftParenthesis:
+		if t.Peek().T == lex.TokenSelect {
+			return t.SubQuery(depth)
+		}
 		t.Next() // Consume  (
 		n := t.O(depth + 1)
 		debugf(depth, "v: paren  T:%T  %v   cur:%v", n, n, t.Cur())
@@ -654,6 +723,172 @@ func (t *tree) v(depth int) Node {
 	return nil
 }
 
+// SubQuery parse a parenthesized sub-select, the pager must know how
+// to parse statements (SubQueryPager)
+func (t *tree) SubQuery(depth int) Node {
+	debugf(depth, "SubQuery: cur:%v peek:%v", t.Cur(), t.Peek())
+	sp, ok := t.TokenPager.(SubQueryPager)
+	if !ok {
+		t.unexpected(t.Cur(), "sub-query not supported here")
+	}
+	stmt, err := sp.ParseSubQuery()
+	if err != nil {
+		t.error(err)
+	}
+	return NewSubQueryNode(stmt)
+}
+
+// Case parse a searched or simple CASE expression
+//
+//	CASE WHEN x > 5 THEN "big" ELSE "small" END
+//	CASE x WHEN 1 THEN "one" WHEN 2 THEN "two" END
+func (t *tree) Case(depth int) Node {
+	debugf(depth, "Case: cur:%v peek:%v", t.Cur(), t.Peek())
+	t.Next() // consume CASE
+	n := &CaseNode{}
+	if t.Cur().T != lex.TokenWhen {
+		n.Operand = t.O(depth + 1)
+	}
+	for t.Cur().T == lex.TokenWhen {
+		t.Next() // consume WHEN
+		n.Whens = append(n.Whens, t.O(depth+1))
+		t.expect(lex.TokenThen, "CASE expected THEN")
+		t.Next() // consume THEN
+		n.Thens = append(n.Thens, t.O(depth+1))
+	}
+	if len(n.Whens) == 0 {
+		t.unexpected(t.Cur(), "CASE expected WHEN")
+	}
+	if t.Cur().T == lex.TokenElse {
+		t.Next() // consume ELSE
+		n.Else = t.O(depth + 1)
+	}
+	t.expect(lex.TokenEnd, "CASE expected END")
+	t.Next() // consume END
+	if err := n.Validate(); err != nil {
+		t.error(err)
+	}
+	return n
+}
+
+// Window parse the OVER (...) window specification of a function
+//
+//	row_number() OVER (PARTITION BY user_id ORDER BY price DESC)
+//	sum(price) OVER (ORDER BY item_id ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)
+//
+// Filter parse the FILTER (WHERE cond) of an aggregate function, only the
+// rows of the group where cond is true are aggregated.
+//
+//	count(*) FILTER (WHERE price > 10)
+func (t *tree) Filter(depth int, fn *FuncNode) {
+	debugf(depth, "Filter: cur:%v peek:%v", t.Cur(), t.Peek())
+	if !fn.F.Aggregate {
+		t.errorf("FILTER is only allowed on aggregate functions: %s", fn)
+	}
+	t.Next() // consume FILTER
+	t.expect(lex.TokenLeftParenthesis, "FILTER expected (")
+	t.Next() // consume (
+	t.expect(lex.TokenWhere, "FILTER expected ( WHERE")
+	t.Next() // consume WHERE
+	fn.Filter = t.O(depth + 1)
+	t.expect(lex.TokenRightParenthesis, "FILTER expected )")
+	t.Next() // consume )
+}
+
+func (t *tree) Window(depth int, fn *FuncNode) Node {
+	debugf(depth, "Window: cur:%v peek:%v", t.Cur(), t.Peek())
+	t.Next() // consume OVER
+	t.expect(lex.TokenLeftParenthesis, "OVER expected (")
+	t.Next() // consume (
+	n := &WindowNode{Func: fn}
+	if t.Cur().T == lex.TokenPartitionBy {
+		t.Next() // consume PARTITION BY
+		n.Partition = append(n.Partition, t.O(depth+1))
+		for t.Cur().T == lex.TokenComma {
+			t.Next() // consume ,
+			n.Partition = append(n.Partition, t.O(depth+1))
+		}
+	}
+	if t.Cur().T == lex.TokenOrderBy {
+		t.Next() // consume ORDER BY
+		for {
+			n.OrderBy = append(n.OrderBy, t.O(depth+1))
+			desc := false
+			switch t.Cur().T {
+			case lex.TokenDesc:
+				desc = true
+				t.Next()
+			case lex.TokenAsc:
+				t.Next()
+			}
+			n.Desc = append(n.Desc, desc)
+			if t.Cur().T != lex.TokenComma {
+				break
+			}
+			t.Next() // consume ,
+		}
+	}
+	switch t.Cur().T {
+	case lex.TokenRows, lex.TokenRange:
+		n.Frame = &WindowFrame{Range: t.Cur().T == lex.TokenRange, End: FrameCurrentRow}
+		t.Next() // consume ROWS/RANGE
+		if t.Cur().T == lex.TokenBetween {
+			t.Next() // consume BETWEEN
+			n.Frame.Start, n.Frame.StartN = t.frameBound()
+			t.expect(lex.TokenLogicAnd, "BETWEEN expected AND")
+			t.Next() // consume AND
+			n.Frame.End, n.Frame.EndN = t.frameBound()
+		} else {
+			n.Frame.Start, n.Frame.StartN = t.frameBound()
+		}
+	}
+	t.expect(lex.TokenRightParenthesis, "OVER expected )")
+	t.Next() // consume )
+	if err := n.Validate(); err != nil {
+		t.error(err)
+	}
+	return n
+}
+
+// frameBound parse one bound of a window frame
+func (t *tree) frameBound() (FrameBound, int64) {
+	var offset int64
+	unbounded := false
+	switch cur := t.Cur(); cur.T {
+	case lex.TokenCurrentRow:
+		t.Next()
+		return FrameCurrentRow, 0
+	case lex.TokenUnbounded:
+		unbounded = true
+		t.Next()
+	case lex.TokenInteger:
+		iv, err := strconv.ParseInt(cur.V, 10, 64)
+		if err != nil || iv < 0 {
+			t.errorf("invalid window frame offset %q", cur.V)
+		}
+		offset = iv
+		t.Next()
+	default:
+		t.unexpected(cur, "window frame expected UNBOUNDED, <integer> or CURRENT ROW")
+	}
+	switch t.Cur().T {
+	case lex.TokenPreceding:
+		t.Next()
+		if unbounded {
+			return FrameUnboundedPreceding, 0
+		}
+		return FramePreceding, offset
+	case lex.TokenFollowing:
+		t.Next()
+		if unbounded {
+			return FrameUnboundedFollowing, 0
+		}
+		return FrameFollowing, offset
+	}
+	t.unexpected(t.Cur(), "window frame expected PRECEDING or FOLLOWING")
+	return FrameCurrentRow, 0
+}
+
 func (t *tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 	debugf(depth, "Func: tok: %v cur:%v peek:%v", funcTok.V, t.Cur(), t.Peek())
 	if t.Cur().T != lex.TokenLeftParenthesis {
@@ -730,6 +965,18 @@ func (t *tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 				lastComma = true
 				t.Next()
 				continue
+			case lex.TokenIdentity:
+				if len(fn.Args) == 0 && strings.ToLower(firstToken.V) == "distinct" &&
+					t.Peek().T != lex.TokenLeftParenthesis && t.Peek().T != lex.TokenRightParenthesis {
+					// count(DISTINCT x) is same as count(DISTINCT(x))
+					t.Next()
+					distinct := NewFuncNode(firstToken.V, Func{Name: firstToken.V, Eval: EmptyEvalFunc})
+					distinct.Missing = true
+					distinct.append(t.O(depth + 1))
+					node = distinct
+				} else {
+					node = t.O(depth + 1)
+				}
 			default:
 				node = t.O(depth + 1)
 			}
@@ -303,6 +303,11 @@ var exprTests = []exprTest{
 		"",
 		false,
 	},
+	{
+		`count(DISTINCT user_id)`,
+		`count(DISTINCT(user_id))`,
+		true,
+	},
 	// Try a bunch of code simplification
 	{
 		`OR (x == "y")`,
@@ -324,6 +329,48 @@ var exprTests = []exprTest{
 		`AND ( x == "y", stuff == x )`,
 		true,
 	},
+	{
+		`CASE WHEN x > 5 THEN "big" WHEN x > 1 THEN "small" ELSE "none" END`,
+		`CASE WHEN x > 5 THEN "big" WHEN x > 1 THEN "small" ELSE "none" END`,
+		true,
+	},
+	{
+		`case x when 1 then "one" end`,
+		`CASE x WHEN 1 THEN "one" END`,
+		true,
+	},
+	{
+		`CASE x ELSE "one" END`,
+		``,
+		false,
+	},
+	{
+		`rank() over (partition by dept, team order by salary desc, name)`,
+		`rank() OVER (PARTITION BY dept, team ORDER BY salary DESC, name)`,
+		true,
+	},
+	{
+		`avg(price) OVER (ORDER BY day RANGE BETWEEN UNBOUNDED PRECEDING AND 3 FOLLOWING)`,
+		`avg(price) OVER (ORDER BY day RANGE BETWEEN UNBOUNDED PRECEDING AND 3 FOLLOWING)`,
+		true,
+	},
+	{
+		`count(*) filter (where price > 10 AND user_id != "abc")`,
+		`count(*) FILTER (WHERE price > 10 AND user_id != "abc")`,
+		true,
+	},
+	{
+		// only aggregates may be filtered
+		`tolower(name) FILTER (WHERE price > 10)`,
+		``,
+		false,
+	},
+	{
+		// a range offset needs a single order by
+		`sum(price) OVER (RANGE BETWEEN 1 PRECEDING AND CURRENT ROW)`,
+		``,
+		false,
+	},
 }
 
 func TestParseExpressions(t *testing.T) {
@@ -55,6 +55,8 @@ func (m *TypeValidator) walkNode(node expr.Node) error {
 		return nil
 	case *expr.FuncNode:
 		return m.funcExpr(n)
+	case *expr.CaseNode:
+		return fmt.Errorf("esgen: CASE expressions are not supported in filters: %s", n)
 	default:
 		u.Warnf("not handled type validation %v %T", node, node)
 		return fmt.Errorf("esgen: unsupported node in expression: %T (%s)", node, node)
@@ -239,3 +239,25 @@ func TestFilterQLIntersects(t *testing.T) {
 			tv(TokenRightParenthesis, ")"),
 		})
 }
+
+func TestFilterQLCaseWords(t *testing.T) {
+	// end, when ... are only keywords of a case expression
+	verifyFilterQLTokens(t, `FILTER AND (end > 5, b.end < 9, when == "x")`,
+		[]Token{
+			tv(TokenFilter, "FILTER"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "end"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "5"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b.end"),
+			tv(TokenLT, "<"),
+			tv(TokenInteger, "9"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "when"),
+			tv(TokenEqualEqual, "=="),
+			tv(TokenValue, "x"),
+			tv(TokenRightParenthesis, ")"),
+		})
+}
@@ -11,6 +11,7 @@ var (
 	// SqlDialect is a SQL dialect
 	//
 	//    SELECT
+	//    WITH name AS (SELECT ...) SELECT
 	//    UPDATE
 	//    INSERT
 	//    UPSERT
@@ -31,6 +32,7 @@ var (
 		Statements: []*Clause{
 			{Token: TokenPrepare, Clauses: SqlPrepare},
 			{Token: TokenSelect, Clauses: SqlSelect},
+			{Token: TokenWith, Clauses: SqlWith},
 			{Token: TokenUpdate, Clauses: SqlUpdate},
 			{Token: TokenUpsert, Clauses: SqlUpsert},
 			{Token: TokenInsert, Clauses: SqlInsert},
@@ -46,6 +48,7 @@ var (
 			{Token: TokenUse, Clauses: SqlUse},
 			{Token: TokenRollback, Clauses: SqlRollback},
 			{Token: TokenCommit, Clauses: SqlCommit},
+			{Token: TokenAnalyze, Clauses: SqlAnalyze},
 		},
 	}
 	// SqlSelect Select statement.
@@ -60,10 +63,18 @@ var (
 		{Token: TokenOrderBy, Lexer: LexOrderByColumn, Optional: true, Name: "sqlSelect.orderby"},
 		{Token: TokenLimit, Lexer: LexLimit, Optional: true, Name: "sqlSelect.limit"},
 		{Token: TokenOffset, Lexer: LexNumber, Optional: true, Name: "sqlSelect.offset"},
+		{Token: TokenUnion, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.union"},
+		{Token: TokenIntersect, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.intersect"},
+		{Token: TokenExcept, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.except"},
 		{Token: TokenWith, Lexer: LexJsonOrKeyValue, Optional: true, Name: "sqlSelect.with"},
 		{Token: TokenAlias, Lexer: LexIdentifier, Optional: true, Name: "sqlSelect.alias"},
 		{Token: TokenEOF, Lexer: LexEndOfStatement, Optional: false, Name: "sqlSelect.eos"},
 	}
+	// SqlWith common table expressions, named sub-queries for use
+	// as sources of the select statement that follows.
+	SqlWith = []*Clause{
+		{Token: TokenWith, Lexer: LexWithClause, Name: "sqlWith.with"},
+	}
 	fromSource = []*Clause{
 		{KeywordMatcher: sourceMatch, Lexer: LexTableReferenceFirst, Name: "fromSource.matcher"},
 		{Token: TokenSelect, Lexer: LexSelectClause, Name: "fromSource.Select"},
@@ -170,9 +181,13 @@ var (
 	SqlDrop = []*Clause{
 		{Token: TokenDrop, Lexer: LexDrop},
 	}
+	// SqlAnalyze ANALYZE [TABLE] tbl_name
+	SqlAnalyze = []*Clause{
+		{Token: TokenAnalyze, Lexer: LexAnalyze},
+	}
 	// SqlDescribe Describe {table,database}
 	SqlDescribe = []*Clause{
-		{Token: TokenDescribe, Lexer: LexColumns},
+		{Token: TokenDescribe, Lexer: LexExplain},
 	}
 	// SqlDescribeAlt alternate spelling of Describe
 	SqlDescribeAlt = []*Clause{
@@ -180,7 +195,7 @@ var (
 	}
 	// SqlExplain is alias of describe
 	SqlExplain = []*Clause{
-		{Token: TokenExplain, Lexer: LexColumns},
+		{Token: TokenExplain, Lexer: LexExplain},
 	}
 	// SqlShow
 	SqlShow = []*Clause{
@@ -227,7 +242,7 @@ func sourceMatch(c *Clause, peekWord string, l *Lexer) bool {
 		return true
 	case "select":
 		return true
-	case "left", "right", "inner", "outer", "join":
+	case "left", "right", "full", "inner", "outer", "cross", "join":
 		return true
 	}
 	return false
@@ -552,6 +567,20 @@ func LexDrop(l *Lexer) StateFn {
 	return lexNotExists
 }
 
+// LexAnalyze allows us to lex the words after ANALYZE
+//
+//	ANALYZE [TABLE] tbl_name
+func LexAnalyze(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	keyWord := strings.ToLower(l.PeekWord())
+	if keyWord == "table" {
+		l.ConsumeWord(keyWord)
+		l.Emit(TokenTable)
+	}
+	return LexIdentifier
+}
+
 // LexDdlTable data definition language table
 func LexDdlTable(l *Lexer) StateFn {
 
@@ -28,6 +28,20 @@ func TestLexSqlDescribe(t *testing.T) {
 			tv(TokenDesc, "DESC"),
 			tv(TokenIdentity, "mytable"),
 		})
+	verifyTokens(t, `EXPLAIN FORMAT=JSON SELECT a FROM mytable`,
+		[]Token{
+			tv(TokenExplain, "EXPLAIN"),
+			tv(TokenIdentity, "FORMAT"),
+			tv(TokenEqual, "="),
+			tv(TokenIdentity, "JSON"),
+			tv(TokenSelect, "SELECT"),
+		})
+	verifyTokens(t, `EXPLAIN ANALYZE WITH x AS (SELECT a FROM mytable) SELECT a FROM x`,
+		[]Token{
+			tv(TokenExplain, "EXPLAIN"),
+			tv(TokenIdentity, "ANALYZE"),
+			tv(TokenWith, "WITH"),
+		})
 }
 
 func TestLexSqlShow(t *testing.T) {
@@ -296,6 +310,12 @@ func TestLexSqlDrop(t *testing.T) {
 			tv(TokenContinuousView, "CONTINUOUSVIEW"),
 			tv(TokenIdentity, "myv"),
 		})
+	verifyTokens(t, `ANALYZE TABLE orders;`,
+		[]Token{
+			tv(TokenAnalyze, "ANALYZE"),
+			tv(TokenTable, "TABLE"),
+			tv(TokenIdentity, "orders"),
+		})
 }
 
 func TestLexSqlSelect(t *testing.T) {
@@ -358,3 +378,55 @@ func TestLexSqlSelect(t *testing.T) {
 			tv(TokenInteger, "100"),
 		})
 }
+
+func TestLexSqlSetOperations(t *testing.T) {
+	verifyTokens(t, `SELECT a FROM t1 WHERE a > 1 UNION ALL SELECT b FROM t2 ORDER BY a LIMIT 10;`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "a"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "1"),
+			tv(TokenUnion, "UNION"),
+			tv(TokenAll, "ALL"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "b"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "a"),
+			tv(TokenLimit, "LIMIT"),
+			tv(TokenInteger, "10"),
+			tv(TokenEOS, ";"),
+		})
+	verifyTokens(t, `SELECT a FROM t1 INTERSECT SELECT a FROM t2 EXCEPT DISTINCT SELECT a FROM t3`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenIntersect, "INTERSECT"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenExcept, "EXCEPT"),
+			tv(TokenDistinct, "DISTINCT"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t3"),
+			tv(TokenEOF, ""),
+		})
+	verifyTokens(t, `SELECT 1 UNION SELECT 2`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenInteger, "1"),
+			tv(TokenUnion, "UNION"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenInteger, "2"),
+		})
+}
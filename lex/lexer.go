@@ -105,10 +105,16 @@ type Lexer struct {
 	peekedWordPos int
 	peekedWord    string
 	lastQuoteMark byte
+	caseDepth     int // CASE expressions open, whose WHEN, THEN, ELSE, END are keywords
 
 	// Due to nested Expressions and evaluation this allows us to descend/ascend
 	// during lex, using push/pop to add and remove states needing evaluation
 	stack []NamedStateFn
+
+	// A sub-query nested inside of an expression is lexed by its own
+	// lexer, whose tokens are drained before we resume
+	sub    *Lexer
+	subPos int
 }
 
 func (l *Lexer) init() {
@@ -148,6 +154,15 @@ func (l *Lexer) NextToken() Token {
 		case token := <-l.tokens:
 			return token
 		default:
+			if l.sub != nil {
+				token := l.sub.NextToken()
+				if token.T != TokenEOF {
+					token.Pos += l.subPos
+					return token
+				}
+				l.sub = nil
+				continue
+			}
 			if l.state == nil && len(l.stack) > 0 {
 				l.state = l.pop()
 			} else if l.state == nil {
@@ -158,6 +173,16 @@ func (l *Lexer) NextToken() Token {
 	}
 }
 
+// stackHas is the named StateFn on the stack.
+func (l *Lexer) stackHas(name string) bool {
+	for _, s := range l.stack {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Push a named StateFn onto stack.
 func (l *Lexer) Push(name string, state StateFn) {
 	debugf("push %d %v", len(l.stack)+1, name)
@@ -1083,6 +1108,8 @@ func LexValue(l *Lexer) StateFn {
 		//  A:   numbers
 		l.backup()
 		switch rune {
+		case '?', '$':
+			return LexParam(l)
 		case 't', 'T', 'F', 'f':
 			// lets look for Booleans
 			boolCandiate := strings.ToLower(l.PeekWord())
@@ -1365,6 +1392,10 @@ func LexListOfArgs(l *Lexer) StateFn {
 			//u.Warnf("found keyword while looking for arg? %v", string(r))
 			return nil
 		}
+		if peekWord == "case" {
+			l.Push("LexListOfArgs", LexListOfArgs)
+			return LexExpression
+		}
 
 		//u.Debugf("LexListOfArgs sending LexExpressionOrIdentity: %v", string(peekWord))
 		l.Push("LexListOfArgs", LexListOfArgs)
@@ -1576,6 +1607,109 @@ func LexEndOfStatement(l *Lexer) StateFn {
 	return l.errorToken("Unexpected token:" + l.current())
 }
 
+// LexSetOperation lexes the optional quantifier of a set operation
+// (UNION, INTERSECT, EXCEPT) and then re-starts the statement clauses so
+// the following SELECT is lexed.
+//
+//	<set_operation> :== ( UNION | INTERSECT | EXCEPT ) [ALL | DISTINCT] <select_stmt>
+func LexSetOperation(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	switch word := strings.ToLower(l.PeekWord()); word {
+	case "all":
+		l.ConsumeWord(word)
+		l.Emit(TokenAll)
+	case "distinct":
+		l.ConsumeWord(word)
+		l.Emit(TokenDistinct)
+	}
+	l.SkipWhiteSpaces()
+	if l.statement != nil && len(l.statement.Clauses) > 0 {
+		l.curClause = l.statement.Clauses[0]
+	}
+	return nil
+}
+
+// LexWithClause lexes the common table expressions of a WITH clause, each
+// query is lexed as a sub-query, then re-starts the lexer on the SELECT
+// statement that uses them.
+//
+//	<with_stmt> :== WITH [RECURSIVE] <cte> [, <cte>]* <select_stmt>
+//	<cte>       :== name [ ( col [, col]* ) ] AS ( <select_stmt> )
+func LexWithClause(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if word := strings.ToLower(l.PeekWord()); word == "recursive" {
+		l.ConsumeWord(word)
+		l.Emit(TokenRecursive)
+	}
+	return lexCommonTableExpr
+}
+
+func lexCommonTableExpr(l *Lexer) StateFn {
+	l.Push("lexCommonTableExprAs", lexCommonTableExprAs)
+	return LexIdentifier
+}
+
+// lexCommonTableExprAs the optional column list, AS and the query of a
+// common table expression whose name we have consumed.
+func lexCommonTableExprAs(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if l.Peek() == '(' {
+		l.Next()
+		l.Emit(TokenLeftParenthesis)
+		return lexCommonTableExprCols
+	}
+	if word := strings.ToLower(l.PeekWord()); word != "as" {
+		return l.errorToken("expected AS for common table expression: " + l.current())
+	}
+	l.ConsumeWord("as")
+	l.Emit(TokenAs)
+	l.Push("lexCommonTableExprEnd", lexCommonTableExprEnd)
+	return LexSubQueryParens
+}
+
+func lexCommonTableExprCols(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if l.IsEnd() {
+		return l.errorToken("expected ) for common table expression columns")
+	}
+	switch l.Peek() {
+	case ',':
+		l.Next()
+		l.Emit(TokenComma)
+		return lexCommonTableExprCols
+	case ')':
+		l.Next()
+		l.Emit(TokenRightParenthesis)
+		return lexCommonTableExprAs
+	}
+	l.Push("lexCommonTableExprCols", lexCommonTableExprCols)
+	return LexIdentifier
+}
+
+// lexCommonTableExprEnd after a common table expression either another
+// follows or we move on to the select statement.
+func lexCommonTableExprEnd(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if l.Peek() == ',' {
+		l.Next()
+		l.Emit(TokenComma)
+		return lexCommonTableExpr
+	}
+	for _, stmt := range l.dialect.Statements {
+		if stmt.Token == TokenSelect && len(stmt.Clauses) > 0 {
+			l.statement = stmt
+			l.curClause = stmt.Clauses[0]
+			return nil
+		}
+	}
+	return l.errorToken("expected SELECT after common table expressions")
+}
+
 // LexSelectClause Handle start of select statements, specifically looking for
 // @@variables, *, or else we drop into <select_list>
 //
@@ -1600,6 +1734,10 @@ func LexSelectClause(l *Lexer) StateFn {
 	switch word {
 	case "from", "where", "limit", "group", "having":
 		return nil
+	case "as":
+		// alias of the column before it, which in a sub-query would otherwise
+		// be taken as the AS keyword of the sub-query source
+		return LexSelectList
 	case "all": //ALL?
 		l.ConsumeWord("all")
 		l.Emit(TokenAll)
@@ -1687,6 +1825,79 @@ func LexUpsertClause(l *Lexer) StateFn {
 	return nil
 }
 
+// LexSubQueryParens lex a parenthesized sub-query found inside an
+// expression, the statement between the parens is lexed by a nested lexer
+//
+//    user_id IN (SELECT user_id FROM orders)
+//    EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.user_id)
+//
+func LexSubQueryParens(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if r := l.Next(); r != '(' {
+		return l.errorToken("sub-query must begin with a paren: ( " + l.current())
+	}
+	l.Emit(TokenLeftParenthesis)
+
+	end := l.matchingParen()
+	if end < 0 {
+		return l.errorToken("sub-query must end with a paren: ) " + l.current())
+	}
+	l.sub = NewLexer(l.input[l.pos:end], l.dialect)
+	l.subPos = l.pos
+	l.line += strings.Count(l.input[l.pos:end], "\n")
+	l.pos = end
+	l.start = end
+	return LexParenRight
+}
+
+// isSubQuery is the remaining input a parenthesized SELECT statement
+func (l *Lexer) isSubQuery() bool {
+	return l.isParenKeyword(0, "select")
+}
+
+// isParenKeyword is the remaining input, past skip bytes, a left paren
+// followed by the keyword, ie the "(WHERE" of an aggregate FILTER
+func (l *Lexer) isParenKeyword(skip int, keyword string) bool {
+	i := l.pos + skip
+	for ; i < len(l.input) && isWhiteSpace(rune(l.input[i])); i++ {
+	}
+	if i >= len(l.input) || l.input[i] != '(' {
+		return false
+	}
+	for i++; i < len(l.input) && isWhiteSpace(rune(l.input[i])); i++ {
+	}
+	kl := len(keyword)
+	if len(l.input)-i < kl || !strings.EqualFold(l.input[i:i+kl], keyword) {
+		return false
+	}
+	return len(l.input) == i+kl || !isIdentCh(rune(l.input[i+kl]))
+}
+
+// matchingParen find the position of the right paren closing the one
+// we have just consumed, skipping over quoted values and nested parens
+func (l *Lexer) matchingParen() int {
+	depth := 1
+	for i := l.pos; i < len(l.input); i++ {
+		switch r := l.input[i]; r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '\'', '"', '`':
+			for i++; i < len(l.input) && l.input[i] != r; i++ {
+				if l.input[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return -1
+}
+
 // Handle recursive subqueries
 //
 func LexSubQuery(l *Lexer) StateFn {
@@ -1842,6 +2053,13 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 	case ')':
 		l.Next()
 		l.Emit(TokenRightParenthesis)
+		if strings.ToLower(l.PeekWord()) == "as" {
+			// alias of the sub-query source:  (SELECT ...) AS x
+			l.SkipWhiteSpaces()
+			l.ConsumeWord("AS")
+			l.Emit(TokenAs)
+			return LexIdentifier
+		}
 		return LexSelectClause
 	}
 
@@ -1857,6 +2075,9 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 		l.Push("LexTableReferenceFirst", LexTableReferenceFirst)
 		l.Push("LexIdentifier", LexIdentifier)
 		return nil
+	case "left", "right", "full", "cross", "join":
+		// start of a join, let the dialect find the join clause
+		return nil
 	case "in": // are there other functions besides in?
 		l.ConsumeWord(word)
 		l.Emit(TokenIN)
@@ -1968,6 +2189,14 @@ func LexTableReferences(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexTableReferences
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexTableReferences
+	case "cross":
+		l.ConsumeWord(word)
+		l.Emit(TokenCross)
+		return LexTableReferences
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -2078,6 +2307,14 @@ func LexJoinEntry(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexJoinEntry
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexJoinEntry
+	case "cross":
+		l.ConsumeWord(word)
+		l.Emit(TokenCross)
+		return LexJoinEntry
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -2252,6 +2489,10 @@ func LexConditionalClause(l *Lexer) StateFn {
 		//l.Push("LexParenRight", LexParenRight)
 		return nil
 	case '(':
+		if l.isSubQuery() {
+			l.Push("LexConditionalClause", LexConditionalClause)
+			return LexSubQueryParens
+		}
 		l.Next()
 		l.Emit(TokenLeftParenthesis)
 		l.Push("LexConditionalClause", LexConditionalClause)
@@ -2363,6 +2604,15 @@ func LexExpression(l *Lexer) StateFn {
 		u.Warnf("un-handled? ")
 	case '(': // this is a logical Grouping/Ordering and must be a single
 		// logically valid expression
+		l.backup()
+		if l.isSubQuery() {
+			// scalar sub-query may be followed by rest of expression
+			l.Push("LexExpression", l.clauseState())
+			return LexSubQueryParens
+		}
+		l.Next()
+		// resume after the paren, ie the next of a list ((a, b), (a))
+		l.Push("LexExpression", l.clauseState())
 		l.Push("LexParenRight", LexParenRight)
 		l.Emit(TokenLeftParenthesis)
 		l.Push("LexExpression", l.clauseState())
@@ -2485,14 +2735,12 @@ func LexExpression(l *Lexer) StateFn {
 			l.ConsumeWord(word)
 			l.Emit(TokenIN)
 			l.SkipWhiteSpaces()
+			if l.isSubQuery() {
+				return LexSubQueryParens
+			}
 			if l.PeekX(1) == "(" {
 				l.ConsumeWord("(")
 				l.Emit(TokenLeftParenthesis)
-				l.SkipWhiteSpaces()
-				word = strings.ToLower(l.PeekWord())
-				if word == "select" {
-					return nil
-				}
 				l.Push("LexParenRight", LexParenRight)
 				return LexListOfArgs
 			}
@@ -2532,6 +2780,10 @@ func LexExpression(l *Lexer) StateFn {
 		return LexIdentifier
 	case "exists":
 		l.ConsumeWord(word)
+		if l.isSubQuery() {
+			l.Emit(TokenExists)
+			return LexSubQueryParens
+		}
 		r = l.Peek()
 		if r == '(' {
 			l.Emit(TokenUdfExpr)
@@ -2543,6 +2795,79 @@ func LexExpression(l *Lexer) StateFn {
 		}
 		l.Emit(TokenExists)
 		return LexExpression
+	case "case":
+		//  CASE [<expr>] WHEN <expr> THEN <expr> [ELSE <expr>] END
+		l.ConsumeWord(word)
+		l.Emit(TokenCase)
+		l.caseDepth++
+		return LexExpression
+	case "when", "then", "else", "end":
+		// only keywords of an open CASE, elsewhere ie a column named end
+		if l.caseDepth > 0 {
+			l.ConsumeWord(word)
+			switch word {
+			case "when":
+				l.Emit(TokenWhen)
+			case "then":
+				l.Emit(TokenThen)
+			case "else":
+				l.Emit(TokenElse)
+			case "end":
+				l.caseDepth--
+				l.Emit(TokenEnd)
+				return l.clauseState()
+			}
+			return LexExpression
+		}
+	case "over":
+		// window specification of the function before it
+		//    row_number() OVER (PARTITION BY dept ORDER BY salary DESC)
+		if l.peekRunePast(len(word)) == '(' {
+			l.ConsumeWord(word)
+			l.Emit(TokenOver)
+			return LexWindowSpec
+		}
+	case "rollup", "cube":
+		// multiple groupings of a group by, not a rollup() or cube() func
+		//    GROUP BY ROLLUP (year, month)
+		if l.peekRunePast(len(word)) == '(' {
+			l.ConsumeWord(word)
+			if word == "rollup" {
+				l.Emit(TokenRollup)
+			} else {
+				l.Emit(TokenCube)
+			}
+			return LexExpression
+		}
+	case "grouping":
+		// explicit groupings of a group by, not the grouping() func
+		//    GROUP BY GROUPING SETS ((year, month), (year), ())
+		rest := strings.TrimLeftFunc(l.input[l.pos+len(word):], unicode.IsSpace)
+		if len(rest) >= 4 && strings.EqualFold(rest[:4], "sets") && (len(rest) == 4 || !isIdentCh(rune(rest[4]))) {
+			l.ConsumeWord(word)
+			for isWhiteSpace(l.Peek()) {
+				l.Next()
+			}
+			l.ConsumeWord("sets")
+			l.Emit(TokenGroupingSets)
+			return LexExpression
+		}
+	case "filter":
+		// filter of the aggregate function before it, not the filter() func
+		//    count(*) FILTER (WHERE price > 10)
+		if l.isParenKeyword(len(word), "where") {
+			l.ConsumeWord(word)
+			l.Emit(TokenFilter)
+			l.SkipWhiteSpaces()
+			l.ConsumeWord("(")
+			l.Emit(TokenLeftParenthesis)
+			l.SkipWhiteSpaces()
+			l.ConsumeWord("where")
+			l.Emit(TokenWhere)
+			l.Push("LexExpression", l.clauseState())
+			l.Push("LexParenRight", LexParenRight)
+			return LexExpression
+		}
 	case "is":
 		l.ConsumeWord(word)
 		l.Emit(TokenIs)
@@ -2665,7 +2990,15 @@ func LexOrderByColumn(l *Lexer) StateFn {
 		l.Next()
 		l.Emit(TokenComma)
 		l.Push("LexOrderByColumn", LexOrderByColumn)
+		if l.isSubQuery() {
+			return LexSubQueryParens
+		}
 		return LexExpressionOrIdentity
+	case '(':
+		if l.isSubQuery() {
+			l.Push("LexOrderByColumn", LexOrderByColumn)
+			return LexSubQueryParens
+		}
 	}
 
 	word := strings.ToLower(l.PeekWord())
@@ -2684,7 +3017,9 @@ func LexOrderByColumn(l *Lexer) StateFn {
 		l.Emit(TokenDesc)
 		return LexOrderByColumn
 	default:
-		if len(l.stack) < 2 {
+		// the stack is deeper after a sub-query source, so only refuse
+		// if we are already lexing an order by column
+		if !l.stackHas("LexOrderByColumn") {
 			l.Push("LexOrderByColumn", LexOrderByColumn)
 			return LexExpressionOrIdentity
 		} else {
@@ -2696,6 +3031,147 @@ func LexOrderByColumn(l *Lexer) StateFn {
 	return nil
 }
 
+// LexExplain the options of an EXPLAIN (or DESCRIBE), the statement being
+// explained is parsed on its own so is not lexed here.
+//
+//	EXPLAIN [ANALYZE] [EXTENDED] [FORMAT = (TEXT | JSON)] <select statement>
+//	DESCRIBE <table>
+func LexExplain(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	word := strings.ToLower(l.PeekWord())
+	switch word {
+	case "analyze", "extended":
+		l.ConsumeWord(word)
+		l.Emit(TokenIdentity)
+		return LexExplain
+	case "format":
+		l.ConsumeWord(word)
+		l.Emit(TokenIdentity)
+		l.SkipWhiteSpaces()
+		if l.Peek() == '=' {
+			l.Next()
+			l.Emit(TokenEqual)
+		}
+		l.Push("LexExplain", LexExplain)
+		return LexIdentifier
+	case "select", "with":
+		l.ConsumeWord(word)
+		if word == "select" {
+			l.Emit(TokenSelect)
+		} else {
+			l.Emit(TokenWith)
+		}
+		// skip the rest of the statement
+		l.pos = len(l.input)
+		l.ignore()
+		return nil
+	}
+	return LexColumns
+}
+
+// LexWindowSpec the parenthesized window specification of a function
+// after its OVER keyword
+//
+//	OVER ( [PARTITION BY <expr> [, <expr>]*] [ORDER BY <expr> [ASC|DESC] [, ...]] [<frame>] )
+//
+//	<frame>       :== (ROWS | RANGE) ( <frame_bound> | BETWEEN <frame_bound> AND <frame_bound> )
+//	<frame_bound> :== UNBOUNDED (PRECEDING | FOLLOWING) | <number> (PRECEDING | FOLLOWING) | CURRENT ROW
+func LexWindowSpec(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if r := l.Next(); r != '(' {
+		return l.errorToken("window specification must begin with a paren: ( " + l.current())
+	}
+	l.Emit(TokenLeftParenthesis)
+	return lexWindowSpecClause
+}
+
+func lexWindowSpecClause(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if l.IsEnd() {
+		return l.errorToken("window specification must end with a paren: ) " + l.current())
+	}
+
+	switch l.Peek() {
+	case ')':
+		l.Next()
+		l.Emit(TokenRightParenthesis)
+		return l.clauseState()
+	case ',':
+		l.Next()
+		l.Emit(TokenComma)
+		return lexWindowSpecClause
+	case '+', '-', '*', '/', '%':
+		switch l.Next() {
+		case '+':
+			l.Emit(TokenPlus)
+		case '-':
+			l.Emit(TokenMinus)
+		case '*':
+			l.Emit(TokenMultiply)
+		case '/':
+			l.Emit(TokenDivide)
+		case '%':
+			l.Emit(TokenModulus)
+		}
+		return lexWindowSpecClause
+	}
+
+	word := strings.ToLower(l.PeekWord())
+	switch word {
+	case "partition", "order", "current":
+		// two word keywords PARTITION BY, ORDER BY, CURRENT ROW
+		second := "by"
+		if word == "current" {
+			second = "row"
+		}
+		l.ConsumeWord(word)
+		for isWhiteSpace(l.Peek()) {
+			l.Next()
+		}
+		if strings.ToLower(l.PeekWord()) != second {
+			return l.errorToken("expected " + strings.ToUpper(word+" "+second) + " " + l.current())
+		}
+		l.ConsumeWord(second)
+		switch word {
+		case "partition":
+			l.Emit(TokenPartitionBy)
+		case "order":
+			l.Emit(TokenOrderBy)
+		default:
+			l.Emit(TokenCurrentRow)
+		}
+		return lexWindowSpecClause
+	case "asc", "desc", "rows", "range", "between", "and", "unbounded", "preceding", "following":
+		l.ConsumeWord(word)
+		switch word {
+		case "asc":
+			l.Emit(TokenAsc)
+		case "desc":
+			l.Emit(TokenDesc)
+		case "rows":
+			l.Emit(TokenRows)
+		case "range":
+			l.Emit(TokenRange)
+		case "between":
+			l.Emit(TokenBetween)
+		case "and":
+			l.Emit(TokenLogicAnd)
+		case "unbounded":
+			l.Emit(TokenUnbounded)
+		case "preceding":
+			l.Emit(TokenPreceding)
+		case "following":
+			l.Emit(TokenFollowing)
+		}
+		return lexWindowSpecClause
+	}
+	l.Push("lexWindowSpecClause", lexWindowSpecClause)
+	return LexExpressionOrIdentity
+}
+
 // Lex either Json or Key/Value pairs
 //
 //    Must start with { or [ for json
@@ -3042,6 +3518,24 @@ func lexSingleLineComment(l *Lexer) StateFn {
 	return nil
 }
 
+// LexParam a parameter of a prepared statement, positional or numbered
+//
+//  ?
+//  $1
+//
+func LexParam(l *Lexer) StateFn {
+	if l.Next() == '$' {
+		if !unicode.IsDigit(l.Peek()) {
+			return l.errorf("expected parameter number after $ but got %q", l.PeekX(5))
+		}
+		for unicode.IsDigit(l.Peek()) {
+			l.Next()
+		}
+	}
+	l.Emit(TokenParam)
+	return nil
+}
+
 // LexNumber floats, integers, hex, exponential, signed
 //
 //  1.23
@@ -660,6 +660,31 @@ func TestLexSqlJoin(t *testing.T) {
 			TokenInner, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
 			TokenOn, TokenIdentity, TokenEqual, TokenIdentity,
 		})
+
+	verifyTokenTypes(t, `
+		SELECT t1.name, t2.salary
+		FROM employee AS t1 
+		FULL OUTER JOIN info AS t2 ON t1.name = t2.name`,
+		[]TokenType{TokenSelect,
+			TokenIdentity, TokenComma, TokenIdentity,
+			TokenFrom, TokenIdentity, TokenAs, TokenIdentity,
+			TokenFull, TokenOuter, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
+			TokenOn, TokenIdentity, TokenEqual, TokenIdentity,
+		})
+
+	verifyTokenTypes(t, `SELECT t1.name FROM employee AS t1 CROSS JOIN info AS t2`,
+		[]TokenType{TokenSelect, TokenIdentity,
+			TokenFrom, TokenIdentity, TokenAs, TokenIdentity,
+			TokenCross, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
+			TokenEOF,
+		})
+
+	verifyTokenTypes(t, `SELECT t1.name FROM employee t1 LEFT JOIN info AS t2 ON t1.name = t2.name`,
+		[]TokenType{TokenSelect, TokenIdentity,
+			TokenFrom, TokenIdentity, TokenIdentity,
+			TokenLeft, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
+			TokenOn, TokenIdentity, TokenEqual, TokenIdentity,
+		})
 }
 
 func TestLexSqlSubQuery(t *testing.T) {
@@ -677,6 +702,131 @@ func TestLexSqlSubQuery(t *testing.T) {
 			TokenGT, TokenInteger,
 			TokenRightParenthesis,
 		})
+	verifyTokens(t, `SELECT a FROM t1 WHERE a NOT IN (SELECT b FROM t2 WHERE c = ")") AND d > 1`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "a"),
+			tv(TokenNegate, "NOT"),
+			tv(TokenIN, "IN"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "b"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "c"),
+			tv(TokenEqual, "="),
+			tv(TokenValue, ")"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "d"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "1"),
+		})
+	verifyTokens(t, `SELECT a FROM t1 WHERE NOT EXISTS (SELECT 1 FROM t2 WHERE t2.a = t1.a)`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenNegate, "NOT"),
+			tv(TokenExists, "EXISTS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenInteger, "1"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "t2.a"),
+			tv(TokenEqual, "="),
+			tv(TokenIdentity, "t1.a"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenEOF, ""),
+		})
+	// scalar sub-queries in select list and where
+	verifyTokens(t, `SELECT a, (SELECT count(*) FROM t2 WHERE t2.a = t1.a) AS ct FROM t1
+		WHERE (SELECT max(b) FROM t2) > 1`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenComma, ","),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "count"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenStar, "*"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "t2.a"),
+			tv(TokenEqual, "="),
+			tv(TokenIdentity, "t1.a"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenIdentity, "ct"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "max"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "b"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t2"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "1"),
+			tv(TokenEOF, ""),
+		})
+}
+
+func TestLexSqlWith(t *testing.T) {
+
+	verifyTokens(t, `WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM nums), b AS (SELECT a FROM t1)
+		SELECT n FROM nums`,
+		[]Token{
+			tv(TokenWith, "WITH"),
+			tv(TokenRecursive, "RECURSIVE"),
+			tv(TokenIdentity, "nums"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "n"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenInteger, "1"),
+			tv(TokenUnion, "UNION"),
+			tv(TokenAll, "ALL"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "n"),
+			tv(TokenPlus, "+"),
+			tv(TokenInteger, "1"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "nums"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b"),
+			tv(TokenAs, "AS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t1"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "n"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "nums"),
+			tv(TokenEOF, ""),
+		})
 }
 
 func TestLexSqlPreparedStmt(t *testing.T) {
@@ -1008,6 +1158,156 @@ func TestLexSelectNestedExpressions(t *testing.T) {
 		})
 }
 
+func TestLexSelectCase(t *testing.T) {
+
+	verifyTokens(t, `SELECT CASE WHEN x > 5 THEN "big" ELSE "small" END AS sz FROM Product`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenCase, "CASE"),
+			tv(TokenWhen, "WHEN"),
+			tv(TokenIdentity, "x"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "5"),
+			tv(TokenThen, "THEN"),
+			tv(TokenValue, "big"),
+			tv(TokenElse, "ELSE"),
+			tv(TokenValue, "small"),
+			tv(TokenEnd, "END"),
+			tv(TokenAs, "AS"),
+			tv(TokenIdentity, "sz"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "Product"),
+		})
+
+	// outside of a case they are identities, ie a column named end
+	verifyTokens(t, `SELECT end, b.end FROM b WHERE end > 5 AND b.end < 9`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "end"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b.end"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "b"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "end"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "5"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "b.end"),
+			tv(TokenLT, "<"),
+			tv(TokenInteger, "9"),
+		})
+}
+
+func TestLexSelectWindow(t *testing.T) {
+
+	verifyTokens(t, `SELECT sum(price) OVER (PARTITION BY user_id ORDER BY day DESC ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS s FROM orders`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "sum"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "price"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOver, "OVER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenPartitionBy, "PARTITION BY"),
+			tv(TokenIdentity, "user_id"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "day"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenRows, "ROWS"),
+			tv(TokenBetween, "BETWEEN"),
+			tv(TokenInteger, "2"),
+			tv(TokenPreceding, "PRECEDING"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenCurrentRow, "CURRENT ROW"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenIdentity, "s"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "orders"),
+		})
+}
+
+func TestLexSelectAggregateFilter(t *testing.T) {
+
+	verifyTokens(t, `SELECT count(*) FILTER (WHERE price > 10) AS ct, filter(tags, "a*") FROM orders`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "count"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenStar, "*"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFilter, "FILTER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "price"),
+			tv(TokenGT, ">"),
+			tv(TokenInteger, "10"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenIdentity, "ct"),
+			tv(TokenComma, ","),
+			tv(TokenUdfExpr, "filter"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "tags"),
+			tv(TokenComma, ","),
+			tv(TokenValue, "a*"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "orders"),
+		})
+}
+
+func TestLexSelectGroupingSets(t *testing.T) {
+
+	verifyTokens(t, `SELECT grouping(a) FROM t GROUP BY ROLLUP (a, b)`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "grouping"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t"),
+			tv(TokenGroupBy, "GROUP BY"),
+			tv(TokenRollup, "ROLLUP"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b"),
+			tv(TokenRightParenthesis, ")"),
+		})
+
+	verifyTokens(t, `SELECT a FROM t GROUP BY x, GROUPING SETS ((a, b), (a), ()) ORDER BY a`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t"),
+			tv(TokenGroupBy, "GROUP BY"),
+			tv(TokenIdentity, "x"),
+			tv(TokenComma, ","),
+			tv(TokenGroupingSets, "GROUPING SETS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenComma, ","),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenComma, ","),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "a"),
+		})
+}
+
 func TestLexAlter(t *testing.T) {
 
 	verifyTokens(t, `-- lets alter the table
@@ -1323,3 +1623,44 @@ func TestWithJson(t *testing.T) {
 			TokenRightBrace,
 		})
 }
+
+func TestLexParams(t *testing.T) {
+
+	verifyTokens(t, `SELECT a FROM t WHERE a = ? AND b IN ($2, $1)`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "a"),
+			tv(TokenEqual, "="),
+			tv(TokenParam, "?"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "b"),
+			tv(TokenIN, "IN"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenParam, "$2"),
+			tv(TokenComma, ","),
+			tv(TokenParam, "$1"),
+			tv(TokenRightParenthesis, ")"),
+		})
+
+	verifyTokens(t, `INSERT INTO t (a, b) VALUES (?, ?)`,
+		[]Token{
+			tv(TokenInsert, "INSERT"),
+			tv(TokenInto, "INTO"),
+			tv(TokenTable, "t"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenValues, "VALUES"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenParam, "?"),
+			tv(TokenComma, ","),
+			tv(TokenParam, "?"),
+			tv(TokenRightParenthesis, ")"),
+		})
+}
@@ -123,6 +123,11 @@ const (
 	TokenNull             TokenType = 88 // NULL
 	TokenContains         TokenType = 89 // CONTAINS
 	TokenIntersects       TokenType = 90 // INTERSECTS
+	TokenCase             TokenType = 91 // CASE
+	TokenWhen             TokenType = 92 // WHEN
+	TokenThen             TokenType = 93 // THEN
+	TokenElse             TokenType = 94 // ELSE
+	TokenEnd              TokenType = 95 // END
 
 	// ql top-level keywords, these first keywords determine parser
 	TokenPrepare   TokenType = 200
@@ -142,35 +147,55 @@ const (
 	TokenReplace   TokenType = 214 // Insert/Replace are interchangeable on insert statements
 	TokenRollback  TokenType = 215
 	TokenCommit    TokenType = 216
+	TokenAnalyze   TokenType = 217 // collect table statistics
 
 	// Other QL Keywords, These are clause-level keywords that mark separation between clauses
-	TokenFrom     TokenType = 300 // from
-	TokenWhere    TokenType = 301 // where
-	TokenHaving   TokenType = 302 // having
-	TokenGroupBy  TokenType = 303 // group by
-	TokenBy       TokenType = 304 // by
-	TokenAlias    TokenType = 305 // alias
-	TokenWith     TokenType = 306 // with
-	TokenValues   TokenType = 307 // values
-	TokenInto     TokenType = 308 // into
-	TokenLimit    TokenType = 309 // limit
-	TokenOrderBy  TokenType = 310 // order by
-	TokenInner    TokenType = 311 // inner , ie of join
-	TokenCross    TokenType = 312 // cross
-	TokenOuter    TokenType = 313 // outer
-	TokenLeft     TokenType = 314 // left
-	TokenRight    TokenType = 315 // right
-	TokenJoin     TokenType = 316 // Join
-	TokenOn       TokenType = 317 // on
-	TokenDistinct TokenType = 318 // DISTINCT
-	TokenAll      TokenType = 319 // all
-	TokenInclude  TokenType = 320 // INCLUDE
-	TokenExists   TokenType = 321 // EXISTS
-	TokenOffset   TokenType = 322 // OFFSET
-	TokenFull     TokenType = 323 // FULL
-	TokenGlobal   TokenType = 324 // GLOBAL
-	TokenSession  TokenType = 325 // SESSION
-	TokenTables   TokenType = 326 // TABLES
+	TokenFrom      TokenType = 300 // from
+	TokenWhere     TokenType = 301 // where
+	TokenHaving    TokenType = 302 // having
+	TokenGroupBy   TokenType = 303 // group by
+	TokenBy        TokenType = 304 // by
+	TokenAlias     TokenType = 305 // alias
+	TokenWith      TokenType = 306 // with
+	TokenValues    TokenType = 307 // values
+	TokenInto      TokenType = 308 // into
+	TokenLimit     TokenType = 309 // limit
+	TokenOrderBy   TokenType = 310 // order by
+	TokenInner     TokenType = 311 // inner , ie of join
+	TokenCross     TokenType = 312 // cross
+	TokenOuter     TokenType = 313 // outer
+	TokenLeft      TokenType = 314 // left
+	TokenRight     TokenType = 315 // right
+	TokenJoin      TokenType = 316 // Join
+	TokenOn        TokenType = 317 // on
+	TokenDistinct  TokenType = 318 // DISTINCT
+	TokenAll       TokenType = 319 // all
+	TokenInclude   TokenType = 320 // INCLUDE
+	TokenExists    TokenType = 321 // EXISTS
+	TokenOffset    TokenType = 322 // OFFSET
+	TokenFull      TokenType = 323 // FULL
+	TokenGlobal    TokenType = 324 // GLOBAL
+	TokenSession   TokenType = 325 // SESSION
+	TokenTables    TokenType = 326 // TABLES
+	TokenUnion     TokenType = 327 // UNION
+	TokenIntersect TokenType = 328 // INTERSECT
+	TokenExcept    TokenType = 329 // EXCEPT
+	TokenRecursive TokenType = 330 // RECURSIVE
+
+	// window specification of a function, OVER (PARTITION BY ... ORDER BY ... ROWS ...)
+	TokenOver        TokenType = 331 // OVER
+	TokenPartitionBy TokenType = 332 // PARTITION BY
+	TokenRows        TokenType = 333 // ROWS
+	TokenRange       TokenType = 334 // RANGE
+	TokenUnbounded   TokenType = 335 // UNBOUNDED
+	TokenPreceding   TokenType = 336 // PRECEDING
+	TokenFollowing   TokenType = 337 // FOLLOWING
+	TokenCurrentRow  TokenType = 338 // CURRENT ROW
+
+	// multiple groupings of a group by, GROUP BY ROLLUP (a, b)
+	TokenRollup       TokenType = 339 // ROLLUP
+	TokenCube         TokenType = 340 // CUBE
+	TokenGroupingSets TokenType = 341 // GROUPING SETS
 
 	// ddl major words
 	TokenSchema         TokenType = 400 // SCHEMA
@@ -212,6 +237,7 @@ const (
 	TokenValueEscaped TokenType = 602 // '' becomes ' inside the string, parser will need to replace the string
 	TokenRegex        TokenType = 603 // regex
 	TokenDuration     TokenType = 604 // 14d , 22w, 3y, 45ms, 45us, 24hr, 2h, 45m, 30s
+	TokenParam        TokenType = 605 // prepared statement parameter, positional ? or numbered $1
 
 	// Data Type Definitions
 	TokenTypeDef     TokenType = 999
@@ -304,6 +330,11 @@ var (
 		TokenNull:       {Kw: "null", Description: "NULL"},
 		TokenContains:   {Kw: "contains", Description: "contains"},
 		TokenIntersects: {Kw: "intersects", Description: "intersects"},
+		TokenCase:       {Kw: "case", Description: "CASE"},
+		TokenWhen:       {Kw: "when", Description: "WHEN"},
+		TokenThen:       {Kw: "then", Description: "THEN"},
+		TokenElse:       {Kw: "else", Description: "ELSE"},
+		TokenEnd:        {Kw: "end", Description: "END"},
 
 		// Identity ish bools
 		TokenTrue:  {Kw: "true", Description: "True"},
@@ -334,6 +365,7 @@ var (
 		TokenReplace:   {Description: "replace"},
 		TokenRollback:  {Description: "rollback"},
 		TokenCommit:    {Description: "commit"},
+		TokenAnalyze:   {Description: "analyze"},
 
 		// Top Level dml ql clause keywords
 		TokenInto:    {Description: "into"},
@@ -343,27 +375,46 @@ var (
 		TokenHaving:  {Description: "having"},
 		TokenGroupBy: {Description: "group by"},
 		// Other Ql Keywords
-		TokenAlias:    {Description: "alias"},
-		TokenWith:     {Description: "with"},
-		TokenValues:   {Description: "values"},
-		TokenLimit:    {Description: "limit"},
-		TokenOrderBy:  {Description: "order by"},
-		TokenInner:    {Description: "inner"},
-		TokenCross:    {Description: "cross"},
-		TokenOuter:    {Description: "outer"},
-		TokenLeft:     {Description: "left"},
-		TokenRight:    {Description: "right"},
-		TokenJoin:     {Description: "join"},
-		TokenOn:       {Description: "on"},
-		TokenDistinct: {Description: "distinct"},
-		TokenAll:      {Description: "all"},
-		TokenInclude:  {Description: "include"},
-		TokenExists:   {Description: "exists"},
-		TokenOffset:   {Description: "offset"},
-		TokenFull:     {Description: "full"},
-		TokenGlobal:   {Description: "global"},
-		TokenSession:  {Description: "session"},
-		TokenTables:   {Description: "tables"},
+		TokenAlias:     {Description: "alias"},
+		TokenWith:      {Description: "with"},
+		TokenValues:    {Description: "values"},
+		TokenLimit:     {Description: "limit"},
+		TokenOrderBy:   {Description: "order by"},
+		TokenInner:     {Description: "inner"},
+		TokenCross:     {Description: "cross"},
+		TokenOuter:     {Description: "outer"},
+		TokenLeft:      {Description: "left"},
+		TokenRight:     {Description: "right"},
+		TokenJoin:      {Description: "join"},
+		TokenOn:        {Description: "on"},
+		TokenDistinct:  {Description: "distinct"},
+		TokenAll:       {Description: "all"},
+		TokenInclude:   {Description: "include"},
+		TokenExists:    {Description: "exists"},
+		TokenOffset:    {Description: "offset"},
+		TokenFull:      {Description: "full"},
+		TokenGlobal:    {Description: "global"},
+		TokenSession:   {Description: "session"},
+		TokenTables:    {Description: "tables"},
+		TokenUnion:     {Description: "union"},
+		TokenIntersect: {Description: "intersect"},
+		TokenExcept:    {Description: "except"},
+		TokenRecursive: {Description: "recursive"},
+
+		// window specification
+		TokenOver:        {Description: "over"},
+		TokenPartitionBy: {Description: "partition by"},
+		TokenRows:        {Description: "rows"},
+		TokenRange:       {Description: "range"},
+		TokenUnbounded:   {Description: "unbounded"},
+		TokenPreceding:   {Description: "preceding"},
+		TokenFollowing:   {Description: "following"},
+		TokenCurrentRow:  {Description: "current row"},
+
+		// group by groupings
+		TokenRollup:       {Description: "rollup"},
+		TokenCube:         {Description: "cube"},
+		TokenGroupingSets: {Description: "grouping sets"},
 
 		// ddl keywords
 		TokenSchema:         {Description: "schema"},
@@ -401,6 +452,7 @@ var (
 		TokenValueEscaped: {Description: "value-escaped"},
 		TokenRegex:        {Description: "regex"},
 		TokenDuration:     {Description: "duration"},
+		TokenParam:        {Description: "param"},
 
 		// Data TYPES:  ie type system
 		TokenTypeDef:     {Description: "TypeDef"}, // Generic DataType
@@ -2,6 +2,8 @@ package plan
 
 import (
 	"math/rand"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
@@ -50,12 +52,16 @@ type Context struct {
 	Session expr.ContextReadWriter // Session for this connection
 	Schema  *schema.Schema         // this schema for this connection
 	Funcs   expr.FuncResolver      // Local/Dialect specific functions
+	Ctes    map[string]*Cte        // WITH common table expressions, temporary named sources
 
 	// From configuration
 	DisableRecover bool
+	MemoryLimit    int64 // max bytes a buffering task may hold before spilling to disk, 0 = default
+	RecursionLimit int   // max iterations of a WITH RECURSIVE common table expression, 0 = default
 
 	// Local State
 	Errors     []error
+	errMu      sync.Mutex
 	errRecover interface{}
 }
 
@@ -85,6 +91,27 @@ func (m *Context) init() {
 	}
 }
 
+// AddError record an error of running this statement that could not be
+// returned directly, ie from evaluating an expression.
+func (m *Context) AddError(err error) {
+	m.errMu.Lock()
+	m.Errors = append(m.Errors, err)
+	m.errMu.Unlock()
+}
+
+// FirstError the first error recorded with AddError, nil if none.
+func (m *Context) FirstError() error {
+	if m == nil {
+		return nil
+	}
+	m.errMu.Lock()
+	defer m.errMu.Unlock()
+	if len(m.Errors) == 0 {
+		return nil
+	}
+	return m.Errors[0]
+}
+
 // called by go routines/tasks to ensure any recovery panics are captured
 func (m *Context) ToPB() *ContextPb {
 	m.init()
@@ -118,3 +145,57 @@ func (m *Context) Equal(c *Context) bool {
 	}
 	return true
 }
+
+// SubQueryContext create a new context for planning and executing a
+// sub-query (ie WHERE x IN (SELECT ...)) of this statement, it shares the
+// schema, session and configuration but has its own statement, projection.
+func (m *Context) SubQueryContext(stmt rel.SqlStatement) *Context {
+	return &Context{
+		Context:        m.Context,
+		Raw:            stmt.String(),
+		Stmt:           stmt,
+		Session:        m.Session,
+		Schema:         m.Schema,
+		Funcs:          m.Funcs,
+		DisableRecover: m.DisableRecover,
+		MemoryLimit:    m.MemoryLimit,
+		RecursionLimit: m.RecursionLimit,
+		Ctes:           m.copyCtes(),
+	}
+}
+
+// AddCte add a common table expression (WITH) as a temporary named
+// source visible to this statement and its sub-queries.
+func (m *Context) AddCte(cte *Cte) {
+	if m.Ctes == nil {
+		m.Ctes = make(map[string]*Cte)
+	}
+	m.Ctes[strings.ToLower(cte.Name)] = cte
+}
+
+// Cte find a common table expression of this statement by name.
+func (m *Context) Cte(name string) (*Cte, bool) {
+	cte, ok := m.Ctes[strings.ToLower(name)]
+	return cte, ok
+}
+
+// table find the table of a source of this statement, either a common
+// table expression or else a table of the schema.
+func (m *Context) table(name string) (*schema.Table, error) {
+	if cte, ok := m.Cte(name); ok {
+		return cte.Tbl, nil
+	}
+	return m.Schema.Table(name)
+}
+
+// copyCtes so ctes added by a sub-query aren't visible to its parent.
+func (m *Context) copyCtes() map[string]*Cte {
+	if len(m.Ctes) == 0 {
+		return nil
+	}
+	ctes := make(map[string]*Cte, len(m.Ctes))
+	for name, cte := range m.Ctes {
+		ctes[name] = cte
+	}
+	return ctes
+}
@@ -0,0 +1,304 @@
+package plan
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+// ExplainTask is a task of a plan as shown by EXPLAIN, the operator, what
+// it does and the tasks it runs, either in parallel or in sequence.
+type ExplainTask struct {
+	Task     string         `json:"task"`
+	Detail   string         `json:"detail,omitempty"`
+	Parallel bool           `json:"parallel,omitempty"`
+	Stats    *ExplainStats  `json:"stats,omitempty"`
+	Children []*ExplainTask `json:"children,omitempty"`
+}
+
+// ExplainStats the execution statistics of a task, as shown by EXPLAIN
+// ANALYZE, times are in nanoseconds in json.
+type ExplainStats struct {
+	RowsIn       int64         `json:"rows_in"`
+	RowsOut      int64         `json:"rows_out"`
+	Wall         time.Duration `json:"wall"`
+	Blocked      time.Duration `json:"blocked"`
+	PeakBuffered int64         `json:"peak_buffered"`
+}
+
+// ExplainAnalyze an EXPLAIN ANALYZE, the planned statement which must be
+// run by an executor before the rows of its plan, with the statistics of
+// each task, are known.
+type ExplainAnalyze struct {
+	Stmt *rel.SqlDescribe
+	Raw  string // the statement as written
+	Plan Task
+	Ctx  *Context
+}
+
+// Explain describe the dag of tasks of a plan.
+func Explain(t Task) *ExplainTask {
+	return explain(t, nil)
+}
+
+// explain the dag of tasks of a plan, with the statistics of each task
+// if known.
+func explain(t Task, stats func(Task) *ExplainStats) *ExplainTask {
+	e := &ExplainTask{
+		Task:     strings.TrimPrefix(fmt.Sprintf("%T", t), "*plan."),
+		Detail:   oneLine(explainDetail(t)),
+		Parallel: t.IsParallel(),
+	}
+	if stats != nil {
+		e.Stats = stats(t)
+	}
+	var children []Task
+	switch t := t.(type) {
+	case *JoinMerge:
+		// the two sides of a join are run in parallel
+		children = []Task{t.Left, t.Right}
+		e.Parallel = true
+	case *Union:
+		children = []Task{t.Left, t.Right}
+	case *SemiJoin:
+		if t.SubPlan != nil {
+			children = []Task{t.SubPlan}
+		}
+	case *Source:
+		if t.Cte != nil && t.Cte.Plan != nil {
+			children = []Task{t.Cte.Plan}
+		}
+	}
+	for _, child := range append(children, t.Children()...) {
+		e.Children = append(e.Children, explain(child, stats))
+	}
+	if sel, ok := t.(*Select); ok {
+		for _, sq := range sel.SubQueries {
+			se := &ExplainTask{Task: "ScalarSubQuery", Detail: oneLine(sq.Sub.String())}
+			if sq.SubPlan != nil {
+				se.Children = []*ExplainTask{explain(sq.SubPlan, stats)}
+			}
+			e.Children = append(e.Children, se)
+		}
+	}
+	return e
+}
+
+// Text the plan as lines of text, each task indented under its parent.
+func (m *ExplainTask) Text() []string {
+	return m.text(nil, 0)
+}
+
+func (m *ExplainTask) text(lines []string, depth int) []string {
+	line := strings.Repeat("    ", depth) + m.Task
+	if len(m.Children) > 1 {
+		if m.Parallel {
+			line += " (parallel)"
+		} else {
+			line += " (sequential)"
+		}
+	}
+	if m.Detail != "" {
+		line += ": " + m.Detail
+	}
+	if s := m.Stats; s != nil {
+		line += fmt.Sprintf("  (rows in=%d out=%d, wall=%v, blocked=%v, peak buffered=%d)",
+			s.RowsIn, s.RowsOut, s.Wall, s.Blocked, s.PeakBuffered)
+	}
+	lines = append(lines, line)
+	for _, child := range m.Children {
+		lines = child.text(lines, depth+1)
+	}
+	return lines
+}
+
+// oneLine a statement written over several lines (ie joins) as one.
+func oneLine(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, "\t")
+	}
+	return strings.Join(lines, " ")
+}
+
+func explainDetail(t Task) string {
+	switch t := t.(type) {
+	case *Select:
+		return t.Stmt.String()
+	case *Union:
+		detail := strings.ToUpper(t.Stmt.Keyword().String())
+		if t.Stmt.All {
+			detail += " ALL"
+		}
+		return detail
+	case *Source:
+		return explainSource(t)
+	case *Where:
+		if t.Residual != nil {
+			return t.Residual.String()
+		}
+		if t.Stmt.Where != nil && t.Stmt.Where.Expr != nil {
+			return t.Stmt.Where.Expr.String()
+		}
+	case *Having:
+		if t.Stmt.Having != nil {
+			return t.Stmt.Having.String()
+		}
+	case *GroupBy:
+		detail := t.Stmt.GroupBy.String()
+		if len(t.Stmt.GroupingSets) > 0 {
+			detail = t.Stmt.GroupingSetsString()
+		}
+		if t.Partial {
+			detail += " (partial)"
+		}
+		if t.Stream {
+			detail += " (sorted)"
+		}
+		if t.Merge != nil {
+			detail += " (merge partials)"
+		}
+		return detail
+	case *Order:
+		return t.Stmt.OrderBy.String()
+	case *Window:
+		var windows []string
+		for _, n := range t.Nodes() {
+			windows = append(windows, n.String())
+		}
+		return strings.Join(windows, ", ")
+	case *Projection:
+		return t.Stmt.Columns.String()
+	case *JoinMerge:
+		detail := t.Strategy.String()
+		if t.BuildLeft && t.Strategy == JoinHash {
+			detail += " build left"
+		}
+		if t.RightFrom != nil && t.RightFrom.JoinExpr != nil {
+			detail += " ON " + t.RightFrom.JoinExpr.String()
+		}
+		return detail
+	case *SemiJoin:
+		detail := "IN"
+		if t.Exists {
+			detail = "EXISTS"
+		}
+		if t.Anti {
+			detail = "NOT " + detail
+		}
+		if t.Correlated {
+			detail += " correlated"
+		}
+		return detail + " (" + t.Sub.String() + ")"
+	case *JoinKey:
+		if t.Source != nil && t.Source.Stmt != nil {
+			return t.Source.Stmt.SourceName()
+		}
+	}
+	return ""
+}
+
+// explainSource the source of a task, and what of the query was pushed
+// down to it.
+func explainSource(t *Source) string {
+	if t.Stmt == nil {
+		return ""
+	}
+	detail := t.Stmt.SourceName()
+	if t.Stmt.Alias != "" && t.Stmt.Alias != detail {
+		detail += " AS " + t.Stmt.Alias
+	}
+	switch {
+	case t.Cte != nil:
+		detail += " (common table expression)"
+	case t.Complete && t.Stmt.Source != nil:
+		// the source runs the whole query
+		detail += " pushed down: " + t.Stmt.Source.String()
+	case t.Join && t.Stmt.Source != nil:
+		detail += " query: " + t.Stmt.Source.String()
+	}
+	if len(t.Filters) > 0 {
+		filters := make([]string, len(t.Filters))
+		for i, f := range t.Filters {
+			filters[i] = f.String()
+		}
+		detail += " filter: " + strings.Join(filters, " AND ")
+	}
+	if _, ok := t.Conn.(schema.ConnProjection); ok && t.Needed != nil {
+		detail += " columns: " + strings.Join(t.Needed, ", ")
+	}
+	if t.Limit > 0 {
+		detail += fmt.Sprintf(" limit: %d", t.Limit)
+		if len(t.OrderBy) > 0 {
+			detail += " order by: " + t.OrderBy.String()
+		}
+	}
+	if t.GroupBy != nil {
+		aggs := make([]string, len(t.GroupBy.Aggs))
+		for i, agg := range t.GroupBy.Aggs {
+			aggs[i] = agg.String()
+		}
+		detail += " aggregate: " + strings.Join(aggs, ", ")
+		if len(t.GroupBy.Stmt.GroupBy) > 0 {
+			detail += " group by: " + t.GroupBy.Stmt.GroupBy.String()
+		}
+	}
+	return detail
+}
+
+// RewriteExplainAsSelect plan the statement of an EXPLAIN, then re-write
+// the EXPLAIN as a select over the rows of the plan in text, one row
+// per task, or as a single row of json.
+func RewriteExplainAsSelect(stmt *rel.SqlDescribe, ctx *Context) (*rel.SqlSelect, error) {
+
+	// planning re-writes the statement, so show it as written
+	raw := stmt.Stmt.String()
+	sctx := ctx.SubQueryContext(stmt.Stmt)
+	p, err := WalkStmt(sctx, stmt.Stmt, NewPlanner(sctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.Analyze {
+		// the rows aren't known until the executor has run it
+		cte := newCte("query_plan", []string{"plan"})
+		cte.Analyze = &ExplainAnalyze{Stmt: stmt, Raw: raw, Plan: p, Ctx: sctx}
+		ctx.AddCte(cte)
+	} else {
+		rows, err := explainRows(stmt, raw, Explain(p))
+		if err != nil {
+			return nil, err
+		}
+		ctx.AddCte(NewCteRows("query_plan", []string{"plan"}, rows))
+	}
+	return rel.ParseSqlSelect("SELECT plan FROM query_plan")
+}
+
+// Rows the rows of the plan, with the statistics of each task once run.
+func (m *ExplainAnalyze) Rows(stats func(Task) *ExplainStats) ([][]driver.Value, error) {
+	return explainRows(m.Stmt, m.Raw, explain(m.Plan, stats))
+}
+
+// explainRows the rows of the plan in the format of the EXPLAIN.
+func explainRows(stmt *rel.SqlDescribe, raw string, e *ExplainTask) ([][]driver.Value, error) {
+	e.Detail = oneLine(raw)
+	var rows [][]driver.Value
+	switch stmt.Format {
+	case "json":
+		by, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, []driver.Value{string(by)})
+	default:
+		for _, line := range e.Text() {
+			rows = append(rows, []driver.Value{line})
+		}
+	}
+	return rows, nil
+}
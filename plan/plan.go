@@ -12,6 +12,7 @@ import (
 	u "github.com/araddon/gou"
 	"github.com/golang/protobuf/proto"
 
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -27,6 +28,7 @@ var (
 	// Ensure our tasks implement Task Interface
 	_ Task = (*PreparedStatement)(nil)
 	_ Task = (*Select)(nil)
+	_ Task = (*Union)(nil)
 	_ Task = (*Insert)(nil)
 	_ Task = (*Upsert)(nil)
 	_ Task = (*Update)(nil)
@@ -40,14 +42,41 @@ var (
 	_ Task = (*Having)(nil)
 	_ Task = (*GroupBy)(nil)
 	_ Task = (*Order)(nil)
+	_ Task = (*Distinct)(nil)
+	_ Task = (*Window)(nil)
 	_ Task = (*JoinMerge)(nil)
 	_ Task = (*JoinKey)(nil)
+	_ Task = (*SemiJoin)(nil)
 
 	// Force any plan that participates in a Select to implement Proto
 	//  which allows us to serialize and distribute to multiple nodes.
 	_ Proto = (*Select)(nil)
 )
 
+// JoinStrategy how a JoinMerge matches the rows of its two inputs.
+type JoinStrategy uint8
+
+const (
+	// JoinHash matches rows on equal (possibly composite) join key
+	//    ON a.x = b.x AND a.y = b.y
+	JoinHash JoinStrategy = iota
+	// JoinNestedLoop compares each pair of rows, for CROSS JOIN
+	// or join expressions without equality conditions
+	//    ON a.ts BETWEEN b.start AND b.end
+	JoinNestedLoop
+)
+
+// String name of join strategy.
+func (m JoinStrategy) String() string {
+	switch m {
+	case JoinHash:
+		return "hash"
+	case JoinNestedLoop:
+		return "nested-loop"
+	}
+	return "unknown"
+}
+
 type (
 	// SchemaLoader func interface for loading schema.
 	SchemaLoader func(name string) (*schema.Schema, error)
@@ -111,6 +140,7 @@ type (
 		WalkCreate(p *Create) error
 		WalkDrop(p *Drop) error
 		WalkAlter(p *Alter) error
+		WalkAnalyze(p *Analyze) error
 	}
 
 	// SourcePlanner Sources can often do their own planning for sub-select statements
@@ -120,6 +150,12 @@ type (
 		// given our request statement, turn that into a plan.Task.
 		WalkSourceSelect(pl Planner, s *Source) (Task, error)
 	}
+
+	// UnionPlanner is an optional interface for planners that can plan
+	// set operations (UNION, INTERSECT, EXCEPT).
+	UnionPlanner interface {
+		WalkUnion(p *Union) error
+	}
 )
 
 type (
@@ -136,12 +172,26 @@ type (
 	}
 	// Select plan
 	Select struct {
+		*PlanBase
+		Ctx        *Context
+		From       []*Source
+		Stmt       *rel.SqlSelect
+		ChildDag   bool
+		SubQueries []*ScalarSubQuery // sub-queries used as values in expressions
+		pbplan     *PlanPb
+	}
+	// Union plan for set operations (UNION, INTERSECT, EXCEPT) of two
+	// select (or nested union) plans.
+	Union struct {
 		*PlanBase
 		Ctx      *Context
-		From     []*Source
-		Stmt     *rel.SqlSelect
-		ChildDag bool
-		pbplan   *PlanPb
+		Stmt     *rel.SqlUnion
+		Left     Task           // *Select or *Union
+		Right    Task           // *Select or *Union
+		Sel      *rel.SqlSelect // select over result columns for order by, limit
+		ColIndex map[string]int // result column name to position
+		Proj     *rel.Projection
+		known    []bool // which Proj column types are certain
 	}
 	// Insert plan
 	Insert struct {
@@ -201,7 +251,13 @@ type (
 		Schema     *schema.Schema // Schema for this source/from
 		Tbl        *schema.Table  // Table schema for this From
 		Static     []driver.Value // this is static data source
+		Cte        *Cte           // this is a WITH common table expression source
 		Cols       []string
+		Filters    []expr.Node // conditions of the where the connection filters its rows by
+		Needed     []string    // columns of this source the query reads, nil if all of them
+		Limit      int         // rows the connection stops after, 0 if it returns all of them
+		OrderBy    rel.Columns // order by the connection sorts its rows by for the limit
+		GroupBy    *GroupBy    // partial group by the connection computes, nil if it returns its rows
 	}
 	// Into Select INTO table
 	Into struct {
@@ -213,17 +269,33 @@ type (
 		*PlanBase
 		Stmt    *rel.SqlSelect
 		Partial bool
+		Stream  bool             // input is sorted on the group by, emit each group as it completes
+		Aggs    []*expr.FuncNode // aggregates of a partial group by computed by a source
+		Merge   *GroupBy         // partial group by of the source this merges, nil if it aggregates rows
 	}
 	// Order By clause
 	Order struct {
 		*PlanBase
 		Stmt *rel.SqlSelect
 	}
+	// Distinct removes duplicate rows from final projected results
+	// for SELECT DISTINCT.
+	Distinct struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
+	// Window computes the window functions, fn() OVER (...), of the columns
+	// and order by of the statement, after the where, group by and having.
+	Window struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
 	// Where pre-aggregation filter
 	Where struct {
 		*PlanBase
-		Final bool
-		Stmt  *rel.SqlSelect
+		Final    bool
+		Stmt     *rel.SqlSelect
+		Residual expr.Node // conditions left to evaluate if the source took some of the where, else nil
 	}
 	// Having post-aggregation filter plan.
 	Having struct {
@@ -238,6 +310,45 @@ type (
 		LeftFrom  *rel.SqlSource
 		RightFrom *rel.SqlSource
 		ColIndex  map[string]int
+		Strategy  JoinStrategy
+		Residual  []expr.Node // join conditions evaluated per pair of rows
+		BuildLeft bool        // hash the left input and probe it with the right, the left is smaller
+	}
+	// SemiJoin filters rows on a sub-query predicate of the where clause
+	//
+	//    WHERE x [NOT] IN (SELECT ...)
+	//    WHERE [NOT] EXISTS (SELECT ...)
+	//
+	// Un-correlated (or de-correlated) sub-queries are planned once and
+	// their rows hashed on the IN column and correlation keys (Outer).  A
+	// Correlated sub-query is run per distinct value of its outer Refs.
+	SemiJoin struct {
+		*PlanBase
+		Ctx        *Context             // context of the sub-query
+		Stmt       *rel.SqlSelect       // the outer statement
+		Sub        *rel.SqlSelect       // sub-query, de-correlated if possible
+		SubPlan    Task                 // plan of Sub, nil if Correlated
+		Exists     bool                 // EXISTS (vs IN)
+		Anti       bool                 // NOT IN, NOT EXISTS
+		Arg        expr.Node            // left side of IN, evaluated on outer rows
+		Outer      []expr.Node          // outer side of de-correlated equality keys
+		Correlated bool                 // must be run per outer row
+		Refs       []*expr.IdentityNode // outer references of a Correlated sub-query
+	}
+	// ScalarSubQuery a sub-query used as a value of an expression of the
+	// select list, where, having, group by or order by
+	//
+	//    SELECT name, (SELECT count(*) FROM orders o WHERE o.user_id = u.id) AS n FROM users u
+	//
+	// It is not a task, the executor binds an evaluator to the Node that
+	// runs Sub, per distinct value of the outer Refs if correlated.
+	ScalarSubQuery struct {
+		Ctx     *Context             // context of the sub-query
+		Node    *expr.SubQueryNode   // the sub-query expression node
+		Stmt    *rel.SqlSelect       // the outer statement
+		Sub     *rel.SqlSelect       // the sub-query
+		SubPlan Task                 // plan of Sub, nil if correlated
+		Refs    []*expr.IdentityNode // outer references of a correlated sub-query
 	}
 	// JoinKey plan
 	JoinKey struct {
@@ -265,6 +376,20 @@ type (
 		Ctx  *Context
 		Stmt *rel.SqlAlter
 	}
+	// Analyze plan for ANALYZE TABLE, the statistics of the table are the
+	// result of an aggregate query over it
+	//
+	//    SELECT count(*), count(f), approx_count_distinct(f), min(f), max(f), ... FROM tbl
+	Analyze struct {
+		*PlanBase
+		Ctx     *Context
+		Stmt    *rel.SqlAnalyze
+		Tbl     *schema.Table  // the table analyzed
+		Fields  []string       // fields of the table, in the order of their aggregates in Sub
+		SubCtx  *Context       // context of the sub-query
+		Sub     *rel.SqlSelect // the aggregate query
+		SubPlan Task           // plan of Sub
+	}
 )
 
 // WalkStmt Walk given statement for given Planner to produce a query plan
@@ -275,6 +400,8 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 	switch st := stmt.(type) {
 	case *rel.SqlSelect:
 		p = &Select{Stmt: st, PlanBase: base, Ctx: ctx}
+	case *rel.SqlUnion:
+		p = NewUnion(ctx, st)
 	case *rel.SqlInsert:
 		p = &Insert{Stmt: st, PlanBase: base}
 	case *rel.SqlUpsert:
@@ -291,6 +418,16 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 		ctx.Stmt = sel
 		p = &Select{Stmt: sel, PlanBase: base, Ctx: ctx}
 	case *rel.SqlDescribe:
+		if st.Stmt != nil {
+			// EXPLAIN SELECT ...
+			sel, err := RewriteExplainAsSelect(st, ctx)
+			if err != nil {
+				return nil, err
+			}
+			ctx.Stmt = sel
+			p = &Select{Stmt: sel, PlanBase: base, Ctx: ctx}
+			break
+		}
 		sel, err := RewriteDescribeAsSelect(st, ctx)
 		if err != nil {
 			return nil, err
@@ -307,6 +444,8 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 		p = &Drop{Stmt: st, PlanBase: base, Ctx: ctx}
 	case *rel.SqlAlter:
 		p = &Alter{Stmt: st, PlanBase: base, Ctx: ctx}
+	case *rel.SqlAnalyze:
+		p = &Analyze{Stmt: st, PlanBase: base, Ctx: ctx}
 	default:
 		panic(fmt.Sprintf("Not implemented for %T", stmt))
 	}
@@ -340,6 +479,10 @@ func SelectTaskFromTaskPb(pb *PlanPb, ctx *Context, sel *rel.SqlSelect) (Task, e
 		return GroupByFromPB(pb), nil
 	case pb.Order != nil:
 		return OrderFromPB(pb), nil
+	case pb.Distinct != nil:
+		return DistinctFromPB(pb), nil
+	case pb.Window != nil:
+		return WindowFromPB(pb), nil
 	case pb.Projection != nil:
 		return ProjectionFromPB(pb, sel), nil
 	case pb.JoinMerge != nil:
@@ -413,6 +556,21 @@ func (m *Source) Walk(p Planner) error            { return p.WalkSourceSelect(m)
 func (m *Create) Walk(p Planner) error            { return p.WalkCreate(m) }
 func (m *Drop) Walk(p Planner) error              { return p.WalkDrop(m) }
 func (m *Alter) Walk(p Planner) error             { return p.WalkAlter(m) }
+func (m *Analyze) Walk(p Planner) error           { return p.WalkAnalyze(m) }
+
+// Walk the set operation, if the planner is a UnionPlanner.
+func (m *Union) Walk(p Planner) error {
+	up, ok := p.(UnionPlanner)
+	if !ok {
+		return ErrNotImplemented
+	}
+	return up.WalkUnion(m)
+}
+
+// NewUnion creates a new Union Task plan.
+func NewUnion(ctx *Context, stmt *rel.SqlUnion) *Union {
+	return &Union{Stmt: stmt, PlanBase: NewPlanBase(false), Ctx: ctx}
+}
 
 // NewCreate creates a new Create Task plan.
 func NewCreate(ctx *Context, stmt *rel.SqlCreate) *Create {
@@ -429,6 +587,11 @@ func NewAlter(ctx *Context, stmt *rel.SqlAlter) *Alter {
 	return &Alter{Stmt: stmt, PlanBase: NewPlanBase(false), Ctx: ctx}
 }
 
+// NewAnalyze create Analyze plan task.
+func NewAnalyze(ctx *Context, stmt *rel.SqlAnalyze) *Analyze {
+	return &Analyze{Stmt: stmt, PlanBase: NewPlanBase(false), Ctx: ctx}
+}
+
 func (m *Select) Marshal() ([]byte, error) {
 	err := m.serializeToPb()
 	if err != nil {
@@ -757,6 +920,12 @@ func (m *Source) load() error {
 	if m.ctx == nil {
 		return fmt.Errorf("missing context in Source")
 	}
+	if cte, ok := m.ctx.Cte(fromName); ok && m.Stmt.Schema == "" {
+		m.Cte = cte
+		m.DataSource = cte
+		m.Tbl = cte.Tbl
+		return projectionForSourcePlan(m)
+	}
 	if m.ctx.Schema == nil {
 		u.Errorf("missing schema in *plan.Source load() from:%q", fromName)
 		return fmt.Errorf("Missing schema for %v", fromName)
@@ -861,6 +1030,14 @@ func NewJoinMerge(l, r Task, lf, rf *rel.SqlSource) *JoinMerge {
 	m.LeftFrom = lf
 	m.RightFrom = rf
 
+	// Equality conditions between the two sides are hashed as the join key,
+	// the rest (or everything if there are none) are evaluated per pair.
+	equi, residual := rel.JoinConditions(rf.JoinExpr)
+	if len(equi) == 0 {
+		m.Strategy = JoinNestedLoop
+	}
+	m.Residual = residual
+
 	// Build an index of source to destination column indexing
 	for _, col := range lf.Source.Columns {
 		//u.Debugf("left col:  idx=%d  key=%q as=%q col=%v parentidx=%v", len(m.colIndex), col.Key(), col.As, col.String(), col.ParentIndex)
@@ -881,6 +1058,11 @@ func NewJoinKey(s *Source) *JoinKey {
 	return &JoinKey{Source: s, PlanBase: NewPlanBase(false)}
 }
 
+// NewSemiJoin creates a SemiJoin for given outer statement and sub-query.
+func NewSemiJoin(ctx *Context, stmt, sub *rel.SqlSelect) *SemiJoin {
+	return &SemiJoin{Stmt: stmt, Sub: sub, Ctx: ctx, PlanBase: NewPlanBase(false)}
+}
+
 // NewWhere new Where Task from SqlSelect statement.
 func NewWhere(stmt *rel.SqlSelect) *Where {
 	return &Where{Stmt: stmt, PlanBase: NewPlanBase(false)}
@@ -906,6 +1088,16 @@ func NewOrder(stmt *rel.SqlSelect) *Order {
 	return &Order{Stmt: stmt, PlanBase: NewPlanBase(false)}
 }
 
+// NewDistinct from SqlSelect statement.
+func NewDistinct(stmt *rel.SqlSelect) *Distinct {
+	return &Distinct{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+
+// NewWindow from SqlSelect statement.
+func NewWindow(stmt *rel.SqlSelect) *Window {
+	return &Window{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+
 // Equal compares equality of two tasks.
 func (m *Into) Equal(t Task) bool {
 	if m == nil && t == nil {
@@ -1072,6 +1264,129 @@ func OrderFromPB(pb *PlanPb) *Order {
 	return &m
 }
 
+func (m *Distinct) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Distinct = &DistinctPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Distinct) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Distinct)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func DistinctFromPB(pb *PlanPb) *Distinct {
+	m := Distinct{
+		Stmt: rel.SqlSelectFromPb(pb.Distinct.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
+func (m *Window) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Window = &WindowPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Window) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Window)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func WindowFromPB(pb *PlanPb) *Window {
+	m := Window{
+		Stmt: rel.SqlSelectFromPb(pb.Window.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
+func (m *Union) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Union)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	if !m.Stmt.Equal(s.Stmt) {
+		return false
+	}
+	return true
+}
+
+// Equal compares equality of two tasks.
+func (m *SemiJoin) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*SemiJoin)
+	if !ok {
+		return false
+	}
+	if m.Exists != s.Exists || m.Anti != s.Anti || m.Correlated != s.Correlated {
+		return false
+	}
+	if !m.Sub.Equal(s.Sub) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+
 func (m *JoinMerge) Equal(t Task) bool {
 	if m == nil && t == nil {
 		return true
@@ -1086,6 +1401,12 @@ func (m *JoinMerge) Equal(t Task) bool {
 	if !ok {
 		return false
 	}
+	if m.Strategy != s.Strategy {
+		return false
+	}
+	if m.BuildLeft != s.BuildLeft {
+		return false
+	}
 
 	if !m.PlanBase.EqualBase(s.PlanBase) {
 		return false
@@ -8,17 +8,21 @@
 	It is generated from these files:
 		plan.proto
 
-	It has these top-level messages:
-		PlanPb
-		SelectPb
-		ContextPb
-		SourcePb
-		WherePb
-		GroupByPb
-		HavingPb
-		OrderPb
-		JoinMergePb
-		JoinKeyPb
+	plan.proto
+
+It has these top-level messages:
+
+	PlanPb
+	SelectPb
+	ContextPb
+	SourcePb
+	WherePb
+	GroupByPb
+	HavingPb
+	OrderPb
+	JoinMergePb
+	JoinKeyPb
+	DistinctPb
 */
 package plan
 
@@ -55,6 +59,8 @@ type PlanPb struct {
 	JoinKey          *JoinKeyPb        `protobuf:"bytes,10,opt,name=joinKey" json:"joinKey,omitempty"`
 	Projection       *rel.ProjectionPb `protobuf:"bytes,11,opt,name=projection" json:"projection,omitempty"`
 	Children         []*PlanPb         `protobuf:"bytes,12,rep,name=children" json:"children,omitempty"`
+	Distinct         *DistinctPb       `protobuf:"bytes,13,opt,name=distinct" json:"distinct,omitempty"`
+	Window           *WindowPb         `protobuf:"bytes,14,opt,name=window" json:"window,omitempty"`
 	XXX_unrecognized []byte            `json:"-"`
 }
 
@@ -173,6 +179,26 @@ func (m *JoinKeyPb) String() string            { return proto.CompactTextString(
 func (*JoinKeyPb) ProtoMessage()               {}
 func (*JoinKeyPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{9} }
 
+type DistinctPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *DistinctPb) Reset()                    { *m = DistinctPb{} }
+func (m *DistinctPb) String() string            { return proto.CompactTextString(m) }
+func (*DistinctPb) ProtoMessage()               {}
+func (*DistinctPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{10} }
+
+type WindowPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *WindowPb) Reset()                    { *m = WindowPb{} }
+func (m *WindowPb) String() string            { return proto.CompactTextString(m) }
+func (*WindowPb) ProtoMessage()               {}
+func (*WindowPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{11} }
+
 func init() {
 	proto.RegisterType((*PlanPb)(nil), "plan.PlanPb")
 	proto.RegisterType((*SelectPb)(nil), "plan.SelectPb")
@@ -184,6 +210,8 @@ func init() {
 	proto.RegisterType((*OrderPb)(nil), "plan.OrderPb")
 	proto.RegisterType((*JoinMergePb)(nil), "plan.JoinMergePb")
 	proto.RegisterType((*JoinKeyPb)(nil), "plan.JoinKeyPb")
+	proto.RegisterType((*DistinctPb)(nil), "plan.DistinctPb")
+	proto.RegisterType((*WindowPb)(nil), "plan.WindowPb")
 }
 func (m *PlanPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -310,6 +338,26 @@ func (m *PlanPb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Distinct != nil {
+		data[i] = 0x6a
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Distinct.Size()))
+		n10, err := m.Distinct.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.Window != nil {
+		data[i] = 0x72
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Window.Size()))
+		n11, err := m.Window.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -671,6 +719,68 @@ func (m *JoinKeyPb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *DistinctPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *DistinctPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n30, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *WindowPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n31, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n31
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func encodeFixed64Plan(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	data[offset+1] = uint8(v >> 8)
@@ -744,6 +854,14 @@ func (m *PlanPb) Size() (n int) {
 			n += 1 + l + sovPlan(uint64(l))
 		}
 	}
+	if m.Distinct != nil {
+		l = m.Distinct.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.Window != nil {
+		l = m.Window.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -885,6 +1003,32 @@ func (m *JoinKeyPb) Size() (n int) {
 	return n
 }
 
+func (m *DistinctPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovPlan(x uint64) (n int) {
 	for {
 		n++
@@ -1277,6 +1421,72 @@ func (m *PlanPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Distinct == nil {
+				m.Distinct = &DistinctPb{}
+			}
+			if err := m.Distinct.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Window == nil {
+				m.Window = &WindowPb{}
+			}
+			if err := m.Window.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPlan(data[iNdEx:])
@@ -2353,6 +2563,174 @@ func (m *JoinKeyPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *DistinctPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DistinctPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DistinctPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPlan(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -2459,43 +2837,46 @@ var (
 )
 
 var fileDescriptorPlan = []byte{
-	// 602 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x5b, 0x8e, 0xd3, 0x3c,
-	0x18, 0x9d, 0xf4, 0x9a, 0x7c, 0xed, 0xff, 0x53, 0xc2, 0x08, 0x99, 0x79, 0x28, 0x55, 0xb8, 0xa8,
-	0x30, 0x22, 0x11, 0x65, 0x07, 0x45, 0xc0, 0x68, 0x10, 0x43, 0xa5, 0x79, 0x40, 0xe2, 0x2d, 0x17,
-	0x4f, 0x92, 0x91, 0x1b, 0xa7, 0x6e, 0x0a, 0x9d, 0x9d, 0xb0, 0x05, 0x76, 0xd2, 0x47, 0x56, 0x80,
-	0xb8, 0x3c, 0xb0, 0x0d, 0x1c, 0x3b, 0x71, 0x5d, 0x24, 0x46, 0xe5, 0x21, 0x52, 0x72, 0x7c, 0x7c,
-	0xfc, 0xf9, 0x3b, 0xe7, 0x0b, 0x40, 0x4e, 0xfc, 0xcc, 0xcd, 0x19, 0x2d, 0xa8, 0xdd, 0x2a, 0xdf,
-	0x8f, 0x9e, 0xc4, 0x69, 0x91, 0xac, 0x02, 0x37, 0xa4, 0x73, 0x2f, 0xa6, 0x31, 0xf5, 0xc4, 0x62,
-	0xb0, 0xba, 0x10, 0x5f, 0xe2, 0x43, 0xbc, 0xc9, 0x4d, 0x47, 0x8f, 0x34, 0xba, 0xcf, 0xfc, 0x28,
-	0xa2, 0x99, 0xb7, 0x20, 0x01, 0x4b, 0xa3, 0x18, 0x7b, 0x0c, 0x13, 0x6f, 0xb9, 0x20, 0x15, 0xf5,
-	0xf8, 0x3a, 0x2a, 0x5e, 0xe7, 0xcc, 0xcb, 0x68, 0x84, 0x25, 0xd9, 0xf9, 0xdc, 0x84, 0xce, 0x8c,
-	0xd7, 0x33, 0x0b, 0xec, 0xdb, 0x60, 0xe6, 0x9c, 0x4f, 0x08, 0x26, 0xc8, 0x18, 0x35, 0xc6, 0xe6,
-	0xb4, 0xb5, 0xf9, 0x7a, 0xf7, 0xc0, 0xbe, 0x0f, 0x9d, 0x25, 0x26, 0x38, 0x2c, 0x50, 0x73, 0x64,
-	0x8c, 0x7b, 0x93, 0xff, 0x5d, 0x71, 0x99, 0x73, 0x81, 0xcd, 0x02, 0xc1, 0x32, 0x04, 0x8b, 0xae,
-	0x58, 0x88, 0x51, 0x6b, 0x87, 0x25, 0x30, 0xc5, 0x72, 0xa0, 0xfd, 0x31, 0xc1, 0x0c, 0xa3, 0xb6,
-	0x20, 0xfd, 0x27, 0x49, 0xef, 0x4a, 0x48, 0x57, 0x4a, 0xfc, 0x0f, 0x69, 0x16, 0xa3, 0x8e, 0xae,
-	0x74, 0x22, 0x30, 0xc5, 0x7a, 0x08, 0xdd, 0x98, 0xd1, 0x55, 0x3e, 0xbd, 0x42, 0x5d, 0x41, 0xbb,
-	0x21, 0x69, 0xaf, 0x24, 0xa8, 0x9f, 0x48, 0x59, 0x84, 0x19, 0x32, 0xf5, 0x13, 0xdf, 0x96, 0x90,
-	0xe2, 0x3c, 0x06, 0xeb, 0x92, 0xa6, 0xd9, 0x1b, 0xcc, 0x62, 0x8c, 0x2c, 0xc1, 0xbb, 0x29, 0x79,
-	0xa7, 0x35, 0xac, 0x9f, 0x5b, 0x72, 0x5f, 0xe3, 0x2b, 0x04, 0xfa, 0xb9, 0xa7, 0x12, 0x54, 0xbc,
-	0x63, 0xee, 0x39, 0xa3, 0x97, 0xbc, 0x45, 0x29, 0xcd, 0x50, 0xaf, 0x12, 0xe5, 0x4e, 0xb9, 0x33,
-	0x05, 0x6b, 0x57, 0x36, 0xc3, 0x24, 0x25, 0x11, 0xc3, 0x19, 0xea, 0x8f, 0x9a, 0x9c, 0xda, 0x97,
-	0xaa, 0xd2, 0x1a, 0xc9, 0x72, 0xde, 0x83, 0x59, 0x37, 0x9d, 0x97, 0x51, 0x9b, 0x52, 0x5a, 0xd5,
-	0x9b, 0x0c, 0x84, 0xf4, 0xf9, 0x82, 0xfc, 0x61, 0x0b, 0x2f, 0x37, 0xa4, 0x59, 0x81, 0xd7, 0x05,
-	0x6a, 0xe8, 0xe5, 0x3e, 0x97, 0xa0, 0xd2, 0x3e, 0x03, 0x4b, 0x41, 0xf6, 0x21, 0x17, 0x0f, 0x13,
-	0x3c, 0xf7, 0x85, 0xb8, 0x55, 0xe5, 0x60, 0x00, 0x8d, 0x34, 0xe2, 0x2a, 0x8d, 0x71, 0xab, 0x42,
-	0xee, 0x40, 0xef, 0x82, 0x7b, 0x82, 0x59, 0xce, 0xd2, 0xac, 0x8c, 0x87, 0x5a, 0x72, 0x7e, 0x19,
-	0xbc, 0xd8, 0xca, 0x7b, 0x7b, 0x08, 0x83, 0x0c, 0xe3, 0x68, 0x79, 0xe2, 0x2f, 0x13, 0x3f, 0x20,
-	0xb8, 0x6c, 0x5e, 0x43, 0x4b, 0xd8, 0x2d, 0x68, 0x73, 0x1d, 0x9f, 0x08, 0x85, 0x1a, 0xe4, 0x71,
-	0xe4, 0x09, 0xce, 0x09, 0x2e, 0xca, 0x48, 0x6d, 0x71, 0x1b, 0x5a, 0xa5, 0x01, 0x3c, 0x41, 0x5b,
-	0x0c, 0x01, 0xc8, 0xf0, 0xbd, 0x58, 0xe3, 0x90, 0xc7, 0x66, 0xbb, 0xc2, 0xaf, 0x12, 0xae, 0x96,
-	0x05, 0x9d, 0x8b, 0x94, 0xf4, 0xab, 0xae, 0xdc, 0x03, 0x8b, 0xcf, 0x8b, 0xac, 0xaf, 0x0a, 0xc6,
-	0xb6, 0x81, 0x75, 0xd5, 0x0f, 0x76, 0x1c, 0xb4, 0xfe, 0xe2, 0xa0, 0xf3, 0x12, 0xba, 0x55, 0x7e,
-	0x77, 0x4c, 0x31, 0xae, 0x31, 0x45, 0xdd, 0x57, 0x6b, 0x82, 0xf3, 0x0c, 0x2c, 0x95, 0xdd, 0x7d,
-	0x95, 0x9c, 0x09, 0x98, 0xf5, 0x5c, 0xec, 0xbd, 0xe7, 0x29, 0x74, 0xab, 0xf8, 0xff, 0xc3, 0x96,
-	0x9e, 0x36, 0x09, 0x7c, 0xa6, 0xea, 0x09, 0x95, 0xdb, 0xfa, 0x6e, 0xf9, 0x5b, 0x71, 0xcf, 0xf8,
-	0x6f, 0x45, 0x6d, 0xf1, 0xc0, 0x52, 0x23, 0xb1, 0xcf, 0x86, 0xe9, 0xe1, 0xe6, 0xfb, 0xf0, 0x60,
-	0xf3, 0x63, 0x68, 0x7c, 0xe1, 0xcf, 0x37, 0xfe, 0x7c, 0xfa, 0x39, 0x3c, 0xf8, 0x1d, 0x00, 0x00,
-	0xff, 0xff, 0xed, 0x08, 0xa3, 0x5b, 0x39, 0x05, 0x00, 0x00,
+	// 645 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0xdf, 0x6e, 0xd3, 0x3e,
+	0x14, 0xc7, 0x97, 0xfe, 0x4d, 0x4e, 0xbb, 0xfd, 0xf6, 0x0b, 0xd3, 0x64, 0x76, 0x51, 0xaa, 0x00,
+	0x53, 0x61, 0xa2, 0x15, 0x83, 0x27, 0x18, 0xff, 0xa6, 0x21, 0x46, 0xa5, 0x5d, 0x4c, 0xe2, 0x2e,
+	0x4d, 0xce, 0x52, 0x4f, 0xae, 0x9d, 0x3a, 0x29, 0xdb, 0xde, 0x84, 0x47, 0xda, 0x25, 0x4f, 0x80,
+	0x60, 0x5c, 0xf0, 0x0c, 0xdc, 0xa1, 0xd8, 0x89, 0xe7, 0x21, 0x31, 0x75, 0x77, 0xf1, 0xd7, 0x9f,
+	0x73, 0x6c, 0x9f, 0x73, 0xbe, 0x01, 0x48, 0x59, 0xc8, 0x87, 0xa9, 0x14, 0xb9, 0xf0, 0x1b, 0xc5,
+	0xf7, 0xd6, 0xb3, 0x84, 0xe6, 0xd3, 0xc5, 0x64, 0x18, 0x89, 0xd9, 0x28, 0x11, 0x89, 0x18, 0xa9,
+	0xcd, 0xc9, 0xe2, 0x44, 0xad, 0xd4, 0x42, 0x7d, 0xe9, 0xa0, 0xad, 0x27, 0x16, 0x1e, 0xca, 0x30,
+	0x8e, 0x05, 0x1f, 0xcd, 0xd9, 0x44, 0xd2, 0x38, 0xc1, 0x91, 0x44, 0x36, 0xca, 0xe6, 0xac, 0x44,
+	0x77, 0x6e, 0x43, 0xf1, 0x3c, 0x95, 0x23, 0x2e, 0x62, 0xd4, 0x70, 0xf0, 0xbb, 0x0e, 0xad, 0x31,
+	0x0b, 0xf9, 0x78, 0xe2, 0x6f, 0x82, 0x9b, 0x86, 0x32, 0x64, 0x0c, 0x19, 0x71, 0xfa, 0xb5, 0x81,
+	0xbb, 0xd7, 0xb8, 0xfc, 0xf6, 0x60, 0xc5, 0x7f, 0x04, 0xad, 0x0c, 0x19, 0x46, 0x39, 0xa9, 0xf7,
+	0x9d, 0x41, 0x67, 0x77, 0x6d, 0xa8, 0x1e, 0x73, 0xa4, 0xb4, 0xf1, 0x44, 0x51, 0x8e, 0xa2, 0xc4,
+	0x42, 0x46, 0x48, 0x1a, 0x37, 0x28, 0xa5, 0x19, 0x2a, 0x80, 0xe6, 0xd9, 0x14, 0x25, 0x92, 0xa6,
+	0x82, 0x56, 0x35, 0x74, 0x5c, 0x48, 0x76, 0xa6, 0x69, 0xf8, 0x99, 0xf2, 0x84, 0xb4, 0xec, 0x4c,
+	0xfb, 0x4a, 0x33, 0xd4, 0x36, 0xb4, 0x13, 0x29, 0x16, 0xe9, 0xde, 0x05, 0x69, 0x2b, 0xec, 0x3f,
+	0x8d, 0xbd, 0xd3, 0xa2, 0x7d, 0xa2, 0x90, 0x31, 0x4a, 0xe2, 0xda, 0x27, 0x7e, 0x2c, 0x24, 0xc3,
+	0x3c, 0x05, 0xef, 0x54, 0x50, 0xfe, 0x01, 0x65, 0x82, 0xc4, 0x53, 0xdc, 0xff, 0x9a, 0x3b, 0xa8,
+	0x64, 0xfb, 0xdc, 0x82, 0x7d, 0x8f, 0x17, 0x04, 0xec, 0x73, 0x0f, 0xb4, 0x68, 0xb8, 0x1d, 0x80,
+	0x54, 0x8a, 0x53, 0x8c, 0x72, 0x2a, 0x38, 0xe9, 0x94, 0x49, 0x25, 0xb2, 0xe1, 0xd8, 0xc8, 0xd6,
+	0x93, 0xdd, 0x68, 0x4a, 0x59, 0x2c, 0x91, 0x93, 0x6e, 0xbf, 0x3e, 0xe8, 0xec, 0x76, 0x75, 0x56,
+	0xdd, 0x9a, 0x92, 0x1a, 0x80, 0x1b, 0xd3, 0x2c, 0xa7, 0x3c, 0xca, 0xc9, 0xaa, 0x4a, 0xb8, 0xae,
+	0xa9, 0xd7, 0xa5, 0x6a, 0x97, 0xf0, 0x8c, 0xf2, 0x58, 0x9c, 0x91, 0x35, 0xbb, 0x84, 0xc7, 0x4a,
+	0xab, 0xa8, 0xe0, 0x13, 0xb8, 0x55, 0x13, 0xfd, 0x6d, 0xd3, 0xe4, 0xa2, 0xf5, 0x45, 0xe6, 0xe2,
+	0xaa, 0x47, 0x73, 0xf6, 0x57, 0x9b, 0xb7, 0xa1, 0x1d, 0x09, 0x9e, 0xe3, 0x79, 0x4e, 0x6a, 0xf6,
+	0xf3, 0x5f, 0x69, 0xd1, 0xe4, 0x3e, 0x04, 0xcf, 0x48, 0xfe, 0x06, 0xb4, 0xb2, 0x68, 0x8a, 0xb3,
+	0x50, 0x25, 0xf7, 0xca, 0xb9, 0x5a, 0x87, 0x1a, 0x8d, 0x49, 0xad, 0x5f, 0x1b, 0x34, 0x4a, 0xe5,
+	0x3e, 0x74, 0x4e, 0x28, 0x4f, 0x50, 0xa6, 0x92, 0xf2, 0x62, 0xdc, 0xcc, 0x56, 0xf0, 0xcb, 0x01,
+	0xb7, 0x9a, 0x25, 0xbf, 0x07, 0xeb, 0x1c, 0x31, 0xce, 0xf6, 0xc3, 0x6c, 0x1a, 0x4e, 0x18, 0x16,
+	0xcd, 0xa8, 0x59, 0x13, 0x7b, 0x0f, 0x9a, 0x27, 0x94, 0x87, 0x8c, 0xd4, 0x2d, 0x71, 0x13, 0xdc,
+	0x48, 0xcc, 0x52, 0x86, 0x79, 0x31, 0xa2, 0xd7, 0xba, 0x0f, 0x8d, 0xa2, 0xa1, 0xa4, 0x69, 0x69,
+	0x04, 0x40, 0x0f, 0xf3, 0x9b, 0x73, 0x8c, 0x48, 0xcb, 0xda, 0xd9, 0x80, 0x56, 0xb4, 0xc8, 0x72,
+	0x31, 0x53, 0x53, 0xd7, 0x2d, 0xab, 0xf2, 0x10, 0xbc, 0x6c, 0xce, 0xf4, 0xfd, 0xca, 0x41, 0xbb,
+	0x2e, 0x60, 0x75, 0xeb, 0xc7, 0x37, 0x26, 0xc2, 0xfb, 0xc7, 0x44, 0x04, 0x6f, 0xa1, 0x5d, 0xfa,
+	0xe1, 0x46, 0x53, 0x9c, 0x5b, 0x9a, 0x62, 0xde, 0x6b, 0x15, 0x21, 0x78, 0x01, 0x9e, 0xf1, 0xc2,
+	0xb2, 0x99, 0x82, 0x5d, 0x70, 0x2b, 0x9f, 0x2d, 0x1d, 0xf3, 0x1c, 0xda, 0xa5, 0x9d, 0xee, 0x10,
+	0xd2, 0xb1, 0x9c, 0xe5, 0x07, 0xc6, 0xf1, 0x3a, 0xac, 0x3b, 0x2c, 0x7e, 0x53, 0xc3, 0x43, 0x11,
+	0x1b, 0xdf, 0x05, 0x23, 0xf0, 0x8c, 0xc5, 0x96, 0x0a, 0x78, 0x09, 0x70, 0xed, 0x8b, 0xbb, 0x14,
+	0xa0, 0x72, 0xc9, 0xb2, 0x31, 0x7b, 0x1b, 0x97, 0x3f, 0x7a, 0x2b, 0x97, 0x57, 0x3d, 0xe7, 0xeb,
+	0x55, 0xcf, 0xf9, 0x7e, 0xd5, 0x73, 0xbe, 0xfc, 0xec, 0xad, 0xfc, 0x19, 0x00, 0x1d, 0xa6, 0x89,
+	0x54, 0xf3, 0x05, 0x00, 0x00,
 }
@@ -2,13 +2,18 @@ package plan
 
 import (
 	"fmt"
+	"strings"
 
 	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
 )
 
 var (
 	// Ensure our default planner meets Planner interface.
-	_ Planner = (*PlannerDefault)(nil)
+	_ Planner      = (*PlannerDefault)(nil)
+	_ UnionPlanner = (*PlannerDefault)(nil)
 )
 
 // PlannerDefault is implementation of Planner that creates a dag of plan.Tasks
@@ -65,3 +70,35 @@ func (m *PlannerDefault) WalkAlter(p *Alter) error {
 	u.Debugf("WalkAlter %#v", p)
 	return nil
 }
+
+// WalkAnalyze walk an ANALYZE TABLE plan, planning the aggregate query of
+// the statistics of the table's fields.
+func (m *PlannerDefault) WalkAnalyze(p *Analyze) error {
+	u.Debugf("WalkAnalyze %+v", p.Stmt)
+	if m.Ctx.Schema == nil {
+		return fmt.Errorf("must have schema")
+	}
+	tbl, err := m.Ctx.Schema.Table(strings.ToLower(p.Stmt.Identity))
+	if err != nil {
+		return err
+	}
+	if tbl == nil {
+		return fmt.Errorf("No table found for %q", p.Stmt.Identity)
+	}
+	p.Tbl = tbl
+
+	cols := []string{"count(*)"}
+	for _, f := range tbl.Fields {
+		name := expr.IdentityMaybeQuote('`', f.Name)
+		cols = append(cols, fmt.Sprintf("count(%[1]s), approx_count_distinct(%[1]s), min(%[1]s), max(%[1]s)", name))
+		p.Fields = append(p.Fields, f.Name)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), expr.IdentityMaybeQuote('`', tbl.Name))
+	p.Sub, err = rel.ParseSqlSelect(sql)
+	if err != nil {
+		return err
+	}
+	p.SubCtx = m.Ctx.SubQueryContext(p.Sub)
+	p.SubPlan, err = WalkStmt(p.SubCtx, p.Sub, NewPlanner(p.SubCtx))
+	return err
+}
@@ -0,0 +1,203 @@
+package plan
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure a common table expression is a source for the planner
+	_ schema.Source      = (*Cte)(nil)
+	_ schema.ConnColumns = (*Cte)(nil)
+)
+
+// Cte is a common table expression (WITH name AS (SELECT ...)) of a statement,
+// a temporary named source in the plan Context.  Its query is run, and rows
+// materialized, at most once no matter how many sources reference it.
+//
+// A recursive common table expression is planned as its anchor (the left
+// side of the UNION) then the Step (right side, referencing the cte itself)
+// is re-planned and run against the rows of the previous iteration until
+// no new rows are found.
+type Cte struct {
+	Stmt *rel.SqlCte
+	Name string
+	Cols []string
+	Tbl  *schema.Table
+	Ctx  *Context // context of the cte query, sees the cte's before it
+	Plan Task     // the planned query, or anchor if recursive
+
+	// Recursive
+	Step *rel.SqlSelect // the select referencing itself
+	All  bool           // UNION ALL keeps duplicate rows
+
+	// Analyze the rows are of an EXPLAIN ANALYZE, its plan once run
+	Analyze *ExplainAnalyze
+
+	once sync.Once
+	rows [][]driver.Value
+	err  error
+}
+
+// Rows the materialized rows of this common table expression, run is only
+// called on first use to produce them.
+func (m *Cte) Rows(run func() ([][]driver.Value, error)) ([][]driver.Value, error) {
+	m.once.Do(func() {
+		m.rows, m.err = run()
+	})
+	return m.rows, m.err
+}
+
+// WorkTable the working table of a recursive common table expression, a
+// cte of the same name and columns holding the rows of the last iteration.
+func (m *Cte) WorkTable(rows [][]driver.Value) *Cte {
+	w := &Cte{Stmt: m.Stmt, Name: m.Name, Cols: m.Cols, Tbl: m.Tbl}
+	w.once.Do(func() {
+		w.rows = rows
+	})
+	return w
+}
+
+// NewCteRows a common table expression of rows that are already known,
+// ie the rows of an EXPLAIN.
+func NewCteRows(name string, cols []string, rows [][]driver.Value) *Cte {
+	m := newCte(name, cols)
+	m.once.Do(func() {
+		m.rows = rows
+	})
+	return m
+}
+
+// newCte a common table expression of string columns, whose rows are
+// provided rather than by a query.
+func newCte(name string, cols []string) *Cte {
+	tbl := schema.NewTable(name)
+	for _, col := range cols {
+		tbl.AddFieldType(col, value.StringType)
+	}
+	tbl.SetColumns(cols)
+	return &Cte{Name: name, Cols: cols, Tbl: tbl}
+}
+
+// Recursive does this common table expression reference itself.
+func (m *Cte) Recursive() bool { return m.Step != nil }
+
+func (m *Cte) Init()                                     {}
+func (m *Cte) Setup(*schema.Schema) error                { return nil }
+func (m *Cte) Close() error                              { return nil }
+func (m *Cte) Open(source string) (schema.Conn, error)   { return m, nil }
+func (m *Cte) Tables() []string                          { return []string{m.Name} }
+func (m *Cte) Table(table string) (*schema.Table, error) { return m.Tbl, nil }
+func (m *Cte) Columns() []string                         { return m.Cols }
+
+// walkCtes plan the common table expressions (WITH) of a statement, each is
+// added to the context so it is visible as a source to the statement, its
+// sub-queries and the common table expressions that follow it.
+func (m *PlannerDefault) walkCtes(ctes []*rel.SqlCte) error {
+	for _, stmt := range ctes {
+		cte, err := m.walkCte(stmt)
+		if err != nil {
+			return err
+		}
+		m.Ctx.AddCte(cte)
+	}
+	return nil
+}
+
+func (m *PlannerDefault) walkCte(stmt *rel.SqlCte) (*Cte, error) {
+
+	cte := &Cte{Stmt: stmt, Name: stmt.Name}
+	query := stmt.Stmt
+	if stmt.Recursive {
+		if su, ok := stmt.Stmt.(*rel.SqlUnion); ok && sourcesReference(su.Right, stmt.Name) {
+			step, isSelect := su.Right.(*rel.SqlSelect)
+			if su.Op != lex.TokenUnion || !isSelect || sourcesReference(su.Left, stmt.Name) {
+				return nil, fmt.Errorf("recursive WITH %q must be: anchor UNION [ALL] SELECT ... FROM %s", stmt.Name, stmt.Name)
+			}
+			if len(su.OrderBy) > 0 || su.Limit > 0 {
+				return nil, fmt.Errorf("recursive WITH %q does not support ORDER BY or LIMIT", stmt.Name)
+			}
+			cte.Step, cte.All = step, su.All
+			query = su.Left
+		}
+		// otherwise it never references itself, so isn't really recursive
+	}
+
+	ctx := m.Ctx.SubQueryContext(query)
+	task, cols, err := NewPlanner(ctx).walkUnionArm(query)
+	if err != nil {
+		return nil, err
+	}
+	if cols == nil {
+		return nil, fmt.Errorf("could not determine the columns of WITH %q", stmt.Name)
+	}
+	if len(stmt.Columns) > 0 {
+		if len(stmt.Columns) != len(cols) {
+			return nil, fmt.Errorf("WITH %q has %d column names but its query has %d columns",
+				stmt.Name, len(stmt.Columns), len(cols))
+		}
+		for i, name := range stmt.Columns {
+			cols[i].name = name
+		}
+	}
+
+	cte.Ctx, cte.Plan = ctx, task
+	cte.Tbl = schema.NewTable(stmt.Name)
+	cte.Cols = make([]string, len(cols))
+	for i, col := range cols {
+		cte.Cols[i] = col.name
+		cte.Tbl.AddFieldType(col.name, col.vt)
+	}
+	cte.Tbl.SetColumns(cte.Cols)
+	return cte, nil
+}
+
+// walkSubQuerySource plan the sub-query of a single source select
+//
+//	SELECT ... FROM (SELECT ...) AS x
+//
+// as a common table expression named by its alias, so the outer query
+// sees the rows of the sub-query (ie filters on its window functions).
+func (m *PlannerDefault) walkSubQuerySource(from *rel.SqlSource) error {
+	if from.Alias == "" {
+		return fmt.Errorf("a sub-query source must have an alias: %s", from.SubQuery)
+	}
+	cte, err := m.walkCte(&rel.SqlCte{Name: from.Alias, Stmt: from.SubQuery})
+	if err != nil {
+		return err
+	}
+	m.Ctx.AddCte(cte)
+	from.Name = from.Alias
+	from.SubQuery = nil
+	return nil
+}
+
+// sourcesReference do the sources of the selects of this statement
+// reference the given name.
+func sourcesReference(stmt rel.SqlStatement, name string) bool {
+	var sels []*rel.SqlSelect
+	switch st := stmt.(type) {
+	case *rel.SqlSelect:
+		sels = []*rel.SqlSelect{st}
+	case *rel.SqlUnion:
+		sels = st.Selects()
+	}
+	for _, sel := range sels {
+		for _, from := range sel.From {
+			if strings.EqualFold(from.Name, name) {
+				return true
+			}
+			if from.SubQuery != nil && sourcesReference(from.SubQuery, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package plan
+
+import (
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+// joinSource a source of an inner join, and its rows estimated from the
+// statistics of its table collected by ANALYZE TABLE.
+type joinSource struct {
+	alias string
+	stats *schema.TableStats
+	rows  float64 // estimated rows passing the where conditions on this source
+}
+
+// joinCond a condition of the ON clause of an inner join, with the aliases
+// of the sources it refers to.  Equality of columns of two sources has the
+// columns, to estimate how many rows they match.
+type joinCond struct {
+	aliases     map[string]bool
+	left, right *expr.IdentityNode
+}
+
+// orderJoins estimate the rows of each source of an inner join of analyzed
+// tables, and reorder the sources so the joins with the smallest estimated
+// results are done first.  Returns the estimated rows of each source in the
+// (new) FROM order, and of the join of each source with those before it,
+// or nil if this is not an inner join or a source has no statistics.
+//
+// Sources are joined greedily: the pair of sources with the smallest join,
+// in FROM order, then the source which joined to those makes the smallest
+// result, preferring sources that have a join condition to a cross join.
+func (m *PlannerDefault) orderJoins(stmt *rel.SqlSelect) (rows, joined []float64) {
+	if m.Ctx.Schema == nil {
+		return nil, nil
+	}
+	srcs := make([]*joinSource, len(stmt.From))
+	var conds []*joinCond
+	for i, from := range stmt.From {
+		if from.SubQuery != nil || from.LeftOrRight != 0 {
+			return nil, nil
+		}
+		switch from.JoinType {
+		case 0, lex.TokenInner, lex.TokenCross:
+		default:
+			return nil, nil
+		}
+		name := strings.ToLower(from.SourceName())
+		if _, isCte := m.Ctx.Cte(name); isCte && from.Schema == "" {
+			return nil, nil
+		}
+		tbl, err := m.Ctx.Schema.Table(name)
+		if err != nil || tbl == nil {
+			return nil, nil
+		}
+		stats := tbl.Stats()
+		if stats == nil {
+			return nil, nil
+		}
+		alias := strings.ToLower(from.Alias)
+		if alias == "" {
+			alias = name
+		}
+		srcs[i] = &joinSource{alias: alias, stats: stats, rows: float64(stats.Rows)}
+		for _, node := range whereConjuncts(from.JoinExpr, nil) {
+			if node != nil {
+				conds = append(conds, newJoinCond(node))
+			}
+		}
+	}
+	if stmt.Where != nil && stmt.Where.Expr != nil {
+		for _, node := range whereConjuncts(stmt.Where.Expr, nil) {
+			for _, src := range srcs {
+				src.rows *= src.selectivity(node)
+			}
+		}
+	}
+
+	// The first pair, keeping FROM order if none are joined by a condition.
+	first, second, best := 0, 1, -1.0
+	for i := range srcs {
+		for j := i + 1; j < len(srcs); j++ {
+			est, connected := joinRows(srcs[i].rows, []*joinSource{srcs[i]}, srcs[j], conds)
+			if connected && (best < 0 || est < best) {
+				first, second, best = i, j, est
+			}
+		}
+	}
+	order := []int{first, second}
+	done := []*joinSource{srcs[first], srcs[second]}
+	used := map[int]bool{first: true, second: true}
+	rows = []float64{srcs[first].rows, srcs[second].rows}
+	est, _ := joinRows(srcs[first].rows, done[:1], srcs[second], conds)
+	joined = []float64{srcs[first].rows, est}
+
+	for len(order) < len(srcs) {
+		next, nextRows, nextConnected := -1, 0.0, false
+		for i, src := range srcs {
+			if used[i] {
+				continue
+			}
+			est, connected := joinRows(joined[len(joined)-1], done, src, conds)
+			if next < 0 || (connected && !nextConnected) || (connected == nextConnected && est < nextRows) {
+				next, nextRows, nextConnected = i, est, connected
+			}
+		}
+		order = append(order, next)
+		done = append(done, srcs[next])
+		used[next] = true
+		rows = append(rows, srcs[next].rows)
+		joined = append(joined, nextRows)
+	}
+
+	for k, i := range order {
+		if k != i {
+			if stmt.Star {
+				// the columns of SELECT * are in FROM order
+				return nil, nil
+			}
+			if err := stmt.ReorderJoins(order); err != nil {
+				return nil, nil
+			}
+			break
+		}
+	}
+	return rows, joined
+}
+
+func newJoinCond(node expr.Node) *joinCond {
+	c := &joinCond{aliases: make(map[string]bool)}
+	for _, in := range expr.FindAllIdentities(node) {
+		if left, _, hasLeft := in.LeftRight(); hasLeft {
+			c.aliases[strings.ToLower(left)] = true
+		}
+	}
+	if equi, _ := rel.JoinConditions(node); len(equi) == 1 {
+		bn := equi[0].(*expr.BinaryNode)
+		c.left, _ = bn.Args[0].(*expr.IdentityNode)
+		c.right, _ = bn.Args[1].(*expr.IdentityNode)
+	}
+	return c
+}
+
+// joinRows estimate the rows of joining src to the sources done so far, and
+// whether any join condition is between them.  Each equality condition
+// divides their cross product by the larger count of distinct values of
+// its two columns.
+func joinRows(rows float64, done []*joinSource, src *joinSource, conds []*joinCond) (float64, bool) {
+	est := rows * src.rows
+	connected := false
+	for _, c := range conds {
+		if !c.aliases[src.alias] {
+			continue
+		}
+		others := 0
+		for alias := range c.aliases {
+			if alias == src.alias {
+				continue
+			}
+			if joinSourceOf(done, alias) == nil {
+				others = -1
+				break
+			}
+			others++
+		}
+		if others <= 0 {
+			continue
+		}
+		connected = true
+		if c.left == nil || c.right == nil {
+			continue
+		}
+		d := joinDistinct(done, src, c.left)
+		if dr := joinDistinct(done, src, c.right); dr > d {
+			d = dr
+		}
+		if d > 1 {
+			est /= d
+		}
+	}
+	return est, connected
+}
+
+// joinDistinct the estimated distinct values of a join column, all of the
+// rows of its source if there are no statistics of the column.
+func joinDistinct(done []*joinSource, src *joinSource, in *expr.IdentityNode) float64 {
+	left, right, _ := in.LeftRight()
+	s := src
+	if alias := strings.ToLower(left); alias != src.alias {
+		s = joinSourceOf(done, alias)
+	}
+	if s == nil {
+		return 0
+	}
+	return s.distinct(right)
+}
+
+func joinSourceOf(srcs []*joinSource, alias string) *joinSource {
+	for _, src := range srcs {
+		if src.alias == alias {
+			return src
+		}
+	}
+	return nil
+}
+
+// distinct the estimated distinct values of a field of this source, never
+// more than its rows.
+func (m *joinSource) distinct(field string) float64 {
+	if fs := m.stats.FieldStats(field); fs != nil && float64(fs.Distinct) < m.rows {
+		return float64(fs.Distinct)
+	}
+	return m.rows
+}
+
+// selectivity the estimated fraction of rows of this source passing a where
+// condition, of a column of this source compared to a literal value.
+func (m *joinSource) selectivity(node expr.Node) float64 {
+	bn, ok := node.(*expr.BinaryNode)
+	if !ok {
+		return 1
+	}
+	in, ok := bn.Args[0].(*expr.IdentityNode)
+	lit := bn.Args[1]
+	if !ok {
+		in, ok = bn.Args[1].(*expr.IdentityNode)
+		lit = bn.Args[0]
+	}
+	if !ok {
+		return 1
+	}
+	switch lit.(type) {
+	case *expr.StringNode, *expr.NumberNode, *expr.ValueNode, *expr.ParamNode:
+	default:
+		return 1
+	}
+	left, right, hasLeft := in.LeftRight()
+	if !hasLeft || strings.ToLower(left) != m.alias {
+		return 1
+	}
+	switch bn.Operator.T {
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		if d := m.distinct(right); d > 1 {
+			return 1 / d
+		}
+	case lex.TokenLT, lex.TokenLE, lex.TokenGT, lex.TokenGE:
+		// a range, without histograms assume a third of the rows
+		return 1.0 / 3
+	}
+	return 1
+}
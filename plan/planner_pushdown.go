@@ -0,0 +1,254 @@
+package plan
+
+import (
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+// pushFilters offer the conditions of the where of a source to its
+// connection, which filters its own rows by those it takes.  Returns the
+// conditions left to evaluate on its rows, nil if it took all of them.
+func pushFilters(p *Source, conn schema.ConnFilter) expr.Node {
+	where := p.Stmt.Source.Where.Expr
+	var conds []expr.Node
+	for _, cond := range whereConjuncts(where, nil) {
+		// sub-query predicates are run as semi-joins
+		if !hasSubQuery(cond) {
+			conds = append(conds, cond)
+		}
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	took := conn.Filter(conds)
+	for i, cond := range conds {
+		if i < len(took) && took[i] {
+			p.Filters = append(p.Filters, cond)
+		}
+	}
+	if len(p.Filters) == 0 {
+		return nil
+	}
+	return residualWhere(where, p.Filters)
+}
+
+// residualWhere the conditions of a where which are not one of the given
+// (pushed down) conditions, nil if there are none.
+func residualWhere(where expr.Node, pushed []expr.Node) expr.Node {
+	var rest expr.Node
+	for _, cond := range whereConjuncts(where, nil) {
+		if containsNode(pushed, cond) {
+			continue
+		}
+		if rest == nil {
+			rest = cond
+		} else {
+			rest = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, rest, cond)
+		}
+	}
+	return rest
+}
+
+func containsNode(nodes []expr.Node, node expr.Node) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// neededColumns the columns of a source the statement reads, those of its
+// select list, where, join conditions, group by, having and order by, and
+// the outer references of its sub-queries.  Returns nil if it reads all
+// of them (SELECT *).
+func neededColumns(stmt *rel.SqlSelect, from *rel.SqlSource) []string {
+	var nodes []expr.Node
+	for _, col := range stmt.Columns {
+		if col.Star {
+			return nil
+		}
+		if in, ok := col.Expr.(*expr.IdentityNode); ok {
+			if _, right, _ := expr.LeftRight(in.Text); right == "*" {
+				// SELECT u.*
+				return nil
+			}
+		}
+		nodes = append(nodes, col.Expr, col.Guard)
+	}
+	if stmt.Where != nil {
+		nodes = append(nodes, stmt.Where.Expr)
+	}
+	for _, f := range stmt.From {
+		nodes = append(nodes, f.JoinExpr)
+	}
+	for _, col := range stmt.GroupBy {
+		nodes = append(nodes, col.Expr)
+	}
+	nodes = append(nodes, stmt.Having)
+	for _, col := range stmt.OrderBy {
+		nodes = append(nodes, col.Expr)
+	}
+
+	aliases := sourceAliases(stmt)
+	var idents []*expr.IdentityNode
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		idents = append(idents, expr.FindAllIdentities(node)...)
+		for _, sq := range subQueryNodes(node, nil) {
+			if sub, ok := sq.Stmt.(*rel.SqlSelect); ok {
+				idents = append(idents, allOuterRefs(sub, aliases, sourceAliases(sub))...)
+			}
+		}
+	}
+
+	alias, name := strings.ToLower(from.Alias), strings.ToLower(from.SourceName())
+	cols := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(name string) {
+		name = strings.ToLower(name)
+		if !seen[name] {
+			seen[name] = true
+			cols = append(cols, name)
+		}
+	}
+	for _, in := range idents {
+		// not in.LeftRight(), which would keep its split of the identity
+		// from before the sources rewrite it
+		left, right, hasLeft := expr.LeftRight(in.Text)
+		switch {
+		case !hasLeft:
+			add(in.Text)
+		case strings.ToLower(left) == alias || strings.ToLower(left) == name:
+			add(right)
+		case !aliases[strings.ToLower(left)]:
+			// not one of our sources, a column name containing a period
+			add(in.Text)
+			add(right)
+		}
+	}
+	return expr.FilterSpecialIdentities(cols)
+}
+
+// pushLimit offer the LIMIT (and ORDER BY) of a single source select to its
+// connection, when every row of the source is a row of the result: no where
+// is left to evaluate and there is no group by, distinct or window.  The
+// limit is only pushed with an order by if the connection sorts its rows.
+// Returns whether it does, the rows then need no sort.
+func pushLimit(stmt *rel.SqlSelect, p *Source) bool {
+	conn, ok := p.Conn.(schema.ConnLimit)
+	if !ok || stmt.Limit == 0 || stmt.Distinct || stmt.IsAggQuery() || stmt.Having != nil || hasWindows(stmt) {
+		return false
+	}
+	var orderBy []expr.Node
+	var desc []bool
+	for _, col := range stmt.OrderBy {
+		if col.Expr == nil || len(subQueryNodes(col.Expr, nil)) > 0 {
+			return false
+		}
+		orderBy = append(orderBy, col.Expr)
+		desc = append(desc, !col.Asc())
+	}
+	n := stmt.Limit + stmt.Offset
+	if !conn.LimitRows(n, orderBy, desc) {
+		return false
+	}
+	p.Limit = n
+	p.OrderBy = stmt.OrderBy
+	return len(orderBy) > 0
+}
+
+// pushAggregates offer the group by, and the aggregates of the columns and
+// having, of a single source select to its connection, when every row of the
+// source is in a group: no where is left to evaluate.  Returns the partial
+// group by the connection computes, nil if it doesn't.
+func pushAggregates(stmt *rel.SqlSelect, p *Source) *GroupBy {
+	conn, ok := p.Conn.(schema.ConnAggregate)
+	if !ok || len(stmt.Groupings()) > 1 || hasWindows(stmt) {
+		return nil
+	}
+	groupBy := make([]expr.Node, len(stmt.GroupBy))
+	for i, col := range stmt.GroupBy {
+		if col.Expr == nil || hasSubQuery(col.Expr) {
+			return nil
+		}
+		groupBy[i] = col.Expr
+	}
+	var aggs []*expr.FuncNode
+	add := func(node expr.Node) bool {
+		for _, fn := range expr.FindAggregates(node) {
+			if hasSubQuery(fn) {
+				return false
+			}
+			if !containsAgg(aggs, fn) {
+				aggs = append(aggs, fn)
+			}
+		}
+		return true
+	}
+colLoop:
+	for _, col := range stmt.Columns {
+		if col.Expr == nil {
+			return nil
+		}
+		for _, gb := range stmt.GroupBy {
+			if gb.As == col.As || col.Expr.Equal(gb.Expr) {
+				continue colLoop
+			}
+		}
+		// the engine evaluates an expression over aggregates of their
+		// results only, not of the values of a row of the group
+		if refsOutsideAggs(col.Expr) || !add(col.Expr) {
+			return nil
+		}
+	}
+	if stmt.Having != nil && !add(stmt.Having) {
+		return nil
+	}
+	if len(aggs) == 0 || !conn.Aggregate(groupBy, aggs) {
+		return nil
+	}
+	gb := NewGroupBy(stmt)
+	gb.Partial = true
+	gb.Aggs = aggs
+	p.GroupBy = gb
+	return gb
+}
+
+func containsAgg(aggs []*expr.FuncNode, fn *expr.FuncNode) bool {
+	for _, agg := range aggs {
+		if agg.Equal(fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// refsOutsideAggs does the expression refer to any identities, or
+// sub-queries, other than as arguments of its aggregates.
+func refsOutsideAggs(node expr.Node) bool {
+	switch n := node.(type) {
+	case *expr.FuncNode:
+		if n.F.Aggregate {
+			return false
+		}
+	case *expr.IdentityNode, *expr.SubQueryNode, *expr.WindowNode:
+		return true
+	case *expr.UnaryNode:
+		return refsOutsideAggs(n.Arg)
+	}
+	if na, ok := node.(expr.NodeArgs); ok {
+		for _, arg := range na.ChildrenArgs() {
+			if refsOutsideAggs(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,9 +2,11 @@ package plan
 
 import (
 	"fmt"
+	"strings"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -20,6 +22,9 @@ func needsFinalProjection(s *rel.SqlSelect) bool {
 	if len(s.GroupBy) > 0 {
 		return true
 	}
+	if hasWindows(s) {
+		return true
+	}
 	return false
 }
 
@@ -29,6 +34,32 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 	// u.Debugf("VisitSelect ctx:%p  %+v", p.Ctx, p.Stmt)
 
 	needsFinalProject := true
+	// filtered: rows of the source are removed by a where or semi-join,
+	// sorted: the source sorts its rows for the order by of a limit
+	var filtered, sorted bool
+
+	if err := m.walkCtes(p.Stmt.Ctes); err != nil {
+		return err
+	}
+
+	// Sub-query predicates of the where (x IN (SELECT ...), EXISTS (SELECT ...))
+	// are run as semi-joins after the rest of the where.
+	var subQueries []*subQueryCond
+	var whereRest expr.Node
+	if p.Stmt.Where != nil {
+		whereRest = p.Stmt.Where.Expr
+	}
+	if whereRest != nil && hasSubQuery(whereRest) {
+		var err error
+		subQueries, whereRest, err = subQueryConditions(whereRest)
+		if err != nil {
+			return err
+		}
+	}
+	// Sub-queries used as values, (SELECT count(*) FROM orders) AS ct
+	if err := m.walkScalarSubQueries(p, whereRest); err != nil {
+		return err
+	}
 
 	if len(p.Stmt.From) == 0 {
 
@@ -36,12 +67,19 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 	} else if len(p.Stmt.From) == 1 {
 
+		if p.Stmt.From[0].SubQuery != nil {
+			if err := m.walkSubQuerySource(p.Stmt.From[0]); err != nil {
+				return err
+			}
+		}
+
 		p.Stmt.From[0].Source = p.Stmt // TODO:   move to a Finalize() in query parser/planner
 
 		srcPlan, err := NewSource(m.Ctx, p.Stmt.From[0], true)
 		if err != nil {
 			return err
 		}
+		srcPlan.Needed = neededColumns(p.Stmt, p.Stmt.From[0])
 		p.From = append(p.From, srcPlan)
 		p.Add(srcPlan)
 
@@ -50,17 +88,26 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 			return err
 		}
 
-		if srcPlan.Complete && !needsFinalProjection(p.Stmt) {
+		if srcPlan.Complete && !needsFinalProjection(p.Stmt) && len(subQueries) == 0 {
 			goto finalProjection
 		}
 
 	} else {
 
+		// Inner joins of analyzed tables are reordered, and the build side of
+		// each join picked, by their estimated rows.
+		rows, joined := m.orderJoins(p.Stmt)
+
 		var prevSource *Source
 		var prevTask Task
 
 		for i, from := range p.Stmt.From {
 
+			if from.Alias == "" && from.SubQuery == nil {
+				// Columns of a source without an alias are qualified by its
+				// name, ie a common table expression joined by name
+				from.Alias = from.Name
+			}
 			// Need to rewrite the From statement to ensure all fields necessary to support
 			//  joins, wheres, etc exist but is standalone query
 			from.Rewrite(p.Stmt)
@@ -68,6 +115,7 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 			if err != nil {
 				return nil
 			}
+			srcPlan.Needed = neededColumns(p.Stmt, from)
 			err = m.Planner.WalkSourceSelect(srcPlan)
 			if err != nil {
 				u.Errorf("Could not visitsubselect %v  %s", err, from)
@@ -79,6 +127,9 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 				from.Seekable = true
 				// fold this source into previous
 				curMergeTask := NewJoinMerge(prevTask, srcPlan, prevSource.Stmt, srcPlan.Stmt)
+				if rows != nil {
+					curMergeTask.BuildLeft = joined[i-1] < rows[i]
+				}
 				prevTask = curMergeTask
 			} else {
 				prevTask = srcPlan
@@ -90,23 +141,59 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 	}
 
+	if len(subQueries) > 0 {
+		// The sources have been planned (and have the columns the sub-query
+		// predicates need), only the rest of the where is left to evaluate.
+		p.Stmt.Where.Expr = whereRest
+	}
+
+	filtered = len(subQueries) > 0
 	if p.Stmt.Where != nil {
 		switch {
-		case p.Stmt.Where.Source != nil:
-			// SELECT id from article WHERE id in (select article_id from comments where comment_ct > 50);
-			u.Warnf("Found un-supported subquery: %#v", p.Stmt.Where)
-			return ErrNotImplemented
+		case p.Stmt.Where.Expr != nil && len(p.From) == 1 && len(p.From[0].Filters) > 0:
+			// the source filtered its rows by some of the where
+			where := NewWhere(p.Stmt)
+			if where.Residual = residualWhere(p.Stmt.Where.Expr, p.From[0].Filters); where.Residual != nil {
+				p.Add(where)
+				filtered = true
+			}
 		case p.Stmt.Where.Expr != nil:
 			p.Add(NewWhere(p.Stmt))
+			filtered = true
+		case len(subQueries) > 0:
+			// where was only sub-query predicates
 		default:
 			u.Warnf("Found un-supported where type: %#v", p.Stmt.Where)
 			return fmt.Errorf("Unsupported Where Type")
 		}
 	}
 
+	for _, cond := range subQueries {
+		semi, err := m.walkSemiJoin(p.Stmt, cond)
+		if err != nil {
+			return err
+		}
+		p.Add(semi)
+	}
+
+	// The rows of the source are the rows of the result, it may stop at
+	// the limit (sorted for the order by).
+	if len(p.From) == 1 && !filtered {
+		sorted = pushLimit(p.Stmt, p.From[0])
+	}
+
 	if p.Stmt.IsAggQuery() {
 		//u.Debugf("Adding aggregate/group by? %#v", m.Planner)
-		p.Add(NewGroupBy(p.Stmt))
+		gb := NewGroupBy(p.Stmt)
+		if len(p.From) == 1 && !filtered {
+			// the source may compute partials of the aggregates of each
+			// group, only merged here
+			gb.Merge = pushAggregates(p.Stmt, p.From[0])
+		}
+		if gb.Merge == nil {
+			gb.Stream = m.sortedOnGroupBy(p.Stmt)
+		}
+		p.Add(gb)
 		needsFinalProject = false
 	}
 
@@ -114,7 +201,11 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		p.Add(NewHaving(p.Stmt))
 	}
 
-	if len(p.Stmt.OrderBy) > 0 {
+	if err := m.walkWindows(p); err != nil {
+		return err
+	}
+
+	if len(p.Stmt.OrderBy) > 0 && !sorted {
 		p.Add(NewOrder(p.Stmt))
 	}
 
@@ -125,6 +216,12 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		}
 	}
 
+	if p.Stmt.Distinct {
+		// De-dupe on the final projected values, also applies the limit
+		// and offset as the projection can't know how many rows will survive.
+		p.Add(NewDistinct(p.Stmt))
+	}
+
 finalProjection:
 	if m.Ctx.Projection == nil {
 		proj, err := NewProjectionFinal(m.Ctx, p)
@@ -217,6 +314,9 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 
 	} else {
 
+		if conn, ok := p.Conn.(schema.ConnProjection); ok && p.Needed != nil {
+			conn.Project(p.Needed)
+		}
 		if schemaCols, ok := p.Conn.(schema.ConnColumns); ok {
 			if err := buildColIndex(schemaCols, p); err != nil {
 				return err
@@ -228,7 +328,13 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 		if p.Stmt.Source != nil && p.Stmt.Source.Where != nil {
 			switch {
 			case p.Stmt.Source.Where.Expr != nil:
-				p.Add(NewWhere(p.Stmt.Source))
+				where := NewWhere(p.Stmt.Source)
+				if conn, ok := p.Conn.(schema.ConnFilter); ok {
+					where.Residual = pushFilters(p, conn)
+				}
+				if len(p.Filters) == 0 || where.Residual != nil {
+					p.Add(where)
+				}
 			default:
 				u.Warnf("Found un-supported where type: %#v", p.Stmt.Source)
 				return fmt.Errorf("Unsupported Where clause:  %q", p.Stmt)
@@ -252,6 +358,59 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 	return nil
 }
 
+// sortedOnGroupBy is the single source of the select a sub-query, or
+// common table expression, sorted on the columns of the group by so the
+// group by can emit each group as soon as the next starts.
+//
+//	SELECT user_id, count(*) FROM (SELECT user_id FROM orders ORDER BY user_id) AS o GROUP BY user_id
+func (m *PlannerDefault) sortedOnGroupBy(stmt *rel.SqlSelect) bool {
+	if len(stmt.From) != 1 || len(stmt.GroupBy) == 0 || len(stmt.Groupings()) > 1 {
+		return false
+	}
+	cte, ok := m.Ctx.Cte(stmt.From[0].Name)
+	if !ok || cte.Stmt == nil || cte.Recursive() || len(cte.Stmt.Columns) > 0 {
+		return false
+	}
+	var order rel.Columns
+	switch st := cte.Stmt.Stmt.(type) {
+	case *rel.SqlSelect:
+		order = st.OrderBy
+	case *rel.SqlUnion:
+		order = st.OrderBy
+	}
+	if len(order) < len(stmt.GroupBy) {
+		return false
+	}
+	// the leading order by columns are those of the group by, in any order
+	order = order[:len(stmt.GroupBy)]
+gbLoop:
+	for _, gb := range stmt.GroupBy {
+		for _, col := range order {
+			if sameColumn(gb.Expr, col.Expr) {
+				continue gbLoop
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// sameColumn are the expressions the same, identities the same column
+// whether or not qualified by source.
+func sameColumn(a, b expr.Node) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	ai, aIsIdent := a.(*expr.IdentityNode)
+	bi, bIsIdent := b.(*expr.IdentityNode)
+	if aIsIdent && bIsIdent {
+		_, ar, _ := ai.LeftRight()
+		_, br, _ := bi.LeftRight()
+		return strings.EqualFold(ar, br)
+	}
+	return a.Equal(b)
+}
+
 // WalkProjectionSource non final projection (ie, per from).
 func (m *PlannerDefault) WalkProjectionSource(p *Source) error {
 	// Add a Non-Final Projection to choose the columns for results
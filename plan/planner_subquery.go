@@ -0,0 +1,445 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+)
+
+// subQueryCond a sub-query predicate found in the where clause
+//
+//    x [NOT] IN (SELECT ...)
+//    [NOT] EXISTS (SELECT ...)
+type subQueryCond struct {
+	sub    *rel.SqlSelect
+	exists bool
+	anti   bool
+	arg    expr.Node
+}
+
+// subQueryConditions split the where expression on its top level AND's into
+// the sub-query predicates (which become semi/anti joins) and the rest of
+// the where.  An IN or EXISTS sub-query anywhere else (under OR, in a
+// function) is an error, scalar sub-queries are left in the rest.
+func subQueryConditions(node expr.Node) ([]*subQueryCond, expr.Node, error) {
+	var conds []*subQueryCond
+	var rest expr.Node
+	for _, conj := range whereConjuncts(node, nil) {
+		cond, err := subQueryCondition(conj, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cond != nil {
+			conds = append(conds, cond)
+			continue
+		}
+		if hasSetSubQuery(conj) {
+			return nil, nil, fmt.Errorf("sub-query only supported as [NOT] IN or [NOT] EXISTS condition of where: %s", conj)
+		}
+		if rest == nil {
+			rest = conj
+		} else {
+			rest = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, rest, conj)
+		}
+	}
+	return conds, rest, nil
+}
+
+func whereConjuncts(node expr.Node, conjs []expr.Node) []expr.Node {
+	if bn, ok := node.(*expr.BinaryNode); ok {
+		switch bn.Operator.T {
+		case lex.TokenAnd, lex.TokenLogicAnd:
+			conjs = whereConjuncts(bn.Args[0], conjs)
+			return whereConjuncts(bn.Args[1], conjs)
+		}
+	}
+	return append(conjs, node)
+}
+
+func subQueryCondition(node expr.Node, anti bool) (*subQueryCond, error) {
+	var cond *subQueryCond
+	var sqn *expr.SubQueryNode
+	switch n := node.(type) {
+	case *expr.UnaryNode:
+		switch n.Operator.T {
+		case lex.TokenNegate:
+			return subQueryCondition(n.Arg, !anti)
+		case lex.TokenExists:
+			if sn, ok := n.Arg.(*expr.SubQueryNode); ok {
+				sqn = sn
+				cond = &subQueryCond{exists: true, anti: anti}
+			}
+		}
+	case *expr.BinaryNode:
+		if n.Operator.T == lex.TokenIN {
+			if sn, ok := n.Args[1].(*expr.SubQueryNode); ok {
+				sqn = sn
+				cond = &subQueryCond{arg: n.Args[0], anti: anti}
+			}
+		}
+	}
+	if cond == nil {
+		return nil, nil
+	}
+	sub, ok := sqn.Stmt.(*rel.SqlSelect)
+	if !ok {
+		return nil, fmt.Errorf("unsupported sub-query %T: %s", sqn.Stmt, sqn)
+	}
+	cond.sub = sub
+	if !cond.exists && (len(sub.Columns) != 1 || sub.Columns[0].Star) {
+		return nil, fmt.Errorf("sub-query for IN must return exactly one column: %s", sub)
+	}
+	return cond, nil
+}
+
+func hasSubQuery(node expr.Node) bool {
+	switch n := node.(type) {
+	case *expr.SubQueryNode:
+		return true
+	case *expr.UnaryNode:
+		return hasSubQuery(n.Arg)
+	case expr.NodeArgs:
+		for _, arg := range n.ChildrenArgs() {
+			if hasSubQuery(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSetSubQuery does node have an IN or EXISTS sub-query predicate
+func hasSetSubQuery(node expr.Node) bool {
+	switch n := node.(type) {
+	case *expr.UnaryNode:
+		if _, ok := n.Arg.(*expr.SubQueryNode); ok && n.Operator.T == lex.TokenExists {
+			return true
+		}
+		return hasSetSubQuery(n.Arg)
+	case *expr.BinaryNode:
+		if _, ok := n.Args[1].(*expr.SubQueryNode); ok && n.Operator.T == lex.TokenIN {
+			return true
+		}
+	}
+	if na, ok := node.(expr.NodeArgs); ok {
+		for _, arg := range na.ChildrenArgs() {
+			if hasSetSubQuery(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// subQueryNodes append the sub-queries of node, not descending into them.
+func subQueryNodes(node expr.Node, nodes []*expr.SubQueryNode) []*expr.SubQueryNode {
+	switch n := node.(type) {
+	case *expr.SubQueryNode:
+		return append(nodes, n)
+	case *expr.UnaryNode:
+		return subQueryNodes(n.Arg, nodes)
+	case expr.NodeArgs:
+		for _, arg := range n.ChildrenArgs() {
+			nodes = subQueryNodes(arg, nodes)
+		}
+	}
+	return nodes
+}
+
+// walkScalarSubQueries plan the sub-queries used as values in expressions of
+// the select list, where (other than semi-join predicates), group by, having
+// and order by.  Un-correlated ones are planned here, correlated ones
+// per distinct value of their outer references when run.
+func (m *PlannerDefault) walkScalarSubQueries(p *Select, where expr.Node) error {
+
+	stmt := p.Stmt
+	var nodes []*expr.SubQueryNode
+	for _, col := range stmt.Columns {
+		if col.Expr != nil {
+			nodes = subQueryNodes(col.Expr, nodes)
+		}
+	}
+	if where != nil {
+		nodes = subQueryNodes(where, nodes)
+	}
+	for _, col := range stmt.GroupBy {
+		if col.Expr != nil {
+			nodes = subQueryNodes(col.Expr, nodes)
+		}
+	}
+	if stmt.Having != nil {
+		nodes = subQueryNodes(stmt.Having, nodes)
+	}
+	for _, col := range stmt.OrderBy {
+		if in, ok := col.Expr.(*expr.IdentityNode); ok {
+			// order by is before the final projection, so ORDER BY n of a
+			// sub-query column (SELECT ...) AS n evaluates the sub-query
+			for _, sel := range stmt.Columns {
+				if sel.As == in.Text && sel.Expr != nil && len(subQueryNodes(sel.Expr, nil)) > 0 {
+					col.Expr = sel.Expr
+					break
+				}
+			}
+		}
+		if col.Expr != nil {
+			nodes = subQueryNodes(col.Expr, nodes)
+		}
+	}
+
+	outer := sourceAliases(stmt)
+	seen := make(map[*expr.SubQueryNode]bool, len(nodes))
+	for _, node := range nodes {
+		if seen[node] {
+			continue
+		}
+		seen[node] = true
+		sub, ok := node.Stmt.(*rel.SqlSelect)
+		if !ok {
+			return fmt.Errorf("unsupported sub-query %T: %s", node.Stmt, node)
+		}
+		if len(sub.Columns) != 1 || sub.Columns[0].Star {
+			return fmt.Errorf("scalar sub-query must return exactly one column: %s", sub)
+		}
+		sq := &ScalarSubQuery{Node: node, Stmt: stmt, Sub: sub, Ctx: m.Ctx.SubQueryContext(sub)}
+		sq.Refs = allOuterRefs(sub, outer, sourceAliases(sub))
+		if len(sq.Refs) == 0 {
+			subPlan, err := WalkStmt(sq.Ctx, sub, NewPlanner(sq.Ctx))
+			if err != nil {
+				return err
+			}
+			sq.SubPlan = subPlan
+		}
+		p.SubQueries = append(p.SubQueries, sq)
+	}
+	return nil
+}
+
+// sourceAliases the lower-cased names and aliases a statement's sources
+// may be referred to by.
+func sourceAliases(stmt *rel.SqlSelect) map[string]bool {
+	aliases := make(map[string]bool, len(stmt.From)*2)
+	for _, from := range stmt.From {
+		if from.Name != "" {
+			aliases[strings.ToLower(from.Name)] = true
+		}
+		if from.Alias != "" {
+			aliases[strings.ToLower(from.Alias)] = true
+		}
+	}
+	return aliases
+}
+
+// outerRefs the identities in node that refer to a source of the outer
+// statement but not one of the sub-query's own sources.
+func outerRefs(node expr.Node, outer, inner map[string]bool) []*expr.IdentityNode {
+	var refs []*expr.IdentityNode
+	for _, in := range expr.FindAllIdentities(node) {
+		left, _, hasLeft := in.LeftRight()
+		if !hasLeft {
+			continue
+		}
+		left = strings.ToLower(left)
+		if outer[left] && !inner[left] {
+			refs = append(refs, in)
+		}
+	}
+	return refs
+}
+
+// walkSemiJoin plan the sub-query of a where clause sub-query predicate as a
+// semi join (IN, EXISTS) or anti join (NOT IN, NOT EXISTS).
+//
+// A correlated sub-query (refers to columns of the outer statement) is
+// de-correlated if the outer references only occur in equality conditions
+// of its where clause:
+//
+//    SELECT name FROM users
+//    WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.user_id AND qty > 1)
+//
+//    =>  SELECT orders.user_id FROM orders WHERE qty > 1
+//        hashed on orders.user_id, probed with users.user_id
+//
+// Otherwise it is run per distinct value of its outer references.
+func (m *PlannerDefault) walkSemiJoin(stmt *rel.SqlSelect, cond *subQueryCond) (*SemiJoin, error) {
+
+	sub := cond.sub
+	outer := sourceAliases(stmt)
+	inner := sourceAliases(sub)
+
+	var refs []*expr.IdentityNode
+	if sub.Where != nil {
+		refs = outerRefs(sub.Where.Expr, outer, inner)
+	}
+	// outer references anywhere but the where itself, ie the select list,
+	// a join condition or a nested sub-query, can't be de-correlated
+	otherRefs := len(allOuterRefs(sub, outer, inner)) > len(refs)
+
+	p := NewSemiJoin(nil, stmt, sub)
+	p.Exists = cond.exists
+	p.Anti = cond.anti
+	p.Arg = cond.arg
+
+	switch {
+	case otherRefs:
+		p.Correlated = true
+	case len(refs) > 0:
+		decorrelated, outerKeys := decorrelate(sub, outer, inner, cond.exists)
+		if decorrelated == nil {
+			p.Correlated = true
+			break
+		}
+		sub2, err := rel.ParseSqlSelect(decorrelated.String())
+		if err != nil {
+			return nil, err
+		}
+		p.Sub = sub2
+		p.Outer = outerKeys
+	case cond.exists:
+		// only need to know there is a row
+		sub2, err := rel.ParseSqlSelect(sub.String())
+		if err != nil {
+			return nil, err
+		}
+		if sub2.Limit == 0 && !sub2.IsAggQuery() {
+			sub2.Limit = 1
+		}
+		p.Sub = sub2
+	}
+
+	if p.Correlated {
+		p.Refs = allOuterRefs(sub, outer, inner)
+		p.Ctx = m.Ctx.SubQueryContext(sub)
+		return p, nil
+	}
+
+	p.Ctx = m.Ctx.SubQueryContext(p.Sub)
+	subPlan, err := WalkStmt(p.Ctx, p.Sub, NewPlanner(p.Ctx))
+	if err != nil {
+		return nil, err
+	}
+	p.SubPlan = subPlan
+	return p, nil
+}
+
+// allOuterRefs all outer references of a correlated sub-query, of its
+// select list, where, join conditions, group by, having and order by, and
+// of the sub-queries nested in it not referring to a source of their own.
+func allOuterRefs(sub *rel.SqlSelect, outer, inner map[string]bool) []*expr.IdentityNode {
+	var refs []*expr.IdentityNode
+	var nested []*rel.SqlSelect
+	for _, node := range stmtExprs(sub) {
+		refs = append(refs, outerRefs(node, outer, inner)...)
+		for _, sq := range subQueryNodes(node, nil) {
+			if sel, ok := sq.Stmt.(*rel.SqlSelect); ok {
+				nested = append(nested, sel)
+			}
+		}
+	}
+	for _, from := range sub.From {
+		if from.SubQuery != nil {
+			nested = append(nested, from.SubQuery)
+		}
+	}
+	if sub.Where != nil && sub.Where.Source != nil {
+		nested = append(nested, sub.Where.Source)
+	}
+	for _, sel := range nested {
+		// its own sources hide those of the same name outside it
+		scope := sourceAliases(sel)
+		for alias := range inner {
+			scope[alias] = true
+		}
+		refs = append(refs, allOuterRefs(sel, outer, scope)...)
+	}
+	return refs
+}
+
+// stmtExprs the expressions of the clauses of a statement, not of the
+// sub-queries of its sources.
+func stmtExprs(stmt *rel.SqlSelect) []expr.Node {
+	var nodes []expr.Node
+	for _, cols := range []rel.Columns{stmt.Columns, stmt.GroupBy, stmt.OrderBy} {
+		for _, col := range cols {
+			if col.Expr != nil {
+				nodes = append(nodes, col.Expr)
+			}
+		}
+	}
+	if stmt.Where != nil && stmt.Where.Expr != nil {
+		nodes = append(nodes, stmt.Where.Expr)
+	}
+	for _, from := range stmt.From {
+		if from.JoinExpr != nil {
+			nodes = append(nodes, from.JoinExpr)
+		}
+	}
+	if stmt.Having != nil {
+		nodes = append(nodes, stmt.Having)
+	}
+	return nodes
+}
+
+// decorrelate rewrite a sub-query whose outer references are only used in
+// equality conditions (inner = outer) of its where into an un-correlated
+// one that projects the inner side of those conditions.  Returns nil
+// if it can't be de-correlated.
+func decorrelate(sub *rel.SqlSelect, outer, inner map[string]bool, exists bool) (*rel.SqlSelect, []expr.Node) {
+
+	if len(sub.GroupBy) > 0 || sub.Having != nil || sub.IsAggQuery() || sub.Limit > 0 {
+		return nil, nil
+	}
+
+	var outerKeys []expr.Node
+	var innerKeys []expr.Node
+	var rest expr.Node
+	for _, conj := range whereConjuncts(sub.Where.Expr, nil) {
+		if len(outerRefs(conj, outer, inner)) == 0 {
+			if rest == nil {
+				rest = conj
+			} else {
+				rest = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, rest, conj)
+			}
+			continue
+		}
+		bn, ok := conj.(*expr.BinaryNode)
+		if !ok || (bn.Operator.T != lex.TokenEqual && bn.Operator.T != lex.TokenEqualEqual) {
+			return nil, nil
+		}
+		switch {
+		case onlyOuterRefs(bn.Args[0], outer, inner) && len(outerRefs(bn.Args[1], outer, inner)) == 0:
+			outerKeys = append(outerKeys, bn.Args[0])
+			innerKeys = append(innerKeys, bn.Args[1])
+		case onlyOuterRefs(bn.Args[1], outer, inner) && len(outerRefs(bn.Args[0], outer, inner)) == 0:
+			outerKeys = append(outerKeys, bn.Args[1])
+			innerKeys = append(innerKeys, bn.Args[0])
+		default:
+			return nil, nil
+		}
+	}
+
+	cp := *sub
+	cp.Columns = make(rel.Columns, 0, len(innerKeys)+1)
+	if !exists {
+		cp.Columns = append(cp.Columns, sub.Columns[0])
+	}
+	for _, key := range innerKeys {
+		cp.Columns = append(cp.Columns, &rel.Column{Expr: key, As: key.String()})
+	}
+	cp.Where = nil
+	if rest != nil {
+		cp.Where = &rel.SqlWhere{Expr: rest}
+	}
+	return &cp, outerKeys
+}
+
+// onlyOuterRefs does node refer to outer statement columns and nothing else.
+func onlyOuterRefs(node expr.Node, outer, inner map[string]bool) bool {
+	idents := expr.FindAllIdentities(node)
+	if len(idents) == 0 {
+		return false
+	}
+	return len(outerRefs(node, outer, inner)) == len(idents)
+}
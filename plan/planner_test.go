@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
 )
 
 type plantest struct {
@@ -37,3 +39,93 @@ func TestPlans(t *testing.T) {
 
 	}
 }
+
+func TestPlanJoinOrder(t *testing.T) {
+	users, err := td.MockSchema.Table("users")
+	assert.Equal(t, nil, err)
+	orders, err := td.MockSchema.Table("orders")
+	assert.Equal(t, nil, err)
+
+	// no statistics, FROM order
+	sql := `SELECT o.order_id, o2.order_id, u.email
+		FROM orders AS o
+		INNER JOIN orders AS o2 ON o.item_id = o2.item_id
+		INNER JOIN users AS u ON u.user_id = o.user_id
+		WHERE u.email = "bob@bob.com"`
+	p := selectPlan(t, td.TestContext(sql))
+	assert.Equal(t, "o,o2,u", joinAliases(p))
+
+	users.SetStats(&schema.TableStats{Rows: 1000, Fields: map[string]*schema.FieldStats{
+		"user_id": {Distinct: 1000},
+		"email":   {Distinct: 1000},
+	}})
+	orders.SetStats(&schema.TableStats{Rows: 100000, Fields: map[string]*schema.FieldStats{
+		"user_id": {Distinct: 1000},
+		"item_id": {Distinct: 50},
+	}})
+	defer func() {
+		users.SetStats(nil)
+		orders.SetStats(nil)
+	}()
+
+	// the one user's orders are joined first, then the orders of the same items
+	p = selectPlan(t, td.TestContext(sql))
+	assert.Equal(t, "o,u,o2", joinAliases(p))
+	assert.Equal(t, "u.user_id = o.user_id", p.Stmt.From[1].JoinExpr.String())
+	assert.Equal(t, "o.item_id = o2.item_id", p.Stmt.From[2].JoinExpr.String())
+	outer := p.Children()[0].(*plan.JoinMerge)
+	inner := outer.Left.(*plan.JoinMerge)
+	// the user is hashed, then the ~100 orders of the user
+	assert.Equal(t, false, inner.BuildLeft)
+	assert.Equal(t, true, outer.BuildLeft)
+
+	// outer joins are not reordered
+	p = selectPlan(t, td.TestContext(`SELECT o.order_id, u.email
+		FROM orders AS o
+		LEFT JOIN users AS u ON u.user_id = o.user_id`))
+	assert.Equal(t, "o,u", joinAliases(p))
+	assert.Equal(t, false, p.Children()[0].(*plan.JoinMerge).BuildLeft)
+
+	// a smaller left side is hashed
+	p = selectPlan(t, td.TestContext(`SELECT o.order_id, u.email
+		FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id`))
+	assert.Equal(t, "u,o", joinAliases(p))
+	assert.Equal(t, true, p.Children()[0].(*plan.JoinMerge).BuildLeft)
+}
+
+func TestPlanNeededColumns(t *testing.T) {
+	p := selectPlan(t, td.TestContext(`SELECT user_id FROM users WHERE email != "x" ORDER BY reg_date`))
+	assert.Equal(t, []string{"user_id", "email", "reg_date"}, p.From[0].Needed)
+
+	p = selectPlan(t, td.TestContext(`SELECT * FROM users`))
+	assert.Equal(t, []string(nil), p.From[0].Needed)
+
+	p = selectPlan(t, td.TestContext(`SELECT count(*) FROM users`))
+	assert.Equal(t, []string{}, p.From[0].Needed)
+
+	// outer references of a correlated sub-query
+	p = selectPlan(t, td.TestContext(`SELECT email FROM users
+		WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.user_id)`))
+	assert.Equal(t, []string{"email", "user_id"}, p.From[0].Needed)
+
+	p = selectPlan(t, td.TestContext(`SELECT u.email, sum(o.price)
+		FROM orders AS o
+		INNER JOIN users AS u ON u.user_id = o.user_id
+		WHERE o.item_count > 1
+		GROUP BY u.email`))
+	join := p.Children()[0].(*plan.JoinMerge)
+	assert.Equal(t, []string{"price", "item_count", "user_id"}, join.Left.(*plan.Source).Needed)
+	assert.Equal(t, []string{"email", "user_id"}, join.Right.(*plan.Source).Needed)
+}
+
+func joinAliases(p *plan.Select) string {
+	aliases := ""
+	for i, from := range p.Stmt.From {
+		if i > 0 {
+			aliases += ","
+		}
+		aliases += from.Alias
+	}
+	return aliases
+}
@@ -0,0 +1,188 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+// unionCol describes one result column of one side of a set operation.
+// The type is only trusted (known) if it came from a schema field or a literal,
+// function and expression columns are typed as strings by the projection.
+type unionCol struct {
+	name  string
+	vt    value.ValueType
+	known bool
+}
+
+// WalkUnion walk a set operation (UNION, INTERSECT, EXCEPT) by planning each
+// side as its own statement and then ensuring they are union compatible,
+// ie same number of columns, and types that can be compared.
+func (m *PlannerDefault) WalkUnion(p *Union) error {
+
+	if err := m.walkCtes(p.Stmt.Ctes); err != nil {
+		return err
+	}
+	// the order by, limit written after the last select are of the whole
+	// result, of any other they would be ignored
+	sels := p.Stmt.Selects()
+	for _, sel := range sels[:len(sels)-1] {
+		if len(sel.OrderBy) > 0 || sel.Limit > 0 || sel.Offset > 0 {
+			return fmt.Errorf("%s: ORDER BY, LIMIT are only allowed after the last select of %s",
+				strings.ToUpper(p.Stmt.Op.String()), sel)
+		}
+	}
+	left, leftCols, err := m.walkUnionArm(p.Stmt.Left)
+	if err != nil {
+		return err
+	}
+	right, rightCols, err := m.walkUnionArm(p.Stmt.Right)
+	if err != nil {
+		return err
+	}
+	p.Left, p.Right = left, right
+
+	opName := strings.ToUpper(p.Stmt.Op.String())
+	if leftCols != nil && rightCols != nil {
+		if len(leftCols) != len(rightCols) {
+			return fmt.Errorf("%s requires the same number of columns on each side but got %d and %d",
+				opName, len(leftCols), len(rightCols))
+		}
+		for i, lc := range leftCols {
+			rc := rightCols[i]
+			if !lc.known || !rc.known {
+				continue
+			}
+			if unionTypeCategory(lc.vt) != unionTypeCategory(rc.vt) {
+				return fmt.Errorf("%s column %d type mismatch: %q is %s but %q is %s",
+					opName, i+1, lc.name, lc.vt, rc.name, rc.vt)
+			}
+		}
+	}
+	if leftCols == nil {
+		// We can't see into the left side projection (ie select * on a join)
+		// so the best we can do is use the written column names.
+		for _, col := range p.Stmt.Columns() {
+			if col.Star {
+				return fmt.Errorf("%s could not determine the columns of %s", opName, p.Stmt.Left)
+			}
+			leftCols = append(leftCols, &unionCol{name: col.As, vt: value.StringType})
+		}
+	}
+
+	// The result columns are named by the left side, their types are
+	// taken from whichever side we are certain of.
+	p.Proj = rel.NewProjection()
+	p.ColIndex = make(map[string]int, len(leftCols))
+	p.Sel = rel.NewSqlSelect()
+	p.known = make([]bool, len(leftCols))
+	for i, lc := range leftCols {
+		vt := lc.vt
+		p.known[i] = lc.known
+		if !lc.known && rightCols != nil && rightCols[i].known {
+			vt = rightCols[i].vt
+			p.known[i] = true
+		}
+		p.Proj.AddColumnShort(lc.name, vt)
+		p.ColIndex[lc.name] = i
+		col := rel.NewColumn(lc.name)
+		col.Index = i
+		col.ParentIndex = i
+		col.SourceIndex = i
+		p.Sel.Columns = append(p.Sel.Columns, col)
+	}
+	p.Sel.OrderBy = p.Stmt.OrderBy
+	p.Sel.Limit = p.Stmt.Limit
+	p.Sel.Offset = p.Stmt.Offset
+
+	if len(p.Sel.OrderBy) > 0 {
+		p.Add(NewOrder(p.Sel))
+	}
+	if p.Sel.Limit > 0 || p.Sel.Offset > 0 {
+		proj := NewProjectionStatic(p.Proj)
+		proj.Stmt = p.Sel
+		proj.Final = true
+		p.Add(proj)
+	}
+
+	m.Ctx.Projection = NewProjectionStatic(p.Proj)
+	return nil
+}
+
+// walkUnionArm plan one side of a set operation, each side shares the
+// context but has its own projection.
+func (m *PlannerDefault) walkUnionArm(stmt rel.SqlStatement) (Task, []*unionCol, error) {
+
+	m.Ctx.Projection = nil
+	task, err := WalkStmt(m.Ctx, stmt, m.Planner)
+	if err != nil {
+		return nil, nil, err
+	}
+	proj := m.Ctx.Projection
+	m.Ctx.Projection = nil
+
+	switch st := task.(type) {
+	case *Union:
+		cols := make([]*unionCol, len(st.Proj.Columns))
+		for i, rc := range st.Proj.Columns {
+			cols[i] = &unionCol{name: rc.As, vt: rc.Type, known: st.known[i]}
+		}
+		return task, cols, nil
+	case *Select:
+		if proj == nil || proj.Proj == nil {
+			return task, nil, nil
+		}
+		return task, selectUnionColumns(st.Stmt, proj.Proj), nil
+	}
+	return nil, nil, fmt.Errorf("unsupported set operation statement %T", task)
+}
+
+func selectUnionColumns(sel *rel.SqlSelect, proj *rel.Projection) []*unionCol {
+
+	// Match the projection columns back to select columns so we know
+	// which were simple field references or literals.
+	var selCols rel.Columns
+	starOnly := len(sel.Columns) == 1 && sel.Columns[0].Star
+	for _, col := range sel.Columns {
+		if col.Star && !starOnly {
+			selCols = nil
+			break
+		}
+		if col.InFinalProjection() {
+			selCols = append(selCols, col)
+		}
+	}
+	cols := make([]*unionCol, len(proj.Columns))
+	for i, rc := range proj.Columns {
+		uc := &unionCol{name: rc.As, vt: rc.Type}
+		if uc.name == "" {
+			uc.name = rc.Name
+		}
+		switch {
+		case starOnly:
+			uc.known = true
+		case len(selCols) == len(proj.Columns):
+			switch selCols[i].Expr.(type) {
+			case *expr.IdentityNode, *expr.NumberNode, *expr.StringNode:
+				uc.known = true
+			}
+		}
+		cols[i] = uc
+	}
+	return cols
+}
+
+// unionTypeCategory groups value types into those that can be compared
+// to each other in set operations.
+func unionTypeCategory(vt value.ValueType) value.ValueType {
+	switch {
+	case vt.IsNumeric():
+		return value.NumberType
+	case vt == value.StringType, vt == value.ByteSliceType:
+		return value.StringType
+	}
+	return vt
+}
@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+)
+
+// Nodes the distinct window functions of the select columns and order by
+// of the statement, each is computed once no matter how often it is used.
+func (m *Window) Nodes() []*expr.WindowNode {
+	return windowNodes(m.Stmt)
+}
+
+func windowNodes(stmt *rel.SqlSelect) []*expr.WindowNode {
+	var nodes []*expr.WindowNode
+	for _, col := range stmt.Columns {
+		if col.Expr != nil {
+			nodes = findWindows(col.Expr, nodes)
+		}
+	}
+	for _, col := range stmt.OrderBy {
+		if col.Expr != nil {
+			nodes = findWindows(col.Expr, nodes)
+		}
+	}
+	seen := make(map[string]bool, len(nodes))
+	distinct := nodes[:0]
+	for _, n := range nodes {
+		if key := n.String(); !seen[key] {
+			seen[key] = true
+			distinct = append(distinct, n)
+		}
+	}
+	return distinct
+}
+
+func findWindows(node expr.Node, nodes []*expr.WindowNode) []*expr.WindowNode {
+	switch n := node.(type) {
+	case *expr.WindowNode:
+		return append(nodes, n)
+	case *expr.SubQueryNode:
+		// windows of a sub-query are its own
+		return nodes
+	case *expr.UnaryNode:
+		return findWindows(n.Arg, nodes)
+	case expr.NodeArgs:
+		for _, arg := range n.ChildrenArgs() {
+			nodes = findWindows(arg, nodes)
+		}
+	}
+	return nodes
+}
+
+// hasWindows does the statement use window functions, fn() OVER (...)
+func hasWindows(stmt *rel.SqlSelect) bool {
+	return len(windowNodes(stmt)) > 0
+}
+
+// walkWindows plan the window functions of a select, they are computed after
+// the where, group by and having so the order by and final projection may use
+// them.  Of an aggregate query the window is over the grouped rows, so any
+// aggregate it uses must be a column of the select which it is re-written to.
+//
+//	SELECT user_id, sum(price) AS total, rank() OVER (ORDER BY sum(price) DESC)
+//	FROM orders GROUP BY user_id
+func (m *PlannerDefault) walkWindows(p *Select) error {
+
+	stmt := p.Stmt
+	nodes := windowNodes(stmt)
+	if len(nodes) == 0 {
+		return nil
+	}
+	for _, n := range nodes {
+		for _, arg := range n.ChildrenArgs() {
+			if len(findWindows(arg, nil)) > 0 {
+				return fmt.Errorf("window functions may not be nested: %s", n)
+			}
+		}
+		if n.Func.Filter != nil {
+			return fmt.Errorf("FILTER is not supported on window functions: %s", n)
+		}
+		if !stmt.IsAggQuery() {
+			continue
+		}
+		var err error
+		for _, args := range [][]expr.Node{n.Func.Args, n.Partition, n.OrderBy} {
+			for i, arg := range args {
+				if args[i], err = replaceAggs(stmt, arg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// order by is before the final projection, so ORDER BY rn of a
+	// window column row_number() OVER (...) AS rn reads the window
+	for _, col := range stmt.OrderBy {
+		if in, ok := col.Expr.(*expr.IdentityNode); ok {
+			for _, sel := range stmt.Columns {
+				if sel.As == in.Text && sel.Expr != nil && len(findWindows(sel.Expr, nil)) > 0 {
+					col.Expr = sel.Expr
+					break
+				}
+			}
+		}
+	}
+
+	p.Add(NewWindow(stmt))
+	return nil
+}
+
+// replaceAggs re-write the aggregates of an expression evaluated against the
+// grouped rows of an aggregate query to the select column holding them.
+func replaceAggs(stmt *rel.SqlSelect, node expr.Node) (expr.Node, error) {
+	var err error
+	switch n := node.(type) {
+	case *expr.FuncNode:
+		if !n.F.Aggregate {
+			break
+		}
+		for _, col := range stmt.Columns {
+			if col.Expr != nil && col.Expr.Equal(n) {
+				return expr.NewIdentityNodeVal(col.Key()), nil
+			}
+		}
+		return nil, fmt.Errorf("aggregate %s used in a window function must also be a column of the select", n)
+	case *expr.UnaryNode:
+		n.Arg, err = replaceAggs(stmt, n.Arg)
+		return n, err
+	case *expr.CaseNode:
+		if n.Operand != nil {
+			if n.Operand, err = replaceAggs(stmt, n.Operand); err != nil {
+				return nil, err
+			}
+		}
+		for i := range n.Whens {
+			if n.Whens[i], err = replaceAggs(stmt, n.Whens[i]); err != nil {
+				return nil, err
+			}
+			if n.Thens[i], err = replaceAggs(stmt, n.Thens[i]); err != nil {
+				return nil, err
+			}
+		}
+		if n.Else != nil {
+			n.Else, err = replaceAggs(stmt, n.Else)
+		}
+		return n, err
+	case *expr.SubQueryNode:
+		return n, nil
+	}
+	if na, ok := node.(expr.NodeArgs); ok {
+		args := na.ChildrenArgs()
+		for i, arg := range args {
+			if args[i], err = replaceAggs(stmt, arg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return node, nil
+}
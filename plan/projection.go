@@ -96,7 +96,7 @@ func (m *Projection) loadFinal(ctx *Context, isFinal bool) error {
 	for _, from := range m.Stmt.From {
 
 		fromName := strings.ToLower(from.SourceName())
-		tbl, err := ctx.Schema.Table(fromName)
+		tbl, err := ctx.table(fromName)
 		if err != nil {
 			u.Errorf("could not get table: %v", err)
 			return err
@@ -206,7 +206,7 @@ func projectionForSourcePlan(plan *Source) error {
 					} else {
 						plan.Proj.AddColumnShort(col.As, value.NumberType)
 					}
-				case *expr.FuncNode, *expr.BinaryNode:
+				case *expr.FuncNode, *expr.BinaryNode, *expr.SubQueryNode, *expr.CaseNode, *expr.WindowNode:
 					// Probably not string?
 					plan.Proj.AddColumnShort(col.As, value.StringType)
 				default:
@@ -0,0 +1,234 @@
+package rel
+
+import (
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
+)
+
+// Params the parameters (positional ? or numbered $1) of a statement, a
+// numbered parameter may appear more than once.
+func Params(stmt SqlStatement) []*expr.ParamNode {
+	var params []*expr.ParamNode
+	walkStmtParams(stmt, func(p *expr.ParamNode) {
+		params = append(params, p)
+	})
+	return params
+}
+
+// NumParams the number of arguments a statement needs to be executed,
+// the highest index of its parameters.
+func NumParams(stmt SqlStatement) int {
+	n := 0
+	for _, p := range Params(stmt) {
+		if p.Index > n {
+			n = p.Index
+		}
+	}
+	return n
+}
+
+// BindParams bind the arguments to a copy of a statement, argument i is
+// the value of parameter $i+1.  The parsed statement is never changed, as
+// it may be bound and planned once per execution and planning changes the
+// statement it plans, ie removes the sub-queries of its where.
+func BindParams(stmt SqlStatement, args []value.Value) (SqlStatement, error) {
+	if n := NumParams(stmt); n != len(args) {
+		return nil, fmt.Errorf("statement has %d parameters but %d arguments were given", n, len(args))
+	}
+	stmt = copyStmt(stmt)
+	for _, p := range Params(stmt) {
+		p.Bind(args[p.Index-1])
+	}
+	return stmt, nil
+}
+
+// copyStmt a copy of a statement, and of its expressions.
+func copyStmt(stmt SqlStatement) SqlStatement {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return st.Copy()
+	case *SqlUnion:
+		return SqlUnionFromPb(st.ToPB())
+	case *SqlInsert:
+		cp := *st
+		cp.Rows = copyRows(st.Rows)
+		if st.Select != nil {
+			cp.Select = st.Select.Copy()
+		}
+		return &cp
+	case *SqlUpsert:
+		cp := *st
+		cp.Rows = copyRows(st.Rows)
+		cp.Values = copyValues(st.Values)
+		cp.Where = copyWhere(st.Where)
+		return &cp
+	case *SqlUpdate:
+		cp := *st
+		cp.Values = copyValues(st.Values)
+		cp.Where = copyWhere(st.Where)
+		return &cp
+	case *SqlDelete:
+		cp := *st
+		cp.Where = copyWhere(st.Where)
+		return &cp
+	case *PreparedStatement:
+		cp := *st
+		cp.Statement = copyStmt(st.Statement)
+		return &cp
+	}
+	return stmt
+}
+
+func copyRows(rows [][]*ValueColumn) [][]*ValueColumn {
+	if rows == nil {
+		return nil
+	}
+	cp := make([][]*ValueColumn, len(rows))
+	for i, row := range rows {
+		cp[i] = make([]*ValueColumn, len(row))
+		for j, vc := range row {
+			cp[i][j] = copyValueColumn(vc)
+		}
+	}
+	return cp
+}
+
+func copyValues(vals map[string]*ValueColumn) map[string]*ValueColumn {
+	if vals == nil {
+		return nil
+	}
+	cp := make(map[string]*ValueColumn, len(vals))
+	for k, vc := range vals {
+		cp[k] = copyValueColumn(vc)
+	}
+	return cp
+}
+
+func copyValueColumn(vc *ValueColumn) *ValueColumn {
+	if vc == nil {
+		return nil
+	}
+	return &ValueColumn{Value: vc.Value, Expr: copyNode(vc.Expr)}
+}
+
+func copyWhere(where *SqlWhere) *SqlWhere {
+	if where == nil {
+		return nil
+	}
+	cp := *where
+	cp.Expr = copyNode(where.Expr)
+	if where.Source != nil {
+		cp.Source = where.Source.Copy()
+	}
+	return &cp
+}
+
+// copyNode a copy of an expression, through its protobuf as
+// SqlSelect.Copy() does.
+func copyNode(n expr.Node) expr.Node {
+	if n == nil {
+		return nil
+	}
+	return expr.NodeFromNodePb(n.NodePb())
+}
+
+func walkStmtParams(stmt SqlStatement, fn func(p *expr.ParamNode)) {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		walkSelectParams(st, fn)
+	case *SqlUnion:
+		walkCteParams(st.Ctes, fn)
+		walkStmtParams(st.Left, fn)
+		walkStmtParams(st.Right, fn)
+		walkColumnParams(st.OrderBy, fn)
+	case *SqlInsert:
+		walkRowParams(st.Rows, fn)
+		if st.Select != nil {
+			walkSelectParams(st.Select, fn)
+		}
+	case *SqlUpsert:
+		walkRowParams(st.Rows, fn)
+		walkValueParams(st.Values, fn)
+		walkWhereParams(st.Where, fn)
+	case *SqlUpdate:
+		walkValueParams(st.Values, fn)
+		walkWhereParams(st.Where, fn)
+	case *SqlDelete:
+		walkWhereParams(st.Where, fn)
+	case *PreparedStatement:
+		walkStmtParams(st.Statement, fn)
+	}
+}
+
+func walkSelectParams(sel *SqlSelect, fn func(p *expr.ParamNode)) {
+	if sel == nil {
+		return
+	}
+	walkCteParams(sel.Ctes, fn)
+	walkColumnParams(sel.Columns, fn)
+	for _, from := range sel.From {
+		walkNodeParams(from.JoinExpr, fn)
+		walkSelectParams(from.SubQuery, fn)
+	}
+	walkWhereParams(sel.Where, fn)
+	walkColumnParams(sel.GroupBy, fn)
+	walkNodeParams(sel.Having, fn)
+	walkColumnParams(sel.OrderBy, fn)
+}
+
+func walkCteParams(ctes []*SqlCte, fn func(p *expr.ParamNode)) {
+	for _, cte := range ctes {
+		walkStmtParams(cte.Stmt, fn)
+	}
+}
+
+func walkWhereParams(where *SqlWhere, fn func(p *expr.ParamNode)) {
+	if where == nil {
+		return
+	}
+	walkNodeParams(where.Expr, fn)
+	walkSelectParams(where.Source, fn)
+}
+
+func walkColumnParams(cols Columns, fn func(p *expr.ParamNode)) {
+	for _, col := range cols {
+		walkNodeParams(col.Expr, fn)
+		walkNodeParams(col.Guard, fn)
+	}
+}
+
+func walkRowParams(rows [][]*ValueColumn, fn func(p *expr.ParamNode)) {
+	for _, row := range rows {
+		for _, vc := range row {
+			walkNodeParams(vc.Expr, fn)
+		}
+	}
+}
+
+func walkValueParams(vals map[string]*ValueColumn, fn func(p *expr.ParamNode)) {
+	for _, vc := range vals {
+		walkNodeParams(vc.Expr, fn)
+	}
+}
+
+func walkNodeParams(n expr.Node, fn func(p *expr.ParamNode)) {
+	switch nt := n.(type) {
+	case nil:
+		return
+	case *expr.ParamNode:
+		fn(nt)
+	case *expr.SubQueryNode:
+		if st, ok := nt.Stmt.(SqlStatement); ok {
+			walkStmtParams(st, fn)
+		}
+	case *expr.FuncNode:
+		walkNodeParams(nt.Filter, fn)
+	}
+	if na, ok := n.(expr.NodeArgs); ok {
+		for _, arg := range na.ChildrenArgs() {
+			walkNodeParams(arg, fn)
+		}
+	}
+}
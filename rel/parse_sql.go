@@ -104,7 +104,9 @@ func (m *Sqlbridge) parse() (SqlStatement, error) {
 	case lex.TokenPrepare:
 		return m.parsePrepare()
 	case lex.TokenSelect:
-		return m.parseSqlSelect()
+		return m.parseSqlSelectOrUnion()
+	case lex.TokenWith:
+		return m.parseSqlWith()
 	case lex.TokenInsert, lex.TokenReplace:
 		return m.parseSqlInsert()
 	case lex.TokenUpdate:
@@ -125,6 +127,8 @@ func (m *Sqlbridge) parse() (SqlStatement, error) {
 		return m.parseCreate()
 	case lex.TokenDrop:
 		return m.parseDrop()
+	case lex.TokenAnalyze:
+		return m.parseAnalyze()
 	}
 	return nil, fmt.Errorf("Unrecognized request type: %v", m.l.PeekWord())
 }
@@ -166,6 +170,169 @@ func discardComments(m expr.TokenPager) {
 	}
 }
 
+// First keyword was SELECT, parse the select and any set operations
+// (UNION, INTERSECT, EXCEPT) that follow it.  INTERSECT binds tighter
+// than UNION and EXCEPT, otherwise they are evaluated left to right.
+// The ORDER BY, LIMIT, OFFSET of the last select apply to the whole result.
+func (m *Sqlbridge) parseSqlSelectOrUnion() (SqlStatement, error) {
+
+	raw := m.l.RawInput()
+	sel, err := m.parseSqlSelect()
+	if err != nil {
+		return nil, err
+	}
+
+	arms := []SqlStatement{sel}
+	ops := make([]*SqlUnion, 0)
+	for isSetOperation(m.Cur().T) {
+		op := NewSqlUnion(m.Cur().T, nil, nil)
+		m.Next()
+		switch m.Cur().T {
+		case lex.TokenAll:
+			op.All = true
+			m.Next()
+		case lex.TokenDistinct:
+			m.Next()
+		}
+		if m.Cur().T != lex.TokenSelect {
+			return nil, m.ErrMsg(fmt.Sprintf("expected SELECT after %s", op.Op))
+		}
+		sel, err = m.parseSqlSelect()
+		if err != nil {
+			return nil, err
+		}
+		arms = append(arms, sel)
+		ops = append(ops, op)
+	}
+
+	if len(ops) == 0 {
+		return sel, nil
+	}
+
+	// INTERSECT has higher precedence than UNION, EXCEPT
+	for i := 0; i < len(ops); {
+		if ops[i].Op != lex.TokenIntersect {
+			i++
+			continue
+		}
+		ops[i].Left, ops[i].Right = arms[i], arms[i+1]
+		arms = append(arms[:i], append([]SqlStatement{ops[i]}, arms[i+2:]...)...)
+		ops = append(ops[:i], ops[i+1:]...)
+	}
+	var stmt SqlStatement = arms[0]
+	for i, op := range ops {
+		op.Left, op.Right = stmt, arms[i+1]
+		stmt = op
+	}
+	root := stmt.(*SqlUnion)
+	root.Raw = raw
+
+	// the trailing order by/limit belong to the combined result not last select
+	root.OrderBy, sel.OrderBy = sel.OrderBy, nil
+	root.Limit, sel.Limit = sel.Limit, 0
+	root.Offset, sel.Offset = sel.Offset, 0
+	for _, s := range root.Selects() {
+		s.Raw = s.String()
+	}
+	return root, nil
+}
+
+// parseSqlWith parse the common table expressions of a WITH clause and the
+// select (or set operation) statement that uses them.
+//
+//	WITH [RECURSIVE] name [(col, ...)] AS (SELECT ...) [, name AS (...)] SELECT ...
+func (m *Sqlbridge) parseSqlWith() (SqlStatement, error) {
+
+	m.Next() // Consume WITH
+	recursive := false
+	if m.Cur().T == lex.TokenRecursive {
+		recursive = true
+		m.Next()
+	}
+
+	ctes := make([]*SqlCte, 0)
+	for {
+		if m.Cur().T != lex.TokenIdentity {
+			return nil, m.ErrMsg("expected name for common table expression")
+		}
+		cte := &SqlCte{Name: m.Cur().V, Recursive: recursive}
+		for _, prev := range ctes {
+			if strings.ToLower(prev.Name) == strings.ToLower(cte.Name) {
+				return nil, m.ErrMsg(fmt.Sprintf("duplicate common table expression name %q", cte.Name))
+			}
+		}
+		m.Next()
+
+		if m.Cur().T == lex.TokenLeftParenthesis {
+			m.Next()
+			for m.Cur().T != lex.TokenRightParenthesis {
+				switch m.Cur().T {
+				case lex.TokenIdentity:
+					cte.Columns = append(cte.Columns, m.Cur().V)
+				case lex.TokenComma:
+				default:
+					return nil, m.ErrMsg("expected column names for common table expression")
+				}
+				m.Next()
+			}
+			m.Next() // discard right paren
+		}
+
+		if m.Cur().T != lex.TokenAs {
+			return nil, m.ErrMsg("expected AS for common table expression")
+		}
+		m.Next()
+		if m.Cur().T != lex.TokenLeftParenthesis {
+			return nil, m.ErrMsg("expected left paren ( for common table expression")
+		}
+		m.Next()
+		stmt, err := m.parseSqlSelectOrUnion()
+		if err != nil {
+			return nil, err
+		}
+		switch st := stmt.(type) {
+		case *SqlSelect:
+			st.Raw = st.String()
+		case *SqlUnion:
+			st.Raw = st.String()
+		}
+		cte.Stmt = stmt
+		if m.Cur().T != lex.TokenRightParenthesis {
+			return nil, m.ErrMsg("expected right paren ) ")
+		}
+		m.Next() // discard right paren
+		ctes = append(ctes, cte)
+
+		if m.Cur().T != lex.TokenComma {
+			break
+		}
+		m.Next()
+	}
+
+	if m.Cur().T != lex.TokenSelect {
+		return nil, m.ErrMsg("expected SELECT after common table expressions")
+	}
+	stmt, err := m.parseSqlSelectOrUnion()
+	if err != nil {
+		return nil, err
+	}
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		st.Ctes = ctes
+	case *SqlUnion:
+		st.Ctes = ctes
+	}
+	return stmt, nil
+}
+
+func isSetOperation(t lex.TokenType) bool {
+	switch t {
+	case lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
+		return true
+	}
+	return false
+}
+
 // First keyword was SELECT, so use the SELECT parser rule-set
 func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 
@@ -196,7 +363,8 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 
 	// SPECIAL END CASE for simple selects
 	// SELECT last_insert_id();
-	if m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenEOF {
+	switch m.Cur().T {
+	case lex.TokenEOS, lex.TokenEOF, lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 		// valid end
 		return req, nil
 	}
@@ -263,7 +431,9 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 		return nil, err
 	}
 
-	if m.Cur().T == lex.TokenEOF || m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenRightParenthesis {
+	switch m.Cur().T {
+	case lex.TokenEOF, lex.TokenEOS, lex.TokenRightParenthesis,
+		lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 
 		if err := req.Finalize(); err != nil {
 			return nil, err
@@ -503,42 +673,51 @@ func (m *Sqlbridge) parsePrepare() (*PreparedStatement, error) {
 }
 
 // First keyword was DESCRIBE
+//
+//	DESCRIBE mytable
+//	EXPLAIN [EXTENDED] [FORMAT = (TEXT | JSON)] SELECT ...
 func (m *Sqlbridge) parseDescribe() (SqlStatement, error) {
 
 	req := &SqlDescribe{Raw: m.l.RawInput()}
 	req.Tok = m.Cur()
 	m.Next() // Consume Describe
 
-	//u.Debugf("token:  %v", m.Cur())
-	switch nextWord := strings.ToLower(m.Cur().V); nextWord {
-	case "select":
-		// TODO:  make the lexer handle this
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
-		sqlSel, err := ParseSql(sqlText)
-		if err != nil {
-			return nil, err
-		}
-		req.Stmt = sqlSel
-		return req, nil
-	case "extended":
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
-		sqlText = strings.Replace(sqlText, m.Cur().V, "", 1)
-		sqlSel, err := ParseSql(sqlText)
-		if err != nil {
-			return nil, err
-		}
-		req.Stmt = sqlSel
-		return req, nil
-	default:
-		if lex.TokenIdentity == m.Cur().T {
-			req.Identity = m.Cur().V
-		} else {
+	for {
+		//u.Debugf("token:  %v", m.Cur())
+		cur := m.Cur()
+		switch word := strings.ToLower(cur.V); {
+		case cur.T == lex.TokenSelect, cur.T == lex.TokenWith:
+			// the lexer stops here, the statement is parsed on its own
+			stmt, err := ParseSql(req.Raw[cur.Pos-len(cur.V):])
+			if err != nil {
+				return nil, err
+			}
+			req.Stmt = stmt
+			return req, nil
+		case cur.T == lex.TokenIdentity && word == "extended":
+			m.Next()
+		case cur.T == lex.TokenIdentity && word == "analyze":
+			req.Analyze = true
+			m.Next()
+		case cur.T == lex.TokenIdentity && word == "format":
+			m.Next()
+			if m.Cur().T == lex.TokenEqual {
+				m.Next()
+			}
+			switch format := strings.ToLower(m.Cur().V); format {
+			case "text", "json":
+				req.Format = format
+			default:
+				return nil, m.ErrMsg("expected FORMAT TEXT or JSON")
+			}
+			m.Next()
+		case cur.T == lex.TokenIdentity:
+			req.Identity = cur.V
+			return req, nil
+		default:
 			return nil, m.ErrMsg("expected idenity")
 		}
-
 	}
-
-	return req, nil
 }
 
 // First keyword was SHOW
@@ -864,6 +1043,26 @@ func (m *Sqlbridge) parseDrop() (*SqlDrop, error) {
 	return req, nil
 }
 
+func (m *Sqlbridge) parseAnalyze() (*SqlAnalyze, error) {
+
+	req := NewSqlAnalyze()
+	m.Next() // Consume ANALYZE token
+	req.Raw = m.l.RawInput()
+
+	// ANALYZE [TABLE] <identity>
+	if m.Cur().T == lex.TokenTable {
+		m.Next()
+	}
+	switch m.Cur().T {
+	case lex.TokenTable, lex.TokenIdentity:
+		req.Identity = m.Next().V
+	default:
+		return nil, m.ErrMsg("Expected table name after ANALYZE TABLE")
+	}
+	discardComments(m)
+	return req, nil
+}
+
 func (m *Sqlbridge) parseTransaction() (*SqlCommand, error) {
 
 	// rollback, commit
@@ -922,6 +1121,10 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 							col.As = n.Name
 						}
 					}
+				case *expr.WindowNode:
+					// row_number() OVER (...) is named by its function
+					n.Func.Name = funcName
+					col.As = funcName
 				case *expr.BinaryNode:
 					//u.Debugf("udf? %T ", col.Expr)
 					col.As = expr.FindIdentityName(0, n, "")
@@ -953,6 +1156,17 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 				return err
 			}
 			col.Expr = exprNode
+		case lex.TokenLeftParenthesis, lex.TokenCase:
+			// parenthesized expression, scalar sub-query or case
+			//    (SELECT count(*) FROM orders WHERE orders.user_id = users.id) AS ct
+			//    CASE WHEN age > 30 THEN "old" ELSE "young" END AS bucket
+			col = &Column{}
+			exprNode, err := expr.ParseExprWithFuncs(m, fr)
+			if err != nil {
+				return err
+			}
+			col.Expr = exprNode
+			col.As = exprNode.String()
 		}
 		//u.Debugf("after colstart?:   %v  ", m.Cur())
 		comment += readComment(m)
@@ -970,7 +1184,8 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 				continue
 			}
 			return m.ErrMsg("expected identity")
-		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			// This indicates we have come to the End of the columns
 			col.Comment = comment
 			stmt.AddColumn(*col)
@@ -1067,6 +1282,15 @@ func (m *Sqlbridge) parseUpdateList() (map[string]*ValueColumn, error) {
 				return nil, err
 			}
 			cols[lastColName] = &ValueColumn{Expr: exprNode}
+		case lex.TokenParam:
+			param, err := expr.NewParamNode(m.Cur().V)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.AddParam(param); err != nil {
+				return nil, m.ErrMsg(err.Error())
+			}
+			cols[lastColName] = &ValueColumn{Expr: param}
 		default:
 			u.Warnf("don't know how to handle ?  %v", m.Cur())
 			return nil, m.ErrMsg("expected column")
@@ -1096,6 +1320,7 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 			row = make([]*ValueColumn, 0)
 		case lex.TokenRightParenthesis:
 			values = append(values, row)
+			row = nil
 		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
 			if len(row) > 0 {
 				values = append(values, row)
@@ -1141,6 +1366,16 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 			u.Infof("what is token?  %v peek:%v", m.Cur(), m.Peek())
 		case lex.TokenComma:
 			// don't need to do anything
+		case lex.TokenParam:
+			// VALUES (?, ?)  bound at execution
+			param, err := expr.NewParamNode(m.Cur().V)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.AddParam(param); err != nil {
+				return nil, m.ErrMsg(err.Error())
+			}
+			row = append(row, &ValueColumn{Expr: param})
 		case lex.TokenUdfExpr:
 			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
 			if err != nil {
@@ -1189,13 +1424,15 @@ func (m *Sqlbridge) parseSources(req *SqlSelect) error {
 			if m.Cur().T == lex.TokenRightParenthesis {
 				m.Next()
 			}
-		case lex.TokenLeft, lex.TokenRight, lex.TokenInner, lex.TokenOuter, lex.TokenJoin:
+		case lex.TokenLeft, lex.TokenRight, lex.TokenFull, lex.TokenInner, lex.TokenOuter,
+			lex.TokenCross, lex.TokenJoin:
 			// JOIN
 			if err := m.parseSourceJoin(src); err != nil {
 				return err
 			}
 		case lex.TokenEOF, lex.TokenEOS, lex.TokenWhere, lex.TokenGroupBy, lex.TokenLimit,
-			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy:
+			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			return nil
 		default:
 			return m.ErrMsg("unexpected token")
@@ -1245,6 +1482,28 @@ func (m *Sqlbridge) parseSourceSubQuery(src *SqlSource) error {
 	return nil
 }
 
+// ParseSubQuery parse a sub-query nested inside of an expression, starting
+// on its left paren, implements expr.SubQueryPager
+//
+//    WHERE user_id IN (SELECT user_id FROM orders)
+//
+func (m *Sqlbridge) ParseSubQuery() (expr.SubQuery, error) {
+
+	m.Next() // page forward off of (
+
+	subQuery, err := m.parseSqlSelect()
+	if err != nil {
+		return nil, err
+	}
+	subQuery.Raw = subQuery.String()
+
+	if m.Cur().T != lex.TokenRightParenthesis {
+		return nil, m.ErrMsg("expected right paren ) ")
+	}
+	m.Next() // discard right paren
+	return subQuery, nil
+}
+
 func (m *Sqlbridge) parseSourceTable(req *SqlSelect) error {
 
 	if m.Cur().T != lex.TokenIdentity {
@@ -1254,9 +1513,13 @@ func (m *Sqlbridge) parseSourceTable(req *SqlSelect) error {
 	src := SqlSource{}
 	req.From = append(req.From, &src)
 	src.Schema, src.Name, _ = expr.LeftRight(m.Next().V)
-	if m.Cur().T == lex.TokenAs {
+	switch m.Cur().T {
+	case lex.TokenAs:
 		m.Next() // Skip over "AS", we don't need it
 		src.Alias = m.Next().V
+	case lex.TokenIdentity:
+		// SELECT name FROM users u
+		src.Alias = m.Next().V
 	}
 	return nil
 }
@@ -1264,14 +1527,14 @@ func (m *Sqlbridge) parseSourceTable(req *SqlSelect) error {
 func (m *Sqlbridge) parseSourceJoin(src *SqlSource) error {
 
 	switch m.Cur().T {
-	case lex.TokenLeft, lex.TokenRight:
+	case lex.TokenLeft, lex.TokenRight, lex.TokenFull:
 		src.LeftOrRight = m.Cur().T
 		m.Next()
 	}
 
-	// Optional Inner/Outer
+	// Optional Inner/Outer/Cross
 	switch m.Cur().T {
-	case lex.TokenInner, lex.TokenOuter:
+	case lex.TokenInner, lex.TokenOuter, lex.TokenCross:
 		src.JoinType = m.Cur().T
 		m.Next()
 	}
@@ -1316,19 +1579,6 @@ func (m *Sqlbridge) parseInto(req *SqlSelect) error {
 	return nil
 }
 
-func (m *Sqlbridge) parseWhereSubSelect(req *SqlSelect) error {
-
-	if m.Cur().T != lex.TokenSelect {
-		return nil
-	}
-	stmt, err := m.parseSqlSelect()
-	if err != nil {
-		return err
-	}
-	req = stmt
-	return nil
-}
-
 func (m *Sqlbridge) parseWhereSelect(req *SqlSelect) error {
 
 	var err error
@@ -1338,12 +1588,6 @@ func (m *Sqlbridge) parseWhereSelect(req *SqlSelect) error {
 	defer func() {
 		if r := recover(); r != nil {
 			u.Errorf("where error? %v \n %v\n%s", r, m.Cur(), m.Lexer().RawInput())
-			if m.Cur().T == lex.TokenSelect {
-				// TODO this is deeply flawed, need to fix/use tokenpager
-				//    with rewind ability
-				err = m.parseWhereSubSelect(req)
-				return
-			}
 			err = fmt.Errorf("panic err: %v", r)
 		}
 	}()
@@ -1369,39 +1613,14 @@ func (m *Sqlbridge) parseWhere() (*SqlWhere, error) {
 
 	where := SqlWhere{}
 
-	// We are going to Peek forward at the next 3 tokens used
-	// to determine which type of where clause
-	m.Next() // x
-	t2 := m.Cur().T
-	m.Next()
-	t3 := m.Cur().T
-	m.Next()
-	t4 := m.Cur().T
-	m.Backup()
-	m.Backup()
-	m.Backup()
-
-	// Check for Types of Where
-	//                                 t1            T2      T3     T4
-	//    SELECT x FROM user   WHERE user_id         IN      (      SELECT user_id from orders where ...)
-	//    SELECT * FROM t1     WHERE column1         =       (      SELECT column1 FROM t2);
-	//    select a FROM movies WHERE director        IN      (     "Quentin","copola","Bay","another")
+	// Sub-queries are parsed as expression nodes (expr.SubQueryNode)
+	//
+	//    SELECT x FROM user   WHERE user_id         IN      (SELECT user_id from orders where ...)
+	//    SELECT x FROM user   WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = user.user_id)
+	//    select a FROM movies WHERE director        IN      ("Quentin","copola","Bay","another")
 	//    select b FROM movies WHERE director        =       "bob";
 	//    select b FROM movies WHERE create          BETWEEN "2015" AND "2010";
 	//    select b from movies WHERE director        LIKE    "%bob"
-	// TODO:
-	//    SELECT * FROM t3     WHERE ROW(5*t2.s1,77) =       (      SELECT 50,11*s1 FROM t4)
-	switch {
-	case (t2 == lex.TokenIN || t2 == lex.TokenEqual) && t3 == lex.TokenLeftParenthesis && t4 == lex.TokenSelect:
-		//u.Infof("in parseWhere: %v", m.Cur())
-		m.Next() // T1  ?? this might be udf?
-		m.Next() // t2  (IN | =)
-		m.Next() // t3 = (
-		//m.Next() // t4 = SELECT
-		where.Op = t2
-		where.Source = &SqlSelect{}
-		return &where, m.parseWhereSubSelect(where.Source)
-	}
 	exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
 	if err != nil {
 		return nil, err
@@ -1418,60 +1637,35 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 	m.Next()
 
 	var col *Column
+	// the plain columns, and groupings of each ROLLUP, CUBE, GROUPING SETS
+	var plain []int
+	var groupings [][][]int
 
 	for {
 
 		//u.Debugf("Group By? %v", m.Cur())
 		switch m.Cur().T {
-		case lex.TokenUdfExpr:
-			// we have a udf/functional expression column
-			//u.Infof("udf: %v", m.Cur().V)
-			col = NewColumnFromToken(m.Cur())
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+		case lex.TokenRollup, lex.TokenCube, lex.TokenGroupingSets:
+			sets, err := m.parseGroupings(req)
 			if err != nil {
 				return err
 			}
-			col.Expr = exprNode
-
-			if m.Cur().T != lex.TokenAs {
-				switch n := col.Expr.(type) {
-				case *expr.FuncNode:
-					col.As = expr.FindIdentityName(0, n, "")
-					if col.As == "" {
-						col.As = n.Name
-					}
-				case *expr.BinaryNode:
-					//u.Debugf("udf? %T ", n)
-					col.As = expr.FindIdentityName(0, n, "")
-					if col.As == "" {
-						u.Errorf("could not find as name: %#v", n)
-					}
-				}
-			}
-			//u.Debugf("next? %v", m.Cur())
-
-		case lex.TokenIdentity:
-			//u.Warnf("?? %v", m.Cur())
-			col = NewColumnFromToken(m.Cur())
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
-			if err != nil {
-				return err
-			}
-			col.Expr = exprNode
-		case lex.TokenValue:
-			// Value Literal
-			col = NewColumnFromToken(m.Cur())
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+			groupings = append(groupings, sets)
+			col = nil
+		default:
+			col, err = m.parseGroupByExpr()
 			if err != nil {
 				return err
 			}
-			col.Expr = exprNode
 		}
 		//u.Debugf("GroupBy after colstart?:   %v  ", m.Cur())
 
 		// since we can loop inside switch statement
 		switch m.Cur().T {
 		case lex.TokenAs:
+			if col == nil {
+				return m.ErrMsg("expected column of group by ")
+			}
 			m.Next()
 			//u.Debug(m.Cur())
 			switch m.Cur().T {
@@ -1484,13 +1678,23 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 			}
 			return m.ErrMsg("expected identity")
 		case lex.TokenFrom, lex.TokenOrderBy, lex.TokenInto, lex.TokenLimit, lex.TokenHaving,
-			lex.TokenWith, lex.TokenEOS, lex.TokenEOF:
+			lex.TokenWith, lex.TokenEOS, lex.TokenEOF, lex.TokenRightParenthesis:
 
-			// This indicates we have come to the End of the columns
-			req.GroupBy = append(req.GroupBy, col)
+			// This indicates we have come to the End of the columns, a right
+			// paren is the end of the sub-query this group by is part of.
+			if col != nil {
+				plain = append(plain, len(req.GroupBy))
+				req.GroupBy = append(req.GroupBy, col)
+			}
+			if len(groupings) > 0 {
+				return m.setGroupingSets(req, plain, groupings)
+			}
 			return nil
 		case lex.TokenIf:
 			// If guard
+			if col == nil {
+				return m.ErrMsg("expected column of group by ")
+			}
 			m.Next()
 			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
 			if err != nil {
@@ -1499,11 +1703,14 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 			col.Guard = exprNode
 		case lex.TokenCommentSingleLine:
 			m.Next()
-			col.Comment = m.Cur().V
-		case lex.TokenRightParenthesis:
-			// loop on my friend
+			if col != nil {
+				col.Comment = m.Cur().V
+			}
 		case lex.TokenComma:
-			req.GroupBy = append(req.GroupBy, col)
+			if col != nil {
+				plain = append(plain, len(req.GroupBy))
+				req.GroupBy = append(req.GroupBy, col)
+			}
 		default:
 			return m.ErrMsg("expected column of group by ")
 		}
@@ -1511,6 +1718,185 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 	}
 }
 
+// parseGroupByExpr a column of the group by, nil if the current token does
+// not start one.
+func (m *Sqlbridge) parseGroupByExpr() (*Column, error) {
+
+	var col *Column
+
+	switch m.Cur().T {
+	case lex.TokenUdfExpr:
+		// we have a udf/functional expression column
+		//u.Infof("udf: %v", m.Cur().V)
+		col = NewColumnFromToken(m.Cur())
+		exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+		if err != nil {
+			return nil, err
+		}
+		col.Expr = exprNode
+
+		if m.Cur().T != lex.TokenAs {
+			switch n := col.Expr.(type) {
+			case *expr.FuncNode:
+				col.As = expr.FindIdentityName(0, n, "")
+				if col.As == "" {
+					col.As = n.Name
+				}
+			case *expr.BinaryNode:
+				//u.Debugf("udf? %T ", n)
+				col.As = expr.FindIdentityName(0, n, "")
+				if col.As == "" {
+					u.Errorf("could not find as name: %#v", n)
+				}
+			}
+		}
+		//u.Debugf("next? %v", m.Cur())
+
+	case lex.TokenIdentity:
+		//u.Warnf("?? %v", m.Cur())
+		col = NewColumnFromToken(m.Cur())
+		exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+		if err != nil {
+			return nil, err
+		}
+		col.Expr = exprNode
+	case lex.TokenValue:
+		// Value Literal
+		col = NewColumnFromToken(m.Cur())
+		exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+		if err != nil {
+			return nil, err
+		}
+		col.Expr = exprNode
+	}
+	return col, nil
+}
+
+// parseGroupings the grouping sets of a ROLLUP, CUBE or GROUPING SETS of
+// a group by, each the indexes of its columns in the group by.
+//
+//	ROLLUP (a, b)                   => (a, b), (a), ()
+//	CUBE (a, b)                     => (a, b), (a), (b), ()
+//	GROUPING SETS ((a, b), (a), ()) => (a, b), (a), ()
+func (m *Sqlbridge) parseGroupings(req *SqlSelect) ([][]int, error) {
+
+	kind := m.Cur().T
+	m.Next()
+	if m.Cur().T != lex.TokenLeftParenthesis {
+		return nil, m.ErrMsg("expected ( of " + kind.String())
+	}
+	m.Next()
+
+	// each element is a column, or a list of columns in parens
+	var elems [][]int
+	for m.Cur().T != lex.TokenRightParenthesis {
+		var elem []int
+		if m.Cur().T == lex.TokenLeftParenthesis {
+			m.Next()
+			for m.Cur().T != lex.TokenRightParenthesis {
+				idx, err := m.parseGroupingColumn(req)
+				if err != nil {
+					return nil, err
+				}
+				elem = append(elem, idx)
+				if m.Cur().T == lex.TokenComma {
+					m.Next()
+				}
+			}
+			m.Next()
+		} else {
+			idx, err := m.parseGroupingColumn(req)
+			if err != nil {
+				return nil, err
+			}
+			elem = []int{idx}
+		}
+		elems = append(elems, elem)
+
+		switch m.Cur().T {
+		case lex.TokenComma:
+			m.Next()
+		case lex.TokenRightParenthesis:
+		default:
+			return nil, m.ErrMsg("expected , or ) of " + kind.String())
+		}
+	}
+	m.Next()
+
+	switch kind {
+	case lex.TokenRollup:
+		// each prefix of the elements, longest first
+		sets := make([][]int, 0, len(elems)+1)
+		for i := len(elems); i >= 0; i-- {
+			var set []int
+			for _, elem := range elems[:i] {
+				set = append(set, elem...)
+			}
+			sets = append(sets, set)
+		}
+		return sets, nil
+	case lex.TokenCube:
+		// each subset of the elements, all of them first
+		if len(elems) > 16 {
+			return nil, m.ErrMsg("too many columns of CUBE")
+		}
+		sets := make([][]int, 0, 1<<uint(len(elems)))
+		for bits := 1<<uint(len(elems)) - 1; bits >= 0; bits-- {
+			var set []int
+			for i, elem := range elems {
+				if bits&(1<<uint(len(elems)-1-i)) != 0 {
+					set = append(set, elem...)
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets, nil
+	}
+	return elems, nil
+}
+
+// parseGroupingColumn a column of a grouping, the index of it in the
+// group by, which it is added to if not already there.
+func (m *Sqlbridge) parseGroupingColumn(req *SqlSelect) (int, error) {
+	col, err := m.parseGroupByExpr()
+	if err != nil {
+		return 0, err
+	}
+	if col == nil {
+		return 0, m.ErrMsg("expected column of grouping")
+	}
+	for i, gb := range req.GroupBy {
+		if gb.Expr != nil && gb.Expr.Equal(col.Expr) {
+			return i, nil
+		}
+	}
+	req.GroupBy = append(req.GroupBy, col)
+	return len(req.GroupBy) - 1, nil
+}
+
+// setGroupingSets the grouping sets of the group by, the plain columns
+// are in all of them, with each combination of a set of each of the
+// groupings.
+//
+//	GROUP BY a, ROLLUP (b, c)  => (a, b, c), (a, b), (a)
+func (m *Sqlbridge) setGroupingSets(req *SqlSelect, plain []int, groupings [][][]int) error {
+	if len(req.GroupBy) > MaxGroupingColumns {
+		return m.ErrMsg(fmt.Sprintf("too many columns of grouping sets, max %d", MaxGroupingColumns))
+	}
+	sets := [][]int{plain}
+	for _, grouping := range groupings {
+		product := make([][]int, 0, len(sets)*len(grouping))
+		for _, set := range sets {
+			for _, gs := range grouping {
+				product = append(product, groupingSet(append(append([]int{}, set...), gs...)))
+			}
+		}
+		sets = product
+	}
+	req.GroupingSets = sets
+	return nil
+}
+
 func (m *Sqlbridge) parseHaving(req *SqlSelect) (err error) {
 
 	if m.Cur().T != lex.TokenHaving {
@@ -1577,6 +1963,14 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 				return err
 			}
 			col.Expr = exprNode
+		case lex.TokenLeftParenthesis:
+			col = &Column{}
+			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+			if err != nil {
+				return err
+			}
+			col.Expr = exprNode
+			col.As = exprNode.String()
 		}
 		//u.Debugf("OrderBy after colstart?:   %v  ", m.Cur())
 
@@ -1585,15 +1979,15 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 		case lex.TokenAsc, lex.TokenDesc:
 			col.Order = strings.ToUpper(m.Cur().V)
 
-		case lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
-			// This indicates we have come to the End of the columns
+		case lex.TokenInto, lex.TokenLimit, lex.TokenOffset, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenRightParenthesis:
+			// This indicates we have come to the End of the columns, a right
+			// paren is the end of the sub-query this order by is part of.
 			req.OrderBy = append(req.OrderBy, col)
 			return nil
 		case lex.TokenCommentSingleLine:
 			m.Next()
 			col.Comment = m.Cur().V
-		case lex.TokenRightParenthesis:
-			// loop on my friend
 		case lex.TokenComma:
 			req.OrderBy = append(req.OrderBy, col)
 		default:
@@ -2335,7 +2729,8 @@ func (m *SqlTokenPager) ClauseEnd() bool {
 	tok := m.Cur()
 	switch tok.T {
 	case lex.TokenEOF, lex.TokenEOS, lex.TokenFrom, lex.TokenHaving, lex.TokenComma,
-		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect:
+		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect,
+		lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 		return true
 	}
 	return false
@@ -11,6 +11,7 @@ import (
 	"github.com/araddon/qlbridge/expr/builtins"
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
@@ -38,6 +39,13 @@ func parseSqlTest(t *testing.T, sql string) {
 		assert.Equal(t, nil, err)
 		assert.True(t, ss.Equal(ss2))
 	}
+	if su, ok := sqlRequest.(*rel.SqlUnion); ok {
+		pbb, err := su.ToPbStatement().Marshal()
+		assert.Equal(t, nil, err)
+		su2, err := rel.SqlFromPb(pbb)
+		assert.Equal(t, nil, err)
+		assert.True(t, su.Equal(su2))
+	}
 }
 func parseSqlError(t *testing.T, sql string) {
 	u.Debugf("parse looking for error sql: %s", sql)
@@ -314,6 +322,13 @@ func TestSqlParseOnly(t *testing.T) {
 	//parseSqlTest(t, `select user_id, email FROM mockcsv.users
 	//    WHERE tolower(email) IN (select email from mockcsv.orders)`)
 
+	parseSqlTest(t, `SELECT user_id, CASE WHEN count > 10 THEN "high" ELSE "low" END AS level FROM users
+		WHERE CASE category WHEN "a" THEN true ELSE false END`)
+
+	parseSqlTest(t, `SELECT user_id, row_number() OVER (PARTITION BY user_id ORDER BY price DESC) AS rn FROM orders ORDER BY rn`)
+	parseSqlTest(t, `SELECT user_id, rn FROM (SELECT user_id, rank() OVER (ORDER BY price) AS rn FROM orders) AS o WHERE rn = 1`)
+	parseSqlTest(t, `SELECT user_id, min(price), count(*) FILTER (WHERE price > 10) AS ct FROM orders GROUP BY user_id`)
+
 	parseSqlTest(t, `PREPARE stmt1 FROM 'SELECT toint(field) + 4 AS field FROM table1';`)
 
 	/*
@@ -457,6 +472,25 @@ func TestSqlParseAstCheck(t *testing.T) {
 	assert.True(t, ok, "is SqlSelect: %T", req)
 	u.Info(sel.Where.String())
 
+	req, err = rel.ParseSql(`EXPLAIN FORMAT = JSON SELECT user_id FROM orders WHERE price > 10`)
+	assert.True(t, err == nil && req != nil, "Must parse: %v", err)
+	desc, ok = req.(*rel.SqlDescribe)
+	assert.True(t, ok, "is SqlDescribe: %T", req)
+	assert.Equal(t, "json", desc.Format)
+	assert.Equal(t, false, desc.Analyze)
+	assert.Equal(t, "SELECT user_id FROM orders WHERE price > 10", desc.Stmt.String())
+
+	req, err = rel.ParseSql(`EXPLAIN ANALYZE FORMAT JSON SELECT user_id FROM orders`)
+	assert.True(t, err == nil && req != nil, "Must parse: %v", err)
+	desc, ok = req.(*rel.SqlDescribe)
+	assert.True(t, ok, "is SqlDescribe: %T", req)
+	assert.Equal(t, true, desc.Analyze)
+	assert.Equal(t, "json", desc.Format)
+	assert.Equal(t, "SELECT user_id FROM orders", desc.Stmt.String())
+
+	_, err = rel.ParseSql(`EXPLAIN FORMAT = xml SELECT user_id FROM orders`)
+	assert.True(t, err != nil, "only text or json format")
+
 	// Where In Sub-Query Clause
 	sql = `select user_id, email
 				FROM mockcsv.users
@@ -467,7 +501,38 @@ func TestSqlParseAstCheck(t *testing.T) {
 	sel, ok = req.(*rel.SqlSelect)
 	assert.True(t, ok, "is SqlSelect: %T", req)
 	assert.True(t, len(sel.From) == 1, "has 1 from: %v", sel.From)
-	assert.True(t, sel.Where != nil && sel.Where.Source != nil, "has sub-select: %v", sel.Where)
+	assert.True(t, sel.Where != nil && sel.Where.Expr != nil, "has where: %v", sel.Where)
+	bn, ok := sel.Where.Expr.(*expr.BinaryNode)
+	assert.True(t, ok, "is BinaryNode: %T", sel.Where.Expr)
+	sub, ok := bn.Args[1].(*expr.SubQueryNode)
+	assert.True(t, ok, "is SubQueryNode: %T", bn.Args[1])
+	assert.Equal(t, "SELECT user_id FROM mockcsv.orders", sub.Stmt.String())
+
+	// Exists, Not Exists Sub-Query
+	parseSqlTest(t, "SELECT user_id FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.user_id)")
+	parseSqlTest(t, "SELECT user_id FROM users WHERE NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.user_id) AND email IS NOT NULL")
+	parseSqlTest(t, "SELECT user_id FROM users WHERE user_id NOT IN (SELECT user_id FROM orders WHERE item_name = \")\")")
+
+	// Scalar Sub-Query as column, where, order by
+	sql = "SELECT name, (SELECT count(*) FROM orders o WHERE o.user_id = u.id) AS n FROM users u"
+	sel, err = rel.ParseSqlSelect(sql)
+	assert.True(t, err == nil, "Must parse: %s  \n\t%v", sql, err)
+	assert.True(t, len(sel.Columns) == 2, "has 2 cols: %v", sel.Columns)
+	assert.Equal(t, "n", sel.Columns[1].As)
+	sub, ok = sel.Columns[1].Expr.(*expr.SubQueryNode)
+	assert.True(t, ok, "is SubQueryNode: %T", sel.Columns[1].Expr)
+	assert.Equal(t, "SELECT count(*) FROM orders AS o WHERE o.user_id = u.id", sub.Stmt.String())
+	assert.Equal(t, "u", sel.From[0].Alias)
+	parseSqlTest(t, "SELECT name FROM users WHERE (SELECT count(*) FROM orders WHERE orders.user_id = users.id) > 1")
+	parseSqlTest(t, "SELECT name, 1 + (SELECT count(*) FROM orders) AS n FROM users ORDER BY (SELECT count(*) FROM orders)")
+
+	// Order by of a sub-query
+	parseSqlTest(t, "SELECT user_id FROM users WHERE user_id IN (SELECT user_id FROM orders ORDER BY price DESC, user_id)")
+	sql = "SELECT user_id, count(*) FROM (SELECT user_id FROM orders ORDER BY user_id) AS o GROUP BY user_id"
+	sel, err = rel.ParseSqlSelect(sql)
+	assert.True(t, err == nil, "Must parse: %s  \n\t%v", sql, err)
+	assert.Equal(t, "user_id", sel.From[0].SubQuery.OrderBy.String())
+	assert.Equal(t, "o", sel.From[0].Alias)
 }
 
 func TestSqlAggregateTypeSelect(t *testing.T) {
@@ -599,6 +664,134 @@ func TestSqlAlias(t *testing.T) {
 	assert.True(t, sel.Alias == "user_query", "has alias: %v", sel.Alias)
 }
 
+func TestSqlUnion(t *testing.T) {
+	t.Parallel()
+	parseSqlTest(t, `SELECT a FROM t1 UNION SELECT b FROM t2`)
+	parseSqlTest(t, `SELECT 1 UNION ALL SELECT 2`)
+	parseSqlTest(t, `SELECT a FROM t1 WHERE a > 1 EXCEPT DISTINCT SELECT a FROM t2;`)
+	parseSqlTest(t, `SELECT a, count(*) FROM t1 GROUP BY a HAVING count(*) > 1 UNION SELECT a, 1 FROM t2 GROUP BY a`)
+	// an offset of the whole result, without a limit
+	parseSqlTest(t, `SELECT a FROM t1 UNION SELECT a FROM t2 ORDER BY a DESC OFFSET 2`)
+
+	sql := `SELECT a FROM t1 UNION ALL SELECT a FROM t2 INTERSECT SELECT a FROM t3 ORDER BY a DESC LIMIT 10`
+	req, err := rel.ParseSql(sql)
+	assert.True(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	su, ok := req.(*rel.SqlUnion)
+	assert.True(t, ok, "is SqlUnion: %T", req)
+	assert.Equal(t, lex.TokenUnion, su.Op)
+	assert.True(t, su.All)
+	assert.Equal(t, 10, su.Limit)
+	assert.Equal(t, 1, len(su.OrderBy))
+	assert.Equal(t, 3, len(su.Selects()))
+	// intersect binds tighter than union
+	right, ok := su.Right.(*rel.SqlUnion)
+	assert.True(t, ok, "is SqlUnion: %T", su.Right)
+	assert.Equal(t, lex.TokenIntersect, right.Op)
+	last := su.Selects()[2]
+	assert.Equal(t, 0, last.Limit)
+	assert.Equal(t, 0, len(last.OrderBy))
+	assert.Equal(t, "SELECT a FROM t1 UNION ALL SELECT a FROM t2 INTERSECT SELECT a FROM t3 ORDER BY a DESC LIMIT 10", su.String())
+
+	parseSqlError(t, `SELECT a FROM t1 UNION`)
+	parseSqlError(t, `SELECT a FROM t1 UNION ALL t2`)
+}
+
+func TestSqlWith(t *testing.T) {
+	t.Parallel()
+	parseSqlTest(t, `WITH b AS (SELECT a FROM t1 WHERE a > 1) SELECT a FROM b`)
+	parseSqlTest(t, `WITH b (x, y) AS (SELECT a, count(*) FROM t1 GROUP BY a), c AS (SELECT x FROM b) SELECT x FROM c UNION SELECT y FROM b`)
+	parseSqlTest(t, `WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM nums WHERE n < 10) SELECT n FROM nums`)
+
+	sql := `WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM nums WHERE n < 10) SELECT n FROM nums`
+	req, err := rel.ParseSql(sql)
+	assert.True(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	sel, ok := req.(*rel.SqlSelect)
+	assert.True(t, ok, "is SqlSelect: %T", req)
+	assert.Equal(t, 1, len(sel.Ctes))
+	cte := sel.Ctes[0]
+	assert.Equal(t, "nums", cte.Name)
+	assert.Equal(t, []string{"n"}, cte.Columns)
+	assert.True(t, cte.Recursive)
+	_, ok = cte.Stmt.(*rel.SqlUnion)
+	assert.True(t, ok, "is SqlUnion: %T", cte.Stmt)
+	assert.Equal(t, sql, sel.String())
+
+	parseSqlError(t, `WITH b AS SELECT a FROM t1 SELECT a FROM b`)
+	parseSqlError(t, `WITH b AS (SELECT a FROM t1)`)
+	parseSqlError(t, `WITH b AS (SELECT a FROM t1), b AS (SELECT a FROM t2) SELECT a FROM b`)
+}
+
+func TestSqlGroupingSets(t *testing.T) {
+	t.Parallel()
+	parseSqlTest(t, `SELECT a, b, sum(x), grouping(a, b) FROM t GROUP BY ROLLUP (a, b)`)
+	parseSqlTest(t, `SELECT a, b, count(*) FROM t GROUP BY CUBE (a, b) HAVING count(*) > 1 ORDER BY a`)
+	parseSqlTest(t, `SELECT a, b, c FROM t GROUP BY a, GROUPING SETS ((b, c), (b), ()) LIMIT 10`)
+
+	groupings := func(sql string) [][]int {
+		req, err := rel.ParseSql(sql)
+		assert.True(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+		sel := req.(*rel.SqlSelect)
+		// round trips as GROUPING SETS
+		sel2, err := rel.ParseSqlSelect(sel.String())
+		assert.Equal(t, nil, err)
+		assert.Equal(t, sel.GroupingSets, sel2.GroupingSets)
+		return sel.GroupingSets
+	}
+	assert.Equal(t, [][]int{{0, 1}, {0}, {}}, groupings(`SELECT a FROM t GROUP BY ROLLUP (a, b)`))
+	assert.Equal(t, [][]int{{0, 1}, {0}, {1}, {}}, groupings(`SELECT a FROM t GROUP BY CUBE (a, b)`))
+	assert.Equal(t, [][]int{{0, 1, 2}, {0, 1}, {0}}, groupings(`SELECT a FROM t GROUP BY a, ROLLUP (b, c)`))
+	assert.Equal(t, [][]int{{0, 1, 2}, {0, 1}, {}}, groupings(`SELECT a FROM t GROUP BY ROLLUP ((a, b), c)`))
+	assert.Equal(t, [][]int{{0, 1}, {1}}, groupings(`SELECT a FROM t GROUP BY GROUPING SETS ((a, b), b)`))
+	assert.Equal(t, [][]int(nil), groupings(`SELECT a FROM t GROUP BY a, b`))
+
+	sel, err := rel.ParseSqlSelect(`SELECT a FROM t GROUP BY ROLLUP (a, b)`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "GROUPING SETS ((a, b), (a), ())", sel.GroupingSetsString())
+
+	parseSqlError(t, `SELECT a FROM t GROUP BY ROLLUP a, b`)
+	parseSqlError(t, `SELECT a FROM t GROUP BY GROUPING SETS ((a, b) (a))`)
+}
+
+func TestSqlParams(t *testing.T) {
+	t.Parallel()
+	parseSqlTest(t, `SELECT a FROM t WHERE b = ? AND c IN (SELECT c FROM x WHERE d > ?)`)
+	parseSqlTest(t, `SELECT a FROM t WHERE b = $1 OR c = $1 LIMIT 10`)
+	parseSqlTest(t, `INSERT INTO t (a, b) VALUES (?, ?)`)
+	parseSqlTest(t, `UPDATE t SET a = ? WHERE b = ?`)
+
+	params := func(sql string) []int {
+		stmt, err := rel.ParseSql(sql)
+		assert.True(t, err == nil && stmt != nil, "Must parse: %s  \n\t%v", sql, err)
+		var idx []int
+		for _, p := range rel.Params(stmt) {
+			idx = append(idx, p.Index)
+		}
+		return idx
+	}
+	// positional are numbered in order, sub-queries included
+	assert.Equal(t, []int{1, 2, 3}, params(`SELECT a FROM t WHERE b = ? AND c IN (SELECT c FROM x WHERE d > ?) AND e < ?`))
+	assert.Equal(t, []int{2, 1, 2}, params(`SELECT a FROM t WHERE b = $2 AND c = $1 OR d = $2`))
+	assert.Equal(t, []int{1, 2}, params(`INSERT INTO t (a, b) VALUES (?, ?)`))
+
+	parseSqlError(t, `SELECT a FROM t WHERE b = ? AND c = $1`)
+	parseSqlError(t, `SELECT a FROM t WHERE b = $0`)
+
+	// binding copies the statement, which is left unbound to be re-used
+	sel, err := rel.ParseSqlSelect(`SELECT a FROM t WHERE b = ? AND c > ?`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, rel.NumParams(sel))
+	bound, err := rel.BindParams(sel, []value.Value{value.NewStringValue(`x" OR "1`), value.NewIntValue(5)})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `SELECT a FROM t WHERE b = "x"" OR ""1" AND c > 5`, bound.String())
+	// the bound string is a literal when re-parsed
+	sel2, err := rel.ParseSqlSelect(bound.String())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, bound.String(), sel2.String())
+	assert.Equal(t, `SELECT a FROM t WHERE b = $1 AND c > $2`, sel.String())
+	_, err = rel.BindParams(sel, []value.Value{value.NewIntValue(5)})
+	assert.NotEqual(t, nil, err)
+}
+
 func TestSqlUpsert(t *testing.T) {
 	t.Parallel()
 	// This is obviously not exactly sql standard
@@ -680,6 +873,28 @@ func TestSqlDrop(t *testing.T) {
 	assert.Equal(t, "articles", ds.Identity, "has articles: %v", ds.Identity)
 }
 
+func TestSqlAnalyze(t *testing.T) {
+	t.Parallel()
+	for _, sql := range []string{`ANALYZE TABLE articles;`, `analyze articles`} {
+		req, err := rel.ParseSql(sql)
+		assert.Equal(t, nil, err, sql)
+		as, ok := req.(*rel.SqlAnalyze)
+		assert.True(t, ok, "wanted SqlAnalyze got %T", req)
+		assert.Equal(t, lex.TokenAnalyze, as.Keyword())
+		assert.Equal(t, "articles", as.Identity)
+		assert.Equal(t, "ANALYZE TABLE articles", as.String())
+	}
+	_, err := rel.ParseSql(`ANALYZE TABLE;`)
+	assert.NotEqual(t, nil, err)
+
+	// EXPLAIN ANALYZE is still an explain
+	req, err := rel.ParseSql(`EXPLAIN ANALYZE SELECT * FROM articles`)
+	assert.Equal(t, nil, err)
+	desc, ok := req.(*rel.SqlDescribe)
+	assert.True(t, ok, "wanted SqlDescribe got %T", req)
+	assert.True(t, desc.Analyze)
+}
+
 func TestWithNameValue(t *testing.T) {
 	t.Parallel()
 	// some sql dialects support a WITH name=value syntax
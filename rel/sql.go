@@ -19,9 +19,14 @@ import (
 	"github.com/araddon/qlbridge/value"
 )
 
+// MaxGroupingColumns the most columns of a group by with grouping sets.
+const MaxGroupingColumns = 64
+
 var (
 	// Ensure SqlSelect and cousins etc are SqlStatements
 	_ SqlStatement = (*SqlSelect)(nil)
+	_ SqlStatement = (*SqlUnion)(nil)
+	_ SqlStatement = (*SqlCte)(nil)
 	_ SqlStatement = (*SqlInsert)(nil)
 	_ SqlStatement = (*SqlUpsert)(nil)
 	_ SqlStatement = (*SqlUpdate)(nil)
@@ -30,6 +35,7 @@ var (
 	_ SqlStatement = (*SqlDescribe)(nil)
 	_ SqlStatement = (*SqlCommand)(nil)
 	_ SqlStatement = (*SqlInto)(nil)
+	_ SqlStatement = (*SqlAnalyze)(nil)
 
 	// sub-query statements
 	_ SqlSourceStatement = (*SqlSource)(nil)
@@ -44,6 +50,16 @@ var (
 func init() {
 	starCols = make(Columns, 1)
 	starCols[0] = NewColumnFromToken(lex.Token{T: lex.TokenStar, V: "*"})
+	expr.SubQueryParser = parseSubQuery
+}
+
+// parseSubQuery parse the sql of an expression sub-query (expr.SubQueryNode)
+func parseSubQuery(sql string) (expr.SubQuery, error) {
+	stmt, err := ParseSqlSelect(sql)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
 }
 
 type (
@@ -83,30 +99,64 @@ type (
 	}
 	// SqlSelect SQL Select statement
 	SqlSelect struct {
-		Db        string       // If provided a use "dbname"
-		Raw       string       // full original raw statement
-		Star      bool         // for select * from ...
-		Distinct  bool         // Distinct flag?
-		Columns   Columns      // An array (ordered) list of columns
-		From      []*SqlSource // From, Join
-		Into      *SqlInto     // Into "table"
-		Where     *SqlWhere    // Expr Node, or *SqlSelect
-		Having    expr.Node    // Filter results
-		GroupBy   Columns
-		OrderBy   Columns
-		Limit     int
-		Offset    int
-		Alias     string       // Non-Standard sql, alias/name of sql another way of expression Prepared Statement
-		With      u.JsonHelper // Non-Standard SQL for properties/config info, similar to Cassandra with, purse json
-		proj      *Projection  // Projected fields
-		isAgg     bool         // is this an aggregate query?  has group-by, or aggregate selector expressions (count, cardinality etc)
-		finalized bool         // have we already finalized, ie formalized left/right aliases
-		schemaqry bool         // is this a schema qry?  ie select @@max_packet etc
+		Db       string       // If provided a use "dbname"
+		Raw      string       // full original raw statement
+		Star     bool         // for select * from ...
+		Distinct bool         // Distinct flag?
+		Columns  Columns      // An array (ordered) list of columns
+		From     []*SqlSource // From, Join
+		Into     *SqlInto     // Into "table"
+		Where    *SqlWhere    // Expr Node, or *SqlSelect
+		Having   expr.Node    // Filter results
+		GroupBy  Columns
+		// GroupingSets of GROUP BY ROLLUP, CUBE or GROUPING SETS, each the
+		// indexes of its columns in GroupBy, nil is the one set of all of them
+		GroupingSets [][]int
+		OrderBy      Columns
+		Limit        int
+		Offset       int
+		Alias        string       // Non-Standard sql, alias/name of sql another way of expression Prepared Statement
+		With         u.JsonHelper // Non-Standard SQL for properties/config info, similar to Cassandra with, purse json
+		Ctes         []*SqlCte    // WITH common table expressions
+		proj         *Projection  // Projected fields
+		isAgg        bool         // is this an aggregate query?  has group-by, or aggregate selector expressions (count, cardinality etc)
+		finalized    bool         // have we already finalized, ie formalized left/right aliases
+		schemaqry    bool         // is this a schema qry?  ie select @@max_packet etc
 
 		// Memoized sql, we assume this is an immuteable struct so if this is populated use it
 		pb            *SqlStatementPb
 		fingerprintid int64
 	}
+	// SqlUnion is a set operation (UNION, INTERSECT, EXCEPT) combining
+	// the results of two statements, each either a *SqlSelect or *SqlUnion.
+	//  - SELECT a FROM x UNION ALL SELECT a FROM y
+	//  - SELECT a FROM x INTERSECT SELECT a FROM y ORDER BY a LIMIT 10
+	SqlUnion struct {
+		Raw     string        // full original raw statement
+		Op      lex.TokenType // UNION, INTERSECT, EXCEPT
+		All     bool          // ALL keeps duplicates, otherwise rows are distinct
+		Left    SqlStatement  // *SqlSelect or *SqlUnion
+		Right   SqlStatement  // *SqlSelect or *SqlUnion
+		OrderBy Columns       // Order of the combined result
+		Limit   int
+		Offset  int
+		Ctes    []*SqlCte // WITH common table expressions
+
+		// Memoized sql, we assume this is an immuteable struct so if this is populated use it
+		pb *SqlStatementPb
+	}
+	// SqlCte is a common table expression, a named sub-query usable as a
+	// source by its statement and by the common table expressions after it.
+	// A recursive one is the UNION of an anchor select and a select
+	// which references itself.
+	//  - WITH t AS (SELECT a FROM x) SELECT a FROM t
+	//  - WITH RECURSIVE t (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < 5) SELECT n FROM t
+	SqlCte struct {
+		Name      string       // name to use as a source
+		Columns   []string     // optional column names, otherwise named by the select
+		Recursive bool         // WITH RECURSIVE
+		Stmt      SqlStatement // *SqlSelect or *SqlUnion
+	}
 	// SqlSource is a table name, sub-query, or join as used in
 	// SELECT <columns> FROM <SQLSOURCE>
 	//  - SELECT .. FROM table_name
@@ -195,6 +245,8 @@ type (
 		Identity string    // Describe
 		Tok      lex.Token // Explain, Describe, Desc
 		Stmt     SqlStatement
+		Format   string // EXPLAIN FORMAT = text or json, text if empty
+		Analyze  bool   // EXPLAIN ANALYZE runs the statement
 	}
 	// SqlInto   INTO statement   (select a,b,c from y INTO z)
 	SqlInto struct {
@@ -227,6 +279,12 @@ type (
 		Tok      lex.Token // DROP [TEMP] [TABLE,VIEW,CONTINUOUSVIEW,TRIGGER] etc
 		With     u.JsonHelper
 	}
+	// SqlAnalyze SQL ANALYZE TABLE statement, collects the statistics
+	// the planner uses to order joins
+	SqlAnalyze struct {
+		Raw      string // full original raw statement
+		Identity string // table to analyze
+	}
 	// SqlAlter SQL ALTER statement
 	SqlAlter struct {
 		Raw      string       // full original raw statement
@@ -333,6 +391,9 @@ func NewSqlSelect() *SqlSelect {
 	req.Columns = make(Columns, 0)
 	return req
 }
+func NewSqlUnion(op lex.TokenType, left, right SqlStatement) *SqlUnion {
+	return &SqlUnion{Op: op, Left: left, Right: right}
+}
 func NewSqlInsert() *SqlInsert {
 	req := &SqlInsert{}
 	req.Columns = make(Columns, 0)
@@ -360,6 +421,9 @@ func NewSqlDrop() *SqlDrop {
 	req := &SqlDrop{}
 	return req
 }
+func NewSqlAnalyze() *SqlAnalyze {
+	return &SqlAnalyze{}
+}
 func NewSqlInto(table string) *SqlInto {
 	return &SqlInto{Table: table}
 }
@@ -713,7 +777,7 @@ func (m *Column) IsLiteral() bool {
 			return true
 		}
 		return false
-	case *expr.StringNode, *expr.NumberNode, *expr.ValueNode:
+	case *expr.StringNode, *expr.NumberNode, *expr.ValueNode, *expr.ParamNode:
 		return true
 	default:
 		u.Warnf("Unknown Node column type? %T", n)
@@ -737,8 +801,21 @@ func (m *Column) IsLiteralOrFunc() bool {
 			return true
 		}
 		return false
-	case *expr.StringNode, *expr.NumberNode, *expr.ValueNode:
+	case *expr.StringNode, *expr.NumberNode, *expr.ValueNode, *expr.ParamNode:
+		return true
+	case *expr.SubQueryNode:
+		// (SELECT count(*) FROM orders)
+		return true
+	case *expr.CaseNode:
+		// CASE WHEN x > 5 THEN "big" ELSE "small" END
 		return true
+	case *expr.WindowNode:
+		// row_number() OVER (PARTITION BY user_id ORDER BY price)
+		return true
+	case *expr.BinaryNode, *expr.UnaryNode:
+		// 2 * count(*)
+		// sum(price) / count(*)
+		return m.Agg
 	}
 	return false
 }
@@ -883,7 +960,7 @@ func (m *Column) ToPB() *ColumnPb {
 	return &n
 }
 func columnFromPb(c *ColumnPb) *Column {
-	return &Column{
+	col := &Column{
 		sourceQuoteByte: optionalByte(c.GetSourceQuote()),
 		asQuoteByte:     optionalByte(c.GetAsQuoteByte()),
 		originalAs:      c.GetOriginalAs(),
@@ -899,6 +976,11 @@ func columnFromPb(c *ColumnPb) *Column {
 		Expr:            expr.NodeFromNodePb(c.GetExpr()),
 		Guard:           expr.NodeFromNodePb(c.GetGuard()),
 	}
+	// not serialized, an aggregate column as SqlSelect.AddColumn() has it
+	if col.Expr != nil {
+		col.Agg = len(expr.FindAggregates(col.Expr)) > 0
+	}
+	return col
 }
 
 // Return left, right values if is of form   `table.column` and
@@ -979,6 +1061,9 @@ func sqlSelectToPbDepth(m *SqlSelect, depth int) *SqlSelectPb {
 	if len(m.GroupBy) > 0 {
 		s.GroupBy = ColumnsToPb(m.GroupBy)
 	}
+	for _, set := range m.GroupingSets {
+		s.GroupingSets = append(s.GroupingSets, groupingSetMask(set))
+	}
 	if len(m.OrderBy) > 0 {
 		s.OrderBy = ColumnsToPb(m.OrderBy)
 	}
@@ -999,6 +1084,9 @@ func sqlSelectToPbDepth(m *SqlSelect, depth int) *SqlSelectPb {
 	if m.Into != nil {
 		s.Into = &m.Into.Table
 	}
+	if len(m.Ctes) > 0 {
+		s.Ctes = ctesToPb(m.Ctes)
+	}
 	return &s
 }
 func (m *SqlSelect) Equal(ss SqlStatement) bool {
@@ -1079,6 +1167,14 @@ func (m *SqlSelect) Equal(ss SqlStatement) bool {
 			return false
 		}
 	}
+	if len(m.GroupingSets) != len(s.GroupingSets) {
+		return false
+	}
+	for i, set := range m.GroupingSets {
+		if groupingSetMask(set) != groupingSetMask(s.GroupingSets[i]) {
+			return false
+		}
+	}
 	if len(m.OrderBy) != len(s.OrderBy) {
 		return false
 	}
@@ -1090,6 +1186,9 @@ func (m *SqlSelect) Equal(ss SqlStatement) bool {
 	if !m.proj.Equal(s.proj) {
 		return false
 	}
+	if !ctesEqual(m.Ctes, s.Ctes) {
+		return false
+	}
 	return true
 }
 
@@ -1125,6 +1224,9 @@ func SqlSelectFromPb(pb *SqlSelectPb) *SqlSelect {
 	if len(pb.GroupBy) > 0 {
 		ss.GroupBy = ColumnsFromPb(pb.GetGroupBy())
 	}
+	for _, mask := range pb.GroupingSets {
+		ss.GroupingSets = append(ss.GroupingSets, groupingSetFromMask(mask))
+	}
 	if len(pb.OrderBy) > 0 {
 		ss.OrderBy = ColumnsFromPb(pb.GetOrderBy())
 	}
@@ -1138,6 +1240,9 @@ func SqlSelectFromPb(pb *SqlSelectPb) *SqlSelect {
 		ss.With = make(u.JsonHelper)
 		json.Unmarshal(pb.With, &ss.With)
 	}
+	if len(pb.Ctes) > 0 {
+		ss.Ctes = ctesFromPb(pb.Ctes)
+	}
 	return &ss
 }
 func (m *SqlSelect) IsAggQuery() bool {
@@ -1153,6 +1258,7 @@ func (m *SqlSelect) String() string {
 }
 func (m *SqlSelect) writeDialectDepth(depth int, w expr.DialectWriter) {
 
+	writeCtes(m.Ctes, w)
 	io.WriteString(w, "SELECT ")
 	if m.Distinct {
 		io.WriteString(w, "DISTINCT ")
@@ -1183,7 +1289,10 @@ func (m *SqlSelect) writeDialectDepth(depth int, w expr.DialectWriter) {
 		io.WriteString(w, " WHERE ")
 		m.Where.writeDialectDepth(depth, w)
 	}
-	if len(m.GroupBy) > 0 {
+	if len(m.GroupingSets) > 0 {
+		io.WriteString(w, " GROUP BY ")
+		m.writeGroupingSets(w)
+	} else if len(m.GroupBy) > 0 {
 		io.WriteString(w, " GROUP BY ")
 		m.GroupBy.WriteDialect(w)
 	}
@@ -1259,6 +1368,68 @@ func (m *SqlSelect) ColIndexes() map[string]int {
 	return cols
 }
 
+// Groupings the grouping sets of the group by, each the indexes of its
+// columns in GroupBy, of a plain group by the one set of all of them.
+func (m *SqlSelect) Groupings() [][]int {
+	if len(m.GroupingSets) > 0 {
+		return m.GroupingSets
+	}
+	set := make([]int, len(m.GroupBy))
+	for i := range set {
+		set[i] = i
+	}
+	return [][]int{set}
+}
+
+// GroupingSetsString the grouping sets of the group by, in the form of
+// GROUPING SETS ((a, b), (a), ()) whether they were a ROLLUP, CUBE etc.
+func (m *SqlSelect) GroupingSetsString() string {
+	w := expr.NewDefaultWriter()
+	m.writeGroupingSets(w)
+	return w.String()
+}
+func (m *SqlSelect) writeGroupingSets(w expr.DialectWriter) {
+	io.WriteString(w, "GROUPING SETS (")
+	for i, set := range m.GroupingSets {
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		io.WriteString(w, "(")
+		for j, idx := range set {
+			if j > 0 {
+				io.WriteString(w, ", ")
+			}
+			m.GroupBy[idx].Expr.WriteDialect(w)
+		}
+		io.WriteString(w, ")")
+	}
+	io.WriteString(w, ")")
+}
+
+// groupingSet the distinct column indexes of a grouping set in order.
+func groupingSet(set []int) []int {
+	return groupingSetFromMask(groupingSetMask(set))
+}
+
+// groupingSetMask a grouping set as the bits of its columns, which is
+// why there are at most MaxGroupingColumns.
+func groupingSetMask(set []int) uint64 {
+	mask := uint64(0)
+	for _, idx := range set {
+		mask |= 1 << uint(idx)
+	}
+	return mask
+}
+func groupingSetFromMask(mask uint64) []int {
+	set := make([]int, 0)
+	for idx := 0; idx < MaxGroupingColumns; idx++ {
+		if mask&(1<<uint(idx)) != 0 {
+			set = append(set, idx)
+		}
+	}
+	return set
+}
+
 func (m *SqlSelect) AddColumn(colArg Column) error {
 	col := &colArg
 	col.Index = len(m.Columns)
@@ -1270,6 +1441,10 @@ func (m *SqlSelect) AddColumn(colArg Column) error {
 	if col.As == "" && col.Expr == nil && !col.Star {
 		return fmt.Errorf("Must have *, Expression, or Identity to be a column %+v", col)
 	}
+	if !col.Agg && col.Expr != nil {
+		// an expression over aggregates   sum(price) / count(*)
+		col.Agg = len(expr.FindAggregates(col.Expr)) > 0
+	}
 	if col.Agg && !m.isAgg {
 		m.isAgg = true
 	}
@@ -1309,6 +1484,287 @@ func (m *SqlSelect) RewriteAsRawSelect() {
 	RewriteSelect(m)
 }
 
+func (m *SqlUnion) Keyword() lex.TokenType { return m.Op }
+func (m *SqlUnion) String() string {
+	w := NewSqlDialect()
+	m.writeDialectDepth(0, w)
+	return w.String()
+}
+func (m *SqlUnion) WriteDialect(w expr.DialectWriter) {
+	m.writeDialectDepth(0, w)
+}
+func (m *SqlUnion) writeDialectDepth(depth int, w expr.DialectWriter) {
+	writeCtes(m.Ctes, w)
+	writeUnionArm(m.Left, depth, w)
+	io.WriteString(w, " ")
+	io.WriteString(w, strings.ToUpper(m.Op.String()))
+	if m.All {
+		io.WriteString(w, " ALL")
+	}
+	io.WriteString(w, " ")
+	writeUnionArm(m.Right, depth, w)
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, " ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+	}
+	if m.Limit > 0 {
+		io.WriteString(w, fmt.Sprintf(" LIMIT %d", m.Limit))
+	}
+	if m.Offset > 0 {
+		io.WriteString(w, fmt.Sprintf(" OFFSET %d", m.Offset))
+	}
+}
+func writeUnionArm(arm SqlStatement, depth int, w expr.DialectWriter) {
+	switch st := arm.(type) {
+	case *SqlSelect:
+		st.writeDialectDepth(depth, w)
+	case *SqlUnion:
+		st.writeDialectDepth(depth, w)
+	}
+}
+
+// Selects the ordered list of select statements that make up this set operation.
+func (m *SqlUnion) Selects() []*SqlSelect {
+	sels := make([]*SqlSelect, 0, 2)
+	for _, arm := range []SqlStatement{m.Left, m.Right} {
+		switch st := arm.(type) {
+		case *SqlSelect:
+			sels = append(sels, st)
+		case *SqlUnion:
+			sels = append(sels, st.Selects()...)
+		}
+	}
+	return sels
+}
+
+// Columns of the result, which are named by the left-most select.
+func (m *SqlUnion) Columns() Columns {
+	sels := m.Selects()
+	if len(sels) == 0 {
+		return nil
+	}
+	return sels[0].Columns
+}
+func (m *SqlUnion) Equal(ss SqlStatement) bool {
+	s, ok := ss.(*SqlUnion)
+	if !ok {
+		return false
+	}
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if m.Raw != s.Raw {
+		return false
+	}
+	if m.Op != s.Op {
+		return false
+	}
+	if m.All != s.All {
+		return false
+	}
+	if m.Limit != s.Limit {
+		return false
+	}
+	if m.Offset != s.Offset {
+		return false
+	}
+	if !m.OrderBy.Equal(s.OrderBy) {
+		return false
+	}
+	if !unionArmEqual(m.Left, s.Left) {
+		return false
+	}
+	if !unionArmEqual(m.Right, s.Right) {
+		return false
+	}
+	if !ctesEqual(m.Ctes, s.Ctes) {
+		return false
+	}
+	return true
+}
+func unionArmEqual(a, b SqlStatement) bool {
+	switch st := a.(type) {
+	case *SqlSelect:
+		return st.Equal(b)
+	case *SqlUnion:
+		return st.Equal(b)
+	}
+	return a == nil && b == nil
+}
+func (m *SqlUnion) ToPbStatement() *SqlStatementPb {
+	if m.pb == nil {
+		m.pb = &SqlStatementPb{Union: SqlUnionToPb(m)}
+	}
+	return m.pb
+}
+func (m *SqlUnion) ToPB() *SqlUnionPb {
+	return m.ToPbStatement().Union
+}
+
+// SqlUnionToPb convert a set operation statement to its protobuf form
+func SqlUnionToPb(m *SqlUnion) *SqlUnionPb {
+	s := SqlUnionPb{}
+	s.Op = int32(m.Op)
+	s.All = m.All
+	s.Raw = m.Raw
+	s.Limit = int32(m.Limit)
+	s.Offset = int32(m.Offset)
+	s.Left = statementToPb(m.Left)
+	s.Right = statementToPb(m.Right)
+	if len(m.OrderBy) > 0 {
+		s.OrderBy = ColumnsToPb(m.OrderBy)
+	}
+	if len(m.Ctes) > 0 {
+		s.Ctes = ctesToPb(m.Ctes)
+	}
+	return &s
+}
+
+// SqlUnionFromPb take a protobuf set operation and convert to SqlUnion
+func SqlUnionFromPb(pb *SqlUnionPb) *SqlUnion {
+	su := SqlUnion{
+		Raw:    pb.GetRaw(),
+		Op:     lex.TokenType(pb.GetOp()),
+		All:    pb.GetAll(),
+		Limit:  int(pb.GetLimit()),
+		Offset: int(pb.GetOffset()),
+	}
+	if pb.Left != nil {
+		su.Left = statementFromPb(pb.Left)
+	}
+	if pb.Right != nil {
+		su.Right = statementFromPb(pb.Right)
+	}
+	if len(pb.OrderBy) > 0 {
+		su.OrderBy = ColumnsFromPb(pb.GetOrderBy())
+	}
+	if len(pb.Ctes) > 0 {
+		su.Ctes = ctesFromPb(pb.Ctes)
+	}
+	return &su
+}
+
+func (m *SqlCte) Keyword() lex.TokenType { return lex.TokenWith }
+func (m *SqlCte) String() string {
+	w := NewSqlDialect()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *SqlCte) WriteDialect(w expr.DialectWriter) {
+	w.WriteIdentity(m.Name)
+	if len(m.Columns) > 0 {
+		io.WriteString(w, " (")
+		for i, col := range m.Columns {
+			if i > 0 {
+				io.WriteString(w, ", ")
+			}
+			w.WriteIdentity(col)
+		}
+		io.WriteString(w, ")")
+	}
+	io.WriteString(w, " AS (")
+	writeUnionArm(m.Stmt, 0, w)
+	io.WriteString(w, ")")
+}
+
+// writeCtes write the WITH clause for the common table expressions
+// of a statement.
+func writeCtes(ctes []*SqlCte, w expr.DialectWriter) {
+	if len(ctes) == 0 {
+		return
+	}
+	io.WriteString(w, "WITH ")
+	if ctes[0].Recursive {
+		io.WriteString(w, "RECURSIVE ")
+	}
+	for i, cte := range ctes {
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		cte.WriteDialect(w)
+	}
+	io.WriteString(w, " ")
+}
+
+// Selects of the query of this common table expression.
+func (m *SqlCte) Selects() []*SqlSelect {
+	switch st := m.Stmt.(type) {
+	case *SqlSelect:
+		return []*SqlSelect{st}
+	case *SqlUnion:
+		return st.Selects()
+	}
+	return nil
+}
+func (m *SqlCte) Equal(c *SqlCte) bool {
+	if m == nil || c == nil {
+		return m == nil && c == nil
+	}
+	if m.Name != c.Name || m.Recursive != c.Recursive {
+		return false
+	}
+	if len(m.Columns) != len(c.Columns) {
+		return false
+	}
+	for i, col := range m.Columns {
+		if col != c.Columns[i] {
+			return false
+		}
+	}
+	return unionArmEqual(m.Stmt, c.Stmt)
+}
+func ctesEqual(a, b []*SqlCte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, cte := range a {
+		if !cte.Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SqlCteToPb convert a common table expression to its protobuf form
+func SqlCteToPb(m *SqlCte) *SqlCtePb {
+	return &SqlCtePb{
+		Name:      m.Name,
+		Columns:   m.Columns,
+		Recursive: m.Recursive,
+		Stmt:      statementToPb(m.Stmt),
+	}
+}
+
+// SqlCteFromPb convert a protobuf common table expression
+func SqlCteFromPb(pb *SqlCtePb) *SqlCte {
+	cte := &SqlCte{
+		Name:      pb.GetName(),
+		Columns:   pb.GetColumns(),
+		Recursive: pb.GetRecursive(),
+	}
+	if pb.Stmt != nil {
+		cte.Stmt = statementFromPb(pb.Stmt)
+	}
+	return cte
+}
+func ctesToPb(ctes []*SqlCte) []*SqlCtePb {
+	pbs := make([]*SqlCtePb, len(ctes))
+	for i, cte := range ctes {
+		pbs[i] = SqlCteToPb(cte)
+	}
+	return pbs
+}
+func ctesFromPb(pbs []*SqlCtePb) []*SqlCte {
+	ctes := make([]*SqlCte, len(pbs))
+	for i, pb := range pbs {
+		ctes[i] = SqlCteFromPb(pb)
+	}
+	return ctes
+}
+
 func (m *SqlSource) IsLiteral() bool        { return len(m.Name) == 0 }
 func (m *SqlSource) Keyword() lex.TokenType { return m.Op }
 func (m *SqlSource) SourceName() string {
@@ -1339,6 +1795,17 @@ func (m *SqlSource) WriteDialect(w expr.DialectWriter) {
 func (m *SqlSource) writeDialectDepth(depth int, w expr.DialectWriter) {
 
 	if int(m.Op) == 0 && int(m.LeftOrRight) == 0 && int(m.JoinType) == 0 {
+		if m.SubQuery != nil {
+			//  (SELECT ...) AS x
+			io.WriteString(w, "(")
+			m.SubQuery.writeDialectDepth(depth+1, w)
+			io.WriteString(w, ")")
+			if m.Alias != "" {
+				io.WriteString(w, " AS ")
+				w.WriteIdentity(m.Alias)
+			}
+			return
+		}
 		if m.Alias != "" {
 			w.WriteIdentity(m.Name)
 			io.WriteString(w, " AS ")
@@ -1357,6 +1824,11 @@ func (m *SqlSource) writeDialectDepth(depth int, w expr.DialectWriter) {
 
 	//   Jointype                Op
 	//  INNER JOIN orders AS o 	ON
+	//  LEFT OUTER JOIN orders AS o 	ON
+	if int(m.LeftOrRight) != 0 {
+		io.WriteString(w, strings.ToTitle(m.LeftOrRight.String())) // left/right/full
+		io.WriteString(w, " ")
+	}
 	if int(m.JoinType) != 0 {
 		io.WriteString(w, strings.ToTitle(m.JoinType.String())) // inner/outer
 		io.WriteString(w, " ")
@@ -1382,6 +1854,10 @@ func (m *SqlSource) writeDialectDepth(depth int, w expr.DialectWriter) {
 		w.WriteIdentity(m.Alias)
 	}
 
+	if int(m.Op) == 0 {
+		// CROSS JOIN, no join expression
+		return
+	}
 	io.WriteString(w, " ")
 	io.WriteString(w, strings.ToTitle(m.Op.String()))
 
@@ -1969,6 +2445,14 @@ func (m *SqlDrop) FingerPrint(r rune) string         { return m.String() }
 func (m *SqlDrop) String() string                    { return fmt.Sprintf("DROP %s %v", m.Tok.T, m.Identity) }
 func (m *SqlDrop) WriteDialect(w expr.DialectWriter) {}
 
+func (m *SqlAnalyze) Keyword() lex.TokenType    { return lex.TokenAnalyze }
+func (m *SqlAnalyze) FingerPrint(r rune) string { return m.String() }
+func (m *SqlAnalyze) String() string            { return fmt.Sprintf("ANALYZE TABLE %s", m.Identity) }
+func (m *SqlAnalyze) WriteDialect(w expr.DialectWriter) {
+	io.WriteString(w, "ANALYZE TABLE ")
+	w.WriteIdentity(m.Identity)
+}
+
 func (m *SqlAlter) Keyword() lex.TokenType            { return lex.TokenAlter }
 func (m *SqlAlter) FingerPrint(r rune) string         { return m.String() }
 func (m *SqlAlter) String() string                    { return fmt.Sprintf("not-implemented") }
@@ -1999,6 +2483,17 @@ func statementFromPb(s *SqlStatementPb) SqlStatement {
 	case s.Source != nil:
 		var ss *SqlSource
 		return ss.FromPB(s.Source)
+	case s.Union != nil:
+		return SqlUnionFromPb(s.Union)
+	}
+	return nil
+}
+func statementToPb(stmt SqlStatement) *SqlStatementPb {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return &SqlStatementPb{Select: SqlSelectToPb(st)}
+	case *SqlUnion:
+		return &SqlStatementPb{Union: SqlUnionToPb(st)}
 	}
 	return nil
 }
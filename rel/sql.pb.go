@@ -8,16 +8,21 @@
 	It is generated from these files:
 		sql.proto
 
-	It has these top-level messages:
-		SqlStatementPb
-		SqlSelectPb
-		SqlSourcePb
-		SqlWherePb
-		ProjectionPb
-		ResultColumnPb
-		KvInt
-		ColumnPb
-		CommandColumnPb
+	sql.proto
+
+It has these top-level messages:
+
+	SqlStatementPb
+	SqlSelectPb
+	SqlSourcePb
+	SqlWherePb
+	ProjectionPb
+	ResultColumnPb
+	KvInt
+	ColumnPb
+	CommandColumnPb
+	SqlUnionPb
+	SqlCtePb
 */
 package rel
 
@@ -44,6 +49,7 @@ type SqlStatementPb struct {
 	Select           *SqlSelectPb  `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
 	Source           *SqlSourcePb  `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
 	Projection       *ProjectionPb `protobuf:"bytes,4,opt,name=projection" json:"projection,omitempty"`
+	Union            *SqlUnionPb   `protobuf:"bytes,5,opt,name=union" json:"union,omitempty"`
 	XXX_unrecognized []byte        `json:"-"`
 }
 
@@ -73,6 +79,13 @@ func (m *SqlStatementPb) GetProjection() *ProjectionPb {
 	return nil
 }
 
+func (m *SqlStatementPb) GetUnion() *SqlUnionPb {
+	if m != nil {
+		return m.Union
+	}
+	return nil
+}
+
 type SqlSelectPb struct {
 	Db               string         `protobuf:"bytes,1,req,name=db" json:"db"`
 	Raw              string         `protobuf:"bytes,2,req,name=raw" json:"raw"`
@@ -93,6 +106,8 @@ type SqlSelectPb struct {
 	Finalized        bool           `protobuf:"varint,17,req,name=finalized" json:"finalized"`
 	Schemaqry        bool           `protobuf:"varint,18,req,name=schemaqry" json:"schemaqry"`
 	With             []byte         `protobuf:"bytes,19,opt,name=with" json:"with,omitempty"`
+	Ctes             []*SqlCtePb    `protobuf:"bytes,20,rep,name=ctes" json:"ctes,omitempty"`
+	GroupingSets     []uint64       `protobuf:"varint,21,rep,name=groupingSets" json:"groupingSets,omitempty"`
 	XXX_unrecognized []byte         `json:"-"`
 }
 
@@ -234,6 +249,20 @@ func (m *SqlSelectPb) GetWith() []byte {
 	return nil
 }
 
+func (m *SqlSelectPb) GetCtes() []*SqlCtePb {
+	if m != nil {
+		return m.Ctes
+	}
+	return nil
+}
+
+func (m *SqlSelectPb) GetGroupingSets() []uint64 {
+	if m != nil {
+		return m.GroupingSets
+	}
+	return nil
+}
+
 type SqlSourcePb struct {
 	Final            bool           `protobuf:"varint,1,opt,name=final" json:"final"`
 	AliasInner       *string        `protobuf:"bytes,2,opt,name=aliasInner" json:"aliasInner,omitempty"`
@@ -689,6 +718,130 @@ func (m *CommandColumnPb) GetName() string {
 	return ""
 }
 
+// Set operation (UNION, INTERSECT, EXCEPT) of two statements
+type SqlUnionPb struct {
+	Op               int32           `protobuf:"varint,1,req,name=op" json:"op"`
+	All              bool            `protobuf:"varint,2,req,name=all" json:"all"`
+	Raw              string          `protobuf:"bytes,3,req,name=raw" json:"raw"`
+	Left             *SqlStatementPb `protobuf:"bytes,4,opt,name=left" json:"left,omitempty"`
+	Right            *SqlStatementPb `protobuf:"bytes,5,opt,name=right" json:"right,omitempty"`
+	OrderBy          []*ColumnPb     `protobuf:"bytes,6,rep,name=orderBy" json:"orderBy,omitempty"`
+	Limit            int32           `protobuf:"varint,7,opt,name=limit" json:"limit"`
+	Offset           int32           `protobuf:"varint,8,opt,name=offset" json:"offset"`
+	Ctes             []*SqlCtePb     `protobuf:"bytes,9,rep,name=ctes" json:"ctes,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlUnionPb) Reset()                    { *m = SqlUnionPb{} }
+func (m *SqlUnionPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlUnionPb) ProtoMessage()               {}
+func (*SqlUnionPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{9} }
+
+func (m *SqlUnionPb) GetOp() int32 {
+	if m != nil {
+		return m.Op
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+func (m *SqlUnionPb) GetRaw() string {
+	if m != nil {
+		return m.Raw
+	}
+	return ""
+}
+
+func (m *SqlUnionPb) GetLeft() *SqlStatementPb {
+	if m != nil {
+		return m.Left
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetRight() *SqlStatementPb {
+	if m != nil {
+		return m.Right
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetOrderBy() []*ColumnPb {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetCtes() []*SqlCtePb {
+	if m != nil {
+		return m.Ctes
+	}
+	return nil
+}
+
+// Common table expression, a named sub-query statement
+type SqlCtePb struct {
+	Name             string          `protobuf:"bytes,1,req,name=name" json:"name"`
+	Columns          []string        `protobuf:"bytes,2,rep,name=columns" json:"columns,omitempty"`
+	Recursive        bool            `protobuf:"varint,3,req,name=recursive" json:"recursive"`
+	Stmt             *SqlStatementPb `protobuf:"bytes,4,opt,name=stmt" json:"stmt,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlCtePb) Reset()                    { *m = SqlCtePb{} }
+func (m *SqlCtePb) String() string            { return proto.CompactTextString(m) }
+func (*SqlCtePb) ProtoMessage()               {}
+func (*SqlCtePb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{10} }
+
+func (m *SqlCtePb) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SqlCtePb) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *SqlCtePb) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *SqlCtePb) GetStmt() *SqlStatementPb {
+	if m != nil {
+		return m.Stmt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SqlStatementPb)(nil), "rel.SqlStatementPb")
 	proto.RegisterType((*SqlSelectPb)(nil), "rel.SqlSelectPb")
@@ -699,6 +852,8 @@ func init() {
 	proto.RegisterType((*KvInt)(nil), "rel.KvInt")
 	proto.RegisterType((*ColumnPb)(nil), "rel.ColumnPb")
 	proto.RegisterType((*CommandColumnPb)(nil), "rel.CommandColumnPb")
+	proto.RegisterType((*SqlUnionPb)(nil), "rel.SqlUnionPb")
+	proto.RegisterType((*SqlCtePb)(nil), "rel.SqlCtePb")
 }
 func (m *SqlStatementPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -745,6 +900,16 @@ func (m *SqlStatementPb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.Union != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Union.Size()))
+		n4, err := m.Union.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -924,6 +1089,29 @@ func (m *SqlSelectPb) MarshalTo(data []byte) (int, error) {
 		i = encodeVarintSql(data, i, uint64(len(m.With)))
 		i += copy(data[i:], m.With)
 	}
+	if len(m.Ctes) > 0 {
+		for _, msg := range m.Ctes {
+			data[i] = 0xa2
+			i++
+			data[i] = 0x1
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.GroupingSets) > 0 {
+		for _, num := range m.GroupingSets {
+			data[i] = 0xa8
+			i++
+			data[i] = 0x1
+			i++
+			i = encodeVarintSql(data, i, uint64(num))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1420,6 +1608,150 @@ func (m *CommandColumnPb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *SqlUnionPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlUnionPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0x8
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Op))
+	data[i] = 0x10
+	i++
+	if m.All {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	data[i] = 0x1a
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Raw)))
+	i += copy(data[i:], m.Raw)
+	if m.Left != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Left.Size()))
+		n1, err := m.Left.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Right != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Right.Size()))
+		n2, err := m.Right.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.OrderBy) > 0 {
+		for _, msg := range m.OrderBy {
+			data[i] = 0x32
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	data[i] = 0x38
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Limit))
+	data[i] = 0x40
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Offset))
+	if len(m.Ctes) > 0 {
+		for _, msg := range m.Ctes {
+			data[i] = 0x4a
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SqlCtePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlCtePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Name)))
+	i += copy(data[i:], m.Name)
+	if len(m.Columns) > 0 {
+		for _, s := range m.Columns {
+			data[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
+	data[i] = 0x18
+	i++
+	if m.Recursive {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if m.Stmt != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Stmt.Size()))
+		n1, err := m.Stmt.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func encodeFixed64Sql(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	data[offset+1] = uint8(v >> 8)
@@ -1462,6 +1794,10 @@ func (m *SqlStatementPb) Size() (n int) {
 		l = m.Projection.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
+	if m.Union != nil {
+		l = m.Union.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1530,6 +1866,17 @@ func (m *SqlSelectPb) Size() (n int) {
 		l = len(m.With)
 		n += 2 + l + sovSql(uint64(l))
 	}
+	if len(m.Ctes) > 0 {
+		for _, e := range m.Ctes {
+			l = e.Size()
+			n += 2 + l + sovSql(uint64(l))
+		}
+	}
+	if len(m.GroupingSets) > 0 {
+		for _, e := range m.GroupingSets {
+			n += 2 + sovSql(uint64(e))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1741,16 +2088,73 @@ func (m *CommandColumnPb) Size() (n int) {
 	return n
 }
 
-func sovSql(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
-}
+func (m *SqlUnionPb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovSql(uint64(m.Op))
+	n += 2
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	if m.Left != nil {
+		l = m.Left.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Right != nil {
+		l = m.Right.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	n += 1 + sovSql(uint64(m.Limit))
+	n += 1 + sovSql(uint64(m.Offset))
+	if len(m.Ctes) > 0 {
+		for _, e := range m.Ctes {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlCtePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	if len(m.Columns) > 0 {
+		for _, s := range m.Columns {
+			l = len(s)
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	n += 2
+	if m.Stmt != nil {
+		l = m.Stmt.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovSql(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
 func sozSql(x uint64) (n int) {
 	return sovSql(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
@@ -1882,6 +2286,39 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Union", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Union == nil {
+				m.Union = &SqlUnionPb{}
+			}
+			if err := m.Union.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -2451,6 +2888,57 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				m.With = []byte{}
 			}
 			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ctes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ctes = append(m.Ctes, &SqlCtePb{})
+			if err := m.Ctes[len(m.Ctes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupingSets", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.GroupingSets = append(m.GroupingSets, v)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -4216,6 +4704,475 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *SqlUnionPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlUnionPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlUnionPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Op |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field All", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.All = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Raw = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000004)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Left == nil {
+				m.Left = &SqlStatementPb{}
+			}
+			if err := m.Left.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Right == nil {
+				m.Right = &SqlStatementPb{}
+			}
+			if err := m.Right.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Limit |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Offset |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ctes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ctes = append(m.Ctes, &SqlCtePb{})
+			if err := m.Ctes[len(m.Ctes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SqlCtePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlCtePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlCtePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Columns = append(m.Columns, string(data[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Recursive = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stmt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stmt == nil {
+				m.Stmt = &SqlStatementPb{}
+			}
+			if err := m.Stmt.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipSql(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -4322,72 +5279,80 @@ var (
 )
 
 var fileDescriptorSql = []byte{
-	// 1067 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x0e, 0x25, 0x51, 0x96, 0x56, 0x8e, 0xed, 0x6c, 0x82, 0x62, 0x61, 0x14, 0xae, 0x41, 0x14,
-	0x81, 0xd1, 0x34, 0x52, 0x91, 0x1e, 0x7a, 0x8e, 0x83, 0xb6, 0x30, 0x0a, 0xa4, 0x8e, 0x5c, 0xa0,
-	0x67, 0x4a, 0x5c, 0x51, 0x8c, 0x29, 0xae, 0xbc, 0x24, 0xe5, 0x38, 0x4f, 0xd2, 0x4b, 0x81, 0x5e,
-	0x7b, 0xeb, 0x33, 0xf4, 0xe4, 0x63, 0x9f, 0xa0, 0xe8, 0x0f, 0xfa, 0x1e, 0x9d, 0x99, 0x25, 0x97,
-	0x6b, 0x47, 0x72, 0x72, 0x10, 0xc0, 0xfd, 0xe6, 0xdb, 0xdd, 0x99, 0xd9, 0x6f, 0x66, 0xc4, 0xfa,
-	0xf9, 0x45, 0x3a, 0x5c, 0x6a, 0x55, 0x28, 0xde, 0xd6, 0x32, 0xdd, 0x7f, 0x12, 0x27, 0xc5, 0xbc,
-	0x9c, 0x0c, 0xa7, 0x6a, 0x31, 0x0a, 0x75, 0x18, 0x45, 0x2a, 0x1b, 0x5d, 0xa4, 0x13, 0x9d, 0x44,
-	0xb1, 0x1c, 0xc9, 0x37, 0x4b, 0x3d, 0xca, 0x54, 0x24, 0xcd, 0x8e, 0xfd, 0xa7, 0x0e, 0x39, 0x56,
-	0xb1, 0x1a, 0x11, 0x3c, 0x29, 0x67, 0xb4, 0xa2, 0x05, 0x7d, 0x19, 0x7a, 0xf0, 0xab, 0xc7, 0x76,
-	0xce, 0x2e, 0xd2, 0xb3, 0x22, 0x2c, 0xe4, 0x42, 0x66, 0xc5, 0xe9, 0x84, 0x0f, 0x59, 0x37, 0x97,
-	0xa9, 0x9c, 0x16, 0xc2, 0x3b, 0xf4, 0x8e, 0x06, 0xcf, 0xf6, 0x86, 0xe0, 0xc4, 0x10, 0x49, 0x84,
-	0x9e, 0x4e, 0x8e, 0x3b, 0xd7, 0x7f, 0x7e, 0xe2, 0x8d, 0x2b, 0x16, 0xf1, 0x55, 0xa9, 0xa7, 0x52,
-	0xb4, 0x6e, 0xf1, 0x09, 0x75, 0xf8, 0xb4, 0xe6, 0x5f, 0x31, 0x06, 0x77, 0xbf, 0x86, 0xad, 0x89,
-	0xca, 0x44, 0x87, 0xf6, 0x3c, 0xa0, 0x3d, 0xa7, 0x16, 0xb6, 0x9b, 0x1c, 0x6a, 0xf0, 0x9b, 0xcf,
-	0x06, 0x8e, 0x1b, 0xfc, 0x11, 0x6b, 0x45, 0x13, 0x70, 0xb2, 0x75, 0xd4, 0x27, 0xf6, 0xbd, 0x31,
-	0xac, 0xf9, 0x47, 0xac, 0xad, 0xc3, 0x4b, 0xf0, 0xa5, 0x81, 0x11, 0xe0, 0x82, 0x75, 0xf2, 0x22,
-	0xd4, 0xa2, 0x0d, 0x86, 0x5e, 0x65, 0x20, 0x84, 0x1f, 0xb2, 0x5e, 0x94, 0xe4, 0x45, 0x92, 0x41,
-	0xc8, 0x1d, 0xc7, 0x6a, 0x51, 0xfe, 0x94, 0x6d, 0x4d, 0x55, 0x5a, 0x2e, 0xb2, 0x5c, 0xf8, 0x87,
-	0x6d, 0xf0, 0xf7, 0x3e, 0xf9, 0xfb, 0x82, 0x30, 0xeb, 0x6b, 0xcd, 0xe1, 0x9f, 0xb1, 0xce, 0x4c,
-	0xab, 0x85, 0xe8, 0x12, 0x77, 0x53, 0x3e, 0x88, 0x83, 0x6e, 0x25, 0x59, 0xa1, 0xc4, 0x16, 0xe4,
-	0xa1, 0x5f, 0x5b, 0x10, 0xe1, 0x4f, 0x98, 0x7f, 0x39, 0x97, 0x5a, 0x8a, 0x1e, 0xa5, 0x68, 0xb7,
-	0x3e, 0xe6, 0x47, 0x04, 0xed, 0x29, 0x86, 0x03, 0x57, 0x76, 0xe7, 0xe1, 0x2a, 0xc9, 0x62, 0xd1,
-	0x27, 0xf6, 0xf6, 0x10, 0x85, 0x31, 0x7c, 0x09, 0xc2, 0x68, 0x1e, 0xc0, 0x30, 0x30, 0x1a, 0xa5,
-	0x23, 0xa9, 0x8f, 0xaf, 0x04, 0xbb, 0x23, 0x9a, 0x8a, 0x83, 0xf4, 0x58, 0xab, 0x72, 0x09, 0xf4,
-	0xc1, 0x1d, 0xf4, 0x8a, 0xc3, 0xf7, 0x99, 0x9f, 0x26, 0x8b, 0xa4, 0x10, 0xdb, 0xe0, 0x88, 0x5f,
-	0xa5, 0xd2, 0x40, 0xfc, 0x63, 0xd6, 0x55, 0xb3, 0x59, 0x2e, 0x0b, 0x71, 0xdf, 0x31, 0x56, 0x18,
-	0xee, 0x0c, 0xd3, 0x24, 0xcc, 0xc5, 0x8e, 0x93, 0x0b, 0x03, 0xdd, 0x12, 0xcd, 0xee, 0x07, 0x8b,
-	0x06, 0x0f, 0x4d, 0xf2, 0xe7, 0x71, 0x2c, 0xf6, 0x9c, 0x97, 0x35, 0x10, 0x0f, 0x58, 0x7f, 0x96,
-	0x64, 0x70, 0xc1, 0x5b, 0x19, 0x89, 0x07, 0x8e, 0xbd, 0x81, 0x91, 0x93, 0x4f, 0xe7, 0x72, 0x11,
-	0x5e, 0xe8, 0x2b, 0xc1, 0x5d, 0x8e, 0x85, 0xf1, 0x0d, 0x2f, 0xa1, 0xea, 0xc4, 0x43, 0x70, 0x6b,
-	0xbb, 0x7e, 0x43, 0x44, 0x82, 0xdf, 0x3b, 0x46, 0xb2, 0xd5, 0xcb, 0xa3, 0x37, 0x74, 0x34, 0x95,
-	0x96, 0xf5, 0x86, 0x20, 0xfe, 0x29, 0x63, 0x14, 0xeb, 0x49, 0x96, 0x49, 0x4d, 0xb5, 0x54, 0xe7,
-	0xc0, 0xc1, 0x5d, 0x29, 0xb6, 0x3f, 0x40, 0x8a, 0x9f, 0xb3, 0x1e, 0x7c, 0x9e, 0x64, 0x91, 0x7c,
-	0x03, 0xda, 0x46, 0x3e, 0x23, 0xfe, 0x77, 0xab, 0x93, 0xac, 0xa8, 0x75, 0x5e, 0x33, 0xf8, 0x17,
-	0xac, 0xff, 0x5a, 0x25, 0x19, 0xaa, 0xa6, 0x56, 0xfa, 0x3a, 0x21, 0x35, 0x24, 0xa7, 0xf8, 0xbb,
-	0xef, 0x69, 0x16, 0xa6, 0xf8, 0xab, 0xea, 0x6c, 0xd4, 0xde, 0x54, 0x67, 0x16, 0x2e, 0x8c, 0xd6,
-	0x6b, 0x03, 0x21, 0x8d, 0x2a, 0xfa, 0x8e, 0xa9, 0x52, 0x05, 0x74, 0x00, 0xb5, 0x04, 0x11, 0xb7,
-	0xac, 0x96, 0x60, 0xcd, 0x1f, 0xb3, 0x41, 0x2a, 0x67, 0xc5, 0xf7, 0x7a, 0x9c, 0xc4, 0xf3, 0x02,
-	0x44, 0xdb, 0x98, 0x5d, 0x03, 0xd6, 0x3d, 0x06, 0xf2, 0xc3, 0xd5, 0x52, 0x82, 0x58, 0x1b, 0x92,
-	0x45, 0x21, 0x3a, 0xfa, 0xfe, 0x1a, 0x32, 0x40, 0x8a, 0x5d, 0x9f, 0x0e, 0xcb, 0xe1, 0xcf, 0x58,
-	0x2f, 0x2f, 0x27, 0xaf, 0x4a, 0x09, 0x5a, 0xd9, 0xb9, 0x33, 0x1f, 0x96, 0x87, 0x5e, 0xe4, 0x52,
-	0x9e, 0x87, 0x93, 0x54, 0x92, 0xae, 0x6d, 0xf7, 0xa9, 0xd1, 0xe0, 0x2d, 0x63, 0x4d, 0xd9, 0x57,
-	0x31, 0x7b, 0xb7, 0x62, 0xde, 0xdc, 0x84, 0xd7, 0xbf, 0xc3, 0x63, 0xd6, 0xa1, 0xa8, 0xda, 0x1b,
-	0xa3, 0xea, 0x20, 0x14, 0xfc, 0xec, 0xb1, 0x6d, 0xb7, 0xc2, 0x6e, 0x34, 0x4b, 0x6f, 0x6d, 0xb3,
-	0xb4, 0x1a, 0x6f, 0xb9, 0x15, 0x67, 0x34, 0xbe, 0x4f, 0x72, 0x7c, 0x09, 0xef, 0x6a, 0xe4, 0xdb,
-	0x1f, 0xdb, 0x35, 0xff, 0xb2, 0x51, 0xb6, 0x51, 0xea, 0x43, 0x8a, 0x61, 0x2c, 0xf3, 0x32, 0x2d,
-	0x36, 0xe8, 0x3b, 0xf8, 0x0f, 0xe6, 0xd7, 0x4d, 0xc6, 0xba, 0x1a, 0xf3, 0xea, 0xfb, 0x6b, 0x99,
-	0xb9, 0xd3, 0xc1, 0xc8, 0x0c, 0x5a, 0x13, 0x9c, 0x79, 0xaa, 0x72, 0x1a, 0x10, 0xb6, 0x35, 0x19,
-	0x0c, 0x7a, 0x71, 0xd7, 0xdc, 0x58, 0xcd, 0xab, 0xb5, 0x45, 0x57, 0x51, 0xec, 0xa4, 0xf1, 0x9d,
-	0xfb, 0xcd, 0xa4, 0x81, 0xb7, 0x03, 0x21, 0x77, 0xdd, 0x89, 0x05, 0x2a, 0x86, 0x16, 0xb3, 0x0a,
-	0xd3, 0x52, 0x92, 0x10, 0xb7, 0x9c, 0xdb, 0x1b, 0x38, 0x18, 0x31, 0x9f, 0x4a, 0x96, 0x73, 0xe6,
-	0x9d, 0xdf, 0x98, 0x79, 0xde, 0x39, 0x62, 0x2b, 0x0a, 0xa9, 0xde, 0xe8, 0xad, 0x82, 0x5f, 0x3a,
-	0xac, 0x67, 0x53, 0x02, 0x15, 0x61, 0xde, 0xfd, 0x55, 0xa9, 0x0a, 0x49, 0x89, 0xa9, 0xfb, 0x94,
-	0x6b, 0x40, 0x5e, 0x98, 0xd3, 0xe7, 0xf1, 0x55, 0x61, 0xa4, 0x64, 0x79, 0x8e, 0x01, 0x5b, 0x95,
-	0xd2, 0x49, 0x8c, 0x29, 0x7d, 0x9e, 0x93, 0x86, 0x6c, 0xab, 0x6a, 0x70, 0xcc, 0x03, 0x96, 0x1b,
-	0xa5, 0xcc, 0x8e, 0x36, 0x44, 0xf0, 0x89, 0x34, 0xd5, 0xa6, 0xef, 0x76, 0x7a, 0x82, 0xd0, 0x87,
-	0x65, 0xa8, 0xe1, 0xaf, 0x88, 0x69, 0x5a, 0x5d, 0x67, 0x50, 0xb8, 0x06, 0x6a, 0xec, 0xc4, 0xd8,
-	0x72, 0xe7, 0x0c, 0x41, 0x4d, 0xbc, 0xe6, 0x8c, 0x9e, 0x7b, 0x86, 0x63, 0x68, 0x78, 0xdf, 0x24,
-	0x32, 0x8d, 0x9c, 0x0e, 0x63, 0xf3, 0x42, 0x86, 0xea, 0xdd, 0x06, 0x4e, 0x03, 0xc2, 0x77, 0x3b,
-	0x40, 0xc1, 0x2e, 0xf0, 0x5f, 0x13, 0xcd, 0xba, 0x7e, 0xa3, 0x4d, 0x02, 0xd1, 0x43, 0x9a, 0xa1,
-	0xd4, 0x3a, 0x6c, 0x94, 0x04, 0x59, 0x8d, 0xec, 0xbc, 0xa3, 0x11, 0xe8, 0x90, 0x21, 0x8c, 0x2b,
-	0xb7, 0x15, 0x20, 0x60, 0x2b, 0x76, 0xef, 0xee, 0x8a, 0xe5, 0x47, 0xcc, 0xff, 0xb6, 0x0c, 0x35,
-	0x0e, 0xb4, 0x4d, 0x44, 0x3f, 0x46, 0x42, 0x70, 0xc6, 0x76, 0x5f, 0x80, 0xab, 0x61, 0x16, 0x39,
-	0x42, 0x31, 0x97, 0x78, 0xef, 0xb9, 0x64, 0x63, 0x1d, 0x1d, 0x3f, 0xba, 0xfe, 0xfb, 0xc0, 0xbb,
-	0xfe, 0xe7, 0xc0, 0xfb, 0x03, 0x7e, 0x7f, 0xc1, 0xef, 0xa7, 0x7f, 0x0f, 0xee, 0xfd, 0x1f, 0x00,
-	0x00, 0xff, 0xff, 0xa8, 0x40, 0xb5, 0x23, 0xd3, 0x0a, 0x00, 0x00,
+	// 1197 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x4f, 0x6f, 0x23, 0xc5,
+	0x13, 0xdd, 0x1e, 0xcf, 0x38, 0x76, 0xdb, 0x9b, 0xec, 0xf6, 0xae, 0x56, 0xad, 0xe8, 0x27, 0xff,
+	0x2c, 0x0b, 0x2d, 0xd6, 0x86, 0xd8, 0x28, 0x1c, 0x38, 0x6f, 0x22, 0x40, 0x11, 0xd2, 0x92, 0x75,
+	0x40, 0x9c, 0xc7, 0x9e, 0xf6, 0x78, 0x36, 0x33, 0xd3, 0x4e, 0x4f, 0x8f, 0x13, 0xef, 0xb7, 0xe0,
+	0xc6, 0x05, 0x89, 0xcf, 0x82, 0x38, 0xe4, 0xc8, 0x09, 0x89, 0x0b, 0x82, 0x20, 0xbe, 0x07, 0xea,
+	0x9a, 0x7f, 0xe5, 0xac, 0xed, 0xe4, 0xe6, 0x79, 0xf5, 0xda, 0xdd, 0x5d, 0xfd, 0xea, 0x55, 0xd1,
+	0x66, 0x72, 0x19, 0x0e, 0xe6, 0x4a, 0x6a, 0xc9, 0x6a, 0x4a, 0x84, 0xfb, 0x07, 0x7e, 0xa0, 0x67,
+	0xe9, 0x78, 0x30, 0x91, 0xd1, 0xd0, 0x55, 0xae, 0xe7, 0xc9, 0x78, 0x78, 0x19, 0x8e, 0x55, 0xe0,
+	0xf9, 0x62, 0x28, 0xae, 0xe7, 0x6a, 0x18, 0x4b, 0x4f, 0x64, 0x2b, 0xf6, 0x0f, 0x11, 0xd9, 0x97,
+	0xbe, 0x1c, 0x02, 0x3c, 0x4e, 0xa7, 0xf0, 0x05, 0x1f, 0xf0, 0x2b, 0xa3, 0xf7, 0x7e, 0x27, 0x74,
+	0xf7, 0xfc, 0x32, 0x3c, 0xd7, 0xae, 0x16, 0x91, 0x88, 0xf5, 0xd9, 0x98, 0x0d, 0x68, 0x3d, 0x11,
+	0xa1, 0x98, 0x68, 0x4e, 0xba, 0xa4, 0xdf, 0x3a, 0x7a, 0x32, 0x50, 0x22, 0x1c, 0x18, 0x12, 0xa0,
+	0x67, 0xe3, 0x63, 0xfb, 0xe6, 0xcf, 0xff, 0x93, 0x51, 0xce, 0x02, 0xbe, 0x4c, 0xd5, 0x44, 0x70,
+	0xeb, 0x0e, 0x1f, 0x50, 0xc4, 0x87, 0x6f, 0xf6, 0x39, 0xa5, 0x73, 0x25, 0xdf, 0x89, 0x89, 0x0e,
+	0x64, 0xcc, 0x6d, 0x58, 0xf3, 0x14, 0xd6, 0x9c, 0x95, 0x70, 0xb9, 0x08, 0x51, 0xd9, 0x01, 0x75,
+	0xd2, 0xd8, 0xac, 0x71, 0x60, 0xcd, 0x5e, 0xb1, 0xcf, 0x77, 0x31, 0x5e, 0x91, 0x71, 0x7a, 0x7f,
+	0x38, 0xb4, 0x85, 0xce, 0xcc, 0x9e, 0x53, 0xcb, 0x1b, 0x73, 0xd2, 0xb5, 0xfa, 0x4d, 0x20, 0x3e,
+	0x1a, 0x59, 0xde, 0x98, 0xbd, 0xa0, 0x35, 0xe5, 0x5e, 0x71, 0x0b, 0xc1, 0x06, 0x60, 0x9c, 0xda,
+	0x89, 0x76, 0x15, 0xaf, 0x75, 0xad, 0x7e, 0x23, 0x0f, 0x00, 0xc2, 0xba, 0xb4, 0xe1, 0x05, 0x89,
+	0x0e, 0xe2, 0x89, 0xe6, 0x36, 0x8a, 0x96, 0x28, 0x3b, 0xa4, 0x3b, 0x13, 0x19, 0xa6, 0x51, 0x9c,
+	0x70, 0xa7, 0x5b, 0xeb, 0xb7, 0x8e, 0x1e, 0xc3, 0x41, 0x4f, 0x00, 0x2b, 0x8f, 0x59, 0x70, 0xd8,
+	0x2b, 0x6a, 0x4f, 0x95, 0x8c, 0x78, 0xbd, 0x5b, 0xdb, 0x92, 0x3c, 0xe0, 0x98, 0x63, 0x05, 0xb1,
+	0x96, 0x7c, 0xa7, 0x4b, 0xf2, 0xf3, 0x92, 0x11, 0x20, 0x26, 0x37, 0x57, 0x33, 0xa1, 0x04, 0x6f,
+	0xac, 0xe6, 0xe6, 0x7b, 0x03, 0x56, 0xb9, 0x01, 0x0e, 0x7b, 0x45, 0xeb, 0x33, 0x77, 0x11, 0xc4,
+	0x3e, 0x6f, 0x02, 0xbb, 0x3d, 0x30, 0x2a, 0x1a, 0xbc, 0x91, 0x1e, 0x7a, 0xad, 0x8c, 0x61, 0x6e,
+	0x23, 0x95, 0x27, 0xd4, 0xf1, 0x92, 0xd3, 0x2d, 0xb7, 0xc9, 0x39, 0x86, 0xee, 0x2b, 0x99, 0xce,
+	0x8f, 0x97, 0xbc, 0xb5, 0x85, 0x9e, 0x73, 0xd8, 0x3e, 0x75, 0xc2, 0x20, 0x0a, 0x34, 0x6f, 0x77,
+	0x49, 0xdf, 0xc9, 0x53, 0x99, 0x41, 0xec, 0x7f, 0xb4, 0x2e, 0xa7, 0xd3, 0x44, 0x68, 0xfe, 0x18,
+	0x05, 0x73, 0xcc, 0xac, 0x74, 0xc3, 0xc0, 0x4d, 0xf8, 0x2e, 0xca, 0x45, 0x06, 0xdd, 0x51, 0xd8,
+	0xde, 0xc3, 0x15, 0xb6, 0x4f, 0x9d, 0x20, 0x79, 0xed, 0xfb, 0xfc, 0x09, 0x7a, 0xd9, 0x0c, 0x62,
+	0x3d, 0xda, 0x9c, 0x06, 0xb1, 0x1b, 0x06, 0xef, 0x85, 0xc7, 0x9f, 0xa2, 0x78, 0x05, 0x1b, 0x4e,
+	0x32, 0x99, 0x89, 0xc8, 0xbd, 0x54, 0x4b, 0xce, 0x30, 0xa7, 0x84, 0xcd, 0x1b, 0x5e, 0x05, 0x7a,
+	0xc6, 0x9f, 0x75, 0x49, 0xbf, 0x5d, 0xbc, 0xa1, 0x41, 0xd8, 0xc7, 0xd4, 0x9e, 0x68, 0x91, 0xf0,
+	0xe7, 0x28, 0x71, 0xe7, 0x97, 0xe1, 0x89, 0x46, 0x32, 0x30, 0x84, 0xde, 0x2f, 0x36, 0x6d, 0x21,
+	0x89, 0x98, 0x63, 0xc3, 0x19, 0xa0, 0x60, 0xcb, 0x63, 0x03, 0xc4, 0x3e, 0xa2, 0x14, 0x92, 0x72,
+	0x1a, 0xc7, 0x42, 0x71, 0x0b, 0x25, 0x0b, 0xe1, 0x58, 0xb3, 0xb5, 0x07, 0x68, 0xf6, 0x13, 0xda,
+	0x98, 0xc8, 0xf0, 0x34, 0xf6, 0xc4, 0x35, 0xb7, 0x81, 0x4f, 0x81, 0xff, 0xf5, 0xe2, 0x34, 0xd6,
+	0x45, 0x41, 0x14, 0x0c, 0xf6, 0x29, 0x6d, 0xbe, 0x93, 0x41, 0x6c, 0xe4, 0x55, 0x94, 0xc4, 0x3a,
+	0xc5, 0x55, 0x24, 0x64, 0x29, 0xf5, 0x7b, 0x2c, 0x08, 0x58, 0x45, 0x19, 0x57, 0x65, 0x51, 0x95,
+	0x71, 0xec, 0x46, 0x59, 0x51, 0x14, 0x01, 0x40, 0x2a, 0xf9, 0x34, 0x51, 0x28, 0x83, 0x8c, 0x55,
+	0xc8, 0x39, 0xa7, 0x5d, 0xab, 0x14, 0x9d, 0x25, 0xe7, 0xec, 0x25, 0x6d, 0x85, 0x62, 0xaa, 0xbf,
+	0x51, 0xa3, 0xc0, 0x9f, 0x69, 0xde, 0x42, 0x61, 0x1c, 0x30, 0x06, 0x61, 0x2e, 0xf2, 0xed, 0x72,
+	0x2e, 0x78, 0x1b, 0x91, 0x4a, 0x94, 0x0d, 0x32, 0xc6, 0x17, 0xd7, 0x73, 0x05, 0xd2, 0x5e, 0x9f,
+	0x8e, 0x92, 0xc3, 0x8e, 0x68, 0x23, 0x49, 0xc7, 0x6f, 0x53, 0xa1, 0x96, 0x7c, 0x77, 0x6b, 0x3e,
+	0x4a, 0x9e, 0x39, 0x45, 0x22, 0xc4, 0x85, 0x3b, 0x0e, 0x05, 0xdf, 0x43, 0xaa, 0x28, 0xd1, 0xde,
+	0x7b, 0x4a, 0x2b, 0x7f, 0xc8, 0xef, 0x4c, 0xee, 0xdc, 0x79, 0xb3, 0xb5, 0xaf, 0x7f, 0x87, 0x97,
+	0xd4, 0x86, 0x5b, 0xd5, 0x36, 0xde, 0xca, 0x36, 0x50, 0xef, 0x27, 0x42, 0xdb, 0xb8, 0x14, 0x57,
+	0x5c, 0x95, 0xac, 0x75, 0xd5, 0x52, 0xe3, 0x16, 0x2e, 0x4d, 0x80, 0xd8, 0x3e, 0xc8, 0xf1, 0x8d,
+	0x1b, 0x89, 0x4c, 0xbe, 0xcd, 0x51, 0xf9, 0xcd, 0x3e, 0xab, 0x94, 0x9d, 0x29, 0xf5, 0x19, 0xdc,
+	0x61, 0x24, 0x92, 0x34, 0xd4, 0x1b, 0xf4, 0xdd, 0xfb, 0x97, 0xd0, 0xdd, 0x55, 0xc6, 0xba, 0x1a,
+	0x23, 0xc5, 0xfe, 0x85, 0xcc, 0x70, 0x1b, 0x01, 0xc4, 0x78, 0xd8, 0x44, 0x86, 0x67, 0x32, 0xe1,
+	0x35, 0x94, 0xda, 0x1c, 0x63, 0x07, 0x10, 0x4d, 0xa3, 0xa2, 0x0b, 0xae, 0x2d, 0xba, 0x9c, 0x52,
+	0xb6, 0x24, 0x07, 0xed, 0x0f, 0x88, 0x79, 0x3b, 0x37, 0xe1, 0x75, 0xdc, 0xda, 0xdc, 0xc4, 0x78,
+	0xd1, 0xc2, 0x0d, 0x53, 0x01, 0x42, 0xdc, 0x41, 0xbb, 0x57, 0x70, 0x6f, 0x48, 0x1d, 0x28, 0x59,
+	0xc6, 0x28, 0xb9, 0x58, 0x69, 0x8e, 0xe4, 0xc2, 0x60, 0x0b, 0x6e, 0xa1, 0x85, 0x64, 0xd1, 0xfb,
+	0xd9, 0xa6, 0x8d, 0x32, 0x25, 0x2f, 0x69, 0x2b, 0x7b, 0xf7, 0xb7, 0xa9, 0xd4, 0x82, 0x13, 0x64,
+	0x68, 0x38, 0x60, 0x78, 0x6e, 0x02, 0x3f, 0x8f, 0x97, 0x3a, 0x93, 0x52, 0xc9, 0x43, 0x01, 0x63,
+	0x55, 0x52, 0x05, 0xbe, 0x49, 0xe9, 0xeb, 0x04, 0x34, 0x54, 0x5a, 0x55, 0x85, 0x9b, 0x3c, 0x98,
+	0x72, 0xe3, 0x36, 0x8a, 0x03, 0x62, 0x9e, 0x48, 0x41, 0x6d, 0x3a, 0x28, 0x94, 0x41, 0xe6, 0x0c,
+	0x73, 0x57, 0x89, 0x58, 0x67, 0xa6, 0x55, 0x47, 0x1d, 0x05, 0x07, 0xa0, 0x03, 0x00, 0x63, 0x07,
+	0x37, 0x24, 0x80, 0xaa, 0xfb, 0x66, 0xff, 0xd1, 0xc0, 0xff, 0x81, 0x02, 0x15, 0xef, 0xcb, 0x40,
+	0x84, 0x1e, 0x72, 0x18, 0x32, 0xc2, 0x81, 0xfc, 0xdd, 0x5a, 0x5d, 0xb2, 0xf2, 0x6e, 0x1d, 0x23,
+	0xd8, 0xc8, 0xcc, 0x62, 0xbc, 0x5d, 0x86, 0xc8, 0xa8, 0x00, 0xcd, 0x09, 0xa1, 0xd9, 0xf2, 0xc7,
+	0x28, 0x9a, 0x41, 0xa5, 0x46, 0x76, 0x3f, 0xd0, 0xc8, 0x0b, 0x5a, 0x73, 0x7d, 0x7f, 0xc5, 0x0a,
+	0x0c, 0x50, 0x56, 0xec, 0x93, 0xed, 0x15, 0xcb, 0xfa, 0xd4, 0xf9, 0x2a, 0x75, 0x95, 0xe9, 0x7c,
+	0x9b, 0x88, 0x8e, 0x6f, 0x08, 0xbd, 0x73, 0xba, 0x77, 0x22, 0xa3, 0xc8, 0x8d, 0x3d, 0x24, 0x94,
+	0x6c, 0x13, 0x72, 0xcf, 0x26, 0x1b, 0xeb, 0xa8, 0xf7, 0xab, 0x45, 0x69, 0x35, 0xe9, 0x6d, 0x70,
+	0x2b, 0x73, 0xc7, 0x70, 0xd5, 0x20, 0x0c, 0x50, 0x74, 0x87, 0xda, 0xdd, 0x21, 0xef, 0x10, 0x29,
+	0xa9, 0xf0, 0x85, 0xd5, 0x59, 0x78, 0x45, 0x5e, 0x43, 0x2c, 0xaf, 0xad, 0xfc, 0x5c, 0x73, 0x68,
+	0x74, 0xaa, 0x3f, 0x60, 0x74, 0x2a, 0x67, 0xa1, 0x9d, 0x6d, 0xb3, 0x50, 0x63, 0xcd, 0x2c, 0x54,
+	0x0c, 0x0e, 0xcd, 0xfb, 0x06, 0x87, 0x1f, 0x08, 0x6d, 0x14, 0x81, 0x32, 0xdb, 0xe4, 0x03, 0xd7,
+	0xe2, 0x95, 0x67, 0x5a, 0x60, 0xa7, 0xc5, 0xa7, 0x31, 0x15, 0x25, 0x26, 0xa9, 0x4a, 0x82, 0x85,
+	0x58, 0x19, 0x8e, 0x2b, 0xd8, 0xa4, 0x35, 0xd1, 0xd1, 0x43, 0xd2, 0x6a, 0x68, 0xc7, 0xcf, 0x6f,
+	0xfe, 0xee, 0x90, 0x9b, 0xdb, 0x0e, 0xf9, 0xed, 0xb6, 0x43, 0xfe, 0xba, 0xed, 0x90, 0x1f, 0xff,
+	0xe9, 0x3c, 0xfa, 0x6f, 0x00, 0xfc, 0x26, 0xae, 0xf0, 0x04, 0x0d, 0x00, 0x00,
 }
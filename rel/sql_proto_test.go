@@ -32,4 +32,18 @@ func TestPb(t *testing.T) {
 	}
 }
 
+func TestPbUnion(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT name FROM orders UNION ALL SELECT name FROM users EXCEPT SELECT name FROM banned ORDER BY name LIMIT 5`
+	s, err := rel.ParseSql(sql)
+	assert.True(t, err == nil, "Should not error on parse sql but got [%v] for %s", err, sql)
+	su := s.(*rel.SqlUnion)
+	pbBytes, err := proto.Marshal(su.ToPbStatement())
+	assert.True(t, err == nil, "Should not error on proto.Marshal but got [%v] for %s", err, sql)
+	su2, err := rel.SqlFromPb(pbBytes)
+	assert.True(t, err == nil, "Should not error from pb but got [%v] for %s ", err, sql)
+	assert.True(t, su.Equal(su2), "Equal?")
+	assert.Equal(t, su.String(), su2.String())
+}
+
 var _ = u.EMPTY
@@ -1,6 +1,7 @@
 package rel
 
 import (
+	"fmt"
 	"strings"
 
 	u "github.com/araddon/gou"
@@ -79,15 +80,19 @@ func RewriteSqlSource(m *SqlSource, parentStmt *SqlSelect) *SqlSelect {
 		sql2.Columns = columnsFromJoin(m, from.JoinExpr, sql2.Columns)
 
 		// We also need to create an expression used for evaluating
-		// the values of Join "Keys"
-		if from.JoinExpr != nil {
-			joinNodesForFrom(parentStmt, m, from.JoinExpr, 0)
+		// the values of Join "Keys", only the equality conditions are keys
+		equi, _ := JoinConditions(from.JoinExpr)
+		for _, cond := range equi {
+			joinNodesForFrom(parentStmt, m, cond, 0)
 		}
 	}
 
 	if parentStmt.Where != nil {
 		node, cols := rewriteWhere(parentStmt, m, parentStmt.Where.Expr, make(Columns, 0))
-		if node != nil {
+		if node != nil && !m.nullSupplying(parentStmt) {
+			// The null supplying side of an outer join can't be filtered before the
+			// join, it would pad rows with nulls instead of removing them.  The
+			// where columns are still needed to evaluate the where after the join.
 			sql2.Where = &SqlWhere{Expr: node}
 		}
 		if len(cols) > 0 {
@@ -104,6 +109,163 @@ func RewriteSqlSource(m *SqlSource, parentStmt *SqlSelect) *SqlSelect {
 	m.cols = sql2.UnAliasedColumns()
 	return sql2
 }
+
+// JoinConditions split a join expression (ON ...) on its top level AND's
+// into the equality conditions between the two sides of the join, which
+// are hashable as a (composite) join key, and the residual conditions which
+// must be evaluated on each pair of rows.
+//
+//	ON a.x = b.x AND a.y = b.y          equi: [a.x = b.x, a.y = b.y]
+//	ON a.x = b.x AND a.ts > b.start     equi: [a.x = b.x]  residual: [a.ts > b.start]
+//	ON a.ts BETWEEN b.start AND b.end   residual: [a.ts BETWEEN b.start AND b.end]
+func JoinConditions(node expr.Node) (equi, residual []expr.Node) {
+	if node == nil {
+		return nil, nil
+	}
+	bn, ok := node.(*expr.BinaryNode)
+	if !ok {
+		return nil, []expr.Node{node}
+	}
+	switch bn.Operator.T {
+	case lex.TokenAnd, lex.TokenLogicAnd:
+		e1, r1 := JoinConditions(bn.Args[0])
+		e2, r2 := JoinConditions(bn.Args[1])
+		return append(e1, e2...), append(r1, r2...)
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		left, right := joinSideAlias(bn.Args[0]), joinSideAlias(bn.Args[1])
+		if left != "" && right != "" && left != right {
+			return []expr.Node{node}, nil
+		}
+	}
+	return nil, []expr.Node{node}
+}
+
+// joinSideAlias the single source alias all identities in node refer to,
+// or empty if they refer to more than one source or are not qualified.
+func joinSideAlias(node expr.Node) string {
+	alias := ""
+	for _, in := range expr.FindAllIdentities(node) {
+		left, _, hasLeft := in.LeftRight()
+		if !hasLeft || (alias != "" && alias != left) {
+			return ""
+		}
+		alias = left
+	}
+	return alias
+}
+
+// ReorderJoins reorder the sources of an inner join, order[k] is the
+// current index of the source to put at position k.  Each condition of the
+// ON clauses is moved to the first source by which all of the sources it
+// refers to have been joined.
+//
+//	FROM a JOIN b ON a.x = b.x JOIN c ON b.y = c.y    order [2, 1, 0]
+//	FROM c JOIN b ON b.y = c.y JOIN a ON a.x = b.x
+func (m *SqlSelect) ReorderJoins(order []int) error {
+	if len(order) != len(m.From) {
+		return fmt.Errorf("join order has %d sources but FROM has %d", len(order), len(m.From))
+	}
+	pos := make(map[string]int, len(order))
+	seen := make([]bool, len(m.From))
+	for k, i := range order {
+		if i < 0 || i >= len(m.From) || seen[i] {
+			return fmt.Errorf("invalid join order %v", order)
+		}
+		seen[i] = true
+		from := m.From[i]
+		if from.LeftOrRight != 0 || (from.JoinType != 0 && from.JoinType != lex.TokenInner && from.JoinType != lex.TokenCross) {
+			return fmt.Errorf("only inner joins may be reordered: %s", from)
+		}
+		alias := from.Alias
+		if alias == "" {
+			alias = from.Name
+		}
+		pos[strings.ToLower(alias)] = k
+	}
+
+	conds := make([][]expr.Node, len(order))
+	for _, from := range m.From {
+		for _, cond := range joinConjuncts(from.JoinExpr, nil) {
+			// at least the first join, even if only refers to one source
+			at := 1
+			for _, in := range expr.FindAllIdentities(cond) {
+				left, _, hasLeft := in.LeftRight()
+				k, ok := pos[strings.ToLower(left)]
+				if !hasLeft || !ok {
+					return fmt.Errorf("join condition must qualify its columns with their source: %s", cond)
+				}
+				if k > at {
+					at = k
+				}
+			}
+			conds[at] = append(conds[at], cond)
+		}
+	}
+
+	from := make([]*SqlSource, len(order))
+	for k, i := range order {
+		src := m.From[i]
+		src.Op, src.JoinType, src.JoinExpr = 0, 0, nil
+		switch {
+		case k == 0:
+			// the first source is not joined
+		case len(conds[k]) == 0:
+			src.JoinType = lex.TokenCross
+		default:
+			src.Op = lex.TokenOn
+			src.JoinType = lex.TokenInner
+			src.JoinExpr = conds[k][0]
+			for _, cond := range conds[k][1:] {
+				src.JoinExpr = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, src.JoinExpr, cond)
+			}
+		}
+		src.pb = nil
+		from[k] = src
+	}
+	m.From = from
+	return nil
+}
+
+// joinConjuncts split a join expression on its top level AND's.
+func joinConjuncts(node expr.Node, conds []expr.Node) []expr.Node {
+	if node == nil {
+		return conds
+	}
+	if bn, ok := node.(*expr.BinaryNode); ok {
+		switch bn.Operator.T {
+		case lex.TokenAnd, lex.TokenLogicAnd:
+			conds = joinConjuncts(bn.Args[0], conds)
+			return joinConjuncts(bn.Args[1], conds)
+		}
+	}
+	return append(conds, node)
+}
+
+// nullSupplying is this source the null supplying side of an outer join,
+// ie the right side of LEFT JOIN, left side of RIGHT JOIN, or either side
+// of FULL OUTER JOIN.
+func (m *SqlSource) nullSupplying(stmt *SqlSelect) bool {
+	pos := -1
+	for i, from := range stmt.From {
+		if from == m {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return false
+	}
+	if pos > 0 && (m.LeftOrRight == lex.TokenLeft || m.LeftOrRight == lex.TokenFull) {
+		return true
+	}
+	for _, from := range stmt.From[pos+1:] {
+		if from.LeftOrRight == lex.TokenRight || from.LeftOrRight == lex.TokenFull {
+			return true
+		}
+	}
+	return false
+}
+
 func rewriteIntoProjection(sel *SqlSelect, m Columns) {
 	if len(m) == 0 {
 		return
@@ -114,7 +276,7 @@ func rewriteIntoProjection(sel *SqlSelect, m Columns) {
 		switch n := c.Expr.(type) {
 		case *expr.IdentityNode:
 			colsToAdd = append(colsToAdd, c.SourceField)
-		case *expr.FuncNode:
+		case *expr.FuncNode, *expr.CaseNode, *expr.WindowNode:
 
 			idents := expr.FindAllIdentities(n)
 			for _, in := range idents {
@@ -176,7 +338,7 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 			//u.Debugf("returning original: %s", nt)
 			return node, cols
 		}
-	case *expr.NumberNode, *expr.NullNode, *expr.StringNode:
+	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ParamNode:
 		return nt, cols
 	case *expr.BinaryNode:
 		//u.Infof("binaryNode  T:%v", nt.Operator.T.String())
@@ -209,9 +371,52 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 			} else {
 				//u.Warnf("n1=%#v  n2=%#v    %#v", n1, n2, nt)
 			}
+		case lex.TokenIN:
+			if sub, ok := nt.Args[1].(*expr.SubQueryNode); ok {
+				// Sub-queries are evaluated after the join, we only need the
+				// columns they refer to from this source.
+				cols = subQueryColumns(stmt, from, nt.Args[0], sub, cols)
+				return nil, cols
+			}
 		default:
 			//u.Warnf("un-implemented op: %#v", nt)
 		}
+	case *expr.CaseNode:
+		// only if every part of the case can be rewritten for this source
+		cn := &expr.CaseNode{}
+		var n expr.Node
+		if nt.Operand != nil {
+			if cn.Operand, cols = rewriteWhere(stmt, from, nt.Operand, cols); cn.Operand == nil {
+				return nil, cols
+			}
+		}
+		for i, when := range nt.Whens {
+			if n, cols = rewriteWhere(stmt, from, when, cols); n == nil {
+				return nil, cols
+			}
+			cn.Whens = append(cn.Whens, n)
+			if n, cols = rewriteWhere(stmt, from, nt.Thens[i], cols); n == nil {
+				return nil, cols
+			}
+			cn.Thens = append(cn.Thens, n)
+		}
+		if nt.Else != nil {
+			if cn.Else, cols = rewriteWhere(stmt, from, nt.Else, cols); cn.Else == nil {
+				return nil, cols
+			}
+		}
+		return cn, cols
+	case *expr.UnaryNode:
+		switch arg := nt.Arg.(type) {
+		case *expr.SubQueryNode:
+			// EXISTS (SELECT ...)
+			cols = subQueryColumns(stmt, from, nil, arg, cols)
+		case *expr.UnaryNode, *expr.BinaryNode:
+			// NOT EXISTS (SELECT ...), NOT (x IN (SELECT ...))
+			if n, cols2 := rewriteWhere(stmt, from, arg, cols); n == nil {
+				cols = cols2
+			}
+		}
 	default:
 		u.Warnf("%T node types are not suppored yet for where rewrite", node)
 	}
@@ -219,6 +424,58 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 	return nil, cols
 }
 
+// subQueryColumns find the columns of this source referred to by a sub-query
+// predicate, the left side of IN as well as correlated references from
+// inside of the sub-query.
+func subQueryColumns(stmt *SqlSelect, from *SqlSource, arg expr.Node, sub *expr.SubQueryNode, cols Columns) Columns {
+	var idents expr.IdentityNodes
+	if arg != nil {
+		for _, in := range expr.FindAllIdentities(arg) {
+			// un-qualified names are only ours if we are the only source
+			if _, _, hasLeft := in.LeftRight(); hasLeft || len(stmt.From) == 1 {
+				idents = append(idents, in)
+			}
+		}
+	}
+	if subSel, ok := sub.Stmt.(*SqlSelect); ok {
+		// only qualified names inside the sub-query can refer to us
+		var subIdents expr.IdentityNodes
+		for _, col := range subSel.Columns {
+			if col.Expr != nil {
+				subIdents = append(subIdents, expr.FindAllIdentities(col.Expr)...)
+			}
+		}
+		if subSel.Where != nil {
+			subIdents = append(subIdents, expr.FindAllIdentities(subSel.Where.Expr)...)
+		}
+		if subSel.Having != nil {
+			subIdents = append(subIdents, expr.FindAllIdentities(subSel.Having)...)
+		}
+		for _, in := range subIdents {
+			if _, _, hasLeft := in.LeftRight(); hasLeft {
+				idents = append(idents, in)
+			}
+		}
+	}
+	for _, in := range idents {
+		left, right, hasLeft := in.LeftRight()
+		if hasLeft && left != from.alias {
+			continue
+		}
+		found := false
+		for _, col := range cols {
+			if col.SourceField == right {
+				found = true
+				break
+			}
+		}
+		if !found {
+			cols = append(cols, NewColumn(right))
+		}
+	}
+	return cols
+}
+
 func joinNodesForFrom(stmt *SqlSelect, from *SqlSource, node expr.Node, depth int) expr.Node {
 
 	switch nt := node.(type) {
@@ -240,7 +497,7 @@ func joinNodesForFrom(stmt *SqlSelect, from *SqlSource, node expr.Node, depth in
 		} else {
 			u.Warnf("dropping join expr node: %q", nt.String())
 		}
-	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ValueNode:
+	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ValueNode, *expr.ParamNode:
 		//u.Warnf("skipping? %v", nt.String())
 		return nt
 	case *expr.FuncNode:
@@ -351,25 +608,12 @@ func columnsFromJoin(from *SqlSource, node expr.Node, cols Columns) Columns {
 				}
 			}
 		}
-	case *expr.FuncNode:
-		//u.Warnf("columnsFromJoin func node: %s", nt.String())
-		for _, arg := range nt.Args {
-			cols = columnsFromJoin(from, arg, cols)
-		}
-	case *expr.BinaryNode:
-		switch nt.Operator.T {
-		case lex.TokenAnd, lex.TokenLogicAnd, lex.TokenLogicOr:
-			cols = columnsFromJoin(from, nt.Args[0], cols)
-			cols = columnsFromJoin(from, nt.Args[1], cols)
-		case lex.TokenEqual, lex.TokenEqualEqual:
-			cols = columnsFromJoin(from, nt.Args[0], cols)
-			cols = columnsFromJoin(from, nt.Args[1], cols)
-		default:
-			u.Warnf("un-implemented op: %v", nt.Operator)
-		}
 	default:
-		u.LogTracef(u.INFO, "whoops")
-		u.Warnf("%T node types are not suppored yet for join rewrite %s", node, from.String())
+		// func, binary, between etc, any identity may be needed to
+		// evaluate the join
+		for _, in := range expr.FindAllIdentities(node) {
+			cols = columnsFromJoin(from, in, cols)
+		}
 	}
 	return cols
 }
@@ -386,7 +630,7 @@ func rewriteNode(from *SqlSource, node expr.Node) expr.Node {
 				return &in
 			}
 		}
-	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ValueNode:
+	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ValueNode, *expr.ParamNode:
 		//u.Warnf("skipping? %v", nt.String())
 		return nt
 	case *expr.BinaryNode:
@@ -58,7 +58,12 @@ WHERE
 		FROM users AS u 
 		INNER JOIN orders AS o 
 		ON u.user_id = o.user_id;
-	`}
+	`,
+		`SELECT u.user_id, o.item_id FROM users AS u LEFT JOIN orders AS o ON u.user_id = o.user_id`,
+		`SELECT u.user_id, o.item_id FROM users AS u RIGHT OUTER JOIN orders AS o ON u.user_id = o.user_id`,
+		`SELECT u.user_id, o.item_id FROM users AS u FULL OUTER JOIN orders AS o ON u.user_id = o.user_id`,
+		`SELECT u.user_id, o.item_id FROM users AS u CROSS JOIN orders AS o`,
+	}
 )
 
 func parseOrPanic(t *testing.T, query string) rel.SqlStatement {
@@ -307,6 +312,43 @@ func TestSqlRewrite(t *testing.T) {
 	assert.True(t, sql.String() == `SELECT p.actor, p.`+"`repository.name`"+`, a.title FROM article AS a
 	INNER JOIN github_push AS p ON p.actor = a.author WHERE p.follow_ct > 20 AND a.email != NULL`, "Wrong Full SQL?: '%v'", sql.String())
 
+	// Only equality conditions between the two sides are join keys, the
+	// rest of the join expression columns are still needed for evaluation
+	s = `SELECT u.name, o.item_id
+			FROM users AS u INNER JOIN orders AS o 
+			ON u.user_id = o.user_id AND o.price > u.min_price AND o.item_id = 5;`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	sql.Rewrite()
+	assert.Equal(t, "SELECT name, user_id, min_price FROM users", sql.From[0].Source.String())
+	assert.Equal(t, "SELECT item_id, user_id, price FROM orders", sql.From[1].Source.String())
+	assert.Equal(t, 1, len(sql.From[0].JoinNodes()))
+	assert.Equal(t, 1, len(sql.From[1].JoinNodes()))
+	equi, residual := rel.JoinConditions(sql.From[1].JoinExpr)
+	assert.Equal(t, 1, len(equi))
+	assert.Equal(t, "u.user_id = o.user_id", equi[0].String())
+	assert.Equal(t, 2, len(residual))
+	assert.Equal(t, "o.price > u.min_price", residual[0].String())
+
+	// Outer joins can't push the where down into the null supplying side
+	s = `
+		SELECT 
+			p.actor, a.title
+		FROM article AS a 
+		LEFT JOIN github_push AS p 
+			ON p.actor = a.author
+		WHERE p.follow_ct > 20 AND a.email IS NOT NULL
+	`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	rw0 = sql.From[0].Rewrite(sql)
+	rw1 = sql.From[1].Rewrite(sql)
+	assert.Equal(t, "SELECT title, author, email FROM article WHERE email != NULL", rw0.String())
+	assert.Equal(t, "SELECT actor, follow_ct FROM github_push", rw1.String())
+	sql = parseOrPanic(t, strings.Replace(s, "LEFT JOIN", "FULL OUTER JOIN", 1)).(*rel.SqlSelect)
+	rw0 = sql.From[0].Rewrite(sql)
+	rw1 = sql.From[1].Rewrite(sql)
+	assert.Equal(t, "SELECT title, author, email FROM article", rw0.String())
+	assert.Equal(t, "SELECT actor, follow_ct FROM github_push", rw1.String())
+
 	s = `SELECT u.user_id, o.item_id, u.reg_date, u.email, o.price, o.order_date FROM users AS u
 	INNER JOIN (
 				SELECT price, order_date, user_id from ORDERS
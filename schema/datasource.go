@@ -129,6 +129,53 @@ type (
 	ConnSeeker interface {
 		Get(key driver.Value) (Message, error)
 	}
+	// ConnFilter is an optional interface for a connection that can filter the
+	// rows it scans by conditions of the WHERE clause, ie equality or range
+	// conditions on the keys of a key-value store or the query parameters of
+	// a REST api, without planning the whole query (plan.SourcePlanner).
+	//
+	// The planner offers the top-level AND'ed conditions of the where on this
+	// source, the connection returns for each whether it took it.  Every row
+	// it returns must pass the conditions it took, the rest are evaluated
+	// on its rows.
+	ConnFilter interface {
+		Filter(conds []expr.Node) []bool
+	}
+	// ConnProjection is an optional interface for a connection that can read
+	// only some of the columns of its rows, ie not parse or convert the unused
+	// fields of a wide file or not select them from a database.
+	//
+	// Before the scan the planner passes the columns the query reads.  Rows
+	// keep all of the Columns() in their positions, those not asked for may
+	// be nil.
+	ConnProjection interface {
+		Project(cols []string)
+	}
+	// ConnLimit is an optional interface for a connection that can stop after
+	// the first n rows of a LIMIT, and optionally return its rows sorted by
+	// the ORDER BY of the limit (top-n), ie from an index or a store with its
+	// own sort.
+	//
+	// It is only offered when every row of the source is a row of the result.
+	// The orderBy expressions, desc[i] true for descending, are nil if there
+	// is no order by.  Returns false if it can't, ie can't sort by those
+	// expressions, the engine then sorts and limits the rows.
+	ConnLimit interface {
+		LimitRows(n int, orderBy []expr.Node, desc []bool) bool
+	}
+	// ConnAggregate is an optional interface for a connection that can
+	// compute the aggregates of a group by itself, ie a database running
+	// count(*) ... GROUP BY, rather than returning all of its rows.
+	//
+	// It is only offered when every row of the source is in a group.  It is
+	// passed the group by expressions, none for a single group, and the
+	// aggregate functions of the query, returns false if it can't compute
+	// all of them.  If it can, its rows are one per group: the value of each
+	// group by expression, then the partial (*expr.AggPartial) of each
+	// aggregate in the order given, which the engine merges.
+	ConnAggregate interface {
+		Aggregate(groupBy []expr.Node, aggs []*expr.FuncNode) bool
+	}
 	// ConnMutation creates a Mutator connection similar to Open() connection for select
 	// - accepts the plan context used in this upsert/insert/update
 	// - returns a connection which must be closed
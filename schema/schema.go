@@ -111,6 +111,23 @@ type (
 		cols           []string               // array of column names
 		lastRefreshed  time.Time              // Last time we refreshed this schema
 		rows           [][]driver.Value
+		stats          *TableStats  // Statistics collected by ANALYZE TABLE, nil if never analyzed
+		statsMu        sync.RWMutex // stats are replaced while other queries are planned
+	}
+
+	// TableStats statistics of the rows of a table, used by the planner to
+	// estimate the size of each source of a join.
+	TableStats struct {
+		Rows     int64                  // count of rows
+		Fields   map[string]*FieldStats // statistics of each Field, by name
+		Analyzed time.Time              // when these were collected
+	}
+	// FieldStats statistics of the values of a Field.
+	FieldStats struct {
+		Nulls    int64       // count of null values
+		Distinct int64       // estimated count of distinct non-null values
+		Min      value.Value // smallest non-null value, nil if all are null
+		Max      value.Value // largest non-null value, nil if all are null
 	}
 
 	// Field Describes the column info, name, data type, defaults, index, null
@@ -495,6 +512,31 @@ func (m *Table) HasField(name string) bool {
 	return false
 }
 
+// Stats the statistics collected by ANALYZE TABLE, nil if the table has
+// not been analyzed.  They are never modified, only replaced.
+func (m *Table) Stats() *TableStats {
+	m.statsMu.RLock()
+	defer m.statsMu.RUnlock()
+	return m.stats
+}
+
+// SetStats replace the statistics of the table, they must not be modified
+// after as queries planned at the same time read them.
+func (m *Table) SetStats(stats *TableStats) {
+	m.statsMu.Lock()
+	m.stats = stats
+	m.statsMu.Unlock()
+}
+
+// FieldStats the statistics of the given field, nil if the table has not
+// been analyzed or the field is unknown.
+func (m *TableStats) FieldStats(name string) *FieldStats {
+	if m == nil {
+		return nil
+	}
+	return m.Fields[name]
+}
+
 // FieldsAsMessages get list of all fields as interface Message
 // used in schema as sql "describe table"
 func (m *Table) FieldsAsMessages() []Message {
@@ -92,7 +92,23 @@ func RunTestSuite(t TestingT) {
 
 	// Distinct keyword
 	TestSelect(t, "SELECT COUNT(DISTINCT(`users.email`)) AS cd FROM users",
-		[][]driver.Value{{int64(0)}},
+		[][]driver.Value{{int64(3)}},
+	)
+	TestSelect(t, "SELECT COUNT(DISTINCT user_id) AS cd FROM orders",
+		[][]driver.Value{{int64(2)}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id, item_id FROM orders ORDER BY user_id DESC, item_id",
+		[][]driver.Value{{"abcabcabc", "1"}, {"9Ip1aKbeZe2njCDM", "1"}, {"9Ip1aKbeZe2njCDM", "2"}},
+	)
+	// limit applies after de-duping
+	TestSelect(t, "SELECT DISTINCT item_count FROM orders LIMIT 2",
+		[][]driver.Value{{"82"}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id FROM orders ORDER BY user_id DESC LIMIT 1",
+		[][]driver.Value{{"abcabcabc"}},
 	)
 
 	TestSelect(t, "SELECT email FROM users ORDER BY email DESC",
@@ -106,12 +122,54 @@ func RunTestSuite(t TestingT) {
 	// doesn't exist.
 	TestSelectErr(t, "SELECT email, non_existent_field FROM users ORDER BY email ASC", nil)
 
-	/*
-		// TODO: #56 DISTINCT inside count()
-		testutil.TestSelect(t, "SELECT COUNT(DISTINCT(`users.user_id`)) AS cd FROM users",
-			[][]driver.Value{{int64(3)}},
-		)
+	// Set operations
+	TestSelect(t, "SELECT user_id FROM users UNION ALL SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"9Ip1aKbeZe2njCDM"}, {"9Ip1aKbeZe2njCDM"},
+			{"abcabcabc"}, {"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}, {"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id DESC LIMIT 2",
+		[][]driver.Value{{"hT2impsabc345c"}, {"hT2impsOPUREcVPc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id LIMIT 2 OFFSET 1",
+		[][]driver.Value{{"abcabcabc"}, {"hT2impsOPUREcVPc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id OFFSET 2",
+		[][]driver.Value{{"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users INTERSECT SELECT user_id FROM orders",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}},
+	)
+	TestSelect(t, "SELECT user_id FROM orders INTERSECT ALL SELECT user_id FROM orders WHERE order_id > 1 ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users EXCEPT SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM orders EXCEPT ALL SELECT user_id FROM users ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	// Each side must have same number of columns, of comparable types
+	TestSelectErr(t, "SELECT user_id, email FROM users UNION SELECT user_id FROM orders", nil)
+	TestSelectErr(t, "SELECT user_id FROM users UNION SELECT price FROM orders", nil)
+	// Order by, limit only after the last select, of the whole result
+	TestSelectErr(t, "SELECT user_id FROM users ORDER BY user_id UNION SELECT user_id FROM orders", nil)
+	TestSelectErr(t, "SELECT user_id FROM users LIMIT 1 UNION ALL SELECT user_id FROM orders INTERSECT SELECT user_id FROM users", nil)
+
+	// Where sub-queries, as semi/anti joins
+	TestSelect(t, "SELECT email FROM users WHERE user_id IN (SELECT user_id FROM orders)",
+		[][]driver.Value{{"aaron@email.com"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE user_id NOT IN (SELECT user_id FROM orders) ORDER BY email",
+		[][]driver.Value{{"bob@email.com"}, {"not_an_email_2"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE NOT EXISTS (SELECT order_id FROM orders WHERE orders.user_id = users.user_id) ORDER BY email",
+		[][]driver.Value{{"bob@email.com"}, {"not_an_email_2"}},
+	)
 
+	/*
 		// TODO: #56 this doesn't work because ordering is non-deterministic coming out of group by currently
 		//  which technically don't think there is any sql expectation of ordering, but there is for this test harness
 		testutil.TestSelect(t, "select `users`.`user_id` AS userids FROM users GROUP BY `users`.`user_id`;",
@@ -171,7 +229,7 @@ func RunSimpleSuite(t TestingT) {
 
 	// Distinct keyword
 	TestSelect(t, "SELECT COUNT(DISTINCT(`users`.`email`)) AS cd FROM users",
-		[][]driver.Value{{int64(0)}},
+		[][]driver.Value{{int64(3)}},
 	)
 
 	// Function in select projected columns that needs to be late evaluated.
@@ -180,6 +238,14 @@ func RunSimpleSuite(t TestingT) {
 		[][]driver.Value{{"aaron"}},
 	)
 
+	// Case expressions, searched and simple
+	TestSelect(t, "SELECT email, CASE WHEN referral_count > 50 THEN \"high\" ELSE \"low\" END AS bucket FROM users WHERE email = \"aaron@email.com\"",
+		[][]driver.Value{{"aaron@email.com", "high"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE CASE interests WHEN \"swimming\" THEN true ELSE false END",
+		[][]driver.Value{{"bob@email.com"}},
+	)
+
 	return
 	TestSelect(t, "SELECT email FROM users ORDER BY email DESC",
 		[][]driver.Value{{"not_an_email_2"}, {"bob@email.com"}, {"aaron@email.com"}},
@@ -192,12 +258,54 @@ func RunSimpleSuite(t TestingT) {
 	// doesn't exist.
 	TestSelectErr(t, "SELECT email, non_existent_field FROM users ORDER BY email ASC", nil)
 
-	/*
-		// TODO: #56 DISTINCT inside count()
-		testutil.TestSelect(t, "SELECT COUNT(DISTINCT(`users.user_id`)) AS cd FROM users",
-			[][]driver.Value{{int64(3)}},
-		)
+	// Set operations
+	TestSelect(t, "SELECT user_id FROM users UNION ALL SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"9Ip1aKbeZe2njCDM"}, {"9Ip1aKbeZe2njCDM"},
+			{"abcabcabc"}, {"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}, {"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id DESC LIMIT 2",
+		[][]driver.Value{{"hT2impsabc345c"}, {"hT2impsOPUREcVPc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id LIMIT 2 OFFSET 1",
+		[][]driver.Value{{"abcabcabc"}, {"hT2impsOPUREcVPc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id OFFSET 2",
+		[][]driver.Value{{"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users INTERSECT SELECT user_id FROM orders",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}},
+	)
+	TestSelect(t, "SELECT user_id FROM orders INTERSECT ALL SELECT user_id FROM orders WHERE order_id > 1 ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	TestSelect(t, "SELECT user_id FROM users EXCEPT SELECT user_id FROM orders ORDER BY user_id",
+		[][]driver.Value{{"hT2impsOPUREcVPc"}, {"hT2impsabc345c"}},
+	)
+	TestSelect(t, "SELECT user_id FROM orders EXCEPT ALL SELECT user_id FROM users ORDER BY user_id",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	// Each side must have same number of columns, of comparable types
+	TestSelectErr(t, "SELECT user_id, email FROM users UNION SELECT user_id FROM orders", nil)
+	TestSelectErr(t, "SELECT user_id FROM users UNION SELECT price FROM orders", nil)
+	// Order by, limit only after the last select, of the whole result
+	TestSelectErr(t, "SELECT user_id FROM users ORDER BY user_id UNION SELECT user_id FROM orders", nil)
+	TestSelectErr(t, "SELECT user_id FROM users LIMIT 1 UNION ALL SELECT user_id FROM orders INTERSECT SELECT user_id FROM users", nil)
+
+	// Where sub-queries, as semi/anti joins
+	TestSelect(t, "SELECT email FROM users WHERE user_id IN (SELECT user_id FROM orders)",
+		[][]driver.Value{{"aaron@email.com"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE user_id NOT IN (SELECT user_id FROM orders) ORDER BY email",
+		[][]driver.Value{{"bob@email.com"}, {"not_an_email_2"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE NOT EXISTS (SELECT order_id FROM orders WHERE orders.user_id = users.user_id) ORDER BY email",
+		[][]driver.Value{{"bob@email.com"}, {"not_an_email_2"}},
+	)
 
+	/*
 		// TODO: #56 this doesn't work because ordering is non-deterministic coming out of group by currently
 		//  which technically don't think there is any sql expectation of ordering, but there is for this test harness
 		testutil.TestSelect(t, "select `users`.`user_id` AS userids FROM users GROUP BY `users`.`user_id`;",
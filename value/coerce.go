@@ -157,6 +157,63 @@ func Equal(l, r Value) (bool, error) {
 	return false, fmt.Errorf("Could not evaluate equals for %v = %v", l.Value(), r.Value())
 }
 
+// Compare orders two values, returns -1, 0 or 1 as l sorts before, equal
+// to or after r.  Nil values sort first, then numbers (and times) and
+// strings that parse as numbers which compare as numbers, then anything
+// else by its string:
+//
+//	nil < "9" < 10 < "10.5" < "1a" < "abc"
+func Compare(l, r Value) int {
+	lr, lf := compareRank(l)
+	rr, rf := compareRank(r)
+	switch {
+	case lr < rr:
+		return -1
+	case lr > rr:
+		return 1
+	case lr == rankNil:
+		return 0
+	case lr == rankNumber:
+		ln, lok := l.(NumericValue)
+		rn, rok := r.(NumericValue)
+		if lok && rok && l.Type() == r.Type() {
+			return ln.Compare(rn)
+		}
+		switch {
+		case lf < rf:
+			return -1
+		case lf > rf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(l.ToString(), r.ToString())
+}
+
+// ranks of the kinds of values in the order of Compare
+const (
+	rankNil = iota
+	rankNumber
+	rankOther
+)
+
+// compareRank the rank of the kind of a value in the order of Compare, and
+// the number of a number.
+func compareRank(v Value) (int, float64) {
+	if v == nil || v.Nil() {
+		return rankNil, 0
+	}
+	switch vt := v.(type) {
+	case NumericValue:
+		return rankNumber, vt.Float()
+	case StringValue:
+		if f, ok := StringToFloat64(vt.Val()); ok && !math.IsNaN(f) {
+			return rankNumber, f
+		}
+	}
+	return rankOther, 0
+}
+
 // ValueToString convert all scalar values to their go string.
 func ValueToString(val Value) (string, bool) {
 	if val == nil || val.Err() {
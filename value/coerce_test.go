@@ -2,6 +2,7 @@ package value
 
 import (
 	"encoding/json"
+	"sort"
 	"testing"
 	"time"
 
@@ -228,6 +229,48 @@ func TestEqual(t *testing.T) {
 	notEqual(NewStringsValue([]string{"100"}), NewStringsValue([]string{"100", "200"}))
 }
 
+func TestCompare(t *testing.T) {
+	assert.Equal(t, 0, Compare(nil, NewNilValue()))
+	assert.Equal(t, -1, Compare(nil, NewIntValue(1)))
+	assert.Equal(t, 1, Compare(NewStringValue("a"), NewNilValue()))
+
+	assert.Equal(t, -1, Compare(NewIntValue(9), NewIntValue(10)))
+	assert.Equal(t, 1, Compare(NewNumberValue(9.5), NewIntValue(9)))
+	assert.Equal(t, 0, Compare(NewNumberValue(10), NewIntValue(10)))
+
+	// numeric strings compare as numbers, not their text, before any
+	// other string
+	assert.Equal(t, -1, Compare(NewStringValue("9"), NewStringValue("10")))
+	assert.Equal(t, 1, Compare(NewStringValue("10.5"), NewIntValue(9)))
+	assert.Equal(t, -1, Compare(NewStringValue("10"), NewStringValue("1a")))
+	assert.Equal(t, 1, Compare(NewStringValue("1a"), NewIntValue(10)))
+	assert.Equal(t, 0, Compare(NewStringValue("abc"), NewStringValue("abc")))
+
+	// a total order, of mixed numbers, strings of numbers, and other strings
+	vals := []Value{NewStringValue("1a"), NewStringValue("9"), NewStringValue("10"), NewIntValue(10),
+		NewNumberValue(9.5), NewStringValue("abc"), NewNilValue(), NewStringValue("NaN"), NewIntValue(2)}
+	for _, a := range vals {
+		for _, b := range vals {
+			assert.Equal(t, Compare(a, b), -Compare(b, a), "%v %v", a, b)
+			for _, c := range vals {
+				if Compare(a, b) < 0 && Compare(b, c) < 0 {
+					assert.Equal(t, -1, Compare(a, c), "%v < %v < %v", a, b, c)
+				}
+			}
+		}
+	}
+	sort.SliceStable(vals, func(i, j int) bool { return Compare(vals[i], vals[j]) < 0 })
+	sorted := make([]interface{}, len(vals))
+	for i, v := range vals {
+		sorted[i] = v.Value()
+	}
+	assert.Equal(t, []interface{}{nil, int64(2), "9", 9.5, "10", int64(10), "1a", "NaN", "abc"}, sorted)
+
+	t1, _ := dateparse.ParseIn("2016/01/01", time.UTC)
+	t2, _ := dateparse.ParseIn("2016/01/02", time.UTC)
+	assert.Equal(t, -1, Compare(NewTimeValue(t1), NewTimeValue(t2)))
+}
+
 func TestValueToString(t *testing.T) {
 	good := func(expect string, v Value) {
 		val, ok := ValueToString(v)
@@ -122,8 +122,20 @@ func resolveIncludesDepth(ctx expr.Includer, arg expr.Node, depth int) error {
 				return err
 			}
 		}
+	case *expr.CaseNode:
+		for _, narg := range n.ChildrenArgs() {
+			if err := resolveIncludesDepth(ctx, narg, depth+1); err != nil {
+				return err
+			}
+		}
+	case *expr.WindowNode:
+		for _, narg := range n.ChildrenArgs() {
+			if err := resolveIncludesDepth(ctx, narg, depth+1); err != nil {
+				return err
+			}
+		}
 	case *expr.NumberNode, *expr.IdentityNode, *expr.StringNode, nil,
-		*expr.ValueNode, *expr.NullNode:
+		*expr.ValueNode, *expr.NullNode, *expr.ParamNode:
 		return nil
 	case *expr.IncludeNode:
 		return resolveInclude(ctx, n, depth+1)
@@ -171,8 +183,20 @@ func evalDepth(ctx expr.EvalContext, arg expr.Node, depth int) (value.Value, boo
 	case *expr.NullNode:
 		// WHERE (`users.user_id` != NULL)
 		return value.NewNilValue(), true
+	case *expr.ParamNode:
+		// WHERE user_id = ?   the value bound at execution
+		if argVal.Value == nil {
+			return nil, false
+		}
+		return argVal.Value, true
 	case *expr.IncludeNode:
 		return walkInclude(ctx, argVal, depth+1)
+	case *expr.SubQueryNode:
+		return walkSubQuery(ctx, argVal)
+	case *expr.CaseNode:
+		return walkCase(ctx, argVal, depth)
+	case *expr.WindowNode:
+		return walkWindow(ctx, argVal)
 	case *expr.ValueNode:
 		if argVal.Value == nil {
 			return nil, false
@@ -1008,6 +1032,57 @@ func walkFunc(ctx expr.EvalContext, node *expr.FuncNode, depth int) (value.Value
 	return node.Eval(ctx, args)
 }
 
+func walkSubQuery(ctx expr.EvalContext, node *expr.SubQueryNode) (value.Value, bool) {
+	if node.Eval == nil {
+		u.LogThrottle(u.WARN, 10, "No Eval() for sub-query %s", node)
+		return nil, false
+	}
+	return node.Eval(ctx)
+}
+
+// walkWindow a window function is computed by the window task over all rows
+// of its partition, which adds the result to the row under the window's text.
+func walkWindow(ctx expr.EvalContext, node *expr.WindowNode) (value.Value, bool) {
+	return ctx.Get(node.String())
+}
+
+// walkCase evaluate the WHEN's in order, returning the THEN of the first match
+// and only evaluating the expressions needed to get there.  A searched CASE
+// matches on a true WHEN, a simple CASE when the operand equals the WHEN, a
+// NULL never matches.  No match and no ELSE is NULL.
+func walkCase(ctx expr.EvalContext, node *expr.CaseNode, depth int) (value.Value, bool) {
+	var operand value.Value
+	if node.Operand != nil {
+		v, ok := evalDepth(ctx, node.Operand, depth+1)
+		if ok && v != nil && !v.Nil() {
+			operand = v
+		}
+	}
+	for i, when := range node.Whens {
+		if node.Operand == nil {
+			if matched, ok := evalBool(ctx, when, depth+1); !ok || !matched {
+				continue
+			}
+		} else {
+			if operand == nil {
+				break
+			}
+			v, ok := evalDepth(ctx, when, depth+1)
+			if !ok || v == nil || v.Nil() {
+				continue
+			}
+			if eq, err := value.Equal(operand, v); err != nil || !eq {
+				continue
+			}
+		}
+		return evalDepth(ctx, node.Thens[i], depth+1)
+	}
+	if node.Else != nil {
+		return evalDepth(ctx, node.Else, depth+1)
+	}
+	return value.NewNilValue(), true
+}
+
 func operateNumbers(op lex.Token, av, bv value.NumberValue) value.Value {
 	switch op.T {
 	case lex.TokenPlus, lex.TokenStar, lex.TokenMultiply, lex.TokenDivide, lex.TokenMinus,
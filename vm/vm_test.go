@@ -253,6 +253,11 @@ var (
 		//vmt("eq/toint types", `eq(toint(notreal || 6),6)`, true, noError),
 		vmt(`2 * (3 + 5)`, int64(16), noError),
 
+		// case expressions
+		vmt(`CASE WHEN int5 > 10 THEN "big" WHEN int5 > 3 THEN "medium" ELSE "small" END`, "medium", noError),
+		vmt(`CASE user_id WHEN "xyz" THEN 1 WHEN "abc" THEN 2 END`, int64(2), noError),
+		vmt(`CASE WHEN int5 > 10 THEN "big" ELSE "small" END == "small"`, true, noError),
+
 		vmt(`(bvalt == true && bvalf == false)`, true, noError),
 		vmt(`(fld1 != "stuff" AND (field2 == "stuff" AND toint(fieldx) > 7))`, false, noError),
 